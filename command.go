@@ -0,0 +1,158 @@
+package goctl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Command builds the arguments of a goctl command, so that values from users or the API
+// cannot be mistaken for flags. For example,
+//
+//	goctl.New("pr", "list").Flag("--state", "open").Repo("owner/repo").JSON("number", "title")
+//
+// builds "pr list --state=open --repo=owner/repo --json=number,title". Flag values are
+// always joined to their flag with "=", and arguments that start with a dash are placed
+// after a "--" separator. A Command records the first invalid command or flag name it is
+// given and returns it when it is run.
+type Command struct {
+	path  []string
+	flags []string
+	args  []string
+	err   error
+}
+
+// New returns a Command that runs the goctl command with the given name, such as "pr",
+// "list".
+func New(command ...string) *Command {
+	c := &Command{path: command}
+	for _, name := range command {
+		if name == "" || strings.HasPrefix(name, "-") {
+			c.setErr(fmt.Errorf("invalid command name %q", name))
+		}
+	}
+	return c
+}
+
+// Flag adds a flag with an optional value. The name may be given with or without its
+// leading dashes; names of a single letter are short flags. A flag without a value is a
+// boolean flag, and a flag with several values is repeated for each of them.
+func (c *Command) Flag(name string, values ...string) *Command {
+	name, err := flagName(name)
+	if err != nil {
+		c.setErr(err)
+		return c
+	}
+	if len(values) == 0 {
+		c.flags = append(c.flags, name)
+	}
+	for _, value := range values {
+		c.flags = append(c.flags, name+"="+value)
+	}
+	return c
+}
+
+// Repo adds the --repo flag, which selects the repository in the "[HOST/]OWNER/REPO" form
+// instead of the one of the current directory.
+func (c *Command) Repo(repo string) *Command {
+	return c.Flag("--repo", repo)
+}
+
+// JSON adds the --json flag with the given fields. It is not needed with DecodeJSON, which
+// requests the fields of the value it decodes into.
+func (c *Command) JSON(fields ...string) *Command {
+	return c.Flag("--json", strings.Join(fields, ","))
+}
+
+// Arg adds positional arguments, such as the number of a pull request.
+func (c *Command) Arg(args ...string) *Command {
+	c.args = append(c.args, args...)
+	return c
+}
+
+// Args returns the arguments of the command, or an error if an invalid command or flag
+// name was given.
+func (c *Command) Args() ([]string, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	args := make([]string, 0, len(c.path)+len(c.flags)+len(c.args)+1)
+	args = append(args, c.path...)
+	args = append(args, c.flags...)
+	for _, arg := range c.args {
+		if strings.HasPrefix(arg, "-") {
+			args = append(args, "--")
+			break
+		}
+	}
+	return append(args, c.args...), nil
+}
+
+// String returns the arguments of the command separated by spaces, for logging. It is
+// not quoted for a shell.
+func (c *Command) String() string {
+	args, err := c.Args()
+	if err != nil {
+		return fmt.Sprintf("invalid command: %s", err)
+	}
+	return strings.Join(args, " ")
+}
+
+// Exec runs the command with ExecContext and captures its output.
+func (c *Command) Exec(ctx context.Context) (stdout, stderr bytes.Buffer, err error) {
+	args, err := c.Args()
+	if err != nil {
+		return
+	}
+	return ExecContext(ctx, args...)
+}
+
+// ExecWith runs the command with ExecWith and the given options.
+func (c *Command) ExecWith(ctx context.Context, opts ExecOptions) error {
+	args, err := c.Args()
+	if err != nil {
+		return err
+	}
+	return ExecWith(ctx, opts, args...)
+}
+
+// Stream runs the command with ExecStream, ignoring the arguments of opts.
+func (c *Command) Stream(ctx context.Context, opts StreamOptions) error {
+	args, err := c.Args()
+	if err != nil {
+		return err
+	}
+	opts.Args = args
+	return ExecStream(ctx, opts)
+}
+
+// DecodeJSON runs the command and decodes its output as JSON into v, which must be a
+// pointer. Unless JSON was called, the fields of v are requested as in ExecJSON.
+func (c *Command) DecodeJSON(ctx context.Context, v interface{}) error {
+	args, err := c.Args()
+	if err != nil {
+		return err
+	}
+	return execJSON(ctx, jsonArgs(args, reflect.TypeOf(v)), v)
+}
+
+func (c *Command) setErr(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// flagName returns name with its leading dashes, or an error if it is not a valid flag
+// name.
+func flagName(name string) (string, error) {
+	bare := strings.TrimLeft(name, "-")
+	if bare == "" || strings.ContainsAny(bare, "= \t\r\n") {
+		return "", fmt.Errorf("invalid flag name %q", name)
+	}
+	if len(bare) == 1 {
+		return "-" + bare, nil
+	}
+	return "--" + bare, nil
+}
@@ -0,0 +1,75 @@
+package goctl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      *Command
+		wantArgs []string
+		wantErr  string
+	}{
+		{
+			name:     "flags",
+			cmd:      New("pr", "list").Flag("--state", "open").Repo("owner/repo").JSON("number", "title"),
+			wantArgs: []string{"pr", "list", "--state=open", "--repo=owner/repo", "--json=number,title"},
+		},
+		{
+			name:     "flag names without dashes",
+			cmd:      New("issue", "list").Flag("label", "bug", "help wanted").Flag("R", "owner/repo").Flag("web"),
+			wantArgs: []string{"issue", "list", "--label=bug", "--label=help wanted", "-R=owner/repo", "--web"},
+		},
+		{
+			name:     "values that look like flags",
+			cmd:      New("issue", "create").Flag("--title", "--web").Flag("body", "-"),
+			wantArgs: []string{"issue", "create", "--title=--web", "--body=-"},
+		},
+		{
+			name:     "arguments",
+			cmd:      New("pr", "view").Arg("12").Flag("--comments"),
+			wantArgs: []string{"pr", "view", "--comments", "12"},
+		},
+		{
+			name:     "arguments that look like flags",
+			cmd:      New("search", "issues").Arg("crash", "-label:bug").Flag("--limit", "5"),
+			wantArgs: []string{"search", "issues", "--limit=5", "--", "crash", "-label:bug"},
+		},
+		{
+			name:    "invalid flag name",
+			cmd:     New("pr", "list").Flag("--state=open").Flag("--"),
+			wantErr: `invalid flag name "--state=open"`,
+		},
+		{
+			name:    "invalid command name",
+			cmd:     New("pr", "--help"),
+			wantErr: `invalid command name "--help"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := tt.cmd.Args()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestCommandDecodeJSON(t *testing.T) {
+	stubJSONGoctl(t)
+	var prs []testPullRequest
+	err := New("pr", "list").DecodeJSON(context.Background(), &prs)
+	assert.NoError(t, err)
+	assert.Equal(t, []testPullRequest{{Number: 1, HeadRefName: "feature", Title: "Add feature"}}, prs)
+
+	err = New("pr", "list").Flag("--state=open").DecodeJSON(context.Background(), &prs)
+	assert.EqualError(t, err, `invalid flag name "--state=open"`)
+}
@@ -1,6 +1,7 @@
 package goctl_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -29,6 +30,40 @@ func ExampleExec() {
 	fmt.Println(stdErr.String())
 }
 
+// List the open pull requests of a repository with a search query provided by the user.
+func ExampleNew() {
+	query := "-label:wip"
+	var prs []struct {
+		Number int
+		Title  string
+	}
+	err := goctl.New("pr", "list").
+		Repo("khulnasoft-lab/goctl").
+		Flag("--state", "open").
+		Flag("--search", query).
+		DecodeJSON(context.Background(), &prs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(prs)
+}
+
+// Watch a workflow run and print its progress as it is reported.
+func ExampleExecStream() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	err := goctl.ExecStream(ctx, goctl.StreamOptions{
+		Args:   []string{"run", "watch", "-R", "khulnasoft-lab/goctl"},
+		Stderr: os.Stderr,
+		OnStdoutLine: func(line string) {
+			fmt.Println("progress:", line)
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
 // Get tags from khulnasoft-lab/goctl repository using REST API.
 func ExampleDefaultRESTClient() {
 	client, err := api.DefaultRESTClient()
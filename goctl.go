@@ -8,21 +8,97 @@ package goctl
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/khulnasoft-lab/execsafer"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/auth"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/i18n"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/logging"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/middleware"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/redact"
 )
 
+// ExecError is returned when goctl runs but exits with a non-zero status.
+type ExecError struct {
+	Args     []string
+	ExitCode int
+	Err      error
+	// Stderr holds the end of the error output of goctl. It is empty when the error
+	// stream is connected to a file, as with ExecInteractive.
+	Stderr string
+}
+
+func (e *ExecError) Error() string {
+	return fmt.Sprintf("%s: %s", i18n.Sprintf(execFailed), e.Err)
+}
+
+func (e *ExecError) Unwrap() error {
+	return e.Err
+}
+
+// execFailed is the message of errors of goctl runs, translated with the i18n package.
+const execFailed = "goctl execution failed"
+
+// authRequiredExitCode is the exit status of goctl commands that require authentication.
+const authRequiredExitCode = 4
+
+// maxStderrCapture is the number of trailing bytes of the error output kept in ExecError.
+const maxStderrCapture = 64 * 1024
+
+// IsNotLoggedIn reports whether err is an ExecError caused by goctl not being
+// authenticated with the host, or by a rejected token.
+func IsNotLoggedIn(err error) bool {
+	var exitErr *ExecError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	return exitErr.ExitCode == authRequiredExitCode ||
+		stderrContains(exitErr, "goctl auth login", "not logged in", "authentication required", "http 401", "bad credentials")
+}
+
+// IsNotFound reports whether err is an ExecError caused by a repository or other resource
+// that does not exist or is not visible to the authenticated user.
+func IsNotFound(err error) bool {
+	var exitErr *ExecError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	return stderrContains(exitErr, "http 404", "not found", "could not resolve to a")
+}
+
+// IsRateLimited reports whether err is an ExecError caused by an exhausted primary or
+// secondary API rate limit.
+func IsRateLimited(err error) bool {
+	var exitErr *ExecError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	return stderrContains(exitErr, "rate limit", "http 429")
+}
+
+func stderrContains(err *ExecError, patterns ...string) bool {
+	stderr := strings.ToLower(err.Stderr)
+	for _, p := range patterns {
+		if strings.Contains(stderr, p) {
+			return true
+		}
+	}
+	return false
+}
+
 // Exec invokes a goctl command in a subprocess and captures the output and error streams.
 func Exec(args ...string) (stdout, stderr bytes.Buffer, err error) {
 	goctlExe, err := Path()
 	if err != nil {
 		return
 	}
-	err = run(context.Background(), goctlExe, nil, nil, &stdout, &stderr, args)
+	err = run(context.Background(), goctlExe, "", nil, nil, &stdout, &stderr, args)
 	return
 }
 
@@ -32,10 +108,47 @@ func ExecContext(ctx context.Context, args ...string) (stdout, stderr bytes.Buff
 	if err != nil {
 		return
 	}
-	err = run(ctx, goctlExe, nil, nil, &stdout, &stderr, args)
+	err = run(ctx, goctlExe, "", nil, nil, &stdout, &stderr, args)
+	return
+}
+
+// ExecWithTokenSource is like ExecContext but authenticates the command with a token from
+// ts, such as an installation token of a GitHub App, instead of the credentials of the
+// logged in user. The token is passed in the GOCTL_TOKEN and GOCTL_ENTERPRISE_TOKEN
+// environment variables, and inherited host-specific token variables such as
+// GOCTL_TOKEN_ghe_example_com are removed so that they do not take precedence.
+func ExecWithTokenSource(ctx context.Context, ts auth.TokenSource, args ...string) (stdout, stderr bytes.Buffer, err error) {
+	goctlExe, err := Path()
+	if err != nil {
+		return
+	}
+	env, err := tokenEnv(ctx, ts)
+	if err != nil {
+		return
+	}
+	err = run(ctx, goctlExe, "", env, nil, &stdout, &stderr, args)
 	return
 }
 
+// tokenEnv returns the environment of a goctl subprocess authenticated with a token from
+// ts, or nil to inherit the environment if ts is nil.
+func tokenEnv(ctx context.Context, ts auth.TokenSource) ([]string, error) {
+	if ts == nil {
+		return nil, nil
+	}
+	token, err := ts.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	var env []string
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(strings.ToUpper(kv), "GOCTL_TOKEN_") {
+			env = append(env, kv)
+		}
+	}
+	return append(env, "GOCTL_TOKEN="+token, "GOCTL_ENTERPRISE_TOKEN="+token), nil
+}
+
 // Exec invokes a goctl command in a subprocess with its stdin, stdout, and stderr streams connected to
 // those of the parent process. This is suitable for running goctl commands with interactive prompts.
 func ExecInteractive(ctx context.Context, args ...string) error {
@@ -43,7 +156,77 @@ func ExecInteractive(ctx context.Context, args ...string) error {
 	if err != nil {
 		return err
 	}
-	return run(ctx, goctlExe, nil, os.Stdin, os.Stdout, os.Stderr, args)
+	return run(ctx, goctlExe, "", nil, os.Stdin, os.Stdout, os.Stderr, args)
+}
+
+// ExecOptions configures a goctl command run by ExecWith.
+type ExecOptions struct {
+	// Dir is the working directory of the command, such as the checkout of the repository
+	// it operates on. The working directory of the parent process is used if it is empty.
+	Dir string
+	// Env holds additional environment variables in the "key=value" form, such as
+	// "GOCTL_HOST=ghe.io", which are added to the environment of the parent process and
+	// take precedence over it.
+	Env []string
+	// Stdin is the standard input of the command. It is empty if nil.
+	Stdin io.Reader
+	// Stdout and Stderr receive the output of the command. Output is discarded if they
+	// are nil.
+	Stdout io.Writer
+	Stderr io.Writer
+	// Timeout is the time after which the command is killed. There is no timeout if it is
+	// zero.
+	Timeout time.Duration
+}
+
+// ExecWith invokes a goctl command in a subprocess configured by opts, waiting for it to
+// exit. The command is killed when ctx is done or the timeout of opts elapses.
+func ExecWith(ctx context.Context, opts ExecOptions, args ...string) error {
+	goctlExe, err := Path()
+	if err != nil {
+		return err
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	return run(ctx, goctlExe, opts.Dir, execEnv(opts.Env), opts.Stdin, opts.Stdout, opts.Stderr, args)
+}
+
+// Executor runs goctl commands. Code that runs goctl through an Executor rather than the
+// functions of this package can be tested without goctl installed, with the FakeExecutor
+// of the goctltest package.
+type Executor interface {
+	// ExecWith runs a goctl command configured by opts, as the ExecWith function does.
+	ExecWith(ctx context.Context, opts ExecOptions, args ...string) error
+}
+
+// ProcessExecutor is the Executor that runs goctl in a subprocess.
+type ProcessExecutor struct{}
+
+// ExecWith calls the ExecWith function.
+func (ProcessExecutor) ExecWith(ctx context.Context, opts ExecOptions, args ...string) error {
+	return ExecWith(ctx, opts, args...)
+}
+
+// execEnv returns the environment of the parent process with the additional variables
+// in extra, or nil to inherit the environment if there are none.
+func execEnv(extra []string) []string {
+	if len(extra) == 0 {
+		return nil
+	}
+	return append(os.Environ(), extra...)
+}
+
+// Middleware hooks into API requests and subprocesses. See [middleware.Middleware].
+type Middleware = middleware.Middleware
+
+// Use registers middleware that is applied to all API clients created and all goctl, git,
+// and extension subprocesses run afterwards, so that cross-cutting behavior such as metrics
+// or retries is configured in one place. Middleware registered first is outermost.
+func Use(mw ...Middleware) {
+	middleware.Use(mw...)
 }
 
 // Path searches for an executable named "goctl" in the directories named by the PATH environment variable.
@@ -52,19 +235,66 @@ func Path() (string, error) {
 	if goctlExe := os.Getenv("GOCTL_PATH"); goctlExe != "" {
 		return goctlExe, nil
 	}
-	return safeexec.LookPath("goctl")
+	return execsafer.LookPath("goctl")
 }
 
-func run(ctx context.Context, goctlExe string, env []string, stdin io.Reader, stdout, stderr io.Writer, args []string) error {
-	cmd := exec.CommandContext(ctx, goctlExe, args...)
-	cmd.Stdin = stdin
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-	if env != nil {
-		cmd.Env = env
+func run(ctx context.Context, goctlExe, dir string, env []string, stdin io.Reader, stdout, stderr io.Writer, args []string) error {
+	cmd := middleware.Cmd{Path: goctlExe, Args: args, Dir: dir, Env: env, Stdin: stdin, Stdout: stdout, Stderr: stderr}
+	// Keep the end of the error output for ExecError, unless it goes to a file such as a
+	// terminal, which goctl would no longer detect if it were wrapped.
+	var stderrTail *tailBuffer
+	if _, isFile := stderr.(*os.File); !isFile {
+		stderrTail = &tailBuffer{max: maxStderrCapture}
+		if stderr != nil {
+			cmd.Stderr = io.MultiWriter(stderr, stderrTail)
+		} else {
+			cmd.Stderr = stderrTail
+		}
 	}
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("goctl execution failed: %w", err)
+	start := time.Now()
+	err := middleware.Run(ctx, cmd)
+	// Arguments may contain secrets, such as the body of "goctl secret set", so only the
+	// command is logged.
+	logger := logging.Logger().With("path", goctlExe, "command", commandName(args), "duration", time.Since(start))
+	if err != nil {
+		logger.DebugContext(ctx, "goctl execution failed", "error", err)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			e := &ExecError{Args: args, ExitCode: exitErr.ExitCode(), Err: err}
+			if stderrTail != nil {
+				e.Stderr = string(stderrTail.buf)
+			}
+			return e
+		}
+		return fmt.Errorf("%s: %w", i18n.Sprintf(execFailed), err)
 	}
+	logger.DebugContext(ctx, "goctl executed")
 	return nil
 }
+
+// commandName returns the leading arguments of a goctl command that name the command,
+// such as "issue list", with anything that looks like a token masked.
+func commandName(args []string) string {
+	var name []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") || len(name) == 2 {
+			break
+		}
+		name = append(name, arg)
+	}
+	return redact.MaskTokens(strings.Join(name, " "))
+}
+
+// tailBuffer is a writer that keeps the last max bytes written to it.
+type tailBuffer struct {
+	buf []byte
+	max int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+	}
+	return len(p), nil
+}
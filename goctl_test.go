@@ -4,11 +4,17 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/i18n"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/middleware"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
 )
 
 func TestHelperProcess(t *testing.T) {
@@ -40,9 +46,19 @@ func TestHelperProcessLongRunning(t *testing.T) {
 	os.Exit(0)
 }
 
+func TestHelperProcessEnv(t *testing.T) {
+	if os.Getenv("GOCTL_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	dir, _ := os.Getwd()
+	input, _ := io.ReadAll(os.Stdin)
+	fmt.Fprintf(os.Stdout, "%s %s %s", filepath.Base(dir), os.Getenv("GOCTL_HOST"), input)
+	os.Exit(0)
+}
+
 func TestRun(t *testing.T) {
 	var stdout, stderr bytes.Buffer
-	err := run(context.TODO(), os.Args[0], []string{"GOCTL_WANT_HELPER_PROCESS=1"}, nil, &stdout, &stderr,
+	err := run(context.TODO(), os.Args[0], "", []string{"GOCTL_WANT_HELPER_PROCESS=1"}, nil, &stdout, &stderr,
 		[]string{"-test.run=TestHelperProcess", "--", "goctl", "issue", "list"})
 	assert.NoError(t, err)
 	assert.Equal(t, "[goctl issue list]", stdout.String())
@@ -51,18 +67,177 @@ func TestRun(t *testing.T) {
 
 func TestRunError(t *testing.T) {
 	var stdout, stderr bytes.Buffer
-	err := run(context.TODO(), os.Args[0], []string{"GOCTL_WANT_HELPER_PROCESS=1"}, nil, &stdout, &stderr,
+	err := run(context.TODO(), os.Args[0], "", []string{"GOCTL_WANT_HELPER_PROCESS=1"}, nil, &stdout, &stderr,
 		[]string{"-test.run=TestHelperProcess", "--", "goctl", "error"})
 	assert.EqualError(t, err, "goctl execution failed: exit status 1")
+	var exitErr *ExecError
+	assert.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 1, exitErr.ExitCode)
+	assert.Equal(t, []string{"-test.run=TestHelperProcess", "--", "goctl", "error"}, exitErr.Args)
+	assert.Equal(t, "process exited with error", exitErr.Stderr)
 	assert.Equal(t, "", stdout.String())
 	assert.Equal(t, "process exited with error", stderr.String())
 }
 
+func TestExecErrorTranslated(t *testing.T) {
+	t.Cleanup(func() { i18n.SetLanguage(language.English) })
+	assert.NoError(t, i18n.SetString(language.German, "goctl execution failed", "goctl-Ausführung fehlgeschlagen"))
+	i18n.SetLanguage(language.German)
+	err := &ExecError{ExitCode: 1, Err: fmt.Errorf("exit status 1")}
+	assert.EqualError(t, err, "goctl-Ausführung fehlgeschlagen: exit status 1")
+}
+
+func TestExecErrorPredicates(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		wantNotLoggedIn bool
+		wantNotFound    bool
+		wantRateLimited bool
+	}{
+		{
+			name:            "auth required exit code",
+			err:             &ExecError{ExitCode: 4},
+			wantNotLoggedIn: true,
+		},
+		{
+			name:            "not logged in",
+			err:             &ExecError{ExitCode: 1, Stderr: "To get started with goctl, please run:  goctl auth login\n"},
+			wantNotLoggedIn: true,
+		},
+		{
+			name:            "bad credentials",
+			err:             fmt.Errorf("listing issues: %w", &ExecError{ExitCode: 1, Stderr: "HTTP 401: Bad credentials (https://api.github.com/graphql)\n"}),
+			wantNotLoggedIn: true,
+		},
+		{
+			name:         "repository not found",
+			err:          &ExecError{ExitCode: 1, Stderr: "GraphQL: Could not resolve to a Repository with the name 'OWNER/REPO'. (repository)\n"},
+			wantNotFound: true,
+		},
+		{
+			name:         "HTTP not found",
+			err:          &ExecError{ExitCode: 1, Stderr: "HTTP 404: Not Found (https://api.github.com/repos/OWNER/REPO)\n"},
+			wantNotFound: true,
+		},
+		{
+			name:            "primary rate limit",
+			err:             &ExecError{ExitCode: 1, Stderr: "HTTP 403: API rate limit exceeded for user ID 1.\n"},
+			wantRateLimited: true,
+		},
+		{
+			name:            "secondary rate limit",
+			err:             &ExecError{ExitCode: 1, Stderr: "You have exceeded a secondary rate limit. Please wait a few minutes before you try again.\n"},
+			wantRateLimited: true,
+		},
+		{
+			name: "other failure",
+			err:  &ExecError{ExitCode: 1, Stderr: "unknown command \"foo\" for \"goctl\"\n"},
+		},
+		{
+			name: "not an exec error",
+			err:  fmt.Errorf("HTTP 404: Not Found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantNotLoggedIn, IsNotLoggedIn(tt.err))
+			assert.Equal(t, tt.wantNotFound, IsNotFound(tt.err))
+			assert.Equal(t, tt.wantRateLimited, IsRateLimited(tt.err))
+		})
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := &tailBuffer{max: 8}
+	for _, chunk := range []string{"abc", "defgh", "ijklmnopq", "r"} {
+		n, err := b.Write([]byte(chunk))
+		assert.NoError(t, err)
+		assert.Equal(t, len(chunk), n)
+	}
+	assert.Equal(t, "klmnopqr", string(b.buf))
+}
+
+func TestCommandName(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"issue", "list", "--json", "number"}, want: "issue list"},
+		{args: []string{"secret", "set", "NAME", "--body", "value"}, want: "secret set"},
+		{args: []string{"api", "-H", "Authorization: token abc"}, want: "api"},
+		{args: []string{"ghp_" + strings.Repeat("a", 36)}, want: "***"},
+		{args: nil, want: ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, commandName(tt.args))
+	}
+}
+
+func TestRunMiddleware(t *testing.T) {
+	t.Cleanup(middleware.Reset)
+	var seen []string
+	Use(Middleware{
+		Command: func(next middleware.RunFunc) middleware.RunFunc {
+			return func(ctx context.Context, cmd middleware.Cmd) error {
+				seen = append(seen, cmd.Args[len(cmd.Args)-1])
+				return next(ctx, cmd)
+			}
+		},
+	})
+	var stdout, stderr bytes.Buffer
+	err := run(context.TODO(), os.Args[0], "", []string{"GOCTL_WANT_HELPER_PROCESS=1"}, nil, &stdout, &stderr,
+		[]string{"-test.run=TestHelperProcess", "--", "goctl", "status"})
+	assert.NoError(t, err)
+	assert.Equal(t, "[goctl status]", stdout.String())
+	assert.Equal(t, []string{"status"}, seen)
+}
+
 func TestRunInteractiveContextCanceled(t *testing.T) {
 	// pass current time to ensure that deadline has already passed
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
 	cancel()
-	err := run(ctx, os.Args[0], []string{"GOCTL_WANT_HELPER_PROCESS=1"}, nil, nil, nil,
+	err := run(ctx, os.Args[0], "", []string{"GOCTL_WANT_HELPER_PROCESS=1"}, nil, nil, nil,
 		[]string{"-test.run=TestHelperProcessLongRunning", "--", "goctl", "issue", "list"})
 	assert.EqualError(t, err, "goctl execution failed: context deadline exceeded")
 }
+
+func TestExecWith(t *testing.T) {
+	t.Setenv("GOCTL_PATH", os.Args[0])
+	t.Setenv("GOCTL_HOST", "github.com")
+	dir := filepath.Join(t.TempDir(), "checkout")
+	assert.NoError(t, os.Mkdir(dir, 0755))
+
+	var stdout bytes.Buffer
+	err := ExecWith(context.Background(), ExecOptions{
+		Dir:    dir,
+		Env:    []string{"GOCTL_WANT_HELPER_PROCESS=1", "GOCTL_HOST=ghe.io"},
+		Stdin:  strings.NewReader("input"),
+		Stdout: &stdout,
+	}, "-test.run=TestHelperProcessEnv", "--", "repo", "view")
+	assert.NoError(t, err)
+	assert.Equal(t, "checkout ghe.io input", stdout.String())
+}
+
+func TestExecWithTimeout(t *testing.T) {
+	t.Setenv("GOCTL_PATH", os.Args[0])
+	start := time.Now()
+	err := ExecWith(context.Background(), ExecOptions{
+		Env:     []string{"GOCTL_WANT_HELPER_PROCESS=1"},
+		Timeout: 100 * time.Millisecond,
+	}, "-test.run=TestHelperProcessLongRunning", "--", "run", "watch")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestProcessExecutor(t *testing.T) {
+	t.Setenv("GOCTL_PATH", os.Args[0])
+	var e Executor = ProcessExecutor{}
+	var stdout bytes.Buffer
+	err := e.ExecWith(context.Background(), ExecOptions{
+		Env:    []string{"GOCTL_WANT_HELPER_PROCESS=1"},
+		Stdout: &stdout,
+	}, "-test.run=TestHelperProcess", "--", "goctl", "status")
+	assert.NoError(t, err)
+	assert.Equal(t, "[goctl status]", stdout.String())
+}
@@ -0,0 +1,63 @@
+//go:build integration
+// +build integration
+
+package goctl_test
+
+import (
+	"testing"
+
+	graphql "github.com/cli/shurcooL-graphql"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/goctltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The integration tests run against the GitHub instances configured by GOCTLTEST_TARGETS:
+//
+//	GOCTLTEST_TARGETS=targets.yml go test -tags integration -run Integration ./...
+
+func TestIntegrationREST(t *testing.T) {
+	goctltest.ForEachTarget(t, func(t *testing.T, target goctltest.Target) {
+		client, err := api.NewRESTClient(target.ClientOptions())
+		require.NoError(t, err)
+
+		var user struct {
+			Login string `json:"login"`
+		}
+		require.NoError(t, client.Get("user", &user))
+		assert.NotEmpty(t, user.Login)
+
+		err = client.Get("repos/goctltest-missing-owner/missing-repo", nil)
+		assert.ErrorIs(t, err, api.ErrNotFound)
+	})
+}
+
+func TestIntegrationGraphQL(t *testing.T) {
+	goctltest.ForEachTarget(t, func(t *testing.T, target goctltest.Target) {
+		client, err := api.NewGraphQLClient(target.ClientOptions())
+		require.NoError(t, err)
+
+		var query struct {
+			Viewer struct {
+				Login string
+			}
+			RateLimit struct {
+				Remaining int
+			}
+		}
+		require.NoError(t, client.Query("Viewer", &query, nil))
+		assert.NotEmpty(t, query.Viewer.Login)
+
+		var repoQuery struct {
+			Repository struct {
+				Name string
+			} `graphql:"repository(owner: $owner, name: $name)"`
+		}
+		err = client.Query("MissingRepository", &repoQuery, map[string]interface{}{
+			"owner": graphql.String("goctltest-missing-owner"),
+			"name":  graphql.String("missing-repo"),
+		})
+		assert.ErrorIs(t, err, api.ErrNotFound)
+	})
+}
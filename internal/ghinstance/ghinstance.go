@@ -0,0 +1,92 @@
+// Package ghinstance resolves the hostnames and endpoints of GitHub instances. It is
+// shared by pkg/auth and pkg/api, and lives below both so that neither imports the other
+// for it.
+package ghinstance
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	github    = "github.com"
+	localhost = "github.localhost"
+	garage    = "garage.github.com"
+)
+
+// IsGarage reports whether host is the garage instance, which is served like an
+// enterprise instance even though it is a subdomain of github.com.
+func IsGarage(host string) bool {
+	return strings.EqualFold(host, garage)
+}
+
+// IsEnterprise reports whether the normalized host is an enterprise instance.
+func IsEnterprise(host string) bool {
+	return host != github && host != localhost
+}
+
+// NormalizeHostname lowercases host and collapses the subdomains of github.com and
+// github.localhost, such as api.github.com, to their parent domain.
+func NormalizeHostname(host string) string {
+	hostname := strings.ToLower(host)
+	if strings.HasSuffix(hostname, "."+github) {
+		return github
+	}
+	if strings.HasSuffix(hostname, "."+localhost) {
+		return localhost
+	}
+	return hostname
+}
+
+// HostPrefix returns the base URL of the web server of host, which serves pages and OAuth
+// endpoints, ending with a slash.
+func HostPrefix(host string) string {
+	if IsGarage(host) {
+		return fmt.Sprintf("https://%s/", host)
+	}
+	host = NormalizeHostname(host)
+	if host == localhost {
+		return fmt.Sprintf("http://%s/", host)
+	}
+	return fmt.Sprintf("https://%s/", host)
+}
+
+// RESTPrefix returns the base URL of the REST API of host, ending with a slash.
+func RESTPrefix(host string) string {
+	if IsGarage(host) {
+		return fmt.Sprintf("https://%s/api/v3/", host)
+	}
+	host = NormalizeHostname(host)
+	if IsEnterprise(host) {
+		return fmt.Sprintf("https://%s/api/v3/", host)
+	}
+	if host == localhost {
+		return fmt.Sprintf("http://api.%s/", host)
+	}
+	return fmt.Sprintf("https://api.%s/", host)
+}
+
+// RESTURL returns the REST API URL of pathOrURL on host. Absolute URLs are returned
+// unchanged.
+func RESTURL(host, pathOrURL string) string {
+	if strings.HasPrefix(pathOrURL, "https://") || strings.HasPrefix(pathOrURL, "http://") {
+		return pathOrURL
+	}
+	return RESTPrefix(host) + pathOrURL
+}
+
+// UploadsPrefix returns the base URL of the upload server of host, which receives
+// release assets, ending with a slash.
+func UploadsPrefix(host string) string {
+	if IsGarage(host) {
+		return fmt.Sprintf("https://%s/api/uploads/", host)
+	}
+	host = NormalizeHostname(host)
+	if IsEnterprise(host) {
+		return fmt.Sprintf("https://%s/api/uploads/", host)
+	}
+	if host == localhost {
+		return fmt.Sprintf("http://uploads.%s/", host)
+	}
+	return fmt.Sprintf("https://uploads.%s/", host)
+}
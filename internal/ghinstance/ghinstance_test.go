@@ -0,0 +1,74 @@
+package ghinstance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeHostname(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "github.com", want: "github.com"},
+		{host: "GitHub.com", want: "github.com"},
+		{host: "api.github.com", want: "github.com"},
+		{host: "garage.github.com", want: "github.com"},
+		{host: "test.github.localhost", want: "github.localhost"},
+		{host: "ghe.example.com", want: "ghe.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeHostname(tt.host))
+		})
+	}
+}
+
+func TestURLs(t *testing.T) {
+	tests := []struct {
+		host        string
+		wantHost    string
+		wantREST    string
+		wantUploads string
+	}{
+		{
+			host:        "github.com",
+			wantHost:    "https://github.com/",
+			wantREST:    "https://api.github.com/repos/o/r",
+			wantUploads: "https://uploads.github.com/repos/o/r",
+		},
+		{
+			host:        "www.GitHub.com",
+			wantHost:    "https://github.com/",
+			wantREST:    "https://api.github.com/repos/o/r",
+			wantUploads: "https://uploads.github.com/repos/o/r",
+		},
+		{
+			host:        "github.localhost",
+			wantHost:    "http://github.localhost/",
+			wantREST:    "http://api.github.localhost/repos/o/r",
+			wantUploads: "http://uploads.github.localhost/repos/o/r",
+		},
+		{
+			host:        "garage.github.com",
+			wantHost:    "https://garage.github.com/",
+			wantREST:    "https://garage.github.com/api/v3/repos/o/r",
+			wantUploads: "https://garage.github.com/api/uploads/repos/o/r",
+		},
+		{
+			host:        "ghe.example.com",
+			wantHost:    "https://ghe.example.com/",
+			wantREST:    "https://ghe.example.com/api/v3/repos/o/r",
+			wantUploads: "https://ghe.example.com/api/uploads/repos/o/r",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			assert.Equal(t, tt.wantHost, HostPrefix(tt.host))
+			assert.Equal(t, tt.wantREST, RESTURL(tt.host, "repos/o/r"))
+			assert.Equal(t, tt.wantUploads, UploadsPrefix(tt.host)+"repos/o/r")
+		})
+	}
+	assert.Equal(t, "https://example.com/x", RESTURL("github.com", "https://example.com/x"))
+}
@@ -2,33 +2,32 @@ package git
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
 
 	"github.com/khulnasoft-lab/execsafer"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/middleware"
 )
 
 func Exec(args ...string) (stdOut, stdErr bytes.Buffer, err error) {
+	return ExecContext(context.Background(), args...)
+}
+
+func ExecContext(ctx context.Context, args ...string) (stdOut, stdErr bytes.Buffer, err error) {
 	path, err := path()
 	if err != nil {
 		err = fmt.Errorf("could not find git executable in PATH. error: %w", err)
 		return
 	}
-	return run(path, nil, args...)
+	return run(ctx, path, nil, args...)
 }
 
 func path() (string, error) {
-	return safeexec.LookPath("git")
+	return execsafer.LookPath("git")
 }
 
-func run(path string, env []string, args ...string) (stdOut, stdErr bytes.Buffer, err error) {
-	cmd := exec.Command(path, args...)
-	cmd.Stdout = &stdOut
-	cmd.Stderr = &stdErr
-	if env != nil {
-		cmd.Env = env
-	}
-	err = cmd.Run()
+func run(ctx context.Context, path string, env []string, args ...string) (stdOut, stdErr bytes.Buffer, err error) {
+	err = middleware.Run(ctx, middleware.Cmd{Path: path, Args: args, Env: env, Stdout: &stdOut, Stderr: &stdErr})
 	if err != nil {
 		err = fmt.Errorf("failed to run git: %s. error: %w", stdErr.String(), err)
 		return
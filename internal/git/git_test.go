@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -26,7 +27,7 @@ func TestHelperProcess(t *testing.T) {
 }
 
 func TestRun(t *testing.T) {
-	stdOut, stdErr, err := run(os.Args[0],
+	stdOut, stdErr, err := run(context.Background(), os.Args[0],
 		[]string{"GOCTL_WANT_HELPER_PROCESS=1"},
 		"-test.run=TestHelperProcess", "--", "git", "status")
 	assert.NoError(t, err)
@@ -35,10 +36,19 @@ func TestRun(t *testing.T) {
 }
 
 func TestRunError(t *testing.T) {
-	stdOut, stdErr, err := run(os.Args[0],
+	stdOut, stdErr, err := run(context.Background(), os.Args[0],
 		[]string{"GOCTL_WANT_HELPER_PROCESS=1"},
 		"-test.run=TestHelperProcess", "--", "git", "status", "error")
 	assert.EqualError(t, err, "failed to run git: process exited with error. error: exit status 1")
 	assert.Equal(t, "", stdOut.String())
 	assert.Equal(t, "process exited with error", stdErr.String())
 }
+
+func TestRunContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := run(ctx, os.Args[0],
+		[]string{"GOCTL_WANT_HELPER_PROCESS=1"},
+		"-test.run=TestHelperProcess", "--", "git", "status")
+	assert.ErrorIs(t, err, context.Canceled)
+}
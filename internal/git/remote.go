@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"net/url"
 	"regexp"
 	"sort"
@@ -41,12 +42,16 @@ func remoteNameSortScore(name string) int {
 }
 
 func Remotes() (RemoteSet, error) {
-	list, err := listRemotes()
+	return RemotesContext(context.Background())
+}
+
+func RemotesContext(ctx context.Context) (RemoteSet, error) {
+	list, err := listRemotes(ctx)
 	if err != nil {
 		return nil, err
 	}
 	remotes := parseRemotes(list)
-	setResolvedRemotes(remotes)
+	setResolvedRemotes(ctx, remotes)
 	sort.Sort(remotes)
 	return remotes, nil
 }
@@ -65,8 +70,8 @@ func (rs RemoteSet) FilterByHosts(hosts []string) RemoteSet {
 	return filtered
 }
 
-func listRemotes() ([]string, error) {
-	stdOut, _, err := Exec("remote", "-v")
+func listRemotes(ctx context.Context) ([]string, error) {
+	stdOut, _, err := ExecContext(ctx, "remote", "-v")
 	if err != nil {
 		return nil, err
 	}
@@ -124,8 +129,8 @@ func parseRemotes(gitRemotes []string) RemoteSet {
 	return remotes
 }
 
-func setResolvedRemotes(remotes RemoteSet) {
-	stdOut, _, err := Exec("config", "--get-regexp", `^remote\..*\.goctl-resolved$`)
+func setResolvedRemotes(ctx context.Context, remotes RemoteSet) {
+	stdOut, _, err := ExecContext(ctx, "config", "--get-regexp", `^remote\..*\.goctl-resolved$`)
 	if err != nil {
 		return
 	}
@@ -141,6 +141,13 @@ func (m *Map) SetEntry(key string, value *Map) {
 		}
 		if v.Value == key {
 			if i+1 < len(m.Content) {
+				// Keep any comments attached to the replaced value.
+				prev := m.Content[i+1]
+				if value.HeadComment == "" && value.LineComment == "" && value.FootComment == "" {
+					value.HeadComment = prev.HeadComment
+					value.LineComment = prev.LineComment
+					value.FootComment = prev.FootComment
+				}
 				m.Content[i+1] = value.Node
 				return
 			}
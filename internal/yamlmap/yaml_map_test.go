@@ -209,6 +209,13 @@ func TestMapSetEntry(t *testing.T) {
 	}
 }
 
+func TestMapSetEntryKeepsComments(t *testing.T) {
+	m, err := Unmarshal([]byte("editor: nano # the default\n"))
+	assert.NoError(t, err)
+	m.SetEntry("editor", StringValue("vim"))
+	assert.Equal(t, "editor: vim # the default\n", m.String())
+}
+
 func TestUnmarshal(t *testing.T) {
 	tests := []struct {
 		name      string
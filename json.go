@@ -0,0 +1,138 @@
+package goctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxJSONErrorOutput is the number of bytes of invalid output kept in JSONError.
+const maxJSONErrorOutput = 1024
+
+// JSONError is returned by ExecJSON when goctl succeeds but its output cannot be decoded.
+type JSONError struct {
+	Args   []string
+	Output string
+	Err    error
+}
+
+func (e *JSONError) Error() string {
+	return fmt.Sprintf("goctl output is not valid JSON: %s", e.Err)
+}
+
+func (e *JSONError) Unwrap() error {
+	return e.Err
+}
+
+// ExecJSON invokes a goctl command in a subprocess and decodes its standard output as JSON
+// into a value of type T.
+//
+// If the arguments do not already request JSON output with --json, and the command is not
+// "api", which always prints JSON, --json is appended with the fields of T. These are the
+// names in the json struct tags of T, or of its elements if T is a slice, falling back to
+// the field names with a lowercase first letter, so that
+//
+//	type PullRequest struct {
+//		Number      int
+//		HeadRefName string
+//	}
+//
+// requests "--json number,headRefName". Commands that filter the output with --jq or
+// --template must print JSON that decodes into T.
+func ExecJSON[T any](ctx context.Context, args ...string) (T, error) {
+	var result T
+	err := execJSON(ctx, jsonArgs(args, reflect.TypeOf(result)), &result)
+	return result, err
+}
+
+// execJSON invokes a goctl command and decodes its standard output into v.
+func execJSON(ctx context.Context, args []string, v interface{}) error {
+	stdout, _, err := ExecContext(ctx, args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(stdout.Bytes(), v); err != nil {
+		output := bytes.TrimSpace(stdout.Bytes())
+		if len(output) > maxJSONErrorOutput {
+			output = output[:maxJSONErrorOutput]
+		}
+		return &JSONError{Args: args, Output: string(output), Err: err}
+	}
+	return nil
+}
+
+// jsonArgs returns args with --json added to request the fields of t, if needed.
+func jsonArgs(args []string, t reflect.Type) []string {
+	if fields := jsonFields(t); len(fields) > 0 && needsJSONFlag(args) {
+		return insertFlags(args, "--json", strings.Join(fields, ","))
+	}
+	return args
+}
+
+// needsJSONFlag reports whether --json must be added to args to get JSON output.
+func needsJSONFlag(args []string) bool {
+	if len(args) > 0 && args[0] == "api" {
+		return false
+	}
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--json" || strings.HasPrefix(arg, "--json=") {
+			return false
+		}
+	}
+	return true
+}
+
+// insertFlags returns a copy of args with flags added before any "--" separator, after
+// which arguments are not parsed as flags.
+func insertFlags(args []string, flags ...string) []string {
+	i := len(args)
+	for j, arg := range args {
+		if arg == "--" {
+			i = j
+			break
+		}
+	}
+	result := make([]string, 0, len(args)+len(flags))
+	result = append(result, args[:i]...)
+	result = append(result, flags...)
+	return append(result, args[i:]...)
+}
+
+// jsonFields returns the JSON field names of the struct type t, or of its element type if
+// t is a slice, array, or pointer. It returns nil for other types.
+func jsonFields(t reflect.Type) []string {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			r, size := utf8.DecodeRuneInString(f.Name)
+			name = string(unicode.ToLower(r)) + f.Name[size:]
+		}
+		fields = append(fields, name)
+	}
+	return fields
+}
@@ -0,0 +1,109 @@
+package goctl
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPullRequest struct {
+	Number      int
+	HeadRefName string
+	Title       string `json:"title,omitempty"`
+	Internal    string `json:"-"`
+}
+
+func stubJSONGoctl(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake goctl is a shell script")
+	}
+	script := `#!/bin/sh
+case "$*" in
+  "pr list --json number,headRefName,title") echo '[{"number":1,"headRefName":"feature","title":"Add feature"}]' ;;
+  "api user") echo '{"login":"monalisa"}' ;;
+  "pr view --json number") echo '{"number":2}' ;;
+  *) echo "no pull requests match your search in OWNER/REPO"
+esac
+`
+	path := filepath.Join(t.TempDir(), "goctl")
+	assert.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	t.Setenv("GOCTL_PATH", path)
+}
+
+func TestExecJSON(t *testing.T) {
+	stubJSONGoctl(t)
+	ctx := context.Background()
+
+	prs, err := ExecJSON[[]testPullRequest](ctx, "pr", "list")
+	assert.NoError(t, err)
+	assert.Equal(t, []testPullRequest{{Number: 1, HeadRefName: "feature", Title: "Add feature"}}, prs)
+
+	user, err := ExecJSON[map[string]string](ctx, "api", "user")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"login": "monalisa"}, user)
+
+	pr, err := ExecJSON[*testPullRequest](ctx, "pr", "view", "--json", "number")
+	assert.NoError(t, err)
+	assert.Equal(t, &testPullRequest{Number: 2}, pr)
+}
+
+func TestExecJSONInvalidOutput(t *testing.T) {
+	stubJSONGoctl(t)
+	_, err := ExecJSON[json.RawMessage](context.Background(), "pr", "status")
+	var jsonErr *JSONError
+	assert.ErrorAs(t, err, &jsonErr)
+	assert.Equal(t, []string{"pr", "status"}, jsonErr.Args)
+	assert.Equal(t, "no pull requests match your search in OWNER/REPO", jsonErr.Output)
+	assert.EqualError(t, err, "goctl output is not valid JSON: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func TestJSONArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		typ      reflect.Type
+		wantArgs []string
+	}{
+		{
+			name:     "struct",
+			args:     []string{"pr", "view", "12"},
+			typ:      reflect.TypeOf(testPullRequest{}),
+			wantArgs: []string{"pr", "view", "12", "--json", "number,headRefName,title"},
+		},
+		{
+			name:     "before separator",
+			args:     []string{"search", "issues", "--", "-label:bug"},
+			typ:      reflect.TypeOf([]*testPullRequest{}),
+			wantArgs: []string{"search", "issues", "--json", "number,headRefName,title", "--", "-label:bug"},
+		},
+		{
+			name:     "json already requested",
+			args:     []string{"pr", "list", "--json=number"},
+			typ:      reflect.TypeOf([]testPullRequest{}),
+			wantArgs: []string{"pr", "list", "--json=number"},
+		},
+		{
+			name:     "api command",
+			args:     []string{"api", "repos/OWNER/REPO/pulls"},
+			typ:      reflect.TypeOf([]testPullRequest{}),
+			wantArgs: []string{"api", "repos/OWNER/REPO/pulls"},
+		},
+		{
+			name:     "not a struct",
+			args:     []string{"repo", "view"},
+			typ:      reflect.TypeOf(map[string]interface{}{}),
+			wantArgs: []string{"repo", "view"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantArgs, jsonArgs(tt.args, tt.typ))
+		})
+	}
+}
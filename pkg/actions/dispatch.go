@@ -0,0 +1,261 @@
+// Package actions triggers GitHub Actions workflows and watches their runs.
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RESTClient is the subset of [api.RESTClient] used to trigger workflows and list their runs.
+type RESTClient interface {
+	RequestWithContext(ctx context.Context, method string, path string, body io.Reader) (*http.Response, error)
+	DoWithContext(ctx context.Context, method string, path string, body io.Reader, response interface{}) error
+}
+
+// maxClientPayloadProperties is the maximum number of top-level properties of the
+// client payload of a repository_dispatch event.
+const maxClientPayloadProperties = 10
+
+// RepositoryDispatch fires a repository_dispatch event of the given type in the repository
+// "OWNER/REPO", which triggers the workflows listening for that type. The client payload
+// is made available to the workflows as github.event.client_payload and may be nil.
+func RepositoryDispatch(client RESTClient, repo, eventType string, clientPayload map[string]interface{}) error {
+	return RepositoryDispatchContext(context.Background(), client, repo, eventType, clientPayload)
+}
+
+// RepositoryDispatchContext is like RepositoryDispatch but aborts the request when the
+// context is done.
+func RepositoryDispatchContext(ctx context.Context, client RESTClient, repo, eventType string, clientPayload map[string]interface{}) error {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return err
+	}
+	if eventType == "" {
+		return errors.New("event type is required")
+	}
+	if len(eventType) > 100 {
+		return errors.New("event type must be at most 100 characters")
+	}
+	if len(clientPayload) > maxClientPayloadProperties {
+		return fmt.Errorf("client payload must have at most %d top-level properties", maxClientPayloadProperties)
+	}
+	body := map[string]interface{}{"event_type": eventType}
+	if clientPayload != nil {
+		body["client_payload"] = clientPayload
+	}
+	return post(ctx, client, fmt.Sprintf("repos/%s/%s/dispatches", owner, name), body)
+}
+
+// InvalidInputError describes an input of a workflow_dispatch run that does not match
+// the inputs declared by the workflow.
+type InvalidInputError struct {
+	Name    string
+	Message string
+}
+
+func (e *InvalidInputError) Error() string {
+	return fmt.Sprintf("input %q %s", e.Name, e.Message)
+}
+
+// WorkflowInput is an input declared by the workflow_dispatch trigger of a workflow.
+type WorkflowInput struct {
+	Description string   `yaml:"description"`
+	Required    bool     `yaml:"required"`
+	Default     string   `yaml:"default"`
+	Type        string   `yaml:"type"`
+	Options     []string `yaml:"options"`
+}
+
+// WorkflowDispatch starts a run of a workflow of the repository "OWNER/REPO" through its
+// workflow_dispatch trigger. The workflow is identified by its file name, e.g. "deploy.yml",
+// or its ID. The run uses the workflow file at ref, or at the default branch if ref is empty.
+//
+// The inputs are validated against the inputs declared in that workflow file before the
+// run is requested, so that mistakes are reported with a *InvalidInputError for each
+// invalid input rather than as an opaque API error.
+func WorkflowDispatch(client RESTClient, repo, workflow, ref string, inputs map[string]string) error {
+	return WorkflowDispatchContext(context.Background(), client, repo, workflow, ref, inputs)
+}
+
+// WorkflowDispatchContext is like WorkflowDispatch but aborts the requests when the
+// context is done.
+func WorkflowDispatchContext(ctx context.Context, client RESTClient, repo, workflow, ref string, inputs map[string]string) error {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return err
+	}
+	if workflow == "" {
+		return errors.New("workflow is required")
+	}
+	if ref == "" {
+		var r struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := client.DoWithContext(ctx, "GET", fmt.Sprintf("repos/%s/%s", owner, name), nil, &r); err != nil {
+			return err
+		}
+		ref = r.DefaultBranch
+	}
+
+	path := ".github/workflows/" + workflow
+	if _, err := strconv.ParseInt(workflow, 10, 64); err == nil {
+		var w struct {
+			Path string `json:"path"`
+		}
+		if err := client.DoWithContext(ctx, "GET", fmt.Sprintf("repos/%s/%s/actions/workflows/%s", owner, name, workflow), nil, &w); err != nil {
+			return err
+		}
+		path = w.Path
+	}
+	var file struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := client.DoWithContext(ctx, "GET", fmt.Sprintf("repos/%s/%s/contents/%s?ref=%s", owner, name, path, url.QueryEscape(ref)), nil, &file); err != nil {
+		return err
+	}
+	content := []byte(file.Content)
+	if file.Encoding == "base64" {
+		if content, err = base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", "")); err != nil {
+			return fmt.Errorf("failed to decode workflow file: %w", err)
+		}
+	}
+	declared, err := ParseWorkflowInputs(content)
+	if err != nil {
+		return err
+	}
+	if err := ValidateInputs(declared, inputs); err != nil {
+		return err
+	}
+
+	body := map[string]interface{}{"ref": ref}
+	if len(inputs) > 0 {
+		body["inputs"] = inputs
+	}
+	return post(ctx, client, fmt.Sprintf("repos/%s/%s/actions/workflows/%s/dispatches", owner, name, url.PathEscape(workflow)), body)
+}
+
+// ParseWorkflowInputs returns the inputs declared by the workflow_dispatch trigger of a
+// workflow file. It fails if the workflow cannot be triggered by workflow_dispatch.
+func ParseWorkflowInputs(workflowFile []byte) (map[string]WorkflowInput, error) {
+	var w struct {
+		On yaml.Node `yaml:"on"`
+	}
+	if err := yaml.Unmarshal(workflowFile, &w); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+	errNoTrigger := errors.New("workflow does not have a workflow_dispatch trigger")
+	switch w.On.Kind {
+	case yaml.ScalarNode:
+		if w.On.Value == "workflow_dispatch" {
+			return map[string]WorkflowInput{}, nil
+		}
+	case yaml.SequenceNode:
+		for _, n := range w.On.Content {
+			if n.Value == "workflow_dispatch" {
+				return map[string]WorkflowInput{}, nil
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(w.On.Content); i += 2 {
+			if w.On.Content[i].Value != "workflow_dispatch" {
+				continue
+			}
+			var trigger struct {
+				Inputs map[string]WorkflowInput `yaml:"inputs"`
+			}
+			if err := w.On.Content[i+1].Decode(&trigger); err != nil {
+				return nil, fmt.Errorf("failed to parse workflow_dispatch inputs: %w", err)
+			}
+			if trigger.Inputs == nil {
+				trigger.Inputs = map[string]WorkflowInput{}
+			}
+			return trigger.Inputs, nil
+		}
+	}
+	return nil, errNoTrigger
+}
+
+// ValidateInputs checks inputs against the inputs declared by a workflow, joining a
+// *InvalidInputError for each input that is unknown, missing, or of the wrong type.
+func ValidateInputs(declared map[string]WorkflowInput, inputs map[string]string) error {
+	var errs []error
+	invalid := func(name, format string, args ...interface{}) {
+		errs = append(errs, &InvalidInputError{Name: name, Message: fmt.Sprintf(format, args...)})
+	}
+	names := make([]string, 0, len(inputs))
+	for n := range inputs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		d, ok := declared[n]
+		if !ok {
+			invalid(n, "is not declared by the workflow")
+			continue
+		}
+		v := inputs[n]
+		switch d.Type {
+		case "boolean":
+			if v != "true" && v != "false" {
+				invalid(n, "must be true or false, got %q", v)
+			}
+		case "number":
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				invalid(n, "must be a number, got %q", v)
+			}
+		case "choice":
+			if !contains(d.Options, v) {
+				invalid(n, "must be one of %s, got %q", strings.Join(d.Options, ", "), v)
+			}
+		}
+	}
+	names = names[:0]
+	for n := range declared {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if _, ok := inputs[n]; !ok && declared[n].Required && declared[n].Default == "" {
+			invalid(n, "is required")
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func post(ctx context.Context, client RESTClient, path string, body interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return client.DoWithContext(ctx, "POST", path, bytes.NewReader(b), nil)
+}
+
+func splitRepo(repo string) (string, string, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("expected the \"OWNER/REPO\" format, got %q", repo)
+	}
+	return owner, name, nil
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
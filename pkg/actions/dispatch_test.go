@@ -0,0 +1,210 @@
+package actions
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedPost struct {
+	path string
+	body map[string]interface{}
+}
+
+type fakeRESTClient struct {
+	responses map[string]interface{}
+	gets      []string
+	posts     []recordedPost
+}
+
+func (c *fakeRESTClient) DoWithContext(ctx context.Context, method string, path string, body io.Reader, resp interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if method == "POST" {
+		return c.post(path, body)
+	}
+	c.gets = append(c.gets, path)
+	r, ok := c.responses[path]
+	if !ok {
+		return errors.New("HTTP 404: Not Found")
+	}
+	data, _ := json.Marshal(r)
+	return json.Unmarshal(data, resp)
+}
+
+func (c *fakeRESTClient) RequestWithContext(ctx context.Context, method string, path string, body io.Reader) (*http.Response, error) {
+	return nil, errors.New("unexpected request")
+}
+
+func (c *fakeRESTClient) post(path string, body io.Reader) error {
+	var payload map[string]interface{}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return err
+	}
+	c.posts = append(c.posts, recordedPost{path: path, body: payload})
+	return nil
+}
+
+func TestRepositoryDispatch(t *testing.T) {
+	client := &fakeRESTClient{}
+	err := RepositoryDispatch(client, "octo-org/hello-world", "deploy", map[string]interface{}{"env": "production"})
+	require.NoError(t, err)
+	assert.Equal(t, []recordedPost{{
+		path: "repos/octo-org/hello-world/dispatches",
+		body: map[string]interface{}{
+			"event_type":     "deploy",
+			"client_payload": map[string]interface{}{"env": "production"},
+		},
+	}}, client.posts)
+
+	err = RepositoryDispatch(client, "hello-world", "deploy", nil)
+	assert.EqualError(t, err, `expected the "OWNER/REPO" format, got "hello-world"`)
+	err = RepositoryDispatch(client, "octo-org/hello-world", "", nil)
+	assert.EqualError(t, err, "event type is required")
+	payload := map[string]interface{}{}
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"} {
+		payload[k] = k
+	}
+	err = RepositoryDispatch(client, "octo-org/hello-world", "deploy", payload)
+	assert.EqualError(t, err, "client payload must have at most 10 top-level properties")
+	assert.Len(t, client.posts, 1)
+}
+
+var deployWorkflow = heredoc.Doc(`
+	name: Deploy
+	on:
+	  push:
+	    branches: [main]
+	  workflow_dispatch:
+	    inputs:
+	      environment:
+	        description: Where to deploy
+	        required: true
+	        type: choice
+	        options: [staging, production]
+	      dry-run:
+	        type: boolean
+	        default: false
+	      replicas:
+	        type: number
+	        required: true
+	        default: 2
+	      version:
+	        required: true
+	jobs: {}
+`)
+
+func TestWorkflowDispatch(t *testing.T) {
+	content := base64.StdEncoding.EncodeToString([]byte(deployWorkflow))
+	client := &fakeRESTClient{responses: map[string]interface{}{
+		"repos/octo-org/hello-world":                          map[string]string{"default_branch": "trunk"},
+		"repos/octo-org/hello-world/actions/workflows/161335": map[string]string{"path": ".github/workflows/deploy.yml"},
+		"repos/octo-org/hello-world/contents/.github/workflows/deploy.yml?ref=trunk": map[string]string{
+			"content":  content[:20] + "\n" + content[20:],
+			"encoding": "base64",
+		},
+	}}
+
+	err := WorkflowDispatch(client, "octo-org/hello-world", "deploy.yml", "", map[string]string{
+		"environment": "staging",
+		"version":     "v1.2.3",
+		"dry-run":     "true",
+	})
+	require.NoError(t, err)
+
+	err = WorkflowDispatch(client, "octo-org/hello-world", "161335", "trunk", map[string]string{
+		"environment": "qa",
+		"replicas":    "many",
+		"colour":      "blue",
+	})
+	assert.Error(t, err)
+	var inputErr *InvalidInputError
+	assert.ErrorAs(t, err, &inputErr)
+	assert.Equal(t, heredoc.Doc(`
+		input "colour" is not declared by the workflow
+		input "environment" must be one of staging, production, got "qa"
+		input "replicas" must be a number, got "many"
+		input "version" is required`), err.Error())
+
+	assert.Equal(t, []recordedPost{{
+		path: "repos/octo-org/hello-world/actions/workflows/deploy.yml/dispatches",
+		body: map[string]interface{}{
+			"ref": "trunk",
+			"inputs": map[string]interface{}{
+				"environment": "staging",
+				"version":     "v1.2.3",
+				"dry-run":     "true",
+			},
+		},
+	}}, client.posts)
+}
+
+func TestParseWorkflowInputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		workflow string
+		want     map[string]WorkflowInput
+		wantErr  string
+	}{
+		{
+			name:     "scalar trigger",
+			workflow: "on: workflow_dispatch\n",
+			want:     map[string]WorkflowInput{},
+		},
+		{
+			name:     "list of triggers",
+			workflow: "on: [push, workflow_dispatch]\n",
+			want:     map[string]WorkflowInput{},
+		},
+		{
+			name:     "trigger without inputs",
+			workflow: "on:\n  workflow_dispatch:\n",
+			want:     map[string]WorkflowInput{},
+		},
+		{
+			name:     "inputs",
+			workflow: deployWorkflow,
+			want: map[string]WorkflowInput{
+				"environment": {Description: "Where to deploy", Required: true, Type: "choice", Options: []string{"staging", "production"}},
+				"dry-run":     {Type: "boolean", Default: "false"},
+				"replicas":    {Type: "number", Required: true, Default: "2"},
+				"version":     {Required: true},
+			},
+		},
+		{
+			name:     "no workflow_dispatch trigger",
+			workflow: "on: [push]\n",
+			wantErr:  "workflow does not have a workflow_dispatch trigger",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWorkflowInputs([]byte(tt.workflow))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWorkflowDispatchContextCancelled(t *testing.T) {
+	client := &fakeRESTClient{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := WorkflowDispatchContext(ctx, client, "octo-org/hello-world", "deploy.yml", "", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, client.gets)
+	assert.Empty(t, client.posts)
+}
@@ -0,0 +1,257 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
+)
+
+const (
+	// defaultWatchInterval is the initial interval between polls of WatchRun.
+	defaultWatchInterval = 3 * time.Second
+	// defaultMaxWatchInterval is the longest interval between polls of WatchRun.
+	defaultMaxWatchInterval = 30 * time.Second
+	// maxPerPage is the largest page size of the Actions API.
+	maxPerPage = 100
+)
+
+// Run is a workflow run.
+type Run struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	DisplayTitle string    `json:"display_title"`
+	WorkflowID   int64     `json:"workflow_id"`
+	RunNumber    int       `json:"run_number"`
+	RunAttempt   int       `json:"run_attempt"`
+	Event        string    `json:"event"`
+	Status       string    `json:"status"`
+	Conclusion   string    `json:"conclusion"`
+	HeadBranch   string    `json:"head_branch"`
+	HeadSHA      string    `json:"head_sha"`
+	HTMLURL      string    `json:"html_url"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Completed reports whether the run has finished, in which case its conclusion is set.
+func (r *Run) Completed() bool {
+	return r.Status == "completed"
+}
+
+// Job is a job of a workflow run.
+type Job struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Conclusion  string     `json:"conclusion"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	HTMLURL     string     `json:"html_url"`
+	Steps       []Step     `json:"steps"`
+}
+
+// Step is a step of a job.
+type Step struct {
+	Number     int    `json:"number"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+}
+
+// RunFilters selects the workflow runs returned by ListRuns. Empty fields do not filter.
+type RunFilters struct {
+	// Workflow is the file name, e.g. "deploy.yml", or the ID of a workflow.
+	Workflow string
+	Branch   string
+	Event    string
+	// Status is a status such as "in_progress", or a conclusion such as "failure".
+	Status  string
+	Actor   string
+	HeadSHA string
+	// Created is a date range in the search syntax, e.g. ">=2023-01-02".
+	Created string
+}
+
+// ListRuns returns the most recent workflow runs of the repository "OWNER/REPO" that
+// match filters, newest first. At most limit runs are returned, or all of them if limit
+// is zero.
+func ListRuns(client RESTClient, repo string, filters RunFilters, limit int) ([]Run, error) {
+	return ListRunsContext(context.Background(), client, repo, filters, limit)
+}
+
+// ListRunsContext is like ListRuns but aborts the requests when the context is done.
+func ListRunsContext(ctx context.Context, client RESTClient, repo string, filters RunFilters, limit int) ([]Run, error) {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("repos/%s/%s/actions/runs", owner, name)
+	if filters.Workflow != "" {
+		path = fmt.Sprintf("repos/%s/%s/actions/workflows/%s/runs", owner, name, url.PathEscape(filters.Workflow))
+	}
+	query := url.Values{}
+	for param, value := range map[string]string{
+		"branch":   filters.Branch,
+		"event":    filters.Event,
+		"status":   filters.Status,
+		"actor":    filters.Actor,
+		"head_sha": filters.HeadSHA,
+		"created":  filters.Created,
+	} {
+		if value != "" {
+			query.Set(param, value)
+		}
+	}
+	perPage := maxPerPage
+	if limit > 0 && limit < perPage {
+		perPage = limit
+	}
+	query.Set("per_page", strconv.Itoa(perPage))
+
+	var runs []Run
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var resp struct {
+			WorkflowRuns []Run `json:"workflow_runs"`
+		}
+		if err := client.DoWithContext(ctx, "GET", path+"?"+query.Encode(), nil, &resp); err != nil {
+			return nil, err
+		}
+		runs = append(runs, resp.WorkflowRuns...)
+		if limit > 0 && len(runs) >= limit {
+			return runs[:limit], nil
+		}
+		if len(resp.WorkflowRuns) < perPage {
+			return runs, nil
+		}
+	}
+}
+
+// RunUpdate is the state of a watched run and its jobs.
+type RunUpdate struct {
+	Run  Run
+	Jobs []Job
+}
+
+type watchOptions struct {
+	interval    time.Duration
+	maxInterval time.Duration
+	clock       clock.Clock
+}
+
+type watchOption func(*watchOptions)
+
+// WithWatchInterval sets the initial and the longest interval between polls. While the
+// run and its jobs do not change, the interval doubles up to the longest interval, and it
+// is reset when they change. They default to 3 and 30 seconds.
+func WithWatchInterval(initial, longest time.Duration) watchOption {
+	return func(o *watchOptions) {
+		o.interval = initial
+		o.maxInterval = longest
+	}
+}
+
+// WithWatchClock sets the clock that spaces polls. It defaults to the system clock.
+func WithWatchClock(c clock.Clock) watchOption {
+	return func(o *watchOptions) {
+		o.clock = c
+	}
+}
+
+// WatchRun polls a workflow run of the repository "OWNER/REPO" and its jobs until the run
+// completes, sending an update to updates each time the status of the run, a job, or a
+// step changes. It returns the completed run, or the error of a failed request or of ctx.
+// The run can have failed; check its conclusion.
+func WatchRun(ctx context.Context, client RESTClient, repo string, runID int64, updates chan<- RunUpdate, opts ...watchOption) (*Run, error) {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+	o := watchOptions{
+		interval:    defaultWatchInterval,
+		maxInterval: defaultMaxWatchInterval,
+		clock:       clock.Real,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	runPath := fmt.Sprintf("repos/%s/%s/actions/runs/%d", owner, name, runID)
+
+	var last string
+	interval := o.interval
+	for {
+		var update RunUpdate
+		if err := client.DoWithContext(ctx, "GET", runPath, nil, &update.Run); err != nil {
+			return nil, err
+		}
+		update.Jobs, err = runJobs(ctx, client, runPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if state := runState(update); state != last {
+			last = state
+			interval = o.interval
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		} else {
+			interval *= 2
+			if interval > o.maxInterval {
+				interval = o.maxInterval
+			}
+		}
+		if update.Run.Completed() {
+			return &update.Run, nil
+		}
+		select {
+		case <-o.clock.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// runJobs returns the jobs of the run at runPath, following the Link header of the
+// response through every page of jobs.
+func runJobs(ctx context.Context, client RESTClient, runPath string) ([]Job, error) {
+	var jobs []Job
+	path := fmt.Sprintf("%s/jobs?per_page=%d", runPath, maxPerPage)
+	for path != "" {
+		resp, err := client.RequestWithContext(ctx, "GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Jobs []Job `json:"jobs"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, page.Jobs...)
+		path = api.NextLink(resp.Header.Get("Link"))
+	}
+	return jobs, nil
+}
+
+// runState summarizes the statuses of a run, its jobs, and their steps, to detect changes.
+func runState(u RunUpdate) string {
+	state := fmt.Sprintf("%d:%s:%s", u.Run.RunAttempt, u.Run.Status, u.Run.Conclusion)
+	for _, j := range u.Jobs {
+		state += fmt.Sprintf("|%d:%s:%s", j.ID, j.Status, j.Conclusion)
+		for _, s := range j.Steps {
+			state += fmt.Sprintf(",%d:%s:%s", s.Number, s.Status, s.Conclusion)
+		}
+	}
+	return state
+}
@@ -0,0 +1,200 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sequenceClient answers each GET request with the next response for its path, repeating
+// the last one. Responses to paths in links have a Link header to the next page.
+type sequenceClient struct {
+	mu        sync.Mutex
+	responses map[string][]interface{}
+	links     map[string]string
+	gets      []string
+}
+
+func (c *sequenceClient) next(path string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gets = append(c.gets, path)
+	seq := c.responses[path]
+	if len(seq) == 0 {
+		return nil, fmt.Errorf("HTTP 404: Not Found (%s)", path)
+	}
+	r := seq[0]
+	if len(seq) > 1 {
+		c.responses[path] = seq[1:]
+	}
+	return json.Marshal(r)
+}
+
+func (c *sequenceClient) RequestWithContext(ctx context.Context, method string, path string, body io.Reader) (*http.Response, error) {
+	data, err := c.next(path)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	if next, ok := c.links[path]; ok {
+		header.Set("Link", fmt.Sprintf(`<https://api.github.com/%s>; rel="next"`, next))
+	}
+	return &http.Response{StatusCode: 200, Header: header, Body: io.NopCloser(strings.NewReader(string(data)))}, nil
+}
+
+func (c *sequenceClient) DoWithContext(ctx context.Context, method string, path string, body io.Reader, resp interface{}) error {
+	data, err := c.next(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, resp)
+}
+
+func runsPage(ids ...int64) map[string]interface{} {
+	runs := []map[string]interface{}{}
+	for _, id := range ids {
+		runs = append(runs, map[string]interface{}{"id": id, "status": "completed", "conclusion": "success"})
+	}
+	return map[string]interface{}{"total_count": 5, "workflow_runs": runs}
+}
+
+func TestListRuns(t *testing.T) {
+	client := &sequenceClient{responses: map[string][]interface{}{
+		"repos/octo-org/hello-world/actions/workflows/deploy.yml/runs?branch=main&page=1&per_page=3&status=success": {runsPage(5, 4, 3)},
+		"repos/octo-org/hello-world/actions/runs?page=1&per_page=100":                                               {runsPage(5, 4, 3, 2, 1)},
+	}}
+
+	runs, err := ListRuns(client, "octo-org/hello-world", RunFilters{Workflow: "deploy.yml", Branch: "main", Status: "success"}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{5, 4, 3}, runIDs(runs))
+
+	runs, err = ListRuns(client, "octo-org/hello-world", RunFilters{}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{5, 4, 3, 2, 1}, runIDs(runs))
+
+	_, err = ListRuns(client, "hello-world", RunFilters{}, 0)
+	assert.EqualError(t, err, `expected the "OWNER/REPO" format, got "hello-world"`)
+}
+
+func TestListRunsPagination(t *testing.T) {
+	ids := make([]int64, 0, 160)
+	for id := int64(160); id > 0; id-- {
+		ids = append(ids, id)
+	}
+	client := &sequenceClient{responses: map[string][]interface{}{
+		"repos/octo-org/hello-world/actions/runs?event=push&page=1&per_page=100": {runsPage(ids[:100]...)},
+		"repos/octo-org/hello-world/actions/runs?event=push&page=2&per_page=100": {runsPage(ids[100:]...)},
+	}}
+	runs, err := ListRuns(client, "octo-org/hello-world", RunFilters{Event: "push"}, 150)
+	require.NoError(t, err)
+	assert.Equal(t, ids[:150], runIDs(runs))
+	assert.Len(t, client.gets, 2)
+}
+
+func runIDs(runs []Run) []int64 {
+	ids := make([]int64, 0, len(runs))
+	for _, r := range runs {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestWatchRun(t *testing.T) {
+	run := func(status, conclusion string) map[string]interface{} {
+		return map[string]interface{}{"id": 42, "status": status, "conclusion": conclusion, "run_attempt": 1}
+	}
+	jobs := func(status, step string) map[string]interface{} {
+		return map[string]interface{}{"jobs": []map[string]interface{}{{
+			"id": 7, "name": "build", "status": status,
+			"steps": []map[string]interface{}{{"number": 1, "name": "Checkout", "status": step}},
+		}}}
+	}
+	client := &sequenceClient{responses: map[string][]interface{}{
+		"repos/octo-org/hello-world/actions/runs/42": {
+			run("queued", ""), run("in_progress", ""), run("in_progress", ""), run("in_progress", ""), run("completed", "failure"),
+		},
+		"repos/octo-org/hello-world/actions/runs/42/jobs?per_page=100": {
+			jobs("queued", "queued"), jobs("in_progress", "in_progress"), jobs("in_progress", "in_progress"), jobs("in_progress", "in_progress"), jobs("completed", "completed"),
+		},
+	}}
+	fake := clock.NewFake(time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC))
+	updates := make(chan RunUpdate, 10)
+	type result struct {
+		run *Run
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		r, err := WatchRun(context.Background(), client, "octo-org/hello-world", 42, updates,
+			WithWatchInterval(time.Second, 3*time.Second), WithWatchClock(fake))
+		done <- result{r, err}
+	}()
+
+	// The interval doubles while nothing changes and is capped.
+	for _, wait := range []time.Duration{time.Second, time.Second, 2 * time.Second, 3 * time.Second} {
+		fake.BlockUntil(1)
+		fake.Advance(wait)
+	}
+	res := <-done
+	require.NoError(t, res.err)
+	assert.Equal(t, "failure", res.run.Conclusion)
+
+	close(updates)
+	var statuses []string
+	for u := range updates {
+		statuses = append(statuses, u.Run.Status+"/"+u.Jobs[0].Steps[0].Status)
+	}
+	assert.Equal(t, []string{"queued/queued", "in_progress/in_progress", "completed/completed"}, statuses)
+}
+
+func TestWatchRunCanceled(t *testing.T) {
+	client := &sequenceClient{responses: map[string][]interface{}{
+		"repos/octo-org/hello-world/actions/runs/42":                   {map[string]interface{}{"id": 42, "status": "queued"}},
+		"repos/octo-org/hello-world/actions/runs/42/jobs?per_page=100": {map[string]interface{}{"jobs": []interface{}{}}},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan RunUpdate)
+	go func() {
+		<-updates
+		cancel()
+	}()
+	_, err := WatchRun(ctx, client, "octo-org/hello-world", 42, updates, WithWatchClock(clock.NewFake(time.Now())))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRunJobsPagination(t *testing.T) {
+	jobs := func(ids ...int64) map[string]interface{} {
+		page := []map[string]interface{}{}
+		for _, id := range ids {
+			page = append(page, map[string]interface{}{"id": id})
+		}
+		return map[string]interface{}{"total_count": 3, "jobs": page}
+	}
+	client := &sequenceClient{
+		responses: map[string][]interface{}{
+			"repos/octo-org/hello-world/actions/runs/42/jobs?per_page=100":                  {jobs(1, 2)},
+			"https://api.github.com/repos/octo-org/hello-world/actions/runs/42/jobs?page=2": {jobs(3)},
+		},
+		links: map[string]string{
+			"repos/octo-org/hello-world/actions/runs/42/jobs?per_page=100": "repos/octo-org/hello-world/actions/runs/42/jobs?page=2",
+		},
+	}
+	got, err := runJobs(context.Background(), client, "repos/octo-org/hello-world/actions/runs/42")
+	require.NoError(t, err)
+	var ids []int64
+	for _, j := range got {
+		ids = append(ids, j.ID)
+	}
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.Len(t, client.gets, 2)
+}
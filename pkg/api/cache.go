@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"bytes"
 	"crypto/sha256"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,11 +12,14 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
 )
 
 type cache struct {
-	dir string
-	ttl time.Duration
+	dir   string
+	ttl   time.Duration
+	clock clock.Clock
 }
 
 type cacheRoundTripper struct {
@@ -26,9 +28,10 @@ type cacheRoundTripper struct {
 }
 
 type fileStorage struct {
-	dir string
-	ttl time.Duration
-	mu  *sync.RWMutex
+	dir   string
+	ttl   time.Duration
+	clock clock.Clock
+	mu    *sync.RWMutex
 }
 
 type readCloser struct {
@@ -74,9 +77,10 @@ func cacheKey(req *http.Request) (string, error) {
 
 func (c cache) RoundTripper(rt http.RoundTripper) http.RoundTripper {
 	fs := fileStorage{
-		dir: c.dir,
-		ttl: c.ttl,
-		mu:  &sync.RWMutex{},
+		dir:   c.dir,
+		ttl:   c.ttl,
+		clock: c.clock,
+		mu:    &sync.RWMutex{},
 	}
 	return cacheRoundTripper{fs: fs, rt: rt}
 }
@@ -102,14 +106,30 @@ func (crt cacheRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 	}
 
 	key, keyErr := cacheKey(req)
+	var stale *http.Response
+	outReq := req
 	if keyErr == nil {
-		if res, err := crt.fs.read(key); err == nil {
+		if res, fresh, err := crt.fs.read(key); err == nil && fresh {
 			res.Request = req
 			return res, nil
+		} else if err == nil && !isConditionalRequest(req) {
+			// Ask the server whether the expired response is still current, so that an
+			// unchanged resource is not downloaded again and does not count against the
+			// rate limit.
+			if condReq, ok := conditionalRequest(req, res); ok {
+				stale = res
+				outReq = condReq
+			}
 		}
 	}
 
-	res, err := crt.rt.RoundTrip(req)
+	res, err := crt.rt.RoundTrip(outReq)
+	if err == nil && stale != nil && res.StatusCode == http.StatusNotModified {
+		res.Body.Close()
+		_ = crt.fs.touch(key)
+		stale.Request = req
+		return stale, nil
+	}
 	if err == nil && keyErr == nil && isCacheableResponse(res) {
 		_ = crt.fs.store(key, res)
 	}
@@ -120,6 +140,29 @@ func (crt cacheRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 	return res, err
 }
 
+func isConditionalRequest(req *http.Request) bool {
+	return req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != ""
+}
+
+// conditionalRequest returns a copy of req that is only answered with a full response if
+// the resource changed since cached was received, as determined by its ETag or
+// Last-Modified headers.
+func conditionalRequest(req *http.Request, cached *http.Response) (*http.Request, bool) {
+	etag := cached.Header.Get("ETag")
+	lastModified := cached.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return nil, false
+	}
+	condReq := req.Clone(req.Context())
+	if etag != "" {
+		condReq.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		condReq.Header.Set("If-Modified-Since", lastModified)
+	}
+	return condReq, true
+}
+
 // Allow an individual request to override cache options.
 func requestCacheOptions(req *http.Request) (string, time.Duration) {
 	var dur time.Duration
@@ -138,7 +181,8 @@ func (fs *fileStorage) filePath(key string) string {
 	return filepath.Join(fs.dir, key)
 }
 
-func (fs *fileStorage) read(key string) (*http.Response, error) {
+// read returns the cached response for key, and whether it is younger than the TTL.
+func (fs *fileStorage) read(key string) (*http.Response, bool, error) {
 	cacheFile := fs.filePath(key)
 
 	fs.mu.RLock()
@@ -146,28 +190,33 @@ func (fs *fileStorage) read(key string) (*http.Response, error) {
 
 	f, err := os.Open(cacheFile)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer f.Close()
 
 	stat, err := f.Stat()
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	age := time.Since(stat.ModTime())
-	if age > fs.ttl {
-		return nil, errors.New("cache expired")
-	}
+	age := fs.clock.Now().Sub(stat.ModTime())
 
 	body := &bytes.Buffer{}
 	_, err = io.Copy(body, f)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	res, err := http.ReadResponse(bufio.NewReader(body), nil)
-	return res, err
+	return res, age <= fs.ttl, err
+}
+
+// touch marks the cached response for key as fresh again.
+func (fs *fileStorage) touch(key string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	now := fs.clock.Now()
+	return os.Chtimes(fs.filePath(key), now, now)
 }
 
 func (fs *fileStorage) store(key string, res *http.Response) (storeErr error) {
@@ -201,6 +250,11 @@ func (fs *fileStorage) store(key string, res *http.Response) (storeErr error) {
 	if origBody != nil {
 		res.Body = origBody
 	}
+	if storeErr == nil {
+		// The age of entries is measured with the cache clock.
+		now := fs.clock.Now()
+		storeErr = os.Chtimes(cacheFile, now, now)
+	}
 
 	return
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -191,3 +192,145 @@ func TestRequestCacheOptions(t *testing.T) {
 	assert.Equal(t, dir, "some/dir/path")
 	assert.Equal(t, ttl, time.Hour)
 }
+
+func TestCacheResponseExpiry(t *testing.T) {
+	counter := 0
+	fakeHTTP := tripper{
+		roundTrip: func(req *http.Request) (*http.Response, error) {
+			counter += 1
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBufferString(fmt.Sprintf("%d", counter))),
+			}, nil
+		},
+	}
+	fakeClock := clock.NewFake(time.Now())
+
+	httpClient, err := NewHTTPClient(
+		ClientOptions{
+			Host:         "github.com",
+			AuthToken:    "token",
+			Transport:    fakeHTTP,
+			EnableCache:  true,
+			CacheDir:     filepath.Join(t.TempDir(), "goctl-cli-cache"),
+			CacheTTL:     time.Hour,
+			Clock:        fakeClock,
+			LogIgnoreEnv: true,
+		},
+	)
+	assert.NoError(t, err)
+
+	get := func() string {
+		res, err := httpClient.Get("http://example.com/path")
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return string(body)
+	}
+
+	assert.Equal(t, "1", get())
+	fakeClock.Advance(59 * time.Minute)
+	assert.Equal(t, "1", get())
+	fakeClock.Advance(2 * time.Minute)
+	assert.Equal(t, "2", get())
+}
+
+func TestCacheResponseRevalidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        string
+		value         string
+		conditional   string
+		notModified   bool
+		wantBodies    []string
+		wantRequests  int
+		wantCondition []string
+	}{
+		{
+			name:          "unchanged ETag",
+			header:        "ETag",
+			value:         `W/"abc"`,
+			conditional:   "If-None-Match",
+			notModified:   true,
+			wantBodies:    []string{"1", "1", "1"},
+			wantRequests:  2,
+			wantCondition: []string{"", `W/"abc"`},
+		},
+		{
+			name:          "unchanged Last-Modified",
+			header:        "Last-Modified",
+			value:         "Mon, 02 Jan 2023 15:04:05 GMT",
+			conditional:   "If-Modified-Since",
+			notModified:   true,
+			wantBodies:    []string{"1", "1", "1"},
+			wantRequests:  2,
+			wantCondition: []string{"", "Mon, 02 Jan 2023 15:04:05 GMT"},
+		},
+		{
+			name:          "changed ETag",
+			header:        "ETag",
+			value:         `"abc"`,
+			conditional:   "If-None-Match",
+			wantBodies:    []string{"1", "2", "2"},
+			wantRequests:  2,
+			wantCondition: []string{"", `"abc"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conditions []string
+			fakeHTTP := tripper{
+				roundTrip: func(req *http.Request) (*http.Response, error) {
+					conditions = append(conditions, req.Header.Get(tt.conditional))
+					if tt.notModified && len(conditions) > 1 {
+						return &http.Response{
+							StatusCode: 304,
+							Header:     http.Header{tt.header: {tt.value}},
+							Body:       io.NopCloser(bytes.NewBufferString("")),
+						}, nil
+					}
+					return &http.Response{
+						StatusCode: 200,
+						Header:     http.Header{tt.header: {tt.value}},
+						Body:       io.NopCloser(bytes.NewBufferString(fmt.Sprintf("%d", len(conditions)))),
+					}, nil
+				},
+			}
+			fakeClock := clock.NewFake(time.Now())
+
+			httpClient, err := NewHTTPClient(
+				ClientOptions{
+					Host:         "github.com",
+					AuthToken:    "token",
+					Transport:    fakeHTTP,
+					EnableCache:  true,
+					CacheDir:     filepath.Join(t.TempDir(), "goctl-cli-cache"),
+					CacheTTL:     time.Hour,
+					Clock:        fakeClock,
+					LogIgnoreEnv: true,
+				},
+			)
+			assert.NoError(t, err)
+
+			get := func() string {
+				res, err := httpClient.Get("http://example.com/path")
+				assert.NoError(t, err)
+				defer res.Body.Close()
+				assert.Equal(t, 200, res.StatusCode)
+				body, _ := io.ReadAll(res.Body)
+				return string(body)
+			}
+
+			var bodies []string
+			bodies = append(bodies, get())
+			fakeClock.Advance(61 * time.Minute)
+			bodies = append(bodies, get())
+			fakeClock.Advance(59 * time.Minute)
+			bodies = append(bodies, get())
+
+			assert.Equal(t, tt.wantBodies, bodies)
+			assert.Equal(t, tt.wantRequests, len(conditions))
+			assert.Equal(t, tt.wantCondition, conditions)
+		})
+	}
+}
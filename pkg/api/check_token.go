@@ -0,0 +1,23 @@
+package api
+
+import (
+	"context"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/auth"
+)
+
+// CheckToken is like auth.CheckToken but sends the request with a client built by
+// NewHTTPClient for host, so that the unix domain socket, default headers and logging
+// configured for goctl apply.
+func CheckToken(host, token string) (*auth.TokenInfo, error) {
+	return CheckTokenContext(context.Background(), host, token)
+}
+
+// CheckTokenContext is like CheckToken but cancels the request when the context is done.
+func CheckTokenContext(ctx context.Context, host, token string) (*auth.TokenInfo, error) {
+	client, err := NewHTTPClient(ClientOptions{Host: host, AuthToken: token})
+	if err != nil {
+		return nil, err
+	}
+	return auth.CheckTokenContext(ctx, host, token, auth.WithCheckTokenHTTPClient(client))
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestCheckToken(t *testing.T) {
+	stubConfig(t, testConfig())
+	t.Cleanup(gock.Off)
+
+	gock.New("https://api.github.com").
+		Get("/user").
+		MatchHeader("Authorization", "token ghp_123").
+		MatchHeader("User-Agent", "go-goctl").
+		Reply(200).
+		SetHeader("X-Oauth-Scopes", "repo, read:org").
+		JSON(`{"login": "monalisa"}`)
+
+	info, err := CheckToken("github.com", "ghp_123")
+	require.NoError(t, err)
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+	assert.Equal(t, "monalisa", info.Login)
+	assert.Equal(t, []string{"repo", "read:org"}, info.Scopes)
+}
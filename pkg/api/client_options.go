@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/auth"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
 )
 
@@ -25,6 +26,10 @@ type ClientOptions struct {
 	// Default is 24 hours.
 	CacheTTL time.Duration
 
+	// Clock is used to determine whether cached API requests have expired.
+	// Default is the system clock.
+	Clock clock.Clock
+
 	// EnableCache specifies if API requests will be cached or not.
 	// Default is no caching.
 	EnableCache bool
@@ -53,6 +58,23 @@ type ClientOptions struct {
 	// Default is only logging request URLs and response statuses.
 	LogVerboseHTTP bool
 
+	// OnRateLimit is called before a rate limited request waits to be retried, for example
+	// to tell the user why the request is taking longer. Returning an error aborts the
+	// request with that error instead of waiting.
+	// Default is to wait silently.
+	OnRateLimit func(RateLimitWait) error
+
+	// RateLimitMaxWait is the longest time a rate limited request waits to be retried.
+	// Rate limited responses that reset later are returned to the caller. The actual wait
+	// can exceed it by the random jitter of up to 10% that spreads out retries.
+	// Default is 1 minute.
+	RateLimitMaxWait time.Duration
+
+	// RateLimitRetries is the number of times a request is retried after it hits a
+	// primary or secondary rate limit, waiting for the limit to reset before each retry.
+	// Default is no retries.
+	RateLimitRetries int
+
 	// SkipDefaultHeaders disables setting of the default headers.
 	SkipDefaultHeaders bool
 
@@ -68,6 +90,12 @@ type ClientOptions struct {
 	// Default is http.DefaultTransport.
 	Transport http.RoundTripper
 
+	// TokenSource provides the authorization token for each API request, for tokens
+	// that expire or are rotated, such as GitHub App installation tokens. See the
+	// token sources of the auth package. It takes precedence over AuthToken.
+	// Default is to use AuthToken.
+	TokenSource auth.TokenSource
+
 	// UnixDomainSocket specifies the Unix domain socket address by which individual
 	// API requests will be routed. If specifed, this will form the base of the API
 	// request transport chain.
@@ -79,7 +107,7 @@ func optionsNeedResolution(opts ClientOptions) bool {
 	if opts.Host == "" {
 		return true
 	}
-	if opts.AuthToken == "" {
+	if opts.AuthToken == "" && opts.TokenSource == nil {
 		return true
 	}
 	if opts.UnixDomainSocket == "" && opts.Transport == nil {
@@ -93,10 +121,10 @@ func resolveOptions(opts ClientOptions) (ClientOptions, error) {
 	if opts.Host == "" {
 		opts.Host, _ = auth.DefaultHost()
 	}
-	if opts.AuthToken == "" {
+	if opts.AuthToken == "" && opts.TokenSource == nil {
 		opts.AuthToken, _ = auth.TokenForHost(opts.Host)
 		if opts.AuthToken == "" {
-			return ClientOptions{}, fmt.Errorf("authentication token not found for host %s", opts.Host)
+			return ClientOptions{}, fmt.Errorf("%w for host %s", auth.ErrTokenNotFound, opts.Host)
 		}
 	}
 	if opts.UnixDomainSocket == "" && cfg != nil {
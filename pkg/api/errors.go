@@ -2,11 +2,33 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/i18n"
+)
+
+// Sentinel errors that HTTPError and GraphQLError match with [errors.Is], so that callers
+// can handle common failures without inspecting status codes or error types.
+var (
+	// ErrUnauthorized matches responses with the 401 status, for example because the
+	// authentication token is invalid or has expired.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches responses with the 403 status that are not caused by rate
+	// limiting, and GraphQL errors of the FORBIDDEN type.
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound matches responses with the 404 status, and GraphQL errors that are all
+	// of the NOT_FOUND type.
+	ErrNotFound = errors.New("not found")
+	// ErrValidationFailed matches responses with the 422 status.
+	ErrValidationFailed = errors.New("validation failed")
+	// ErrRateLimited matches responses with the 429 status or with an exhausted rate
+	// limit, and GraphQL errors of the RATE_LIMITED type.
+	ErrRateLimited = errors.New("rate limit exceeded")
 )
 
 // HTTPError represents an error response from the GitHub API.
@@ -30,11 +52,33 @@ type HTTPErrorItem struct {
 // Allow HTTPError to satisfy error interface.
 func (err *HTTPError) Error() string {
 	if msgs := strings.SplitN(err.Message, "\n", 2); len(msgs) > 1 {
-		return fmt.Sprintf("HTTP %d: %s (%s)\n%s", err.StatusCode, msgs[0], err.RequestURL, msgs[1])
+		return i18n.Sprintf("HTTP %d: %s (%s)\n%s", err.StatusCode, msgs[0], err.RequestURL, msgs[1])
 	} else if err.Message != "" {
-		return fmt.Sprintf("HTTP %d: %s (%s)", err.StatusCode, err.Message, err.RequestURL)
+		return i18n.Sprintf("HTTP %d: %s (%s)", err.StatusCode, err.Message, err.RequestURL)
+	}
+	return i18n.Sprintf("HTTP %d (%s)", err.StatusCode, err.RequestURL)
+}
+
+// Is reports whether the error matches one of the sentinel errors of this package.
+func (err *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return err.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return err.StatusCode == http.StatusForbidden && !err.rateLimited()
+	case ErrNotFound:
+		return err.StatusCode == http.StatusNotFound
+	case ErrValidationFailed:
+		return err.StatusCode == http.StatusUnprocessableEntity
+	case ErrRateLimited:
+		return err.rateLimited()
 	}
-	return fmt.Sprintf("HTTP %d (%s)", err.StatusCode, err.RequestURL)
+	return false
+}
+
+func (err *HTTPError) rateLimited() bool {
+	return err.StatusCode == http.StatusTooManyRequests ||
+		(err.StatusCode == http.StatusForbidden && err.Headers.Get("X-RateLimit-Remaining") == "0")
 }
 
 // GraphQLError represents an error response from GitHub GraphQL API.
@@ -65,7 +109,37 @@ func (gr *GraphQLError) Error() string {
 		}
 		errorMessages = append(errorMessages, msg)
 	}
-	return fmt.Sprintf("GraphQL: %s", strings.Join(errorMessages, ", "))
+	return i18n.Sprintf("GraphQL: %s", strings.Join(errorMessages, ", "))
+}
+
+// Is reports whether the error matches one of the sentinel errors of this package.
+func (gr *GraphQLError) Is(target error) bool {
+	switch target {
+	case ErrForbidden:
+		return gr.hasType("FORBIDDEN")
+	case ErrNotFound:
+		if len(gr.Errors) == 0 {
+			return false
+		}
+		for _, e := range gr.Errors {
+			if e.Type != "NOT_FOUND" {
+				return false
+			}
+		}
+		return true
+	case ErrRateLimited:
+		return gr.hasType("RATE_LIMITED")
+	}
+	return false
+}
+
+func (gr *GraphQLError) hasType(t string) bool {
+	for _, e := range gr.Errors {
+		if e.Type == t {
+			return true
+		}
+	}
+	return false
 }
 
 // Match determines if the GraphQLError is about a specific type on a specific path.
@@ -158,11 +232,11 @@ func HandleHTTPError(resp *http.Response) error {
 func errorCodeToMessage(code string) string {
 	switch code {
 	case "missing", "missing_field":
-		return "is missing"
+		return i18n.Sprintf("is missing")
 	case "invalid", "unprocessable":
-		return "is invalid"
+		return i18n.Sprintf("is invalid")
 	case "already_exists":
-		return "already exists"
+		return i18n.Sprintf("already exists")
 	default:
 		return code
 	}
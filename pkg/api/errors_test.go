@@ -1,9 +1,15 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"testing"
 
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/i18n"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
 )
 
 func TestGraphQLErrorMatch(t *testing.T) {
@@ -58,3 +64,58 @@ func TestGraphQLErrorMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPErrorIs(t *testing.T) {
+	rateLimited := http.Header{"X-Ratelimit-Remaining": {"0"}}
+	tests := []struct {
+		name   string
+		err    *HTTPError
+		target error
+		wantIs bool
+	}{
+		{name: "unauthorized", err: &HTTPError{StatusCode: 401}, target: ErrUnauthorized, wantIs: true},
+		{name: "forbidden", err: &HTTPError{StatusCode: 403}, target: ErrForbidden, wantIs: true},
+		{name: "forbidden by rate limit", err: &HTTPError{StatusCode: 403, Headers: rateLimited}, target: ErrForbidden, wantIs: false},
+		{name: "primary rate limit", err: &HTTPError{StatusCode: 403, Headers: rateLimited}, target: ErrRateLimited, wantIs: true},
+		{name: "secondary rate limit", err: &HTTPError{StatusCode: 429}, target: ErrRateLimited, wantIs: true},
+		{name: "not found", err: &HTTPError{StatusCode: 404}, target: ErrNotFound, wantIs: true},
+		{name: "validation failed", err: &HTTPError{StatusCode: 422}, target: ErrValidationFailed, wantIs: true},
+		{name: "other status", err: &HTTPError{StatusCode: 500}, target: ErrNotFound, wantIs: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", tt.err)
+			assert.Equal(t, tt.wantIs, errors.Is(err, tt.target))
+		})
+	}
+}
+
+func TestGraphQLErrorIs(t *testing.T) {
+	notFound := &GraphQLError{Errors: []GraphQLErrorItem{{Type: "NOT_FOUND"}, {Type: "NOT_FOUND"}}}
+	assert.ErrorIs(t, notFound, ErrNotFound)
+	assert.False(t, errors.Is(notFound, ErrForbidden))
+
+	mixed := &GraphQLError{Errors: []GraphQLErrorItem{{Type: "NOT_FOUND"}, {Type: "FORBIDDEN"}}}
+	assert.False(t, errors.Is(mixed, ErrNotFound))
+	assert.ErrorIs(t, mixed, ErrForbidden)
+
+	assert.ErrorIs(t, &GraphQLError{Errors: []GraphQLErrorItem{{Type: "RATE_LIMITED"}}}, ErrRateLimited)
+	assert.False(t, errors.Is(&GraphQLError{}, ErrNotFound))
+}
+
+func TestErrorsTranslated(t *testing.T) {
+	t.Cleanup(func() { i18n.SetLanguage(language.English) })
+	es := language.Spanish
+	assert.NoError(t, i18n.SetString(es, "HTTP %d: %s (%s)", "HTTP %d: %s (%s) [es]"))
+	assert.NoError(t, i18n.SetString(es, "GraphQL: %s", "GraphQL (es): %s"))
+	assert.NoError(t, i18n.SetString(es, "is missing", "falta"))
+	u, _ := url.Parse("https://api.github.com/repos/o/r")
+
+	i18n.SetLanguage(es)
+	assert.EqualError(t, &HTTPError{StatusCode: 404, Message: "Not Found", RequestURL: u}, "HTTP 404: Not Found (https://api.github.com/repos/o/r) [es]")
+	assert.EqualError(t, &GraphQLError{Errors: []GraphQLErrorItem{{Message: "boom"}}}, "GraphQL (es): boom")
+	assert.Equal(t, "falta", errorCodeToMessage("missing"))
+
+	i18n.SetLanguage(language.English)
+	assert.EqualError(t, &HTTPError{StatusCode: 404, Message: "Not Found", RequestURL: u}, "HTTP 404: Not Found (https://api.github.com/repos/o/r)")
+}
@@ -11,8 +11,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/khulnasoft-lab/go-goctl/v2/internal/ghinstance"
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/asciisanitizer"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/auth"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/logging"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/middleware"
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/term"
 	"github.com/henvic/httpretty"
 	"github.com/thlib/go-timezone-local/tzlocal"
@@ -73,8 +78,15 @@ func NewHTTPClient(opts ClientOptions) (*http.Client, error) {
 	if opts.EnableCache && opts.CacheTTL == 0 {
 		opts.CacheTTL = time.Hour * 24
 	}
-	c := cache{dir: opts.CacheDir, ttl: opts.CacheTTL}
+	if opts.Clock == nil {
+		opts.Clock = clock.Real
+	}
+	if opts.RateLimitRetries > 0 {
+		transport = newRateLimitRoundTripper(transport, opts)
+	}
+	c := cache{dir: opts.CacheDir, ttl: opts.CacheTTL, clock: opts.Clock}
 	transport = c.RoundTripper(transport)
+	transport = middleware.Transport(transport)
 
 	if opts.Log == nil && !opts.LogIgnoreEnv {
 		goctlDebug := os.Getenv("GOCTL_DEBUG")
@@ -105,6 +117,10 @@ func NewHTTPClient(opts ClientOptions) (*http.Client, error) {
 			return !inspectableMIMEType(h.Get(contentType)), nil
 		})
 		transport = logger.RoundTripper(transport)
+	} else {
+		// Requests are only summarized in the structured log when httpretty is not
+		// already logging them.
+		transport = newLoggingRoundTripper(transport)
 	}
 
 	if opts.Headers == nil {
@@ -113,7 +129,7 @@ func NewHTTPClient(opts ClientOptions) (*http.Client, error) {
 	if !opts.SkipDefaultHeaders {
 		resolveHeaders(opts.Headers)
 	}
-	transport = newHeaderRoundTripper(opts.Host, opts.AuthToken, opts.Headers, transport)
+	transport = newHeaderRoundTripper(opts.Host, opts.AuthToken, opts.TokenSource, opts.Headers, transport)
 
 	return &http.Client{Transport: transport, Timeout: opts.Timeout}, nil
 }
@@ -131,28 +147,22 @@ func isSameDomain(requestHost, domain string) bool {
 }
 
 func isGarage(host string) bool {
-	return strings.EqualFold(host, "garage.github.com")
+	return ghinstance.IsGarage(host)
 }
 
 func isEnterprise(host string) bool {
-	return host != github && host != localhost
+	return ghinstance.IsEnterprise(host)
 }
 
 func normalizeHostname(hostname string) string {
-	hostname = strings.ToLower(hostname)
-	if strings.HasSuffix(hostname, "."+github) {
-		return github
-	}
-	if strings.HasSuffix(hostname, "."+localhost) {
-		return localhost
-	}
-	return hostname
+	return ghinstance.NormalizeHostname(hostname)
 }
 
 type headerRoundTripper struct {
-	headers map[string]string
-	host    string
-	rt      http.RoundTripper
+	headers     map[string]string
+	host        string
+	tokenSource auth.TokenSource
+	rt          http.RoundTripper
 }
 
 func resolveHeaders(headers map[string]string) {
@@ -186,14 +196,16 @@ func resolveHeaders(headers map[string]string) {
 	}
 }
 
-func newHeaderRoundTripper(host string, authToken string, headers map[string]string, rt http.RoundTripper) http.RoundTripper {
-	if _, ok := headers[authorization]; !ok && authToken != "" {
-		headers[authorization] = fmt.Sprintf("token %s", authToken)
+func newHeaderRoundTripper(host string, authToken string, tokenSource auth.TokenSource, headers map[string]string, rt http.RoundTripper) http.RoundTripper {
+	if _, ok := headers[authorization]; ok {
+		tokenSource = nil
+	} else if tokenSource == nil && authToken != "" {
+		tokenSource = auth.StaticTokenSource(authToken)
 	}
-	if len(headers) == 0 {
+	if len(headers) == 0 && tokenSource == nil {
 		return rt
 	}
-	return headerRoundTripper{host: host, headers: headers, rt: rt}
+	return headerRoundTripper{host: host, headers: headers, tokenSource: tokenSource, rt: rt}
 }
 
 func (hrt headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -211,6 +223,15 @@ func (hrt headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, erro
 		}
 	}
 
+	// Tokens are only sent to the same domain, like authorization headers.
+	if hrt.tokenSource != nil && req.Header.Get(authorization) == "" && isSameDomain(req.URL.Hostname(), hrt.host) {
+		token, err := hrt.tokenSource.Token(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(authorization, fmt.Sprintf("token %s", token))
+	}
+
 	return hrt.rt.RoundTrip(req)
 }
 
@@ -226,6 +247,26 @@ func newUnixDomainSocketRoundTripper(socketPath string) http.RoundTripper {
 	}
 }
 
+type loggingRoundTripper struct {
+	rt http.RoundTripper
+}
+
+func newLoggingRoundTripper(rt http.RoundTripper) http.RoundTripper {
+	return loggingRoundTripper{rt: rt}
+}
+
+func (lrt loggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := lrt.rt.RoundTrip(req)
+	logger := logging.Logger().With("method", req.Method, "url", req.URL.Redacted(), "duration", time.Since(start))
+	if err != nil {
+		logger.DebugContext(req.Context(), "API request failed", "error", err)
+		return resp, err
+	}
+	logger.DebugContext(req.Context(), "API request", "status", resp.StatusCode)
+	return resp, err
+}
+
 type sanitizerRoundTripper struct {
 	rt http.RoundTripper
 }
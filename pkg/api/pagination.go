@@ -0,0 +1,34 @@
+package api
+
+import "strings"
+
+// NextLink returns the target of the link with the "next" relation in a Link header, such as
+// `<https://api.github.com/hooks/1/deliveries?cursor=b>; rel="next"`, or an empty string.
+// Paginated REST API responses link to their next page this way, and the target can be
+// passed to RESTClient as the path of the next request.
+func NextLink(header string) string {
+	for {
+		start := strings.IndexByte(header, '<')
+		if start < 0 {
+			return ""
+		}
+		end := strings.IndexByte(header[start:], '>')
+		if end < 0 {
+			return ""
+		}
+		target := header[start+1 : start+end]
+		var params string
+		params, header, _ = strings.Cut(header[start+end+1:], ",")
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(param, "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+				continue
+			}
+			for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+				if strings.EqualFold(rel, "next") {
+					return target
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: ""},
+		{header: `<https://api.github.com/hooks/1/deliveries?cursor=b>; rel="next"`, want: "https://api.github.com/hooks/1/deliveries?cursor=b"},
+		{header: `<https://a.test/?page=1>; rel="prev", <https://a.test/?page=3>; rel="next"`, want: "https://a.test/?page=3"},
+		{header: `<https://a.test/?q=a,b>; rel="next last"`, want: "https://a.test/?q=a,b"},
+		{header: `<https://a.test/?page=1>; rel="prev"`, want: ""},
+		{header: `<https://a.test/?page=1; rel="next"`, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, NextLink(tt.header))
+		})
+	}
+}
+
+func FuzzNextLink(f *testing.F) {
+	f.Add(`<https://api.github.com/hooks/1/deliveries?cursor=b>; rel="next"`)
+	f.Add(`<https://a.test/?page=1>; rel="prev", <https://a.test/?page=3>; rel="next"`)
+	f.Add(`<>; rel=next,`)
+	f.Fuzz(func(t *testing.T, header string) {
+		if got := NextLink(header); got != "" {
+			assert.Contains(t, header, "<"+got+">")
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/logging"
+)
+
+const (
+	retryAfter         = "Retry-After"
+	rateLimitRemaining = "X-RateLimit-Remaining"
+	rateLimitReset     = "X-RateLimit-Reset"
+)
+
+// RateLimitWait describes a rate limited request that is about to be retried once the
+// rate limit resets.
+type RateLimitWait struct {
+	// Request is the request that was rate limited.
+	Request *http.Request
+	// Response is the rate limited response. Its body must not be read.
+	Response *http.Response
+	// Attempt is the number of the upcoming retry, starting at 1.
+	Attempt int
+	// Delay is how long the request will wait before it is retried.
+	Delay time.Duration
+}
+
+type rateLimitRoundTripper struct {
+	clock   clock.Clock
+	jitter  func(time.Duration) time.Duration
+	maxWait time.Duration
+	onWait  func(RateLimitWait) error
+	retries int
+	rt      http.RoundTripper
+}
+
+func newRateLimitRoundTripper(rt http.RoundTripper, opts ClientOptions) http.RoundTripper {
+	maxWait := opts.RateLimitMaxWait
+	if maxWait == 0 {
+		maxWait = time.Minute
+	}
+	return rateLimitRoundTripper{
+		clock:   opts.Clock,
+		jitter:  jitter,
+		maxWait: maxWait,
+		onWait:  opts.OnRateLimit,
+		retries: opts.RateLimitRetries,
+		rt:      rt,
+	}
+}
+
+// RoundTrip sends the request and, while the response shows that a primary or secondary
+// rate limit was hit, waits for the limit to reset and sends the request again. The rate
+// limited response is returned when the retries are exhausted, when the wait would exceed
+// the maximum, or when the request body cannot be sent again.
+func (rrt rateLimitRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := rrt.rt.RoundTrip(req)
+		if err != nil || attempt > rrt.retries || !isRateLimited(resp) {
+			return resp, err
+		}
+		delay, ok := rrt.delay(resp, attempt)
+		if !ok || delay > rrt.maxWait || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		// The jitter is added after the comparison so that a wait of exactly the maximum,
+		// such as the first backoff from a secondary rate limit, is still retried.
+		delay = rrt.jitter(delay)
+		if rrt.onWait != nil {
+			if err := rrt.onWait(RateLimitWait{Request: req, Response: resp, Attempt: attempt, Delay: delay}); err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		logging.Logger().DebugContext(req.Context(), "waiting for rate limit to reset",
+			"url", req.URL.Redacted(), "attempt", attempt, "delay", delay)
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-rrt.clock.After(delay):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// delay returns how long to wait before retrying a rate limited response, before jitter
+// is added. The
+// Retry-After header takes precedence, then the reset time of an exhausted primary rate
+// limit, and otherwise the wait doubles with every attempt starting at one minute, as
+// recommended for secondary rate limits.
+func (rrt rateLimitRoundTripper) delay(resp *http.Response, attempt int) (time.Duration, bool) {
+	if v := resp.Header.Get(retryAfter); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return nonNegative(t.Sub(rrt.clock.Now())), true
+		}
+		return 0, false
+	}
+	if resp.Header.Get(rateLimitRemaining) == "0" {
+		reset, err := strconv.ParseInt(resp.Header.Get(rateLimitReset), 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return nonNegative(time.Unix(reset, 0).Sub(rrt.clock.Now())), true
+	}
+	return time.Minute << (attempt - 1), true
+}
+
+// isRateLimited reports whether resp was rejected because of a primary or secondary
+// rate limit. Secondary rate limits are only distinguishable from other 403 responses
+// by their message, so the body of 403 responses without rate limit headers is read
+// and then restored.
+func isRateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+	default:
+		return false
+	}
+	if resp.Header.Get(retryAfter) != "" || resp.Header.Get(rateLimitRemaining) == "0" {
+		return true
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(bytes.NewReader(body), resp.Body),
+		Closer: resp.Body,
+	}
+	return err == nil && strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+// jitter adds up to 10% to d so that clients that were limited at the same time do not
+// retry at the same time.
+func jitter(d time.Duration) time.Duration {
+	return d + time.Duration(rand.Int63n(int64(d)/10+1))
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitRecorder is a clock that records waits and returns from them immediately.
+type waitRecorder struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *waitRecorder) Now() time.Time {
+	return c.now
+}
+
+func (c *waitRecorder) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now.Add(d)
+	return ch
+}
+
+type stubResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+func TestRateLimitRoundTripper(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name       string
+		responses  []stubResponse
+		retries    int
+		maxWait    time.Duration
+		body       string
+		wantStatus int
+		wantBody   string
+		wantWaits  []time.Duration
+	}{
+		{
+			name:       "success is not retried",
+			responses:  []stubResponse{{status: 200, body: "ok"}},
+			retries:    3,
+			wantStatus: 200,
+			wantBody:   "ok",
+		},
+		{
+			name: "retry after seconds",
+			responses: []stubResponse{
+				{status: 429, headers: map[string]string{"Retry-After": "30"}},
+				{status: 200, body: "ok"},
+			},
+			retries:    3,
+			wantStatus: 200,
+			wantBody:   "ok",
+			wantWaits:  []time.Duration{30 * time.Second},
+		},
+		{
+			name: "retry after date",
+			responses: []stubResponse{
+				{status: 403, headers: map[string]string{"Retry-After": now.Add(10 * time.Second).UTC().Format(http.TimeFormat)}},
+				{status: 200, body: "ok"},
+			},
+			retries:    1,
+			wantStatus: 200,
+			wantBody:   "ok",
+			wantWaits:  []time.Duration{10 * time.Second},
+		},
+		{
+			name: "primary rate limit reset",
+			responses: []stubResponse{
+				{status: 403, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000045"}},
+				{status: 200, body: "ok"},
+			},
+			retries:    1,
+			wantStatus: 200,
+			wantBody:   "ok",
+			wantWaits:  []time.Duration{45 * time.Second},
+		},
+		{
+			name: "secondary rate limit backs off exponentially",
+			responses: []stubResponse{
+				{status: 403, body: `{"message":"You have exceeded a secondary rate limit."}`},
+				{status: 403, body: `{"message":"You have exceeded a secondary rate limit."}`},
+				{status: 201, body: "created"},
+			},
+			retries:    2,
+			maxWait:    5 * time.Minute,
+			body:       "payload",
+			wantStatus: 201,
+			wantBody:   "created",
+			wantWaits:  []time.Duration{time.Minute, 2 * time.Minute},
+		},
+		{
+			name: "retries exhausted",
+			responses: []stubResponse{
+				{status: 429, headers: map[string]string{"Retry-After": "1"}},
+				{status: 429, headers: map[string]string{"Retry-After": "1"}, body: "slow down"},
+			},
+			retries:    1,
+			wantStatus: 429,
+			wantBody:   "slow down",
+			wantWaits:  []time.Duration{time.Second},
+		},
+		{
+			name: "wait exceeds maximum",
+			responses: []stubResponse{
+				{status: 403, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700003600"}, body: "limit"},
+			},
+			retries:    3,
+			wantStatus: 403,
+			wantBody:   "limit",
+		},
+		{
+			name:       "forbidden is not retried",
+			responses:  []stubResponse{{status: 403, body: `{"message":"Resource not accessible by integration"}`}},
+			retries:    3,
+			wantStatus: 403,
+			wantBody:   `{"message":"Resource not accessible by integration"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := &waitRecorder{now: now}
+			var bodies []string
+			rt := newRateLimitRoundTripper(tripper{
+				roundTrip: func(req *http.Request) (*http.Response, error) {
+					if req.Body != nil {
+						b, _ := io.ReadAll(req.Body)
+						bodies = append(bodies, string(b))
+					}
+					stub := tt.responses[0]
+					tt.responses = tt.responses[1:]
+					res := &http.Response{StatusCode: stub.status, Header: http.Header{}, Request: req, Body: io.NopCloser(strings.NewReader(stub.body))}
+					for k, v := range stub.headers {
+						res.Header.Set(k, v)
+					}
+					return res, nil
+				},
+			}, ClientOptions{Clock: clk, RateLimitRetries: tt.retries, RateLimitMaxWait: tt.maxWait}).(rateLimitRoundTripper)
+			rt.jitter = func(d time.Duration) time.Duration { return d }
+
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req, err := http.NewRequest("POST", "https://api.github.com/repos/OWNER/REPO/issues", body)
+			assert.NoError(t, err)
+			res, err := rt.RoundTrip(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, res.StatusCode)
+			b, _ := io.ReadAll(res.Body)
+			assert.Equal(t, tt.wantBody, string(b))
+			assert.Equal(t, tt.wantWaits, clk.waits)
+			assert.Empty(t, tt.responses)
+			if tt.body != "" {
+				for _, b := range bodies {
+					assert.Equal(t, tt.body, b)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitRoundTripperDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		response stubResponse
+	}{
+		{
+			name:     "secondary rate limit",
+			response: stubResponse{status: 403, body: `{"message":"You have exceeded a secondary rate limit."}`},
+		},
+		{
+			name:     "retry after a minute",
+			response: stubResponse{status: 429, headers: map[string]string{"Retry-After": "60"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := &waitRecorder{now: time.Unix(1700000000, 0)}
+			responses := []stubResponse{tt.response, {status: 200, body: "ok"}}
+			rt := newRateLimitRoundTripper(tripper{
+				roundTrip: func(req *http.Request) (*http.Response, error) {
+					stub := responses[0]
+					responses = responses[1:]
+					res := &http.Response{StatusCode: stub.status, Header: http.Header{}, Request: req, Body: io.NopCloser(strings.NewReader(stub.body))}
+					for k, v := range stub.headers {
+						res.Header.Set(k, v)
+					}
+					return res, nil
+				},
+			}, ClientOptions{Clock: clk, RateLimitRetries: 1})
+			req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
+			assert.NoError(t, err)
+			res, err := rt.RoundTrip(req)
+			assert.NoError(t, err)
+			assert.Equal(t, 200, res.StatusCode)
+			if assert.Len(t, clk.waits, 1) {
+				assert.GreaterOrEqual(t, clk.waits[0], time.Minute)
+				assert.LessOrEqual(t, clk.waits[0], time.Minute+6*time.Second)
+			}
+		})
+	}
+}
+
+func TestRateLimitRoundTripperHook(t *testing.T) {
+	errAborted := errors.New("aborted")
+	var waits []RateLimitWait
+	attempts := 0
+	client, err := NewHTTPClient(ClientOptions{
+		Host:             "github.com",
+		AuthToken:        "abc123",
+		Clock:            &waitRecorder{},
+		RateLimitRetries: 3,
+		OnRateLimit: func(w RateLimitWait) error {
+			waits = append(waits, w)
+			if w.Attempt == 2 {
+				return errAborted
+			}
+			return nil
+		},
+		Transport: tripper{
+			roundTrip: func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return &http.Response{
+					StatusCode: 429,
+					Header:     http.Header{"Retry-After": {"5"}},
+					Request:    req,
+					Body:       io.NopCloser(strings.NewReader("")),
+				}, nil
+			},
+		},
+	})
+	assert.NoError(t, err)
+	_, err = client.Get("https://api.github.com/user")
+	assert.ErrorIs(t, err, errAborted)
+	assert.Equal(t, 2, attempts)
+	assert.Len(t, waits, 2)
+	assert.Equal(t, 1, waits[0].Attempt)
+	assert.GreaterOrEqual(t, waits[0].Delay, 5*time.Second)
+	assert.Less(t, waits[0].Delay, 6*time.Second)
+	assert.Equal(t, "/user", waits[0].Request.URL.Path)
+}
+
+func TestRateLimitRoundTripperContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rt := newRateLimitRoundTripper(tripper{
+		roundTrip: func(req *http.Request) (*http.Response, error) {
+			cancel()
+			return &http.Response{StatusCode: 429, Header: http.Header{"Retry-After": {"5"}}, Body: io.NopCloser(strings.NewReader(""))}, nil
+		},
+	}, ClientOptions{Clock: &blockingClock{}, RateLimitRetries: 1})
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user", nil)
+	assert.NoError(t, err)
+	_, err = rt.RoundTrip(req)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+type blockingClock struct{}
+
+func (blockingClock) Now() time.Time { return time.Time{} }
+
+func (blockingClock) After(time.Duration) <-chan time.Time { return nil }
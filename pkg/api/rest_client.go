@@ -3,10 +3,10 @@ package api
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
-	"strings"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/internal/ghinstance"
 )
 
 // RESTClient wraps methods for the different types of
@@ -49,7 +49,7 @@ func NewRESTClient(opts ClientOptions) (*RESTClient, error) {
 // The response is returned rather than being populated
 // into a response argument.
 func (c *RESTClient) RequestWithContext(ctx context.Context, method string, path string, body io.Reader) (*http.Response, error) {
-	url := restURL(c.host, path)
+	url := RESTURL(c.host, path)
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
@@ -78,7 +78,7 @@ func (c *RESTClient) Request(method string, path string, body io.Reader) (*http.
 // specified path with the specified body.
 // The response is populated into the response argument.
 func (c *RESTClient) DoWithContext(ctx context.Context, method string, path string, body io.Reader, response interface{}) error {
-	url := restURL(c.host, path)
+	url := RESTURL(c.host, path)
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return err
@@ -148,23 +148,12 @@ func (c *RESTClient) Put(path string, body io.Reader, resp interface{}) error {
 	return c.Do(http.MethodPut, path, body, resp)
 }
 
-func restURL(hostname string, pathOrURL string) string {
-	if strings.HasPrefix(pathOrURL, "https://") || strings.HasPrefix(pathOrURL, "http://") {
-		return pathOrURL
-	}
-	return restPrefix(hostname) + pathOrURL
+// RESTURL returns the REST API URL of pathOrURL on hostname, as requested by RESTClient.
+// Absolute URLs are returned unchanged.
+func RESTURL(hostname string, pathOrURL string) string {
+	return ghinstance.RESTURL(hostname, pathOrURL)
 }
 
 func restPrefix(hostname string) string {
-	if isGarage(hostname) {
-		return fmt.Sprintf("https://%s/api/v3/", hostname)
-	}
-	hostname = normalizeHostname(hostname)
-	if isEnterprise(hostname) {
-		return fmt.Sprintf("https://%s/api/v3/", hostname)
-	}
-	if strings.EqualFold(hostname, localhost) {
-		return fmt.Sprintf("http://api.%s/", hostname)
-	}
-	return fmt.Sprintf("https://api.%s/", hostname)
+	return ghinstance.RESTPrefix(hostname)
 }
@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/internal/ghinstance"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
+)
+
+const (
+	goctlAppID             = "GOCTL_APP_ID"
+	goctlAppInstallationID = "GOCTL_APP_INSTALLATION_ID"
+	goctlAppPrivateKey     = "GOCTL_APP_PRIVATE_KEY"
+)
+
+// tokenRefreshMargin is how long before its expiry an installation token is replaced, so
+// that it does not expire during a request.
+const tokenRefreshMargin = 5 * time.Minute
+
+// AppAuth authenticates as an installation of a GitHub App. It signs JSON Web Tokens with
+// the private key of the app and exchanges them for installation tokens, which are cached
+// and replaced shortly before they expire. It is safe for concurrent use.
+type AppAuth struct {
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	host           string
+	client         *http.Client
+	clock          clock.Clock
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+type appAuthOption func(*AppAuth)
+
+// WithAppHost sets the host of the GitHub App. It defaults to github.com.
+func WithAppHost(host string) appAuthOption {
+	return func(a *AppAuth) {
+		a.host = host
+	}
+}
+
+// WithAppHTTPClient sets the HTTP client used to request installation tokens. It defaults
+// to http.DefaultClient.
+func WithAppHTTPClient(client *http.Client) appAuthOption {
+	return func(a *AppAuth) {
+		a.client = client
+	}
+}
+
+// WithAppClock sets the clock used to sign tokens and to determine when they expire. It
+// is meant for tests.
+func WithAppClock(c clock.Clock) appAuthOption {
+	return func(a *AppAuth) {
+		a.clock = c
+	}
+}
+
+// NewAppAuth returns an AppAuth for the installation of the app with the given ID. The
+// private key of the app must be PEM encoded, as downloaded from the app settings.
+func NewAppAuth(appID, installationID int64, privateKey []byte, opts ...appAuthOption) (*AppAuth, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	a := &AppAuth{
+		appID:          appID,
+		installationID: installationID,
+		key:            key,
+		host:           github,
+		client:         http.DefaultClient,
+		clock:          clock.Real,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a, nil
+}
+
+// NewAppAuthFromEnv returns an AppAuth configured by the GOCTL_APP_ID,
+// GOCTL_APP_INSTALLATION_ID, and GOCTL_APP_PRIVATE_KEY environment variables. The private
+// key is either PEM encoded or the path to a PEM file.
+func NewAppAuthFromEnv(opts ...appAuthOption) (*AppAuth, error) {
+	appID, err := strconv.ParseInt(os.Getenv(goctlAppID), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", goctlAppID, err)
+	}
+	installationID, err := strconv.ParseInt(os.Getenv(goctlAppInstallationID), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", goctlAppInstallationID, err)
+	}
+	key := []byte(os.Getenv(goctlAppPrivateKey))
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%s is not set", goctlAppPrivateKey)
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(key), []byte("-----BEGIN")) {
+		if key, err = os.ReadFile(string(key)); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", goctlAppPrivateKey, err)
+		}
+	}
+	return NewAppAuth(appID, installationID, key, opts...)
+}
+
+// JWT returns a JSON Web Token that authenticates as the app itself, for the endpoints
+// that manage the app and its installations. It is valid for nine minutes.
+func (a *AppAuth) JWT() (string, error) {
+	now := a.clock.Now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		// Allow for clock drift between the machine and GitHub.
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+	signed := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Token returns an installation token, requesting a new one if there is none or if the
+// cached one is about to expire.
+func (a *AppAuth) Token(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != "" && a.clock.Now().Add(tokenRefreshMargin).Before(a.expiresAt) {
+		return a.token, nil
+	}
+	jwt, err := a.JWT()
+	if err != nil {
+		return "", err
+	}
+	path := fmt.Sprintf("app/installations/%d/access_tokens", a.installationID)
+	req, err := http.NewRequestWithContext(ctx, "POST", ghinstance.RESTURL(a.host, path), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	res, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create installation token: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		var body struct{ Message string }
+		_ = json.NewDecoder(io.LimitReader(res.Body, 64*1024)).Decode(&body)
+		return "", fmt.Errorf("failed to create installation token: HTTP %d: %s", res.StatusCode, body.Message)
+	}
+	var body struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to create installation token: %w", err)
+	}
+	a.token = body.Token
+	a.expiresAt = body.ExpiresAt
+	return a.token, nil
+}
+
+// parsePrivateKey decodes a PEM encoded RSA private key in the PKCS #1 format used by
+// GitHub, or in the PKCS #8 format.
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+		return nil, errors.New("invalid private key: no PEM encoded private key found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid private key: not an RSA key")
+	}
+	return rsaKey, nil
+}
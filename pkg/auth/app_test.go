@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAppKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return key, pemKey
+}
+
+func TestAppAuthJWT(t *testing.T) {
+	key, pemKey := testAppKey(t)
+	now := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	a, err := NewAppAuth(42, 7, pemKey, WithAppClock(clock.NewFake(now)))
+	assert.NoError(t, err)
+
+	jwt, err := a.JWT()
+	assert.NoError(t, err)
+	parts := strings.Split(jwt, ".")
+	assert.Len(t, parts, 3)
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	assert.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"alg":"RS256","typ":"JWT"}`, string(header))
+	claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	assert.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{"iat":%d,"exp":%d,"iss":"42"}`, now.Unix()-60, now.Unix()+540), string(claims))
+}
+
+func TestAppAuthToken(t *testing.T) {
+	_, pemKey := testAppKey(t)
+	var requests []string
+	issued := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, fmt.Sprintf("%s %s%s", r.Method, r.Header.Get("X-Original-Host"), r.URL.Path))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "))
+		issued++
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"token":      fmt.Sprintf("ghs_%d", issued),
+			"expires_at": "2023-01-02T16:04:05Z",
+		})
+	}))
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL)
+
+	fake := clock.NewFake(time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC))
+	a, err := NewAppAuth(42, 7, pemKey,
+		WithAppClock(fake),
+		WithAppHTTPClient(&http.Client{Transport: redirectTransport{target: target}}))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	token, err := a.Token(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "ghs_1", token)
+
+	fake.Advance(50 * time.Minute)
+	token, err = a.Token(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "ghs_1", token)
+
+	fake.Advance(6 * time.Minute)
+	token, err = a.Token(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "ghs_2", token)
+
+	assert.Equal(t, []string{
+		"POST api.github.com/app/installations/7/access_tokens",
+		"POST api.github.com/app/installations/7/access_tokens",
+	}, requests)
+}
+
+func TestAppAuthTokenError(t *testing.T) {
+	_, pemKey := testAppKey(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer srv.Close()
+	target, _ := url.Parse(srv.URL)
+
+	a, err := NewAppAuth(42, 7, pemKey,
+		WithAppHost("ghe.io"),
+		WithAppHTTPClient(&http.Client{Transport: redirectTransport{target: target}}))
+	assert.NoError(t, err)
+	_, err = a.Token(context.Background())
+	assert.EqualError(t, err, "failed to create installation token: HTTP 404: Not Found")
+}
+
+func TestNewAppAuthFromEnv(t *testing.T) {
+	_, pemKey := testAppKey(t)
+	keyPath := filepath.Join(t.TempDir(), "app.pem")
+	assert.NoError(t, os.WriteFile(keyPath, pemKey, 0600))
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name: "PEM encoded key",
+			env:  map[string]string{goctlAppID: "42", goctlAppInstallationID: "7", goctlAppPrivateKey: string(pemKey)},
+		},
+		{
+			name: "key path",
+			env:  map[string]string{goctlAppID: "42", goctlAppInstallationID: "7", goctlAppPrivateKey: keyPath},
+		},
+		{
+			name:    "missing app ID",
+			env:     map[string]string{goctlAppInstallationID: "7", goctlAppPrivateKey: keyPath},
+			wantErr: `invalid GOCTL_APP_ID: strconv.ParseInt: parsing "": invalid syntax`,
+		},
+		{
+			name:    "missing key",
+			env:     map[string]string{goctlAppID: "42", goctlAppInstallationID: "7"},
+			wantErr: "GOCTL_APP_PRIVATE_KEY is not set",
+		},
+		{
+			name:    "invalid key",
+			env:     map[string]string{goctlAppID: "42", goctlAppInstallationID: "7", goctlAppPrivateKey: "-----BEGIN nonsense"},
+			wantErr: "invalid private key: no PEM encoded private key found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{goctlAppID, goctlAppInstallationID, goctlAppPrivateKey} {
+				t.Setenv(name, tt.env[name])
+			}
+			a, err := NewAppAuthFromEnv()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int64(42), a.appID)
+			assert.Equal(t, int64(7), a.installationID)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/redact"
+)
+
+// TokenCandidate is a token for a host along with where it was found.
+type TokenCandidate struct {
+	Token string
+	// Source is where the token was found, with the same values as the source returned
+	// by TokenForHost: the name of an environment variable, "oauth_token" for the config
+	// file, "goctl" for the system keyring, or the source of a WithTokenForHost override.
+	Source string
+	// Precedence ranks the candidates from 1, the token TokenForHost returns. Candidates
+	// with a higher precedence are shadowed by those before them.
+	Precedence int
+}
+
+// TokenSources returns every token for host that TokenForHost could return, in order of
+// precedence, to diagnose which of several configured tokens is used. Like TokenForHost,
+// it shells out to "goctl auth token" for the token in the system keyring.
+//
+// The tokens are secrets. Print their sources, or mask the tokens with Redact.
+func TokenSources(host string) []TokenCandidate {
+	return TokenSourcesContext(context.Background(), host)
+}
+
+// TokenSourcesContext is like TokenSources but kills the "goctl auth token" process if
+// the context is done before it exits.
+func TokenSourcesContext(ctx context.Context, host string) []TokenCandidate {
+	cfg, _ := config.Read(nil)
+	return tokenSources(ctx, os.Getenv, cfg, goctlPath(), host)
+}
+
+func tokenSources(ctx context.Context, getenv func(string) string, cfg *config.Config, goctlExe, host string) []TokenCandidate {
+	var candidates []TokenCandidate
+	if token, source, ok := overriddenToken(host); ok {
+		// An override hides every other token.
+		if token != "" {
+			candidates = append(candidates, TokenCandidate{Token: token, Source: source})
+		}
+	} else {
+		candidates = tokenCandidates(getenv, cfg, host)
+		if goctlExe != "" {
+			if token, source := tokenFromGh(ctx, goctlExe, host); token != "" {
+				candidates = append(candidates, TokenCandidate{Token: token, Source: source})
+			}
+		}
+	}
+	for i := range candidates {
+		candidates[i].Precedence = i + 1
+	}
+	return candidates
+}
+
+// Redact returns a [redact.Writer] writing to w that masks every token returned by
+// TokenSources for the known hosts and the default host, as well as the tokens in all
+// token environment variables, so that diagnostic output can be shared without leaking
+// credentials. Call Flush on the writer once done.
+func Redact(w io.Writer) *redact.Writer {
+	cfg, _ := config.Read(nil)
+	return redactTokens(context.Background(), w, os.Environ(), cfg, goctlPath())
+}
+
+func redactTokens(ctx context.Context, w io.Writer, environ []string, cfg *config.Config, goctlExe string) *redact.Writer {
+	rw := redact.NewWriter(w)
+	env := map[string]string{}
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		env[name] = value
+		if strings.HasPrefix(strings.ToUpper(name), goctlTokenPrefix) {
+			rw.AddSecret(value)
+		}
+	}
+	getenv := func(name string) string { return env[name] }
+	for _, name := range []string{goctlToken, githubToken, goctlEnterpriseToken, githubEnterpriseToken} {
+		rw.AddSecret(getenv(name))
+	}
+
+	host, _ := defaultHost(getenv, cfg)
+	hosts := append([]string{host}, knownHosts(getenv, cfg)...)
+	seen := map[string]bool{}
+	for _, host := range hosts {
+		host = normalizeHostname(host)
+		if seen[host] {
+			continue
+		}
+		seen[host] = true
+		for _, c := range tokenSources(ctx, getenv, cfg, goctlExe, host) {
+			rw.AddSecret(c.Token)
+		}
+	}
+	return rw
+}
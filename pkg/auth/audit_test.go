@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenSources(t *testing.T) {
+	tests := []struct {
+		name   string
+		host   string
+		env    map[string]string
+		config *config.Config
+		want   []TokenCandidate
+	}{
+		{
+			name: "github.com",
+			host: "github.com",
+			env: map[string]string{
+				"GOCTL_TOKEN_github_com": "scoped",
+				"GOCTL_TOKEN":            "goctl",
+				"GITHUB_TOKEN":           "github",
+				"GOCTL_ENTERPRISE_TOKEN": "enterprise",
+			},
+			config: testHostsConfig(),
+			want: []TokenCandidate{
+				{Token: "scoped", Source: "GOCTL_TOKEN_github_com", Precedence: 1},
+				{Token: "goctl", Source: "GOCTL_TOKEN", Precedence: 2},
+				{Token: "github", Source: "GITHUB_TOKEN", Precedence: 3},
+				{Token: "xxxxxxxxxxxxxxxxxxxx", Source: "oauth_token", Precedence: 4},
+			},
+		},
+		{
+			name: "enterprise host with config",
+			host: "enterprise.com",
+			env: map[string]string{
+				"GOCTL_TOKEN":             "goctl",
+				"GOCTL_ENTERPRISE_TOKEN":  "enterprise",
+				"GITHUB_ENTERPRISE_TOKEN": "github_enterprise",
+			},
+			config: testHostsConfig(),
+			want: []TokenCandidate{
+				{Token: "enterprise", Source: "GOCTL_ENTERPRISE_TOKEN", Precedence: 1},
+				{Token: "github_enterprise", Source: "GITHUB_ENTERPRISE_TOKEN", Precedence: 2},
+				{Token: "yyyyyyyyyyyyyyyyyyyy", Source: "oauth_token", Precedence: 3},
+			},
+		},
+		{
+			name: "enterprise host without config",
+			host: "enterprise.com",
+			env: map[string]string{
+				"GOCTL_TOKEN":            "goctl",
+				"GOCTL_ENTERPRISE_TOKEN": "enterprise",
+			},
+			want: []TokenCandidate{
+				{Token: "enterprise", Source: "GOCTL_ENTERPRISE_TOKEN", Precedence: 1},
+				{Token: "goctl", Source: "GOCTL_TOKEN", Precedence: 2},
+			},
+		},
+		{
+			name:   "no tokens",
+			host:   "github.com",
+			config: testNoHostsConfig(),
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(name string) string { return tt.env[name] }
+			got := tokenSources(context.Background(), getenv, tt.config, "", tt.host)
+			assert.Equal(t, tt.want, got)
+			if len(tt.want) > 0 {
+				token, source := tokenForHost(getenv, tt.config, tt.host)
+				assert.Equal(t, tt.want[0].Token, token)
+				assert.Equal(t, tt.want[0].Source, source)
+			}
+		})
+	}
+}
+
+func TestTokenSourcesKeyring(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a shell script")
+	}
+	goctlExe := filepath.Join(t.TempDir(), "goctl")
+	require.NoError(t, os.WriteFile(goctlExe, []byte("#!/bin/sh\necho keyring-token\n"), 0755))
+	getenv := func(name string) string {
+		if name == "GOCTL_TOKEN" {
+			return "goctl"
+		}
+		return ""
+	}
+
+	got := tokenSources(context.Background(), getenv, testNoHostsConfig(), goctlExe, "github.com")
+	assert.Equal(t, []TokenCandidate{
+		{Token: "goctl", Source: "GOCTL_TOKEN", Precedence: 1},
+		{Token: "keyring-token", Source: "goctl", Precedence: 2},
+	}, got)
+}
+
+func TestTokenSourcesOverride(t *testing.T) {
+	t.Cleanup(WithTokenForHost(func(host string) (string, string) {
+		return "override-token", "test"
+	}))
+	getenv := func(string) string { return "env-token" }
+
+	got := tokenSources(context.Background(), getenv, testHostsConfig(), "", "github.com")
+	assert.Equal(t, []TokenCandidate{{Token: "override-token", Source: "test", Precedence: 1}}, got)
+}
+
+func TestRedact(t *testing.T) {
+	environ := []string{
+		"GOCTL_TOKEN=goctl-token",
+		"GOCTL_TOKEN_GHE_EXAMPLE_COM=scoped-token",
+		"GITHUB_ENTERPRISE_TOKEN=enterprise-token",
+		"HOME=/home/monalisa",
+	}
+	var out bytes.Buffer
+	w := redactTokens(context.Background(), &out, environ, testHostsConfig(), "")
+	_, err := w.Write([]byte("goctl-token scoped-token enterprise-token\nxxxxxxxxxxxxxxxxxxxx yyyyyyyyyyyyyyyyyyyy /home/monalisa"))
+	require.NoError(t, err)
+	require.NoError(t, w.Flush())
+	assert.Equal(t, "*** *** ***\n*** *** /home/monalisa", out.String())
+}
@@ -3,22 +3,26 @@
 package auth
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
 
+	"github.com/khulnasoft-lab/execsafer"
+	"github.com/khulnasoft-lab/go-goctl/v2/internal/ghinstance"
 	"github.com/khulnasoft-lab/go-goctl/v2/internal/set"
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
-	"github.com/khulnasoft-lab/execsafer"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/logging"
 )
 
 const (
 	codespaces            = "CODESPACES"
 	defaultSource         = "default"
-	goctlEnterpriseToken     = "GOCTL_ENTERPRISE_TOKEN"
-	goctlHost                = "GOCTL_HOST"
-	goctlToken               = "GOCTL_TOKEN"
+	goctlEnterpriseToken  = "GOCTL_ENTERPRISE_TOKEN"
+	goctlHost             = "GOCTL_HOST"
+	goctlToken            = "GOCTL_TOKEN"
+	goctlTokenPrefix      = "GOCTL_TOKEN_"
 	github                = "github.com"
 	githubEnterpriseToken = "GITHUB_ENTERPRISE_TOKEN"
 	githubToken           = "GITHUB_TOKEN"
@@ -31,23 +35,35 @@ const (
 // host. The source can be either an environment variable, configuration file, or the system
 // keyring. In the latter case, this shells out to "goctl auth token" to obtain the token.
 //
+// A token in the environment variable named by HostTokenEnvVar takes precedence over the
+// generic environment variables, so that tokens for several hosts can be supplied at once.
+//
 // Returns "", "default" if no applicable token is found.
+//
+// See WithTokenForHost for supplying tokens programmatically.
 func TokenForHost(host string) (string, string) {
-	if token, source := TokenFromEnvOrConfig(host); token != "" {
+	return TokenForHostContext(context.Background(), host)
+}
+
+// TokenForHostContext is like TokenForHost but kills the "goctl auth token" process if the
+// context is done before it exits, in which case no token is returned from the keyring.
+func TokenForHostContext(ctx context.Context, host string) (string, string) {
+	if token, source, ok := overriddenToken(host); ok {
 		return token, source
 	}
-
-	goctlExe := os.Getenv("GOCTL_PATH")
-	if goctlExe == "" {
-		goctlExe, _ = safeexec.LookPath("goctl")
+	if token, source := TokenFromEnvOrConfig(host); token != "" {
+		logging.Logger().Debug("resolved authentication token", "host", host, "source", source)
+		return token, source
 	}
 
-	if goctlExe != "" {
-		if token, source := tokenFromGh(goctlExe, host); token != "" {
+	if goctlExe := goctlPath(); goctlExe != "" {
+		if token, source := tokenFromGh(ctx, goctlExe, host); token != "" {
+			logging.Logger().Debug("resolved authentication token", "host", host, "source", source)
 			return token, source
 		}
 	}
 
+	logging.Logger().Debug("no authentication token found", "host", host)
 	return "", defaultSource
 }
 
@@ -55,44 +71,84 @@ func TokenForHost(host string) (string, string) {
 // file as fallback, but does not support reading the token from system keyring. Most consumers
 // should use TokenForHost.
 func TokenFromEnvOrConfig(host string) (string, string) {
+	if token, source, ok := overriddenToken(host); ok {
+		return token, source
+	}
 	cfg, _ := config.Read(nil)
-	return tokenForHost(cfg, host)
+	return tokenForHost(os.Getenv, cfg, host)
+}
+
+func tokenForHost(getenv func(string) string, cfg *config.Config, host string) (string, string) {
+	if candidates := tokenCandidates(getenv, cfg, host); len(candidates) > 0 {
+		return candidates[0].Token, candidates[0].Source
+	}
+	if cfg != nil {
+		return "", oauthToken
+	}
+	return "", defaultSource
 }
 
-func tokenForHost(cfg *config.Config, host string) (string, string) {
+// tokenCandidates returns the tokens for host found in environment variables and the
+// config file, in the order of precedence of tokenForHost.
+func tokenCandidates(getenv func(string) string, cfg *config.Config, host string) []TokenCandidate {
 	host = normalizeHostname(host)
+	scoped := HostTokenEnvVar(host)
+	names := []string{scoped, strings.ToUpper(scoped)}
 	if isEnterprise(host) {
-		if token := os.Getenv(goctlEnterpriseToken); token != "" {
-			return token, goctlEnterpriseToken
-		}
-		if token := os.Getenv(githubEnterpriseToken); token != "" {
-			return token, githubEnterpriseToken
-		}
-		if isCodespaces, _ := strconv.ParseBool(os.Getenv(codespaces)); isCodespaces {
-			if token := os.Getenv(githubToken); token != "" {
-				return token, githubToken
-			}
-		}
-		if cfg != nil {
-			token, _ := cfg.Get([]string{hostsKey, host, oauthToken})
-			return token, oauthToken
+		names = append(names, goctlEnterpriseToken, githubEnterpriseToken)
+		if isCodespaces, _ := strconv.ParseBool(getenv(codespaces)); isCodespaces {
+			names = append(names, githubToken)
 		}
 	}
-	if token := os.Getenv(goctlToken); token != "" {
-		return token, goctlToken
+	// Enterprise hosts only fall back to the generic tokens without a config file.
+	if !isEnterprise(host) || cfg == nil {
+		names = append(names, goctlToken, githubToken)
 	}
-	if token := os.Getenv(githubToken); token != "" {
-		return token, githubToken
+
+	var candidates []TokenCandidate
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if token := getenv(name); token != "" {
+			candidates = append(candidates, TokenCandidate{Token: token, Source: name})
+		}
 	}
 	if cfg != nil {
-		token, _ := cfg.Get([]string{hostsKey, host, oauthToken})
-		return token, oauthToken
+		if token, _ := cfg.Get([]string{hostsKey, host, oauthToken}); token != "" {
+			candidates = append(candidates, TokenCandidate{Token: token, Source: oauthToken})
+		}
 	}
-	return "", defaultSource
+	return candidates
 }
 
-func tokenFromGh(path string, host string) (string, string) {
-	cmd := exec.Command(path, "auth", "token", "--secure-storage", "--hostname", host)
+// HostTokenEnvVar returns the name of the environment variable that holds the token for
+// host alone, such as "GOCTL_TOKEN_ghe_example_com" for "ghe.example.com". The name is
+// made of the normalized hostname with every character other than a letter or a digit
+// replaced by an underscore. The upper-case form of the name, such as
+// "GOCTL_TOKEN_GHE_EXAMPLE_COM", is accepted as well.
+func HostTokenEnvVar(host string) string {
+	return goctlTokenPrefix + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, normalizeHostname(host))
+}
+
+// goctlPath returns the path of the goctl executable, or "" if it is not found.
+func goctlPath() string {
+	if goctlExe := os.Getenv("GOCTL_PATH"); goctlExe != "" {
+		return goctlExe
+	}
+	goctlExe, _ := execsafer.LookPath("goctl")
+	return goctlExe
+}
+
+func tokenFromGh(ctx context.Context, path string, host string) (string, string) {
+	cmd := exec.CommandContext(ctx, path, "auth", "token", "--secure-storage", "--hostname", host)
 	result, err := cmd.Output()
 	if err != nil {
 		return "", "goctl"
@@ -106,15 +162,15 @@ func tokenFromGh(path string, host string) (string, string) {
 // Returns an empty string slice if no hosts are found.
 func KnownHosts() []string {
 	cfg, _ := config.Read(nil)
-	return knownHosts(cfg)
+	return knownHosts(os.Getenv, cfg)
 }
 
-func knownHosts(cfg *config.Config) []string {
+func knownHosts(getenv func(string) string, cfg *config.Config) []string {
 	hosts := set.NewStringSet()
-	if host := os.Getenv(goctlHost); host != "" {
+	if host := getenv(goctlHost); host != "" {
 		hosts.Add(host)
 	}
-	if token, _ := tokenForHost(cfg, github); token != "" {
+	if token, _ := tokenForHost(getenv, cfg, github); token != "" {
 		hosts.Add(github)
 	}
 	if cfg != nil {
@@ -132,11 +188,11 @@ func knownHosts(cfg *config.Config) []string {
 // Returns "github.com", "default" if no viable host is found.
 func DefaultHost() (string, string) {
 	cfg, _ := config.Read(nil)
-	return defaultHost(cfg)
+	return defaultHost(os.Getenv, cfg)
 }
 
-func defaultHost(cfg *config.Config) (string, string) {
-	if host := os.Getenv(goctlHost); host != "" {
+func defaultHost(getenv func(string) string, cfg *config.Config) (string, string) {
+	if host := getenv(goctlHost); host != "" {
 		return host, goctlHost
 	}
 	if cfg != nil {
@@ -149,16 +205,9 @@ func defaultHost(cfg *config.Config) (string, string) {
 }
 
 func isEnterprise(host string) bool {
-	return host != github && host != localhost
+	return ghinstance.IsEnterprise(host)
 }
 
 func normalizeHostname(host string) string {
-	hostname := strings.ToLower(host)
-	if strings.HasSuffix(hostname, "."+github) {
-		return github
-	}
-	if strings.HasSuffix(hostname, "."+localhost) {
-		return localhost
-	}
-	return hostname
+	return ghinstance.NormalizeHostname(host)
 }
@@ -1,7 +1,12 @@
 package auth
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
 	"github.com/stretchr/testify/assert"
@@ -92,13 +97,68 @@ func TestTokenForHost(t *testing.T) {
 			t.Setenv("GITHUB_ENTERPRISE_TOKEN", tt.githubEnterpriseToken)
 			t.Setenv("GOCTL_TOKEN", tt.goctlToken)
 			t.Setenv("GOCTL_ENTERPRISE_TOKEN", tt.goctlEnterpriseToken)
-			token, source := tokenForHost(tt.config, tt.host)
+			token, source := tokenForHost(os.Getenv, tt.config, tt.host)
 			assert.Equal(t, tt.wantToken, token)
 			assert.Equal(t, tt.wantSource, source)
 		})
 	}
 }
 
+func TestTokenForHostScopedEnv(t *testing.T) {
+	env := map[string]string{
+		"GOCTL_TOKEN":                 "GOCTL_TOKEN",
+		"GOCTL_ENTERPRISE_TOKEN":      "GOCTL_ENTERPRISE_TOKEN",
+		"GOCTL_TOKEN_github_com":      "github_com",
+		"GOCTL_TOKEN_ghe_example_com": "ghe_example_com",
+		"GOCTL_TOKEN_GHE_OTHER_COM":   "GHE_OTHER_COM",
+	}
+	getenv := func(name string) string { return env[name] }
+	tests := []struct {
+		host       string
+		wantToken  string
+		wantSource string
+	}{
+		{
+			host:       "github.com",
+			wantToken:  "github_com",
+			wantSource: "GOCTL_TOKEN_github_com",
+		},
+		{
+			host:       "tenant.github.com",
+			wantToken:  "github_com",
+			wantSource: "GOCTL_TOKEN_github_com",
+		},
+		{
+			host:       "GHE.example.com",
+			wantToken:  "ghe_example_com",
+			wantSource: "GOCTL_TOKEN_ghe_example_com",
+		},
+		{
+			host:       "ghe-other.com",
+			wantToken:  "GHE_OTHER_COM",
+			wantSource: "GOCTL_TOKEN_GHE_OTHER_COM",
+		},
+		{
+			host:       "ghe.unknown.com",
+			wantToken:  "GOCTL_ENTERPRISE_TOKEN",
+			wantSource: "GOCTL_ENTERPRISE_TOKEN",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			token, source := tokenForHost(getenv, testHostsConfig(), tt.host)
+			assert.Equal(t, tt.wantToken, token)
+			assert.Equal(t, tt.wantSource, source)
+		})
+	}
+}
+
+func TestHostTokenEnvVar(t *testing.T) {
+	assert.Equal(t, "GOCTL_TOKEN_github_com", HostTokenEnvVar("github.com"))
+	assert.Equal(t, "GOCTL_TOKEN_my_ghe_example_com", HostTokenEnvVar("My-GHE.example.com"))
+	assert.Equal(t, "GOCTL_TOKEN_ghe_example_com_8443", HostTokenEnvVar("ghe.example.com:8443"))
+}
+
 func TestDefaultHost(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -142,7 +202,7 @@ func TestDefaultHost(t *testing.T) {
 			if tt.goctlHost != "" {
 				t.Setenv("GOCTL_HOST", tt.goctlHost)
 			}
-			host, source := defaultHost(tt.config)
+			host, source := defaultHost(os.Getenv, tt.config)
 			assert.Equal(t, tt.wantHost, host)
 			assert.Equal(t, tt.wantSource, source)
 		})
@@ -196,7 +256,7 @@ func TestKnownHosts(t *testing.T) {
 			if tt.goctlToken != "" {
 				t.Setenv("GOCTL_TOKEN", tt.goctlToken)
 			}
-			hosts := knownHosts(tt.config)
+			hosts := knownHosts(os.Getenv, tt.config)
 			assert.Equal(t, tt.wantHosts, hosts)
 		})
 	}
@@ -299,3 +359,27 @@ hosts:
 `
 	return config.ReadFromString(data)
 }
+
+func TestTokenFromGh(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a shell script")
+	}
+	dir := t.TempDir()
+	goctlExe := filepath.Join(dir, "goctl")
+	err := os.WriteFile(goctlExe, []byte("#!/bin/sh\necho keyring-token\n"), 0755)
+	assert.NoError(t, err)
+	slowExe := filepath.Join(dir, "goctl-slow")
+	err = os.WriteFile(slowExe, []byte("#!/bin/sh\nexec sleep 10\n"), 0755)
+	assert.NoError(t, err)
+
+	token, source := tokenFromGh(context.Background(), goctlExe, "github.com")
+	assert.Equal(t, "keyring-token", token)
+	assert.Equal(t, "goctl", source)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	token, _ = tokenFromGh(ctx, slowExe, "github.com")
+	assert.Equal(t, "", token)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
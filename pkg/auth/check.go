@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/internal/ghinstance"
+)
+
+// ErrTokenInvalid is returned by CheckToken when the API rejects the token, for example
+// because it was revoked or has expired.
+var ErrTokenInvalid = errors.New("authentication token is invalid")
+
+// TokenType is the kind of an authentication token, as determined by its prefix.
+type TokenType string
+
+// Token types recognized by CheckToken.
+const (
+	TokenTypeClassic      TokenType = "classic"
+	TokenTypeFineGrained  TokenType = "fine-grained"
+	TokenTypeOAuth        TokenType = "oauth"
+	TokenTypeAppUser      TokenType = "app-user"
+	TokenTypeInstallation TokenType = "installation"
+	TokenTypeUnknown      TokenType = "unknown"
+)
+
+// tokenExpirationHeader holds the expiry of tokens that expire.
+const tokenExpirationHeader = "GitHub-Authentication-Token-Expiration"
+
+// TokenInfo describes an authentication token checked with CheckToken.
+type TokenInfo struct {
+	// Login is the login of the authenticated user. It is empty for installation tokens,
+	// which do not act on behalf of a user.
+	Login string
+	// Scopes are the OAuth scopes granted to classic and OAuth app tokens. It is nil for
+	// other tokens, which are limited by fine-grained permissions that the API does not
+	// report.
+	Scopes []string
+	// Type is the kind of token.
+	Type TokenType
+	// ExpiresAt is when the token expires, or the zero time if it does not.
+	ExpiresAt time.Time
+}
+
+// impliedScopes lists the scopes that are granted along with a broader scope.
+var impliedScopes = map[string][]string{
+	"repo":             {"repo:status", "repo_deployment", "public_repo", "repo:invite", "security_events"},
+	"admin:org":        {"write:org", "read:org", "manage_runners:org"},
+	"write:org":        {"read:org"},
+	"admin:public_key": {"write:public_key", "read:public_key"},
+	"write:public_key": {"read:public_key"},
+	"admin:repo_hook":  {"write:repo_hook", "read:repo_hook"},
+	"write:repo_hook":  {"read:repo_hook"},
+	"write:packages":   {"read:packages"},
+	"admin:gpg_key":    {"write:gpg_key", "read:gpg_key"},
+	"write:gpg_key":    {"read:gpg_key"},
+	"user":             {"read:user", "user:email", "user:follow"},
+	"project":          {"read:project"},
+	"admin:enterprise": {"manage_runners:enterprise", "manage_billing:enterprise", "read:enterprise"},
+}
+
+// HasScopes reports whether the API reported OAuth scopes for the token, which it does
+// for classic and OAuth app tokens even if no scopes were granted.
+func (i *TokenInfo) HasScopes() bool {
+	return i.Scopes != nil
+}
+
+// MissingScopes returns the scopes in required that were not granted to the token,
+// taking into account scopes that imply others, such as "repo" implying "public_repo".
+// It returns nil for tokens that do not use OAuth scopes.
+func (i *TokenInfo) MissingScopes(required ...string) []string {
+	if !i.HasScopes() {
+		return nil
+	}
+	granted := map[string]bool{}
+	for _, s := range i.Scopes {
+		granted[s] = true
+		for _, implied := range impliedScopes[s] {
+			granted[implied] = true
+		}
+	}
+	var missing []string
+	for _, s := range required {
+		if !granted[s] {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+type checkTokenOptions struct {
+	client *http.Client
+}
+
+type checkTokenOption func(*checkTokenOptions)
+
+// WithCheckTokenHTTPClient sets the HTTP client used to check the token. It defaults to
+// http.DefaultClient; api.CheckToken passes a client built with api.NewHTTPClient.
+func WithCheckTokenHTTPClient(client *http.Client) checkTokenOption {
+	return func(o *checkTokenOptions) {
+		o.client = client
+	}
+}
+
+// CheckToken validates token against the API of host, and returns the authenticated
+// user, the granted OAuth scopes, the kind of token, and its expiry. An error wrapping
+// ErrTokenInvalid is returned if the API rejects the token.
+//
+// Use api.CheckToken to send the request with the transport, headers and unix domain
+// socket configured for goctl.
+func CheckToken(host, token string, opts ...checkTokenOption) (*TokenInfo, error) {
+	return CheckTokenContext(context.Background(), host, token, opts...)
+}
+
+// CheckTokenContext is like CheckToken but cancels the request when the context is done.
+func CheckTokenContext(ctx context.Context, host, token string, opts ...checkTokenOption) (*TokenInfo, error) {
+	o := checkTokenOptions{client: http.DefaultClient}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return checkToken(ctx, o.client, host, token)
+}
+
+func checkToken(ctx context.Context, client *http.Client, host, token string) (*TokenInfo, error) {
+	info := &TokenInfo{Type: tokenType(token)}
+	path := "user"
+	if info.Type == TokenTypeInstallation {
+		// Installation tokens do not belong to a user.
+		path = "installation/repositories?per_page=1"
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", ghinstance.RESTURL(host, path), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "token "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("%w for host %s", ErrTokenInvalid, normalizeHostname(host))
+	default:
+		var body struct{ Message string }
+		_ = json.NewDecoder(io.LimitReader(res.Body, 64*1024)).Decode(&body)
+		return nil, fmt.Errorf("failed to check token for host %s: HTTP %d: %s", normalizeHostname(host), res.StatusCode, body.Message)
+	}
+
+	if scopes, ok := res.Header["X-Oauth-Scopes"]; ok {
+		info.Scopes = []string{}
+		for _, s := range strings.Split(strings.Join(scopes, ","), ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				info.Scopes = append(info.Scopes, s)
+			}
+		}
+	}
+	if expiry := res.Header.Get(tokenExpirationHeader); expiry != "" {
+		for _, layout := range []string{"2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"} {
+			if t, err := time.Parse(layout, expiry); err == nil {
+				info.ExpiresAt = t
+				break
+			}
+		}
+	}
+	if path == "user" {
+		var user struct{ Login string }
+		if err := json.NewDecoder(res.Body).Decode(&user); err != nil {
+			return nil, fmt.Errorf("failed to check token for host %s: %w", normalizeHostname(host), err)
+		}
+		info.Login = user.Login
+	}
+	return info, nil
+}
+
+// tokenType determines the kind of token from its prefix. Tokens of GitHub Enterprise
+// Server versions that predate prefixes are of unknown type.
+func tokenType(token string) TokenType {
+	switch {
+	case strings.HasPrefix(token, "ghp_"):
+		return TokenTypeClassic
+	case strings.HasPrefix(token, "github_pat_"):
+		return TokenTypeFineGrained
+	case strings.HasPrefix(token, "gho_"):
+		return TokenTypeOAuth
+	case strings.HasPrefix(token, "ghu_"):
+		return TokenTypeAppUser
+	case strings.HasPrefix(token, "ghs_"):
+		return TokenTypeInstallation
+	}
+	return TokenTypeUnknown
+}
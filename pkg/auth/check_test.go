@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		token    string
+		status   int
+		headers  map[string]string
+		body     string
+		wantPath string
+		wantInfo *TokenInfo
+		wantErr  string
+	}{
+		{
+			name:     "classic token",
+			host:     "github.com",
+			token:    "ghp_abc",
+			status:   200,
+			headers:  map[string]string{"X-OAuth-Scopes": "repo, read:org, gist"},
+			body:     `{"login":"monalisa"}`,
+			wantPath: "api.github.com/user",
+			wantInfo: &TokenInfo{Login: "monalisa", Scopes: []string{"repo", "read:org", "gist"}, Type: TokenTypeClassic},
+		},
+		{
+			name:   "expiring fine-grained token",
+			host:   "github.com",
+			token:  "github_pat_abc",
+			status: 200,
+			headers: map[string]string{
+				"GitHub-Authentication-Token-Expiration": "2024-03-01 12:30:00 UTC",
+			},
+			body:     `{"login":"monalisa"}`,
+			wantPath: "api.github.com/user",
+			wantInfo: &TokenInfo{Login: "monalisa", Type: TokenTypeFineGrained, ExpiresAt: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)},
+		},
+		{
+			name:     "OAuth token without scopes on enterprise",
+			host:     "ghe.io",
+			token:    "gho_abc",
+			status:   200,
+			headers:  map[string]string{"X-OAuth-Scopes": ""},
+			body:     `{"login":"hubot"}`,
+			wantPath: "ghe.io/api/v3/user",
+			wantInfo: &TokenInfo{Login: "hubot", Scopes: []string{}, Type: TokenTypeOAuth},
+		},
+		{
+			name:     "installation token",
+			host:     "github.com",
+			token:    "ghs_abc",
+			status:   200,
+			body:     `{"total_count":1,"repositories":[]}`,
+			wantPath: "api.github.com/installation/repositories",
+			wantInfo: &TokenInfo{Type: TokenTypeInstallation},
+		},
+		{
+			name:     "revoked token",
+			host:     "github.com",
+			token:    "ghp_revoked",
+			status:   401,
+			body:     `{"message":"Bad credentials"}`,
+			wantPath: "api.github.com/user",
+			wantErr:  "authentication token is invalid for host github.com",
+		},
+		{
+			name:     "server error",
+			host:     "github.com",
+			token:    "0123456789abcdef",
+			status:   503,
+			body:     `{"message":"Service Unavailable"}`,
+			wantPath: "api.github.com/user",
+			wantErr:  "failed to check token for host github.com: HTTP 503: Service Unavailable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotAuth string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.Header.Get("X-Original-Host") + r.URL.Path
+				gotAuth = r.Header.Get("Authorization")
+				for k, v := range tt.headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer ts.Close()
+			target, _ := url.Parse(ts.URL)
+			client := &http.Client{Transport: redirectTransport{target: target}}
+
+			info, err := checkToken(context.Background(), client, tt.host, tt.token)
+			assert.Equal(t, tt.wantPath, gotPath)
+			assert.Equal(t, "token "+tt.token, gotAuth)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantInfo, info)
+		})
+	}
+}
+
+func TestCheckTokenInvalid(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(401)
+	}))
+	defer ts.Close()
+	target, _ := url.Parse(ts.URL)
+	_, err := checkToken(context.Background(), &http.Client{Transport: redirectTransport{target: target}}, "github.com", "ghp_abc")
+	assert.ErrorIs(t, err, ErrTokenInvalid)
+}
+
+func TestMissingScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     TokenInfo
+		required []string
+		want     []string
+	}{
+		{
+			name:     "all granted",
+			info:     TokenInfo{Scopes: []string{"repo", "read:org"}},
+			required: []string{"repo", "read:org"},
+		},
+		{
+			name:     "implied scopes",
+			info:     TokenInfo{Scopes: []string{"repo", "admin:org", "user"}},
+			required: []string{"public_repo", "read:org", "user:email"},
+		},
+		{
+			name:     "missing scopes",
+			info:     TokenInfo{Scopes: []string{"write:org"}},
+			required: []string{"repo", "read:org", "admin:org"},
+			want:     []string{"repo", "admin:org"},
+		},
+		{
+			name:     "no scopes granted",
+			info:     TokenInfo{Scopes: []string{}},
+			required: []string{"gist"},
+			want:     []string{"gist"},
+		},
+		{
+			name:     "fine-grained token",
+			info:     TokenInfo{Type: TokenTypeFineGrained},
+			required: []string{"repo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.info.MissingScopes(tt.required...))
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/internal/ghinstance"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
+)
+
+// deviceGrantType is the grant type of the OAuth device authorization grant.
+const deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// ErrDeviceFlowDenied is returned by DeviceFlow when the user declines the authorization.
+var ErrDeviceFlowDenied = errors.New("authorization was denied")
+
+// ErrDeviceFlowExpired is returned by DeviceFlow when the user does not enter the code
+// before it expires.
+var ErrDeviceFlowExpired = errors.New("device code expired")
+
+// DeviceCode is the code that the user enters at the verification URI to authorize
+// the device.
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// OAuthToken is an access token obtained through the device flow.
+type OAuthToken struct {
+	Token  string
+	Type   string
+	Scopes []string
+	// RefreshToken and ExpiresAt are set for the user access tokens of GitHub Apps that
+	// expire. RefreshTokenSource uses them to replace the token before it expires.
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// accessTokenResponse is the response of the OAuth access token endpoint.
+type accessTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	Scope        string `json:"scope"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+	Interval     int    `json:"interval"`
+}
+
+// token returns the access token of the response, which was received at now.
+func (r accessTokenResponse) token(now time.Time) *OAuthToken {
+	token := &OAuthToken{Token: r.AccessToken, Type: r.TokenType, RefreshToken: r.RefreshToken}
+	if r.Scope != "" {
+		token.Scopes = strings.Split(r.Scope, ",")
+	}
+	if r.ExpiresIn > 0 {
+		token.ExpiresAt = now.Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+	return token
+}
+
+type deviceFlow struct {
+	client *http.Client
+	clock  clock.Clock
+	prompt func(DeviceCode) error
+	store  TokenStore
+}
+
+type deviceFlowOption func(*deviceFlow)
+
+// WithDeviceFlowHTTPClient sets the HTTP client used to talk to the OAuth endpoints.
+// It defaults to http.DefaultClient.
+func WithDeviceFlowHTTPClient(client *http.Client) deviceFlowOption {
+	return func(f *deviceFlow) {
+		f.client = client
+	}
+}
+
+// WithDeviceCodePrompt sets the function that shows the user code and verification URI
+// to the user, for example by printing them and opening a browser. Authorization fails
+// if it returns an error. By default they are printed to standard error.
+func WithDeviceCodePrompt(prompt func(DeviceCode) error) deviceFlowOption {
+	return func(f *deviceFlow) {
+		f.prompt = prompt
+	}
+}
+
+// WithTokenStore persists the token obtained for the host in store, such as the one
+// returned by NewTokenStore.
+func WithTokenStore(store TokenStore) deviceFlowOption {
+	return func(f *deviceFlow) {
+		f.store = store
+	}
+}
+
+// WithDeviceFlowClock sets the clock used to wait between polls. It is meant for tests.
+func WithDeviceFlowClock(c clock.Clock) deviceFlowOption {
+	return func(f *deviceFlow) {
+		f.clock = c
+	}
+}
+
+// DeviceFlow authorizes the OAuth app with the given client ID to access host on behalf
+// of the user with the device authorization grant, without shelling out to "goctl auth
+// login". The user is shown a code to enter in the browser, and GitHub is polled until the
+// user authorizes the app, declines, or the code expires.
+//
+// The OAuth app must have the device flow enabled.
+func DeviceFlow(host, clientID string, scopes []string, opts ...deviceFlowOption) (*OAuthToken, error) {
+	return DeviceFlowContext(context.Background(), host, clientID, scopes, opts...)
+}
+
+// DeviceFlowContext is like DeviceFlow but stops polling when the context is done.
+func DeviceFlowContext(ctx context.Context, host, clientID string, scopes []string, opts ...deviceFlowOption) (*OAuthToken, error) {
+	f := &deviceFlow{
+		client: http.DefaultClient,
+		clock:  clock.Real,
+		prompt: printDeviceCode,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if !ghinstance.IsGarage(host) {
+		host = normalizeHostname(host)
+	}
+
+	var code DeviceCode
+	err := f.post(ctx, host, "login/device/code", url.Values{
+		"client_id": {clientID},
+		"scope":     {strings.Join(scopes, " ")},
+	}, &code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request device code: %w", err)
+	}
+	if err := f.prompt(code); err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	expiry := f.clock.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-f.clock.After(interval):
+		}
+		if code.ExpiresIn > 0 && !f.clock.Now().Before(expiry) {
+			return nil, ErrDeviceFlowExpired
+		}
+
+		var resp accessTokenResponse
+		err := f.post(ctx, host, "login/oauth/access_token", url.Values{
+			"client_id":   {clientID},
+			"device_code": {code.DeviceCode},
+			"grant_type":  {deviceGrantType},
+		}, &resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to poll for access token: %w", err)
+		}
+		switch resp.Error {
+		case "":
+			token := resp.token(f.clock.Now())
+			if f.store != nil {
+				if err := f.store.SetToken(host, token.Token); err != nil {
+					return token, fmt.Errorf("failed to store token: %w", err)
+				}
+			}
+			return token, nil
+		case "authorization_pending":
+		case "slow_down":
+			if resp.Interval > 0 {
+				interval = time.Duration(resp.Interval) * time.Second
+			} else {
+				interval += 5 * time.Second
+			}
+		case "expired_token":
+			return nil, ErrDeviceFlowExpired
+		case "access_denied":
+			return nil, ErrDeviceFlowDenied
+		default:
+			return nil, fmt.Errorf("device flow failed: %s: %s", resp.Error, resp.Description)
+		}
+	}
+}
+
+// post submits a form to an OAuth endpoint of host and decodes the JSON response.
+func (f *deviceFlow) post(ctx context.Context, host, path string, form url.Values, resp interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", ghinstance.HostPrefix(host)+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	res, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("HTTP %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(res.Body).Decode(resp)
+}
+
+func printDeviceCode(code DeviceCode) error {
+	_, err := fmt.Fprintf(os.Stderr, "First copy your one-time code: %s\nThen open %s in your browser to continue.\n",
+		code.UserCode, code.VerificationURI)
+	return err
+}
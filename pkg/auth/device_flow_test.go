@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Original-Host", req.URL.Host)
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+type deviceFlowServer struct {
+	polls    []map[string]interface{}
+	requests []string
+}
+
+func (s *deviceFlowServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	s.requests = append(s.requests, r.Header.Get("X-Original-Host")+r.URL.Path+" "+r.Form.Encode())
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/login/device/code":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"device_code":      "dc123",
+			"user_code":        "ABCD-1234",
+			"verification_uri": "https://github.com/login/device",
+			"expires_in":       900,
+			"interval":         5,
+		})
+	case "/login/oauth/access_token":
+		resp := s.polls[0]
+		s.polls = s.polls[1:]
+		_ = json.NewEncoder(w).Encode(resp)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func runDeviceFlow(t *testing.T, srv *deviceFlowServer, waits []time.Duration, opts ...deviceFlowOption) (*OAuthToken, DeviceCode, error) {
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	target, _ := url.Parse(ts.URL)
+	fake := clock.NewFake(time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC))
+
+	var shown DeviceCode
+	opts = append([]deviceFlowOption{
+		WithDeviceFlowHTTPClient(&http.Client{Transport: redirectTransport{target: target}}),
+		WithDeviceFlowClock(fake),
+		WithDeviceCodePrompt(func(code DeviceCode) error {
+			shown = code
+			return nil
+		}),
+	}, opts...)
+
+	type result struct {
+		token *OAuthToken
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		token, err := DeviceFlow("GitHub.com", "client123", []string{"repo", "read:org"}, opts...)
+		done <- result{token, err}
+	}()
+	for _, wait := range waits {
+		fake.BlockUntil(1)
+		fake.Advance(wait)
+	}
+	res := <-done
+	return res.token, shown, res.err
+}
+
+func TestDeviceFlow(t *testing.T) {
+	srv := &deviceFlowServer{polls: []map[string]interface{}{
+		{"error": "authorization_pending"},
+		{"error": "slow_down", "interval": 10},
+		{"access_token": "gho_device", "token_type": "bearer", "scope": "repo,read:org"},
+	}}
+	store := &fakeKeyring{secrets: map[string]string{}}
+	token, code, err := runDeviceFlow(t, srv, []time.Duration{5 * time.Second, 5 * time.Second, 10 * time.Second},
+		WithTokenStore(keyringStore{keyring: store}))
+	require.NoError(t, err)
+	assert.Equal(t, &OAuthToken{Token: "gho_device", Type: "bearer", Scopes: []string{"repo", "read:org"}}, token)
+	assert.Equal(t, "ABCD-1234", code.UserCode)
+	assert.Equal(t, "https://github.com/login/device", code.VerificationURI)
+	assert.Equal(t, map[string]string{"goctl:github.com:": "gho_device"}, store.secrets)
+	poll := "github.com/login/oauth/access_token client_id=client123&device_code=dc123&grant_type=urn%3Aietf%3Aparams%3Aoauth%3Agrant-type%3Adevice_code"
+	assert.Equal(t, []string{
+		"github.com/login/device/code client_id=client123&scope=repo+read%3Aorg",
+		poll, poll, poll,
+	}, srv.requests)
+}
+
+func TestDeviceFlowErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		polls   []map[string]interface{}
+		waits   []time.Duration
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "denied",
+			polls:   []map[string]interface{}{{"error": "access_denied"}},
+			waits:   []time.Duration{5 * time.Second},
+			wantErr: ErrDeviceFlowDenied,
+		},
+		{
+			name:    "expired by server",
+			polls:   []map[string]interface{}{{"error": "expired_token"}},
+			waits:   []time.Duration{5 * time.Second},
+			wantErr: ErrDeviceFlowExpired,
+		},
+		{
+			name:    "expired locally",
+			waits:   []time.Duration{15 * time.Minute},
+			wantErr: ErrDeviceFlowExpired,
+		},
+		{
+			name:    "unknown error",
+			polls:   []map[string]interface{}{{"error": "incorrect_client_credentials", "error_description": "The client_id is not valid."}},
+			waits:   []time.Duration{5 * time.Second},
+			wantMsg: "device flow failed: incorrect_client_credentials: The client_id is not valid.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := runDeviceFlow(t, &deviceFlowServer{polls: tt.polls}, tt.waits)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.EqualError(t, err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestDeviceFlowCancelled(t *testing.T) {
+	ts := httptest.NewServer(&deviceFlowServer{})
+	t.Cleanup(ts.Close)
+	target, _ := url.Parse(ts.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := DeviceFlowContext(ctx, "github.com", "client123", nil,
+		WithDeviceFlowHTTPClient(&http.Client{Transport: redirectTransport{target: target}}),
+		WithDeviceCodePrompt(func(DeviceCode) error {
+			cancel()
+			return nil
+		}))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// urlRecorder records the URLs of requests and fails them.
+type urlRecorder struct {
+	urls []string
+}
+
+func (r *urlRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.urls = append(r.urls, req.URL.String())
+	return nil, errors.New("offline")
+}
+
+func TestDeviceFlowHosts(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantURL string
+	}{
+		{host: "GitHub.com", wantURL: "https://github.com/login/device/code"},
+		{host: "api.github.com", wantURL: "https://github.com/login/device/code"},
+		{host: "garage.github.com", wantURL: "https://garage.github.com/login/device/code"},
+		{host: "github.localhost", wantURL: "http://github.localhost/login/device/code"},
+		{host: "ghe.example.com", wantURL: "https://ghe.example.com/login/device/code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			rec := &urlRecorder{}
+			_, err := DeviceFlow(tt.host, "client123", nil, WithDeviceFlowHTTPClient(&http.Client{Transport: rec}))
+			assert.Error(t, err)
+			assert.Equal(t, []string{tt.wantURL}, rec.urls)
+		})
+	}
+}
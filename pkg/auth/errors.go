@@ -0,0 +1,11 @@
+package auth
+
+import "errors"
+
+// ErrTokenNotFound is returned, possibly wrapped, by functions that require an
+// authentication token when none can be found for a host.
+var ErrTokenNotFound = errors.New("authentication token not found")
+
+// ErrUserNotFound is returned, possibly wrapped, by functions that operate on an account
+// of a host when the account is not logged in to the host.
+var ErrUserNotFound = errors.New("user not found")
@@ -0,0 +1,64 @@
+//go:build darwin
+
+package auth
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"strings"
+)
+
+// securityPath is the program used to access the login Keychain.
+const securityPath = "/usr/bin/security"
+
+// securityItemNotFound is the exit status of security when no Keychain item matches.
+const securityItemNotFound = 44
+
+// osKeyring stores secrets as generic passwords in the login Keychain.
+type osKeyring struct{}
+
+func (osKeyring) get(service, user string) (string, error) {
+	out, err := exec.Command(securityPath, "find-generic-password", "-s", service, "-a", user, "-w").Output()
+	if err != nil {
+		return "", securityError(err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (osKeyring) set(service, user, secret string) error {
+	// The secret is passed through standard input in interactive mode, hex encoded, so
+	// that it does not appear in the arguments of the process.
+	cmd := exec.Command(securityPath, "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		securityQuote(service), securityQuote(user), hex.EncodeToString([]byte(secret))))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", securityError(err), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (osKeyring) delete(service, user string) error {
+	if err := exec.Command(securityPath, "delete-generic-password", "-s", service, "-a", user).Run(); err != nil {
+		return securityError(err)
+	}
+	return nil
+}
+
+func securityError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound {
+		return errKeyringNotFound
+	}
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %v", ErrKeyringUnavailable, err)
+	}
+	return err
+}
+
+// securityQuote quotes an argument of a command in the interactive mode of security.
+func securityQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
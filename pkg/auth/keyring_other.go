@@ -0,0 +1,68 @@
+//go:build !darwin && !windows
+
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/khulnasoft-lab/execsafer"
+)
+
+// osKeyring stores secrets with a Secret Service provider, such as GNOME Keyring or
+// KeePassXC, using the secret-tool program of libsecret. The attributes match those used
+// by other Go programs so that tokens stored by goctl are found.
+type osKeyring struct{}
+
+func (osKeyring) get(service, user string) (string, error) {
+	out, err := secretTool(nil, "lookup", "service", service, "username", user)
+	if err != nil {
+		return "", err
+	}
+	if out == "" {
+		return "", errKeyringNotFound
+	}
+	return out, nil
+}
+
+func (osKeyring) set(service, user, secret string) error {
+	label := fmt.Sprintf("Password for '%s' on '%s'", user, service)
+	_, err := secretTool(strings.NewReader(secret), "store", "--label", label, "service", service, "username", user)
+	return err
+}
+
+func (osKeyring) delete(service, user string) error {
+	_, err := secretTool(nil, "clear", "service", service, "username", user)
+	return err
+}
+
+// secretTool runs secret-tool and returns its output. A failure with no error output is
+// how secret-tool reports that no secret matched; other failures usually mean that no
+// Secret Service provider is running.
+func secretTool(stdin *strings.Reader, args ...string) (string, error) {
+	exe, err := execsafer.LookPath("secret-tool")
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrKeyringUnavailable, err)
+	}
+	cmd := exec.Command(exe, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", ErrKeyringUnavailable, msg)
+		}
+		return "", errKeyringNotFound
+	} else if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(stdout.String(), "\n"), nil
+}
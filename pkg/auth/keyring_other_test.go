@@ -0,0 +1,56 @@
+//go:build !darwin && !windows
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSecretTool is a secret-tool replacement that keeps secrets in files named after
+// the service attribute.
+const fakeSecretTool = `#!/bin/sh
+dir=$(dirname "$0")
+case "$1" in
+lookup) cat "$dir/secret-$3" 2>/dev/null ;;
+store) cat > "$dir/secret-$5" ;;
+clear) rm -f "$dir/secret-$3" ;;
+esac
+[ "$1" != lookup ] || [ -f "$dir/secret-$3" ]
+`
+
+func TestOSKeyring(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret-tool"), []byte(fakeSecretTool), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	k := osKeyring{}
+
+	_, err := k.get("goctl:github.com", "")
+	assert.ErrorIs(t, err, errKeyringNotFound)
+
+	require.NoError(t, k.set("goctl:github.com", "", "gho_secret"))
+	secret, err := k.get("goctl:github.com", "")
+	require.NoError(t, err)
+	assert.Equal(t, "gho_secret", secret)
+
+	require.NoError(t, k.delete("goctl:github.com", ""))
+	_, err = k.get("goctl:github.com", "")
+	assert.ErrorIs(t, err, errKeyringNotFound)
+}
+
+func TestOSKeyringUnavailable(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+	_, err := osKeyring{}.get("goctl:github.com", "")
+	assert.ErrorIs(t, err, ErrKeyringUnavailable)
+
+	script := "#!/bin/sh\necho 'Cannot autolaunch D-Bus without X11 $DISPLAY' >&2\nexit 1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret-tool"), []byte(script), 0755))
+	err = osKeyring{}.set("goctl:github.com", "", "gho_secret")
+	assert.ErrorIs(t, err, ErrKeyringUnavailable)
+	assert.Contains(t, err.Error(), "Cannot autolaunch D-Bus")
+}
@@ -0,0 +1,43 @@
+package auth
+
+import "github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+
+// Options resolve tokens and hosts like the package-level functions of the same name, but
+// from an explicit environment and configuration instead of the process environment and
+// configuration file. They suit tests that need to run in parallel, which t.Setenv
+// prevents, and servers that resolve credentials for several users in one process.
+type Options struct {
+	// Env holds the environment variables to consult, such as GOCTL_TOKEN and GOCTL_HOST.
+	// A nil map is an empty environment.
+	Env map[string]string
+	// Config is the configuration to consult, such as one obtained from
+	// [config.ReadFromString]. A nil Config is an empty configuration.
+	Config *config.Config
+}
+
+// TokenFromEnvOrConfig retrieves an authentication token and its source for the host
+// from the environment variables or configuration of the options. The system keyring
+// is never consulted.
+//
+// Returns "", "default" if no applicable token is found.
+func (o Options) TokenFromEnvOrConfig(host string) (string, string) {
+	return tokenForHost(o.getenv, o.Config, host)
+}
+
+// KnownHosts retrieves the hosts that have corresponding authentication tokens in the
+// environment variables or configuration of the options.
+func (o Options) KnownHosts() []string {
+	return knownHosts(o.getenv, o.Config)
+}
+
+// DefaultHost retrieves the authenticated host and its source from the environment
+// variables or configuration of the options.
+//
+// Returns "github.com", "default" if no viable host is found.
+func (o Options) DefaultHost() (string, string) {
+	return defaultHost(o.getenv, o.Config)
+}
+
+func (o Options) getenv(name string) string {
+	return o.Env[name]
+}
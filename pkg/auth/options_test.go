@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsTokenFromEnvOrConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		opts       Options
+		host       string
+		wantToken  string
+		wantSource string
+	}{
+		{
+			name:       "empty options",
+			host:       "github.com",
+			wantToken:  "",
+			wantSource: "default",
+		},
+		{
+			name:       "env token",
+			opts:       Options{Env: map[string]string{"GOCTL_TOKEN": "GOCTL_TOKEN"}, Config: testHostsConfig()},
+			host:       "github.com",
+			wantToken:  "GOCTL_TOKEN",
+			wantSource: "GOCTL_TOKEN",
+		},
+		{
+			name:       "enterprise env token",
+			opts:       Options{Env: map[string]string{"GITHUB_ENTERPRISE_TOKEN": "GITHUB_ENTERPRISE_TOKEN"}},
+			host:       "enterprise.com",
+			wantToken:  "GITHUB_ENTERPRISE_TOKEN",
+			wantSource: "GITHUB_ENTERPRISE_TOKEN",
+		},
+		{
+			name:       "config token",
+			opts:       Options{Config: testHostsConfig()},
+			host:       "enterprise.com",
+			wantToken:  "yyyyyyyyyyyyyyyyyyyy",
+			wantSource: "oauth_token",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			token, source := tt.opts.TokenFromEnvOrConfig(tt.host)
+			assert.Equal(t, tt.wantToken, token)
+			assert.Equal(t, tt.wantSource, source)
+		})
+	}
+}
+
+func TestOptionsDefaultHost(t *testing.T) {
+	t.Parallel()
+	host, source := Options{Env: map[string]string{"GOCTL_HOST": "test.com"}, Config: testSingleHostConfig()}.DefaultHost()
+	assert.Equal(t, "test.com", host)
+	assert.Equal(t, "GOCTL_HOST", source)
+
+	host, source = Options{Config: testSingleHostConfig()}.DefaultHost()
+	assert.Equal(t, "enterprise.com", host)
+	assert.Equal(t, "hosts", source)
+
+	host, source = Options{}.DefaultHost()
+	assert.Equal(t, "github.com", host)
+	assert.Equal(t, "default", source)
+}
+
+func TestOptionsKnownHosts(t *testing.T) {
+	t.Parallel()
+	hosts := Options{
+		Env:    map[string]string{"GOCTL_HOST": "test.com", "GITHUB_TOKEN": "token"},
+		Config: testSingleHostConfig(),
+	}.KnownHosts()
+	assert.Equal(t, []string{"test.com", "github.com", "enterprise.com"}, hosts)
+	assert.Equal(t, []string{}, Options{}.KnownHosts())
+}
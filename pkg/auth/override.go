@@ -0,0 +1,43 @@
+package auth
+
+import "sync"
+
+var (
+	overrideMu sync.RWMutex
+	override   func(host string) (string, string)
+)
+
+// WithTokenForHost makes TokenForHost and TokenFromEnvOrConfig return the token and source
+// returned by fn instead of consulting environment variables, the configuration file, or
+// the system keyring. This lets tests and applications embedding go-goctl supply credentials
+// programmatically. The override applies to the whole process, including API clients
+// created without an explicit AuthToken, until the returned function is called to restore
+// the previous behavior:
+//
+//	t.Cleanup(auth.WithTokenForHost(func(host string) (string, string) {
+//		return "TOKEN", "test"
+//	}))
+func WithTokenForHost(fn func(host string) (string, string)) func() {
+	overrideMu.Lock()
+	defer overrideMu.Unlock()
+	previous := override
+	override = fn
+	return func() {
+		overrideMu.Lock()
+		defer overrideMu.Unlock()
+		override = previous
+	}
+}
+
+// overriddenToken returns the token and source for host from the override set with
+// WithTokenForHost, if any.
+func overriddenToken(host string) (string, string, bool) {
+	overrideMu.RLock()
+	fn := override
+	overrideMu.RUnlock()
+	if fn == nil {
+		return "", "", false
+	}
+	token, source := fn(host)
+	return token, source, true
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTokenForHost(t *testing.T) {
+	t.Setenv("GOCTL_TOKEN", "ENV_TOKEN")
+
+	restore := WithTokenForHost(func(host string) (string, string) {
+		if host == "github.com" {
+			return "OVERRIDE_TOKEN", "test"
+		}
+		return "", "test"
+	})
+	token, source := TokenForHost("github.com")
+	assert.Equal(t, "OVERRIDE_TOKEN", token)
+	assert.Equal(t, "test", source)
+	token, source = TokenFromEnvOrConfig("enterprise.com")
+	assert.Equal(t, "", token)
+	assert.Equal(t, "test", source)
+
+	restoreInner := WithTokenForHost(func(host string) (string, string) {
+		return "INNER_TOKEN", "inner"
+	})
+	token, _ = TokenForHost("github.com")
+	assert.Equal(t, "INNER_TOKEN", token)
+	restoreInner()
+	token, _ = TokenForHost("github.com")
+	assert.Equal(t, "OVERRIDE_TOKEN", token)
+
+	restore()
+	token, source = TokenFromEnvOrConfig("github.com")
+	assert.Equal(t, "ENV_TOKEN", token)
+	assert.Equal(t, "GOCTL_TOKEN", source)
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+)
+
+// ErrKeyringUnavailable is returned by the keyring TokenStore when the operating system
+// provides no credential store that can be used, such as on a headless Linux machine
+// without a Secret Service provider.
+var ErrKeyringUnavailable = errors.New("system keyring is not available")
+
+// keyringServicePrefix is prepended to the host to name the keyring entry of its token,
+// so that tokens stored by goctl itself are shared.
+const keyringServicePrefix = "goctl:"
+
+// TokenStore persists the authentication tokens of hosts.
+type TokenStore interface {
+	// Token returns the token stored for host, or an error wrapping ErrTokenNotFound.
+	Token(host string) (string, error)
+	// SetToken stores the token for host, replacing any existing token.
+	SetToken(host, token string) error
+	// DeleteToken removes the token of host. It succeeds if no token is stored.
+	DeleteToken(host string) error
+}
+
+// keyring is the credential store of the operating system. Secrets are identified by a
+// service and a user name.
+type keyring interface {
+	get(service, user string) (string, error)
+	set(service, user, secret string) error
+	delete(service, user string) error
+}
+
+// errKeyringNotFound is returned by keyring implementations when no secret is stored.
+var errKeyringNotFound = errors.New("secret not found in keyring")
+
+// systemKeyring is replaced in tests.
+var systemKeyring keyring = osKeyring{}
+
+// KeyringStore returns a TokenStore backed by the credential store of the operating
+// system: the Keychain on macOS, the Credential Manager on Windows, and a Secret Service
+// provider such as GNOME Keyring, accessed with secret-tool, on other systems.
+func KeyringStore() TokenStore {
+	return keyringStore{keyring: systemKeyring}
+}
+
+type keyringStore struct {
+	keyring keyring
+}
+
+func (s keyringStore) Token(host string) (string, error) {
+	host = normalizeHostname(host)
+	token, err := s.keyring.get(keyringServicePrefix+host, "")
+	if errors.Is(err, errKeyringNotFound) || (err == nil && token == "") {
+		return "", fmt.Errorf("%w for host %s in keyring", ErrTokenNotFound, host)
+	}
+	return token, err
+}
+
+func (s keyringStore) SetToken(host, token string) error {
+	return s.keyring.set(keyringServicePrefix+normalizeHostname(host), "", token)
+}
+
+func (s keyringStore) DeleteToken(host string) error {
+	err := s.keyring.delete(keyringServicePrefix+normalizeHostname(host), "")
+	if errors.Is(err, errKeyringNotFound) {
+		return nil
+	}
+	return err
+}
+
+// ConfigStore returns a TokenStore that keeps tokens in plain text in the hosts
+// configuration file of cfg. Changes are written to disk immediately.
+func ConfigStore(cfg *config.Config) TokenStore {
+	return configStore{cfg: cfg}
+}
+
+type configStore struct {
+	cfg *config.Config
+}
+
+func (s configStore) Token(host string) (string, error) {
+	host = normalizeHostname(host)
+	token, err := s.cfg.Get([]string{hostsKey, host, oauthToken})
+	if err != nil || token == "" {
+		return "", fmt.Errorf("%w for host %s in config", ErrTokenNotFound, host)
+	}
+	return token, nil
+}
+
+func (s configStore) SetToken(host, token string) error {
+	s.cfg.Set([]string{hostsKey, normalizeHostname(host), oauthToken}, token)
+	return config.Write(s.cfg)
+}
+
+func (s configStore) DeleteToken(host string) error {
+	var keyNotFound *config.KeyNotFoundError
+	err := s.cfg.Remove([]string{hostsKey, normalizeHostname(host), oauthToken})
+	if errors.As(err, &keyNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return config.Write(s.cfg)
+}
+
+// NewTokenStore returns a TokenStore that keeps tokens in the system keyring, and falls
+// back to the hosts configuration file of cfg when the keyring is unavailable. Tokens
+// found in either are returned, and storing a token in the keyring removes any plain
+// text copy from the configuration.
+func NewTokenStore(cfg *config.Config) TokenStore {
+	return fallbackStore{primary: KeyringStore(), fallback: ConfigStore(cfg)}
+}
+
+type fallbackStore struct {
+	primary  TokenStore
+	fallback TokenStore
+}
+
+func (s fallbackStore) Token(host string) (string, error) {
+	token, err := s.primary.Token(host)
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, ErrTokenNotFound) && !errors.Is(err, ErrKeyringUnavailable) {
+		return "", err
+	}
+	return s.fallback.Token(host)
+}
+
+func (s fallbackStore) SetToken(host, token string) error {
+	err := s.primary.SetToken(host, token)
+	if errors.Is(err, ErrKeyringUnavailable) {
+		return s.fallback.SetToken(host, token)
+	} else if err != nil {
+		return err
+	}
+	return s.fallback.DeleteToken(host)
+}
+
+func (s fallbackStore) DeleteToken(host string) error {
+	if err := s.primary.DeleteToken(host); err != nil && !errors.Is(err, ErrKeyringUnavailable) {
+		return err
+	}
+	return s.fallback.DeleteToken(host)
+}
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKeyring struct {
+	secrets map[string]string
+	err     error
+}
+
+func (k *fakeKeyring) get(service, user string) (string, error) {
+	if k.err != nil {
+		return "", k.err
+	}
+	secret, ok := k.secrets[service+":"+user]
+	if !ok {
+		return "", errKeyringNotFound
+	}
+	return secret, nil
+}
+
+func (k *fakeKeyring) set(service, user, secret string) error {
+	if k.err != nil {
+		return k.err
+	}
+	k.secrets[service+":"+user] = secret
+	return nil
+}
+
+func (k *fakeKeyring) delete(service, user string) error {
+	if k.err != nil {
+		return k.err
+	}
+	if _, ok := k.secrets[service+":"+user]; !ok {
+		return errKeyringNotFound
+	}
+	delete(k.secrets, service+":"+user)
+	return nil
+}
+
+func stubKeyring(t *testing.T, k keyring) {
+	orig := systemKeyring
+	systemKeyring = k
+	t.Cleanup(func() { systemKeyring = orig })
+}
+
+func stubConfigDir(t *testing.T, hosts string) *config.Config {
+	dir := t.TempDir()
+	t.Setenv("GOCTL_CONFIG_DIR", dir)
+	return config.ReadFromString(hosts)
+}
+
+func readHostsFile(t *testing.T) string {
+	data, err := os.ReadFile(filepath.Join(os.Getenv("GOCTL_CONFIG_DIR"), "hosts.yml"))
+	if errors.Is(err, os.ErrNotExist) {
+		return ""
+	}
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestKeyringStore(t *testing.T) {
+	k := &fakeKeyring{secrets: map[string]string{}}
+	stubKeyring(t, k)
+	store := KeyringStore()
+
+	_, err := store.Token("github.com")
+	assert.ErrorIs(t, err, ErrTokenNotFound)
+	assert.EqualError(t, err, "authentication token not found for host github.com in keyring")
+
+	require.NoError(t, store.SetToken("GitHub.com", "gho_keyring"))
+	assert.Equal(t, map[string]string{"goctl:github.com:": "gho_keyring"}, k.secrets)
+	token, err := store.Token("github.com")
+	require.NoError(t, err)
+	assert.Equal(t, "gho_keyring", token)
+
+	require.NoError(t, store.DeleteToken("github.com"))
+	require.NoError(t, store.DeleteToken("github.com"))
+	assert.Empty(t, k.secrets)
+}
+
+func TestConfigStore(t *testing.T) {
+	cfg := stubConfigDir(t, "hosts:\n  ghe.io:\n    user: monalisa\n")
+	store := ConfigStore(cfg)
+
+	_, err := store.Token("ghe.io")
+	assert.ErrorIs(t, err, ErrTokenNotFound)
+
+	require.NoError(t, store.SetToken("ghe.io", "gho_config"))
+	token, err := store.Token("ghe.io")
+	require.NoError(t, err)
+	assert.Equal(t, "gho_config", token)
+	assert.Contains(t, readHostsFile(t), "oauth_token: gho_config")
+
+	require.NoError(t, store.DeleteToken("ghe.io"))
+	require.NoError(t, store.DeleteToken("ghe.io"))
+	assert.NotContains(t, readHostsFile(t), "oauth_token")
+	assert.Contains(t, readHostsFile(t), "user: monalisa")
+}
+
+func TestNewTokenStore(t *testing.T) {
+	t.Run("keyring available", func(t *testing.T) {
+		k := &fakeKeyring{secrets: map[string]string{}}
+		stubKeyring(t, k)
+		cfg := stubConfigDir(t, "hosts:\n  github.com:\n    oauth_token: gho_plaintext\n")
+		store := NewTokenStore(cfg)
+
+		token, err := store.Token("github.com")
+		require.NoError(t, err)
+		assert.Equal(t, "gho_plaintext", token)
+
+		require.NoError(t, store.SetToken("github.com", "gho_secure"))
+		assert.Equal(t, "gho_secure", k.secrets["goctl:github.com:"])
+		assert.NotContains(t, readHostsFile(t), "gho_plaintext")
+		token, err = store.Token("github.com")
+		require.NoError(t, err)
+		assert.Equal(t, "gho_secure", token)
+
+		require.NoError(t, store.DeleteToken("github.com"))
+		_, err = store.Token("github.com")
+		assert.ErrorIs(t, err, ErrTokenNotFound)
+	})
+
+	t.Run("keyring unavailable", func(t *testing.T) {
+		stubKeyring(t, &fakeKeyring{err: ErrKeyringUnavailable})
+		cfg := stubConfigDir(t, "")
+		store := NewTokenStore(cfg)
+
+		require.NoError(t, store.SetToken("github.com", "gho_plaintext"))
+		assert.Contains(t, readHostsFile(t), "oauth_token: gho_plaintext")
+		token, err := store.Token("github.com")
+		require.NoError(t, err)
+		assert.Equal(t, "gho_plaintext", token)
+		require.NoError(t, store.DeleteToken("github.com"))
+		assert.NotContains(t, readHostsFile(t), "gho_plaintext")
+	})
+
+	t.Run("keyring failure", func(t *testing.T) {
+		stubKeyring(t, &fakeKeyring{err: errors.New("keychain locked")})
+		cfg := stubConfigDir(t, "hosts:\n  github.com:\n    oauth_token: gho_plaintext\n")
+		store := NewTokenStore(cfg)
+
+		_, err := store.Token("github.com")
+		assert.EqualError(t, err, "keychain locked")
+		assert.EqualError(t, store.SetToken("github.com", "gho_secure"), "keychain locked")
+		assert.Equal(t, "gho_plaintext", cfgToken(cfg))
+	})
+}
+
+func cfgToken(cfg *config.Config) string {
+	token, _ := cfg.Get([]string{hostsKey, "github.com", oauthToken})
+	return token
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+)
+
+// TokenSource provides authentication tokens that may change over time, such as
+// installation tokens that expire after an hour. API clients and goctl subprocesses
+// created with a TokenSource call it whenever they need a token, so that credentials
+// can be rotated without recreating the clients.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f(ctx).
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticTokenSource returns a TokenSource that always provides token.
+func StaticTokenSource(token string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// EnvTokenSource returns a TokenSource that reads the token of host from the same
+// environment variables as TokenForHost each time a token is needed. It returns an error
+// wrapping ErrTokenNotFound if none of them is set.
+func EnvTokenSource(host string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		// An empty config keeps tokenForHost from falling back to GOCTL_TOKEN for
+		// enterprise hosts.
+		if token, _ := tokenForHost(os.Getenv, config.ReadFromString(""), host); token != "" {
+			return token, nil
+		}
+		return "", fmt.Errorf("%w for host %s", ErrTokenNotFound, host)
+	})
+}
+
+// HostTokenSource returns a TokenSource that looks up the token of host with
+// TokenForHostContext each time a token is needed, so that tokens changed with "goctl auth
+// login" or "goctl auth switch" are picked up. It returns an error wrapping
+// ErrTokenNotFound if no token is found.
+func HostTokenSource(host string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (string, error) {
+		if token, _ := TokenForHostContext(ctx, host); token != "" {
+			return token, nil
+		}
+		return "", fmt.Errorf("%w for host %s", ErrTokenNotFound, host)
+	})
+}
+
+// StoreTokenSource returns a TokenSource that reads the token of host from store each time
+// a token is needed, such as the config file with ConfigStore or the system keyring with
+// KeyringStore.
+func StoreTokenSource(store TokenStore, host string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (string, error) {
+		return store.Token(host)
+	})
+}
+
+// RefreshTokenSource returns a TokenSource for an expiring user access token of a GitHub
+// App, such as one obtained with DeviceFlow. Shortly before the token expires it is
+// exchanged for a new one with its refresh token. The client secret of the app may be
+// empty if the app does not require it. The options are those of DeviceFlow; if a token
+// store is set, refreshed tokens are saved in it.
+func RefreshTokenSource(host, clientID, clientSecret string, token *OAuthToken, opts ...deviceFlowOption) TokenSource {
+	f := &deviceFlow{
+		client: http.DefaultClient,
+		clock:  clock.Real,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return &refreshTokenSource{
+		flow:         f,
+		host:         normalizeHostname(host),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		token:        *token,
+	}
+}
+
+type refreshTokenSource struct {
+	flow         *deviceFlow
+	host         string
+	clientID     string
+	clientSecret string
+
+	mu    sync.Mutex
+	token OAuthToken
+}
+
+func (s *refreshTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.ExpiresAt.IsZero() || s.flow.clock.Now().Add(tokenRefreshMargin).Before(s.token.ExpiresAt) {
+		return s.token.Token, nil
+	}
+	if s.token.RefreshToken == "" {
+		return "", errors.New("failed to refresh token: no refresh token")
+	}
+	form := url.Values{
+		"client_id":     {s.clientID},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {s.token.RefreshToken},
+	}
+	if s.clientSecret != "" {
+		form.Set("client_secret", s.clientSecret)
+	}
+	var resp accessTokenResponse
+	if err := s.flow.post(ctx, s.host, "login/oauth/access_token", form, &resp); err != nil {
+		return "", fmt.Errorf("failed to refresh token: %w", err)
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf("failed to refresh token: %s: %s", resp.Error, resp.Description)
+	}
+	s.token = *resp.token(s.flow.clock.Now())
+	if s.flow.store != nil {
+		if err := s.flow.store.SetToken(s.host, s.token.Token); err != nil {
+			return s.token.Token, fmt.Errorf("failed to store token: %w", err)
+		}
+	}
+	return s.token.Token, nil
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvTokenSource(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		env       map[string]string
+		wantToken string
+		wantErr   string
+	}{
+		{
+			name:      "GOCTL_TOKEN",
+			host:      "github.com",
+			env:       map[string]string{goctlToken: "goctl_token"},
+			wantToken: "goctl_token",
+		},
+		{
+			name:      "GOCTL_ENTERPRISE_TOKEN",
+			host:      "ghe.io",
+			env:       map[string]string{goctlToken: "goctl_token", goctlEnterpriseToken: "enterprise_token"},
+			wantToken: "enterprise_token",
+		},
+		{
+			name:    "GOCTL_TOKEN is not used for enterprise hosts",
+			host:    "ghe.io",
+			env:     map[string]string{goctlToken: "goctl_token"},
+			wantErr: "authentication token not found for host ghe.io",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{codespaces, goctlEnterpriseToken, goctlToken, githubEnterpriseToken, githubToken} {
+				t.Setenv(name, tt.env[name])
+			}
+			token, err := EnvTokenSource(tt.host).Token(context.Background())
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.True(t, errors.Is(err, ErrTokenNotFound))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantToken, token)
+		})
+	}
+}
+
+func TestEnvTokenSourceRotation(t *testing.T) {
+	t.Setenv(goctlToken, "first")
+	ts := EnvTokenSource("github.com")
+	token, err := ts.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "first", token)
+
+	t.Setenv(goctlToken, "second")
+	token, err = ts.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "second", token)
+}
+
+func TestStoreTokenSource(t *testing.T) {
+	cfg := config.ReadFromString("hosts:\n  github.com:\n    oauth_token: config_token\n")
+	ts := StoreTokenSource(ConfigStore(cfg), "github.com")
+	token, err := ts.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "config_token", token)
+
+	_, err = StoreTokenSource(ConfigStore(cfg), "ghe.io").Token(context.Background())
+	assert.True(t, errors.Is(err, ErrTokenNotFound))
+}
+
+func TestRefreshTokenSource(t *testing.T) {
+	srv := &deviceFlowServer{polls: []map[string]interface{}{
+		{"access_token": "ghu_second", "token_type": "bearer", "expires_in": 28800, "refresh_token": "ghr_second"},
+		{"error": "bad_refresh_token", "error_description": "The refresh token passed is incorrect or expired."},
+	}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	target, _ := url.Parse(ts.URL)
+	fake := clock.NewFake(time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC))
+	k := &fakeKeyring{secrets: map[string]string{}}
+
+	source := RefreshTokenSource("github.com", "client123", "", &OAuthToken{
+		Token:        "ghu_first",
+		RefreshToken: "ghr_first",
+		ExpiresAt:    fake.Now().Add(8 * time.Hour),
+	},
+		WithDeviceFlowHTTPClient(&http.Client{Transport: redirectTransport{target: target}}),
+		WithDeviceFlowClock(fake),
+		WithTokenStore(keyringStore{keyring: k}))
+	ctx := context.Background()
+
+	token, err := source.Token(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "ghu_first", token)
+	assert.Empty(t, srv.requests)
+
+	fake.Advance(7*time.Hour + 56*time.Minute)
+	token, err = source.Token(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "ghu_second", token)
+	assert.Equal(t, []string{
+		"github.com/login/oauth/access_token client_id=client123&grant_type=refresh_token&refresh_token=ghr_first",
+	}, srv.requests)
+	assert.Equal(t, "ghu_second", k.secrets["goctl:github.com:"])
+
+	fake.Advance(8 * time.Hour)
+	_, err = source.Token(ctx)
+	assert.EqualError(t, err, "failed to refresh token: bad_refresh_token: The refresh token passed is incorrect or expired.")
+}
+
+func TestRefreshTokenSourceWithoutExpiry(t *testing.T) {
+	source := RefreshTokenSource("github.com", "client123", "", &OAuthToken{Token: "gho_token"})
+	token, err := source.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "gho_token", token)
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+)
+
+const (
+	keyringSource = "keyring"
+	userKey       = "user"
+	usersKey      = "users"
+)
+
+// Users returns the accounts logged in to host, as listed under the "users" key of the
+// host in the configuration file. Configuration written by goctl versions that support a
+// single account per host yields its only user.
+func Users(host string) []string {
+	cfg, _ := config.Read(nil)
+	return users(cfg, host)
+}
+
+func users(cfg *config.Config, host string) []string {
+	if cfg == nil {
+		return nil
+	}
+	host = normalizeHostname(host)
+	if names, err := cfg.Keys([]string{hostsKey, host, usersKey}); err == nil && len(names) > 0 {
+		return names
+	}
+	if user, err := cfg.Get([]string{hostsKey, host, userKey}); err == nil && user != "" {
+		return []string{user}
+	}
+	return nil
+}
+
+// ActiveUser returns the account that goctl and TokenForHost use for host.
+func ActiveUser(host string) (string, error) {
+	cfg, _ := config.Read(nil)
+	return activeUser(cfg, host)
+}
+
+func activeUser(cfg *config.Config, host string) (string, error) {
+	host = normalizeHostname(host)
+	if cfg != nil {
+		if user, err := cfg.Get([]string{hostsKey, host, userKey}); err == nil && user != "" {
+			return user, nil
+		}
+	}
+	return "", fmt.Errorf("%w for host %s", ErrUserNotFound, host)
+}
+
+// SwitchUser makes user the active account of host, so that its token is returned by
+// TokenForHost and used by goctl. The user must be logged in to host. The change is
+// written to the configuration file, and to the system keyring if the token is stored
+// there.
+func SwitchUser(host, user string) error {
+	cfg, err := config.Read(nil)
+	if err != nil {
+		return err
+	}
+	return switchUser(cfg, systemKeyring, host, user)
+}
+
+func switchUser(cfg *config.Config, kr keyring, host, user string) error {
+	host = normalizeHostname(host)
+	if !hasUser(users(cfg, host), user) {
+		return fmt.Errorf("%w for host %s: %s", ErrUserNotFound, host, user)
+	}
+	if token, _ := cfg.Get([]string{hostsKey, host, usersKey, user, oauthToken}); token != "" {
+		cfg.Set([]string{hostsKey, host, oauthToken}, token)
+	} else if active, _ := activeUser(cfg, host); active != user {
+		// The token is in the keyring, where the active token of the host is kept in
+		// the entry without a user name.
+		token, err := kr.get(keyringServicePrefix+host, user)
+		if err != nil {
+			return fmt.Errorf("failed to read token of %s from keyring: %w", user, err)
+		}
+		if err := kr.set(keyringServicePrefix+host, "", token); err != nil {
+			return fmt.Errorf("failed to activate token of %s in keyring: %w", user, err)
+		}
+		_ = cfg.Remove([]string{hostsKey, host, oauthToken})
+	}
+	cfg.Set([]string{hostsKey, host, userKey}, user)
+	return config.Write(cfg)
+}
+
+// TokenForHostAndUser retrieves the authentication token of a specific account of host,
+// and its source, from the configuration file or the system keyring. Unlike TokenForHost,
+// environment variables are not consulted because they do not belong to an account.
+//
+// Returns "", "default" if no token is found for the user.
+func TokenForHostAndUser(host, user string) (string, string) {
+	cfg, _ := config.Read(nil)
+	return tokenForHostAndUser(cfg, systemKeyring, host, user)
+}
+
+func tokenForHostAndUser(cfg *config.Config, kr keyring, host, user string) (string, string) {
+	host = normalizeHostname(host)
+	if cfg != nil {
+		if token, _ := cfg.Get([]string{hostsKey, host, usersKey, user, oauthToken}); token != "" {
+			return token, oauthToken
+		}
+	}
+	if token, err := kr.get(keyringServicePrefix+host, user); err == nil && token != "" {
+		return token, keyringSource
+	}
+	if active, err := activeUser(cfg, host); err == nil && active == user {
+		if token, _ := cfg.Get([]string{hostsKey, host, oauthToken}); token != "" {
+			return token, oauthToken
+		}
+		if token, err := kr.get(keyringServicePrefix+host, ""); err == nil && token != "" {
+			return token, keyringSource
+		}
+	}
+	return "", defaultSource
+}
+
+func hasUser(users []string, user string) bool {
+	for _, u := range users {
+		if u == user {
+			return true
+		}
+	}
+	return false
+}
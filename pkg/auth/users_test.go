@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const multiAccountHosts = `hosts:
+  github.com:
+    git_protocol: https
+    users:
+      monalisa:
+        oauth_token: gho_monalisa
+      hubot:
+    user: monalisa
+    oauth_token: gho_monalisa
+  ghe.io:
+    user: octocat
+    oauth_token: gho_octocat
+`
+
+func TestUsers(t *testing.T) {
+	cfg := stubConfigDir(t, multiAccountHosts)
+	assert.Equal(t, []string{"monalisa", "hubot"}, users(cfg, "GitHub.com"))
+	assert.Equal(t, []string{"octocat"}, users(cfg, "ghe.io"))
+	assert.Nil(t, users(cfg, "example.com"))
+	assert.Nil(t, users(nil, "github.com"))
+}
+
+func TestActiveUser(t *testing.T) {
+	cfg := stubConfigDir(t, multiAccountHosts)
+	user, err := activeUser(cfg, "github.com")
+	require.NoError(t, err)
+	assert.Equal(t, "monalisa", user)
+
+	_, err = activeUser(cfg, "example.com")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.EqualError(t, err, "user not found for host example.com")
+}
+
+func TestTokenForHostAndUser(t *testing.T) {
+	cfg := stubConfigDir(t, multiAccountHosts)
+	k := &fakeKeyring{secrets: map[string]string{"goctl:github.com:hubot": "gho_hubot"}}
+
+	tests := []struct {
+		host       string
+		user       string
+		wantToken  string
+		wantSource string
+	}{
+		{host: "github.com", user: "monalisa", wantToken: "gho_monalisa", wantSource: "oauth_token"},
+		{host: "github.com", user: "hubot", wantToken: "gho_hubot", wantSource: "keyring"},
+		{host: "ghe.io", user: "octocat", wantToken: "gho_octocat", wantSource: "oauth_token"},
+		{host: "github.com", user: "octocat", wantToken: "", wantSource: "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host+"/"+tt.user, func(t *testing.T) {
+			token, source := tokenForHostAndUser(cfg, k, tt.host, tt.user)
+			assert.Equal(t, tt.wantToken, token)
+			assert.Equal(t, tt.wantSource, source)
+		})
+	}
+}
+
+func TestSwitchUser(t *testing.T) {
+	t.Run("token in keyring", func(t *testing.T) {
+		cfg := stubConfigDir(t, multiAccountHosts)
+		k := &fakeKeyring{secrets: map[string]string{"goctl:github.com:hubot": "gho_hubot"}}
+
+		require.NoError(t, switchUser(cfg, k, "github.com", "hubot"))
+		user, _ := activeUser(cfg, "github.com")
+		assert.Equal(t, "hubot", user)
+		assert.Equal(t, "gho_hubot", k.secrets["goctl:github.com:"])
+		assert.Equal(t, "", cfgToken(cfg))
+		assert.Contains(t, readHostsFile(t), "user: hubot")
+	})
+
+	t.Run("token in config", func(t *testing.T) {
+		cfg := stubConfigDir(t, multiAccountHosts)
+		k := &fakeKeyring{secrets: map[string]string{"goctl:github.com:hubot": "gho_hubot"}}
+		require.NoError(t, switchUser(cfg, k, "github.com", "hubot"))
+
+		require.NoError(t, switchUser(cfg, k, "github.com", "monalisa"))
+		user, _ := activeUser(cfg, "github.com")
+		assert.Equal(t, "monalisa", user)
+		assert.Equal(t, "gho_monalisa", cfgToken(cfg))
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		cfg := stubConfigDir(t, multiAccountHosts)
+		err := switchUser(cfg, &fakeKeyring{secrets: map[string]string{}}, "github.com", "octocat")
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		assert.EqualError(t, err, "user not found for host github.com: octocat")
+		assert.Equal(t, "", readHostsFile(t))
+	})
+
+	t.Run("token missing from keyring", func(t *testing.T) {
+		cfg := stubConfigDir(t, multiAccountHosts)
+		err := switchUser(cfg, &fakeKeyring{secrets: map[string]string{}}, "github.com", "hubot")
+		assert.EqualError(t, err, "failed to read token of hubot from keyring: secret not found in keyring")
+		user, _ := activeUser(cfg, "github.com")
+		assert.Equal(t, "monalisa", user)
+	})
+}
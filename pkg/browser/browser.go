@@ -2,9 +2,13 @@
 package browser
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"runtime"
+	"strings"
 
 	cliBrowser "github.com/khulnasoft-lab/browser"
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
@@ -14,11 +18,14 @@ import (
 
 // Browser represents a web browser that can be used to open up URLs.
 type Browser struct {
-	launcher string
-	stderr   io.Writer
-	stdout   io.Writer
+	launcher          string
+	stderr            io.Writer
+	stdout            io.Writer
+	clipboardFallback bool
 }
 
+type option func(*Browser)
+
 // New initializes a Browser. If a launcher is not specified
 // one is determined based on environment variables or from the
 // configuration file.
@@ -27,7 +34,14 @@ type Browser struct {
 // - GOCTL_BROWSER environment variable;
 // - browser option from configuration file;
 // - BROWSER environment variable.
-func New(launcher string, stdout, stderr io.Writer) *Browser {
+//
+// A launcher is a command line that is passed the URL as its last argument,
+// or in place of a "%s" placeholder if it contains one. The special launcher
+// "echo" prints the URL to stdout instead of opening it, which is useful in
+// SSH sessions and other environments without a browser. Without a launcher
+// the default browser of the operating system is used, which on the
+// Windows Subsystem for Linux is the default browser of the Windows host.
+func New(launcher string, stdout, stderr io.Writer, opts ...option) *Browser {
 	if launcher == "" {
 		launcher = resolveLauncher()
 	}
@@ -36,27 +50,56 @@ func New(launcher string, stdout, stderr io.Writer) *Browser {
 		stderr:   stderr,
 		stdout:   stdout,
 	}
+	for _, opt := range opts {
+		opt(b)
+	}
 	return b
 }
 
-// Browse opens the launcher and navigates to the specified URL.
+// Browse opens the launcher and navigates to the specified URL. With the
+// clipboard fallback enabled, a URL that cannot be opened is copied to the
+// clipboard instead and a notice is printed to stderr.
 func (b *Browser) Browse(url string) error {
-	return b.browse(url, nil)
+	fallback, err := b.browseWithFallback(url, nil)
+	if err == nil && fallback != NoFallback {
+		fmt.Fprintf(b.stderr, "Could not open a browser; copied %s to the clipboard.\n", url)
+	}
+	return err
 }
 
 func (b *Browser) browse(url string, env []string) error {
-	if b.launcher == "" {
-		return cliBrowser.OpenURL(url)
+	launcher := b.launcher
+	if launcher == "" {
+		if !isWSL() {
+			return cliBrowser.OpenURL(url)
+		}
+		if launcher = wslLauncher(); launcher == "" {
+			return errors.New("unable to open a browser from WSL: install wslu or set the BROWSER environment variable")
+		}
+	}
+	if launcher == "echo" {
+		_, err := fmt.Fprintln(b.stdout, url)
+		return err
 	}
-	launcherArgs, err := shlex.Split(b.launcher)
+	launcherArgs, err := shlex.Split(launcher)
 	if err != nil {
 		return err
 	}
-	launcherExe, err := safeexec.LookPath(launcherArgs[0])
+	if len(launcherArgs) == 0 {
+		return cliBrowser.OpenURL(url)
+	}
+	launcherExe, err := execsafer.LookPath(launcherArgs[0])
 	if err != nil {
 		return err
 	}
-	args := append(launcherArgs[1:], url)
+	args := launcherArgs[1:]
+	if strings.Contains(launcher, "%s") {
+		for i, arg := range args {
+			args[i] = strings.ReplaceAll(arg, "%s", url)
+		}
+	} else {
+		args = append(args, url)
+	}
 	cmd := exec.Command(launcherExe, args...)
 	cmd.Stdout = b.stdout
 	cmd.Stderr = b.stderr
@@ -66,6 +109,38 @@ func (b *Browser) browse(url string, env []string) error {
 	return cmd.Run()
 }
 
+// wslLaunchers are tried in order to open URLs in the browser of the Windows host
+// from the Windows Subsystem for Linux, where xdg-open usually cannot open a browser.
+var wslLaunchers = []string{
+	"wslview",
+	"rundll32.exe url.dll,FileProtocolHandler",
+}
+
+func wslLauncher() string {
+	for _, l := range wslLaunchers {
+		args, err := shlex.Split(l)
+		if err != nil || len(args) == 0 {
+			continue
+		}
+		if _, err := execsafer.LookPath(args[0]); err == nil {
+			return l
+		}
+	}
+	return ""
+}
+
+// isWSL reports whether the program is running in the Windows Subsystem for Linux.
+var isWSL = func() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(release)), "microsoft")
+}
+
 func resolveLauncher() string {
 	if goctlBrowser := os.Getenv("GOCTL_BROWSER"); goctlBrowser != "" {
 		return goctlBrowser
@@ -29,6 +29,43 @@ func TestBrowse(t *testing.T) {
 	assert.Equal(t, "", stderr.String())
 }
 
+func TestBrowsePlaceholder(t *testing.T) {
+	launcher := fmt.Sprintf("%q -test.run=TestHelperProcess -- chrome --url=%%s --new-window", os.Args[0])
+	stdout := &bytes.Buffer{}
+	b := Browser{launcher: launcher, stdout: stdout, stderr: &bytes.Buffer{}}
+	err := b.browse("github.com", []string{"GOCTL_WANT_HELPER_PROCESS=1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "[chrome --url=github.com --new-window]", stdout.String())
+}
+
+func TestBrowseEcho(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	b := New("echo", stdout, &bytes.Buffer{})
+	err := b.Browse("https://github.com/login/device")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/login/device\n", stdout.String())
+}
+
+func TestBrowseWSL(t *testing.T) {
+	oldIsWSL := isWSL
+	isWSL = func() bool { return true }
+	defer func() { isWSL = oldIsWSL }()
+	oldLaunchers := wslLaunchers
+	defer func() { wslLaunchers = oldLaunchers }()
+
+	wslLaunchers = []string{"goctl-missing-wslview"}
+	b := Browser{stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}}
+	err := b.browse("github.com", nil)
+	assert.EqualError(t, err, "unable to open a browser from WSL: install wslu or set the BROWSER environment variable")
+
+	wslLaunchers = []string{"goctl-missing-wslview", fmt.Sprintf("%q -test.run=TestHelperProcess -- wslview", os.Args[0])}
+	stdout := &bytes.Buffer{}
+	b = Browser{stdout: stdout, stderr: &bytes.Buffer{}}
+	err = b.browse("github.com", []string{"GOCTL_WANT_HELPER_PROCESS=1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "[wslview github.com]", stdout.String())
+}
+
 func TestResolveLauncher(t *testing.T) {
 	tests := []struct {
 		name         string
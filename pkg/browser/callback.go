@@ -0,0 +1,95 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+const callbackResponse = "<!DOCTYPE html><html><body><p>Authentication complete. You may now close this tab and return to the terminal.</p></body></html>"
+
+// CallbackListener listens on the loopback interface for a single request redirected
+// back from a web page, such as the redirect at the end of the OAuth web flow.
+type CallbackListener struct {
+	listener net.Listener
+	server   *http.Server
+	path     string
+	once     sync.Once
+	result   chan url.Values
+}
+
+// ListenCallback starts listening for a request to path on a random port of the loopback
+// interface. Use URL as the redirect address and Wait to receive the query parameters
+// of the request. The listener must be closed when no longer needed.
+func ListenCallback(path string) (*CallbackListener, error) {
+	if path == "" || path[0] != '/' {
+		path = "/" + path
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for callback: %w", err)
+	}
+	l := &CallbackListener{
+		listener: listener,
+		path:     path,
+		result:   make(chan url.Values, 1),
+	}
+	l.server = &http.Server{Handler: http.HandlerFunc(l.handle)}
+	go func() {
+		_ = l.server.Serve(listener)
+	}()
+	return l, nil
+}
+
+// URL returns the address requests are expected at, e.g. "http://127.0.0.1:50123/callback".
+func (l *CallbackListener) URL() string {
+	return fmt.Sprintf("http://%s%s", l.listener.Addr().String(), l.path)
+}
+
+// Wait blocks until a request is received and returns its query parameters. It returns
+// the error of ctx if ctx is done first, so a deadline on ctx limits how long the user
+// has to complete the flow in the browser.
+func (l *CallbackListener) Wait(ctx context.Context) (url.Values, error) {
+	select {
+	case values := <-l.result:
+		return values, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops listening.
+func (l *CallbackListener) Close() error {
+	return l.server.Close()
+}
+
+func (l *CallbackListener) handle(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != l.path {
+		http.NotFound(w, r)
+		return
+	}
+	l.once.Do(func() {
+		l.result <- r.URL.Query()
+	})
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(callbackResponse))
+}
+
+// BrowseAndWait opens target like Browse and waits for the page to redirect back to the
+// listener, returning the query parameters of the redirect. The listener is closed
+// before returning.
+func (b *Browser) BrowseAndWait(ctx context.Context, target string, l *CallbackListener) (url.Values, error) {
+	defer l.Close()
+	if err := b.Browse(target); err != nil {
+		return nil, err
+	}
+	values, err := l.Wait(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("timed out waiting for the browser to redirect back: %w", err)
+	}
+	return values, err
+}
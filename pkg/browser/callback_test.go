@@ -0,0 +1,80 @@
+package browser
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallbackListener(t *testing.T) {
+	l, err := ListenCallback("callback")
+	require.NoError(t, err)
+	defer l.Close()
+	assert.True(t, strings.HasPrefix(l.URL(), "http://127.0.0.1:"))
+	assert.True(t, strings.HasSuffix(l.URL(), "/callback"))
+
+	resp, err := http.Get(strings.TrimSuffix(l.URL(), "/callback") + "/favicon.ico")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get(l.URL() + "?code=abc123&state=xyz")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "You may now close this tab")
+
+	values, err := l.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", values.Get("code"))
+	assert.Equal(t, "xyz", values.Get("state"))
+}
+
+func TestCallbackListenerContext(t *testing.T) {
+	l, err := ListenCallback("/callback")
+	require.NoError(t, err)
+	defer l.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = l.Wait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestBrowseAndWait(t *testing.T) {
+	l, err := ListenCallback("/callback")
+	require.NoError(t, err)
+
+	stdout := &bytes.Buffer{}
+	b := New("echo", stdout, &bytes.Buffer{})
+	go func() {
+		resp, err := http.Get(l.URL() + "?code=abc123")
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	values, err := b.BrowseAndWait(context.Background(), "https://github.com/login/oauth/authorize", l)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", values.Get("code"))
+	assert.Equal(t, "https://github.com/login/oauth/authorize\n", stdout.String())
+}
+
+func TestBrowseAndWaitTimeout(t *testing.T) {
+	l, err := ListenCallback("/callback")
+	require.NoError(t, err)
+
+	b := New("echo", &bytes.Buffer{}, &bytes.Buffer{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = b.BrowseAndWait(ctx, "https://github.com/login/oauth/authorize", l)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "timed out waiting for the browser to redirect back")
+}
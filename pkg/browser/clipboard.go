@@ -0,0 +1,148 @@
+package browser
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/google/shlex"
+	"github.com/khulnasoft-lab/execsafer"
+	xterm "golang.org/x/term"
+)
+
+// Fallback identifies how a URL was handed to the user when it could not be opened in a browser.
+type Fallback int
+
+const (
+	// NoFallback means the URL was opened in a browser.
+	NoFallback Fallback = iota
+	// ClipboardFallback means the URL was copied with the clipboard command of the platform.
+	ClipboardFallback
+	// OSC52Fallback means the URL was copied with an OSC 52 escape sequence, which asks
+	// the terminal emulator to set the clipboard. This works over SSH connections.
+	OSC52Fallback
+)
+
+func (f Fallback) String() string {
+	switch f {
+	case ClipboardFallback:
+		return "clipboard"
+	case OSC52Fallback:
+		return "OSC 52"
+	default:
+		return "none"
+	}
+}
+
+// WithClipboardFallback makes a Browser copy the URL to the clipboard when it cannot be
+// opened, for example on a headless machine or in an SSH session.
+func WithClipboardFallback() option {
+	return func(b *Browser) {
+		b.clipboardFallback = true
+	}
+}
+
+// BrowseWithFallback opens the launcher and navigates to the specified URL like Browse,
+// and reports which fallback, if any, was used to hand the URL to the user. Unlike
+// Browse it does not print a notice when a fallback was used.
+func (b *Browser) BrowseWithFallback(url string) (Fallback, error) {
+	return b.browseWithFallback(url, nil)
+}
+
+func (b *Browser) browseWithFallback(url string, env []string) (Fallback, error) {
+	err := b.browse(url, env)
+	if err == nil || !b.clipboardFallback {
+		return NoFallback, err
+	}
+	fallback, copyErr := b.copyToClipboard(url, env)
+	if copyErr != nil {
+		return NoFallback, errors.Join(err, copyErr)
+	}
+	return fallback, nil
+}
+
+// copyToClipboard copies text with the clipboard command of the platform or, failing
+// that, with an OSC 52 escape sequence written to the terminal. In SSH sessions the
+// clipboard commands would copy to the clipboard of the remote machine, so OSC 52 is
+// tried first.
+func (b *Browser) copyToClipboard(text string, env []string) (Fallback, error) {
+	osc52 := func() bool {
+		if !isTerminal(b.stderr) {
+			return false
+		}
+		_, err := io.WriteString(b.stderr, osc52Sequence(text))
+		return err == nil
+	}
+	remote := os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+	if remote && osc52() {
+		return OSC52Fallback, nil
+	}
+	if copyWithCommand(text, env) {
+		return ClipboardFallback, nil
+	}
+	if !remote && osc52() {
+		return OSC52Fallback, nil
+	}
+	return NoFallback, errors.New("unable to copy the URL to the clipboard")
+}
+
+func copyWithCommand(text string, env []string) bool {
+	for _, c := range clipboardCommands() {
+		args, err := shlex.Split(c)
+		if err != nil || len(args) == 0 {
+			continue
+		}
+		exe, err := execsafer.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(exe, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if env != nil {
+			cmd.Env = env
+		}
+		if cmd.Run() == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// osc52Sequence returns the escape sequence setting the clipboard to text. Inside tmux
+// the sequence is wrapped so that tmux passes it through to the outer terminal.
+func osc52Sequence(text string) string {
+	seq := fmt.Sprintf("\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	if os.Getenv("TMUX") != "" {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	return seq
+}
+
+// clipboardCommands returns the command lines that are tried in order to copy text
+// from their standard input to the clipboard.
+var clipboardCommands = func() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"pbcopy"}
+	case "windows":
+		return []string{"clip.exe"}
+	}
+	var cmds []string
+	if isWSL() {
+		cmds = append(cmds, "clip.exe")
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, "wl-copy")
+	}
+	return append(cmds, "xclip -selection clipboard", "xsel --clipboard --input")
+}
+
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && xterm.IsTerminal(int(f.Fd()))
+}
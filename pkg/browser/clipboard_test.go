@@ -0,0 +1,141 @@
+package browser
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClipboardHelperProcess(t *testing.T) {
+	if os.Getenv("GOCTL_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	data, _ := io.ReadAll(os.Stdin)
+	if err := os.WriteFile(os.Getenv("GOCTL_CLIPBOARD_FILE"), data, 0600); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func stubClipboard(t *testing.T, cmds []string, terminal bool) {
+	t.Helper()
+	oldCommands := clipboardCommands
+	oldIsTerminal := isTerminal
+	t.Cleanup(func() {
+		clipboardCommands = oldCommands
+		isTerminal = oldIsTerminal
+	})
+	clipboardCommands = func() []string { return cmds }
+	isTerminal = func(io.Writer) bool { return terminal }
+	t.Setenv("SSH_TTY", "")
+	t.Setenv("SSH_CONNECTION", "")
+	t.Setenv("TMUX", "")
+}
+
+func TestBrowseWithFallback(t *testing.T) {
+	helper := fmt.Sprintf("%q -test.run=TestClipboardHelperProcess --", os.Args[0])
+	url := "https://github.com/login/device"
+
+	tests := []struct {
+		name          string
+		fallback      bool
+		cmds          []string
+		terminal      bool
+		ssh           bool
+		wantFallback  Fallback
+		wantClipboard string
+		wantStderr    string
+		wantErr       bool
+	}{
+		{
+			name:    "fallback disabled",
+			cmds:    []string{helper},
+			wantErr: true,
+		},
+		{
+			name:          "platform clipboard",
+			fallback:      true,
+			cmds:          []string{"goctl-missing-clipboard", helper},
+			terminal:      true,
+			wantFallback:  ClipboardFallback,
+			wantClipboard: url,
+		},
+		{
+			name:         "OSC 52 without clipboard command",
+			fallback:     true,
+			cmds:         []string{"goctl-missing-clipboard"},
+			terminal:     true,
+			wantFallback: OSC52Fallback,
+			wantStderr:   "\x1b]52;c;aHR0cHM6Ly9naXRodWIuY29tL2xvZ2luL2RldmljZQ==\a",
+		},
+		{
+			name:         "OSC 52 preferred over SSH",
+			fallback:     true,
+			cmds:         []string{helper},
+			terminal:     true,
+			ssh:          true,
+			wantFallback: OSC52Fallback,
+			wantStderr:   "\x1b]52;c;aHR0cHM6Ly9naXRodWIuY29tL2xvZ2luL2RldmljZQ==\a",
+		},
+		{
+			name:     "no clipboard available",
+			fallback: true,
+			cmds:     []string{"goctl-missing-clipboard"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubClipboard(t, tt.cmds, tt.terminal)
+			if tt.ssh {
+				t.Setenv("SSH_TTY", "/dev/pts/0")
+			}
+			clipboardFile := filepath.Join(t.TempDir(), "clipboard")
+			stderr := &bytes.Buffer{}
+			b := Browser{
+				launcher:          "goctl-missing-browser",
+				stdout:            &bytes.Buffer{},
+				stderr:            stderr,
+				clipboardFallback: tt.fallback,
+			}
+			fallback, err := b.browseWithFallback(url, []string{
+				"GOCTL_WANT_HELPER_PROCESS=1",
+				"GOCTL_CLIPBOARD_FILE=" + clipboardFile,
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantFallback, fallback)
+			assert.Equal(t, tt.wantStderr, stderr.String())
+			clipboard, _ := os.ReadFile(clipboardFile)
+			assert.Equal(t, tt.wantClipboard, string(clipboard))
+		})
+	}
+}
+
+func TestBrowseClipboardNotice(t *testing.T) {
+	stubClipboard(t, nil, true)
+	stderr := &bytes.Buffer{}
+	b := New("goctl-missing-browser", &bytes.Buffer{}, stderr, WithClipboardFallback())
+	err := b.Browse("https://github.com")
+	assert.NoError(t, err)
+	assert.Contains(t, stderr.String(), "Could not open a browser; copied https://github.com to the clipboard.\n")
+}
+
+func TestOSC52SequenceTmux(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1234,0")
+	assert.Equal(t, "\x1bPtmux;\x1b\x1b]52;c;Z2g=\a\x1b\\", osc52Sequence("gh"))
+}
+
+func TestFallbackString(t *testing.T) {
+	assert.Equal(t, "none", NoFallback.String())
+	assert.Equal(t, "clipboard", ClipboardFallback.String())
+	assert.Equal(t, "OSC 52", OSC52Fallback.String())
+}
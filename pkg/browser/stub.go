@@ -0,0 +1,52 @@
+package browser
+
+import (
+	"sync"
+	"testing"
+)
+
+// Stub is a stand-in for Browser that records the URLs it is asked to open
+// instead of launching anything, for use in tests. Stub has the same Browse
+// method as Browser so they both can satisfy the same interface.
+type Stub struct {
+	mu   sync.Mutex
+	urls []string
+	err  error
+}
+
+// NewStub initializes a Stub. If err is not nil it is returned from every
+// call to Browse, to simulate a browser that fails to launch.
+func NewStub(err error) *Stub {
+	return &Stub{err: err}
+}
+
+// Browse records the URL.
+func (s *Stub) Browse(url string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.urls = append(s.urls, url)
+	return s.err
+}
+
+// URLs returns the URLs that have been opened, in order.
+func (s *Stub) URLs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.urls...)
+}
+
+// Verify fails the test unless exactly one URL has been opened and it is the
+// expected URL. Pass an empty expected URL to verify that no URL was opened.
+func (s *Stub) Verify(t *testing.T, expected string) {
+	t.Helper()
+	urls := s.URLs()
+	if expected == "" {
+		if len(urls) > 0 {
+			t.Errorf("expected no URLs to be opened, got %q", urls)
+		}
+		return
+	}
+	if len(urls) != 1 || urls[0] != expected {
+		t.Errorf("expected browser to open %q, got %q", expected, urls)
+	}
+}
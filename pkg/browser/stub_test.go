@@ -0,0 +1,27 @@
+package browser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStub(t *testing.T) {
+	s := NewStub(nil)
+	s.Verify(t, "")
+	assert.NoError(t, s.Browse("https://github.com"))
+	s.Verify(t, "https://github.com")
+	assert.NoError(t, s.Browse("https://github.com/login"))
+	assert.Equal(t, []string{"https://github.com", "https://github.com/login"}, s.URLs())
+
+	fakeT := &testing.T{}
+	s.Verify(fakeT, "https://github.com")
+	assert.True(t, fakeT.Failed())
+}
+
+func TestStubError(t *testing.T) {
+	s := NewStub(errors.New("no browser"))
+	assert.EqualError(t, s.Browse("https://github.com"), "no browser")
+	assert.Equal(t, []string{"https://github.com"}, s.URLs())
+}
@@ -0,0 +1,52 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	assert.Equal(t, start, f.Now())
+
+	now := f.After(0)
+	assert.Equal(t, start, <-now)
+
+	minute := f.After(time.Minute)
+	hour := f.After(time.Hour)
+	f.Advance(30 * time.Second)
+	assertPending(t, minute)
+	assertPending(t, hour)
+
+	f.Advance(30 * time.Second)
+	assert.Equal(t, start.Add(time.Minute), <-minute)
+	assertPending(t, hour)
+
+	f.Set(start.Add(2 * time.Hour))
+	assert.Equal(t, start.Add(2*time.Hour), <-hour)
+	assert.Equal(t, start.Add(2*time.Hour), f.Now())
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	f := NewFake(time.Time{})
+	done := make(chan struct{})
+	go func() {
+		<-f.After(time.Second)
+		close(done)
+	}()
+	f.BlockUntil(1)
+	f.Advance(time.Second)
+	<-done
+}
+
+func assertPending(t *testing.T, ch <-chan time.Time) {
+	t.Helper()
+	select {
+	case v := <-ch:
+		t.Errorf("unexpected tick at %v", v)
+	default:
+	}
+}
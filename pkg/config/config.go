@@ -4,6 +4,7 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"io"
 	"os"
@@ -12,6 +13,8 @@ import (
 	"sync"
 
 	"github.com/khulnasoft-lab/go-goctl/v2/internal/yamlmap"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/logging"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -36,6 +39,9 @@ var (
 type Config struct {
 	entries *yamlmap.Map
 	mu      sync.RWMutex
+	// files maps the root nodes of the loaded config files to their paths, to locate
+	// invalid entries reported by Validate.
+	files map[*yaml.Node]string
 }
 
 // Get a string value from a Config.
@@ -140,10 +146,37 @@ func (c *Config) deepCopy() *Config {
 var Read = func(fallback *Config) (*Config, error) {
 	once.Do(func() {
 		cfg, loadErr = load(generalConfigFile(), hostsConfigFile(), fallback)
+		if loadErr != nil {
+			logging.Logger().Debug("failed to load configuration", "error", loadErr)
+		}
 	})
 	return cfg, loadErr
 }
 
+// ReadContext is like Read but returns ctx.Err() if ctx is done before the configuration
+// files have been loaded. The files keep loading in the background in that case, and
+// later calls of Read return their configuration.
+func ReadContext(ctx context.Context, fallback *Config) (*Config, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	type result struct {
+		cfg *Config
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		c, err := Read(fallback)
+		done <- result{c, err}
+	}()
+	select {
+	case r := <-done:
+		return r.cfg, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 // ReadFromString takes a yaml string and returns a Config.
 func ReadFromString(str string) *Config {
 	m, _ := mapFromString(str)
@@ -157,10 +190,24 @@ func ReadFromString(str string) *Config {
 // It will only write goctl configuration files that have been modified
 // since last being read.
 func Write(c *Config) error {
+	return WriteContext(context.Background(), c)
+}
+
+// WriteContext is like Write but stops with ctx.Err() if ctx is done before a file is
+// written. Each file is replaced atomically, so a canceled write never leaves a partially
+// written file behind, but the hosts file may have been written when the general
+// configuration file has not.
+func WriteContext(ctx context.Context, c *Config) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	hosts, err := c.entries.FindEntry("hosts")
 	if err == nil && hosts.IsModified() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		err := writeFile(hostsConfigFile(), []byte(hosts.String()))
 		if err != nil {
 			return err
@@ -169,6 +216,9 @@ func Write(c *Config) error {
 	}
 
 	if c.entries.IsModified() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		// Hosts gets written to a different file above so remove it
 		// before writing and add it back in after writing.
 		hostsMap, hostsErr := c.entries.FindEntry("hosts")
@@ -189,7 +239,9 @@ func Write(c *Config) error {
 }
 
 func load(generalFilePath, hostsFilePath string, fallback *Config) (*Config, error) {
+	logger := logging.Logger()
 	generalMap, err := mapFromFile(generalFilePath)
+	logger.Debug("loading configuration file", "path", generalFilePath, "exists", !os.IsNotExist(err))
 	if err != nil && !os.IsNotExist(err) {
 		if errors.Is(err, yamlmap.ErrInvalidYaml) ||
 			errors.Is(err, yamlmap.ErrInvalidFormat) {
@@ -203,6 +255,7 @@ func load(generalFilePath, hostsFilePath string, fallback *Config) (*Config, err
 	}
 
 	hostsMap, err := mapFromFile(hostsFilePath)
+	logger.Debug("loading configuration file", "path", hostsFilePath, "exists", !os.IsNotExist(err))
 	if err != nil && !os.IsNotExist(err) {
 		if errors.Is(err, yamlmap.ErrInvalidYaml) ||
 			errors.Is(err, yamlmap.ErrInvalidFormat) {
@@ -216,10 +269,15 @@ func load(generalFilePath, hostsFilePath string, fallback *Config) (*Config, err
 	}
 
 	if generalMap.Empty() && fallback != nil {
+		logger.Debug("using fallback configuration")
 		return fallback.deepCopy(), nil
 	}
 
-	return &Config{entries: generalMap}, nil
+	files := map[*yaml.Node]string{generalMap.Node: generalFilePath}
+	if hostsMap != nil {
+		files[hostsMap.Node] = hostsFilePath
+	}
+	return &Config{entries: generalMap, files: files}, nil
 }
 
 func generalConfigFile() string {
@@ -304,19 +362,35 @@ func readFile(filename string) ([]byte, error) {
 	return data, nil
 }
 
+// writeFile atomically replaces the contents of filename by writing data to a temporary
+// file in the same directory and renaming it over filename, so that readers never see a
+// partially written file. If filename is a symlink, its target is replaced instead.
 func writeFile(filename string, data []byte) (writeErr error) {
-	if writeErr = os.MkdirAll(filepath.Dir(filename), 0771); writeErr != nil {
+	if target, err := filepath.EvalSymlinks(filename); err == nil {
+		filename = target
+	}
+	dir := filepath.Dir(filename)
+	if writeErr = os.MkdirAll(dir, 0771); writeErr != nil {
 		return
 	}
 	var file *os.File
-	if file, writeErr = os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600); writeErr != nil {
+	if file, writeErr = os.CreateTemp(dir, "."+filepath.Base(filename)+".*.tmp"); writeErr != nil {
 		return
 	}
 	defer func() {
-		if err := file.Close(); writeErr == nil && err != nil {
-			writeErr = err
+		if writeErr != nil {
+			_ = os.Remove(file.Name())
 		}
 	}()
-	_, writeErr = file.Write(data)
+	if _, writeErr = file.Write(data); writeErr == nil {
+		writeErr = file.Sync()
+	}
+	if err := file.Close(); writeErr == nil && err != nil {
+		writeErr = err
+	}
+	if writeErr != nil {
+		return
+	}
+	writeErr = os.Rename(file.Name(), filename)
 	return
 }
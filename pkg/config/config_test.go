@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -406,6 +407,33 @@ func TestWrite(t *testing.T) {
 	}
 }
 
+func TestWriteContextCanceled(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("GOCTL_CONFIG_DIR", tempDir)
+	cfg := ReadFromString(testFullConfig())
+	cfg.Set([]string{"editor"}, "vim")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := WriteContext(ctx, cfg)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = os.Stat(generalConfigFile())
+	assert.True(t, os.IsNotExist(err))
+
+	// The configuration is still modified, so a later write succeeds.
+	assert.NoError(t, WriteContext(context.Background(), cfg))
+	data, err := os.ReadFile(generalConfigFile())
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "editor: vim")
+}
+
+func TestReadContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cfg, err := ReadContext(ctx, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, cfg)
+}
+
 func TestWriteEmptyValues(t *testing.T) {
 	tempDir := t.TempDir()
 	t.Setenv("GOCTL_CONFIG_DIR", tempDir)
@@ -418,6 +446,69 @@ func TestWriteEmptyValues(t *testing.T) {
 	assert.Equal(t, "git_protocol: ssh\neditor:\nprompt: enabled\npager: less\n", string(data))
 }
 
+func TestWritePreservesFormatting(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("GOCTL_CONFIG_DIR", tempDir)
+	data := "# What editor goctl should run\neditor: nano # the default\nprompt: enabled\nhosts:\n  github.com:\n    user: monalisa # primary account\n"
+	cfg := ReadFromString(data)
+	cfg.Set([]string{"editor"}, "vim")
+	cfg.Set([]string{"hosts", "github.com", "git_protocol"}, "ssh")
+	cfg.Set([]string{"hosts", "ghe.io", "user"}, "hubot")
+	assert.NoError(t, Write(cfg))
+
+	general, err := os.ReadFile(generalConfigFile())
+	assert.NoError(t, err)
+	assert.Equal(t, "# What editor goctl should run\neditor: vim # the default\nprompt: enabled\n", string(general))
+	hosts, err := os.ReadFile(hostsConfigFile())
+	assert.NoError(t, err)
+	assert.Equal(t, "github.com:\n    user: monalisa # primary account\n    git_protocol: ssh\nghe.io:\n    user: hubot\n", string(hosts))
+}
+
+func TestWriteIsAtomic(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("GOCTL_CONFIG_DIR", tempDir)
+	cfg := ReadFromString(testFullConfig())
+	cfg.Set([]string{"editor"}, "vim")
+	cfg.Set([]string{"hosts", "github.com", "git_protocol"}, "https")
+	assert.NoError(t, Write(cfg))
+
+	entries, err := os.ReadDir(tempDir)
+	assert.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"config.yml", "hosts.yml"}, names)
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(hostsConfigFile())
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+}
+
+func TestWriteFollowsSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require elevated privileges on windows")
+	}
+	tempDir := t.TempDir()
+	dotfiles := t.TempDir()
+	t.Setenv("GOCTL_CONFIG_DIR", tempDir)
+	target := filepath.Join(dotfiles, "goctl.yml")
+	assert.NoError(t, os.WriteFile(target, []byte("editor: nano\n"), 0600))
+	assert.NoError(t, os.Symlink(target, generalConfigFile()))
+
+	cfg := ReadFromString("editor: nano\n")
+	cfg.Set([]string{"editor"}, "vim")
+	assert.NoError(t, Write(cfg))
+
+	info, err := os.Lstat(generalConfigFile())
+	assert.NoError(t, err)
+	assert.Equal(t, os.ModeSymlink, info.Mode()&os.ModeSymlink)
+	data, err := os.ReadFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "editor: vim\n", string(data))
+}
+
 func TestGet(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -693,3 +784,20 @@ func assertKeyWithValue(t *testing.T, cfg *Config, keys []string, value string)
 	assert.NoError(t, err)
 	assert.Equal(t, value, actual)
 }
+
+func FuzzReadFromString(f *testing.F) {
+	f.Add(testFullConfig())
+	f.Add("hosts:\n  github.com:\n    oauth_token: abc\n")
+	f.Add("- a\n- b\n")
+	f.Add("hosts: [\n")
+	f.Fuzz(func(t *testing.T, data string) {
+		cfg := ReadFromString(data)
+		_, _ = cfg.Get([]string{"hosts", "github.com", "oauth_token"})
+		_, _ = cfg.Keys([]string{"hosts"})
+		_ = cfg.Remove([]string{"hosts", "github.com"})
+		cfg.Set([]string{"hosts", "github.com", "user"}, "monalisa")
+		got, err := cfg.Get([]string{"hosts", "github.com", "user"})
+		assert.NoError(t, err)
+		assert.Equal(t, "monalisa", got)
+	})
+}
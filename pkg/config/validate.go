@@ -0,0 +1,282 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var enabledDisabled = []string{"enabled", "disabled"}
+
+// optionKeys are the settings that can be set both globally and for a host, mapped to
+// their allowed values. Settings that accept any value map to nil.
+var optionKeys = map[string][]string{
+	"accessible_colors":    enabledDisabled,
+	"accessible_prompter":  enabledDisabled,
+	"browser":              nil,
+	"color_labels":         enabledDisabled,
+	"editor":               nil,
+	"git_protocol":         {"https", "ssh"},
+	"http_unix_socket":     nil,
+	"locale":               nil,
+	"pager":                nil,
+	"prefer_editor_prompt": enabledDisabled,
+	"prompt":               enabledDisabled,
+	"spinner":              enabledDisabled,
+}
+
+// mapKeys are the global settings whose values are maps of strings.
+var mapKeys = []string{"aliases", "prompt_history"}
+
+// ValidationError describes an invalid entry of a Config.
+type ValidationError struct {
+	// Path is the config file that contains the entry, if it was read from a file.
+	Path string
+	// Line and Column locate the entry in its file, starting at 1. They are zero if the
+	// entry was not read from a file, such as an entry added with Set.
+	Line   int
+	Column int
+	// Keys is the sequence of keys of the entry, as passed to Get.
+	Keys    []string
+	Message string
+}
+
+// Allow ValidationError to satisfy error interface.
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	if e.Path != "" {
+		b.WriteString(e.Path + ":")
+	}
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "%d:%d:", e.Line, e.Column)
+	}
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+	if len(e.Keys) > 0 {
+		b.WriteString(strings.Join(e.Keys, ".") + ": ")
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// Validate reads the goctl configuration files with Read and validates them with
+// Config.Validate.
+func Validate() error {
+	c, err := Read(nil)
+	if err != nil {
+		return err
+	}
+	return c.Validate()
+}
+
+// Validate checks the entries of a Config against the settings known to goctl: unknown
+// keys, which are usually typos, values outside of the allowed set of a setting such as
+// git_protocol or prompt, and host entries that do not have the expected shape.
+// Returns nil if the Config is valid, or each *ValidationError joined with errors.Join
+// in the order the entries appear.
+func (c *Config) Validate() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v := validator{files: c.files}
+	v.validateGlobal(c.entries.Node, v.files[c.entries.Node])
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	files map[*yaml.Node]string
+	errs  []error
+}
+
+func (v *validator) add(path string, n *yaml.Node, keys []string, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ValidationError{
+		Path:    path,
+		Line:    n.Line,
+		Column:  n.Column,
+		Keys:    append([]string(nil), keys...),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// entries calls fn with each key and value of the mapping node n, reporting duplicate
+// keys, which shadow each other.
+func (v *validator) entries(path string, n *yaml.Node, keys []string, fn func(key, value *yaml.Node)) {
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if seen[key.Value] && key.Value != "hosts" {
+			v.add(path, key, append(keys, key.Value), "duplicate key")
+			continue
+		}
+		seen[key.Value] = true
+		fn(key, value)
+	}
+}
+
+func (v *validator) validateGlobal(n *yaml.Node, path string) {
+	known := []string{"hosts", "version"}
+	known = append(known, mapKeys...)
+	for k := range optionKeys {
+		known = append(known, k)
+	}
+	v.entries(path, n, nil, func(key, value *yaml.Node) {
+		keys := []string{key.Value}
+		switch {
+		case key.Value == "hosts":
+			hostsPath := path
+			if p, ok := v.files[value]; ok {
+				hostsPath = p
+			}
+			v.validateHosts(value, hostsPath)
+		case key.Value == "version":
+			v.scalar(path, value, keys)
+		case contains(mapKeys, key.Value):
+			if v.mapping(path, value, keys) {
+				v.entries(path, value, keys, func(k, val *yaml.Node) {
+					v.scalar(path, val, append(keys, k.Value))
+				})
+			}
+		default:
+			if allowed, ok := optionKeys[key.Value]; ok {
+				v.option(path, value, keys, allowed)
+			} else {
+				v.unknown(path, key, nil, known)
+			}
+		}
+	})
+}
+
+func (v *validator) validateHosts(n *yaml.Node, path string) {
+	keys := []string{"hosts"}
+	if !v.mapping(path, n, keys) {
+		return
+	}
+	known := []string{"oauth_token", "user", "users"}
+	for k := range optionKeys {
+		known = append(known, k)
+	}
+	v.entries(path, n, keys, func(hostKey, host *yaml.Node) {
+		hostKeys := append(keys, hostKey.Value)
+		if strings.Contains(hostKey.Value, "://") || strings.Contains(hostKey.Value, "/") || hostKey.Value == "" {
+			v.add(path, hostKey, hostKeys, "expected a hostname such as github.com")
+		}
+		if isNull(host) || !v.mapping(path, host, hostKeys) {
+			return
+		}
+		v.entries(path, host, hostKeys, func(key, value *yaml.Node) {
+			entryKeys := append(append([]string(nil), hostKeys...), key.Value)
+			switch key.Value {
+			case "oauth_token", "user":
+				v.scalar(path, value, entryKeys)
+			case "users":
+				if isNull(value) || !v.mapping(path, value, entryKeys) {
+					return
+				}
+				v.entries(path, value, entryKeys, func(user, userEntry *yaml.Node) {
+					userKeys := append(append([]string(nil), entryKeys...), user.Value)
+					if isNull(userEntry) || !v.mapping(path, userEntry, userKeys) {
+						return
+					}
+					v.entries(path, userEntry, userKeys, func(k, val *yaml.Node) {
+						if k.Value != "oauth_token" {
+							v.unknown(path, k, userKeys, []string{"oauth_token"})
+							return
+						}
+						v.scalar(path, val, append(userKeys, k.Value))
+					})
+				})
+			default:
+				if allowed, ok := optionKeys[key.Value]; ok {
+					v.option(path, value, entryKeys, allowed)
+				} else {
+					v.unknown(path, key, hostKeys, known)
+				}
+			}
+		})
+	})
+}
+
+func (v *validator) unknown(path string, key *yaml.Node, keys []string, known []string) {
+	if suggestion := closest(key.Value, known); suggestion != "" {
+		v.add(path, key, append(keys, key.Value), "unknown key, did you mean %q?", suggestion)
+		return
+	}
+	v.add(path, key, append(keys, key.Value), "unknown key")
+}
+
+// option checks the value of a setting against its allowed values, if any.
+func (v *validator) option(path string, n *yaml.Node, keys []string, allowed []string) {
+	if !v.scalar(path, n, keys) || isNull(n) || allowed == nil {
+		return
+	}
+	if !contains(allowed, n.Value) {
+		v.add(path, n, keys, "invalid value %q, expected one of: %s", n.Value, strings.Join(allowed, ", "))
+	}
+}
+
+func (v *validator) scalar(path string, n *yaml.Node, keys []string) bool {
+	if n.Kind != yaml.ScalarNode {
+		v.add(path, n, keys, "expected a string value")
+		return false
+	}
+	return true
+}
+
+func (v *validator) mapping(path string, n *yaml.Node, keys []string) bool {
+	if n.Kind != yaml.MappingNode {
+		v.add(path, n, keys, "expected a map")
+		return false
+	}
+	return true
+}
+
+func isNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// closest returns the known key that is the fewest edits away from key, if it is close
+// enough to be a likely typo.
+func closest(key string, known []string) string {
+	sort.Strings(known)
+	// Suggestions must be fewer edits away than a third of the length of the key.
+	best, bestDistance := "", len(key)/3+1
+	for _, k := range known {
+		if d := editDistance(key, k); d < bestDistance {
+			best, bestDistance = k, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		wantErrs []string
+	}{
+		{
+			name:   "valid",
+			config: testFullConfig(),
+		},
+		{
+			name: "valid with every known key",
+			config: `
+version: "1"
+git_protocol: https
+editor: vim
+prompt: disabled
+prefer_editor_prompt: enabled
+pager: less
+http_unix_socket:
+browser: firefox
+color_labels: enabled
+accessible_colors: disabled
+accessible_prompter: disabled
+spinner: enabled
+locale: fr
+aliases:
+  co: pr checkout
+prompt_history:
+  repo: octo-org/hello-world
+hosts:
+  github.com:
+    user: monalisa
+    oauth_token: xxxxxxxxxxxxxxxxxxxx
+    git_protocol: ssh
+    users:
+      monalisa:
+        oauth_token: xxxxxxxxxxxxxxxxxxxx
+      hubot:
+`,
+		},
+		{
+			name:   "empty",
+			config: "",
+		},
+		{
+			name: "unknown keys",
+			config: `
+git_protocl: ssh
+colour: always
+`,
+			wantErrs: []string{
+				`2:1: git_protocl: unknown key, did you mean "git_protocol"?`,
+				`3:1: colour: unknown key`,
+			},
+		},
+		{
+			name: "invalid values",
+			config: `
+git_protocol: htps
+prompt: yes
+aliases: co
+`,
+			wantErrs: []string{
+				`2:15: git_protocol: invalid value "htps", expected one of: https, ssh`,
+				`3:9: prompt: invalid value "yes", expected one of: enabled, disabled`,
+				`4:10: aliases: expected a map`,
+			},
+		},
+		{
+			name: "invalid hosts",
+			config: `
+hosts:
+  https://github.com:
+    oauth_token: xxxxxxxxxxxxxxxxxxxx
+  enterprise.com:
+    oauth-token: yyyyyyyyyyyyyyyyyyyy
+    git_protocol: [ssh]
+    users:
+      monalisa:
+        token: zzzzzzzzzzzzzzzzzzzz
+  other.com: token
+`,
+			wantErrs: []string{
+				`3:3: hosts.https://github.com: expected a hostname such as github.com`,
+				`6:5: hosts.enterprise.com.oauth-token: unknown key, did you mean "oauth_token"?`,
+				`7:19: hosts.enterprise.com.git_protocol: expected a string value`,
+				`10:9: hosts.enterprise.com.users.monalisa.token: unknown key`,
+				`11:14: hosts.other.com: expected a map`,
+			},
+		},
+		{
+			name: "duplicate key",
+			config: `
+pager: less
+pager: more
+`,
+			wantErrs: []string{
+				`3:1: pager: duplicate key`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ReadFromString(tt.config).Validate()
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var got []string
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var validationErr *ValidationError
+				require.True(t, errors.As(e, &validationErr))
+				got = append(got, e.Error())
+			}
+			assert.Equal(t, tt.wantErrs, got)
+		})
+	}
+}
+
+func TestValidateLoadedFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	globalFilePath := filepath.Join(tempDir, "config.yml")
+	hostsFilePath := filepath.Join(tempDir, "hosts.yml")
+	require.NoError(t, os.WriteFile(globalFilePath, []byte("git_protocol: ssh\npromt: enabled\n"), 0755))
+	require.NoError(t, os.WriteFile(hostsFilePath, []byte("github.com:\n  git_protocol: git\n"), 0755))
+
+	cfg, err := load(globalFilePath, hostsFilePath, nil)
+	require.NoError(t, err)
+	cfg.Set([]string{"spinner"}, "sometimes")
+	err = cfg.Validate()
+	assert.EqualError(t, err, globalFilePath+`:2:1: promt: unknown key, did you mean "prompt"?`+"\n"+
+		hostsFilePath+`:2:17: hosts.github.com.git_protocol: invalid value "git", expected one of: https, ssh`+"\n"+
+		globalFilePath+`: spinner: invalid value "sometimes", expected one of: enabled, disabled`)
+}
+
+func TestValidationError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ValidationError
+		want string
+	}{
+		{
+			name: "with position",
+			err:  &ValidationError{Path: "hosts.yml", Line: 2, Column: 3, Keys: []string{"hosts", "github.com"}, Message: "expected a map"},
+			want: "hosts.yml:2:3: hosts.github.com: expected a map",
+		},
+		{
+			name: "without position",
+			err:  &ValidationError{Keys: []string{"prompt"}, Message: "unknown key"},
+			want: "prompt: unknown key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, tt.err, tt.want)
+		})
+	}
+}
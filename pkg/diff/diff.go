@@ -0,0 +1,253 @@
+// Package diff facilitates rendering unified diffs to a terminal with ANSI colors.
+package diff
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+)
+
+const (
+	colorHeader  = "\x1b[1;38m"
+	colorHunk    = "\x1b[36m"
+	colorAdded   = "\x1b[32m"
+	colorRemoved = "\x1b[31m"
+	colorReset   = "\x1b[m"
+	emphasisOn   = "\x1b[7m"
+	emphasisOff  = "\x1b[27m"
+
+	// Lines with more words than this are not word-diffed to bound the
+	// quadratic cost of computing the longest common subsequence.
+	maxWordDiffTokens = 500
+)
+
+type option func(*colorizer)
+
+// WithWordDiff enables highlighting of the individual words that changed
+// between a run of removed lines and the run of added lines that follows it.
+func WithWordDiff() option {
+	return func(c *colorizer) {
+		c.wordDiff = true
+	}
+}
+
+type colorizer struct {
+	w        io.Writer
+	wordDiff bool
+	removed  []string
+	added    []string
+}
+
+// Colorize reads a unified diff from r and writes it to w with file headers,
+// hunk headers, and added and removed lines highlighted. The input is processed
+// line by line so arbitrarily large diffs can be streamed.
+func Colorize(w io.Writer, r io.Reader, opts ...option) error {
+	c := &colorizer{w: w}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	c.w = bw
+	inHeader := true
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			if werr := c.line(line, &inHeader); werr != nil {
+				return werr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := c.flushChanges(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func (c *colorizer) line(line string, inHeader *bool) error {
+	content := strings.TrimRight(line, "\r\n")
+	eol := line[len(content):]
+
+	if strings.HasPrefix(content, "diff ") {
+		*inHeader = true
+	} else if strings.HasPrefix(content, "@@") {
+		*inHeader = false
+	}
+
+	if !*inHeader && c.wordDiff {
+		switch {
+		case strings.HasPrefix(content, "-"):
+			if len(c.added) > 0 {
+				if err := c.flushChanges(); err != nil {
+					return err
+				}
+			}
+			c.removed = append(c.removed, line)
+			return nil
+		case strings.HasPrefix(content, "+"):
+			c.added = append(c.added, line)
+			return nil
+		}
+	}
+	if err := c.flushChanges(); err != nil {
+		return err
+	}
+
+	switch {
+	case *inHeader:
+		return c.write(colorHeader, content, eol)
+	case strings.HasPrefix(content, "@@"):
+		return c.write(colorHunk, content, eol)
+	case strings.HasPrefix(content, "+"):
+		return c.write(colorAdded, content, eol)
+	case strings.HasPrefix(content, "-"):
+		return c.write(colorRemoved, content, eol)
+	}
+	_, err := io.WriteString(c.w, line)
+	return err
+}
+
+func (c *colorizer) write(color, content, eol string) error {
+	_, err := io.WriteString(c.w, color+content+colorReset+eol)
+	return err
+}
+
+// flushChanges writes out the buffered run of removed and added lines. When
+// both runs have the same length the lines are paired up and word-diffed.
+func (c *colorizer) flushChanges() error {
+	defer func() {
+		c.removed = c.removed[:0]
+		c.added = c.added[:0]
+	}()
+	paired := len(c.removed) == len(c.added)
+	for i, line := range c.removed {
+		content := strings.TrimRight(line, "\r\n")
+		eol := line[len(content):]
+		if paired {
+			before, _ := highlightWords(content[1:], strings.TrimRight(c.added[i], "\r\n")[1:])
+			content = "-" + before
+		}
+		if err := c.write(colorRemoved, content, eol); err != nil {
+			return err
+		}
+	}
+	for i, line := range c.added {
+		content := strings.TrimRight(line, "\r\n")
+		eol := line[len(content):]
+		if paired {
+			_, after := highlightWords(strings.TrimRight(c.removed[i], "\r\n")[1:], content[1:])
+			content = "+" + after
+		}
+		if err := c.write(colorAdded, content, eol); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// highlightWords compares before and after word by word and returns both strings
+// with the words that are not common to them emphasized.
+func highlightWords(before, after string) (string, string) {
+	a := tokenize(before)
+	b := tokenize(after)
+	if len(a) > maxWordDiffTokens || len(b) > maxWordDiffTokens {
+		return before, after
+	}
+	inA, inB := commonTokens(a, b)
+	return emphasize(a, inA), emphasize(b, inB)
+}
+
+func emphasize(tokens []string, common []bool) string {
+	var sb strings.Builder
+	on := false
+	for i, tok := range tokens {
+		changed := !common[i]
+		if changed && !on && strings.TrimSpace(tok) != "" {
+			sb.WriteString(emphasisOn)
+			on = true
+		} else if !changed && on {
+			sb.WriteString(emphasisOff)
+			on = false
+		}
+		sb.WriteString(tok)
+	}
+	if on {
+		sb.WriteString(emphasisOff)
+	}
+	return sb.String()
+}
+
+// tokenize splits s into alternating runs of word and non-word characters.
+func tokenize(s string) []string {
+	var tokens []string
+	start := 0
+	kind := -1
+	for i, r := range s {
+		k := runeKind(r)
+		if i > 0 && k != kind {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		kind = k
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+func runeKind(r rune) int {
+	switch {
+	case unicode.IsSpace(r):
+		return 0
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+		return 1
+	default:
+		return 2
+	}
+}
+
+// commonTokens marks the tokens of a and b that belong to their longest common subsequence.
+func commonTokens(a, b []string) ([]bool, []bool) {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	inA := make([]bool, len(a))
+	inB := make([]bool, len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			inA[i] = true
+			inB[j] = true
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return inA, inB
+}
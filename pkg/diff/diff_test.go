@@ -0,0 +1,84 @@
+package diff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorize(t *testing.T) {
+	input := heredoc.Doc(`
+		diff --git a/README.md b/README.md
+		index 1234567..89abcde 100644
+		--- a/README.md
+		+++ b/README.md
+		@@ -1,3 +1,3 @@
+		 unchanged
+		-old line
+		+new line
+	`)
+	tests := []struct {
+		name string
+		opts []option
+		want string
+	}{
+		{
+			name: "line colors",
+			want: "\x1b[1;38mdiff --git a/README.md b/README.md\x1b[m\n" +
+				"\x1b[1;38mindex 1234567..89abcde 100644\x1b[m\n" +
+				"\x1b[1;38m--- a/README.md\x1b[m\n" +
+				"\x1b[1;38m+++ b/README.md\x1b[m\n" +
+				"\x1b[36m@@ -1,3 +1,3 @@\x1b[m\n" +
+				" unchanged\n" +
+				"\x1b[31m-old line\x1b[m\n" +
+				"\x1b[32m+new line\x1b[m\n",
+		},
+		{
+			name: "word diff",
+			opts: []option{WithWordDiff()},
+			want: "\x1b[1;38mdiff --git a/README.md b/README.md\x1b[m\n" +
+				"\x1b[1;38mindex 1234567..89abcde 100644\x1b[m\n" +
+				"\x1b[1;38m--- a/README.md\x1b[m\n" +
+				"\x1b[1;38m+++ b/README.md\x1b[m\n" +
+				"\x1b[36m@@ -1,3 +1,3 @@\x1b[m\n" +
+				" unchanged\n" +
+				"\x1b[31m-\x1b[7mold\x1b[27m line\x1b[m\n" +
+				"\x1b[32m+\x1b[7mnew\x1b[27m line\x1b[m\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.Buffer{}
+			err := Colorize(&out, strings.NewReader(input), tt.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestColorizeWordDiffUnpaired(t *testing.T) {
+	input := "@@ -1,2 +1,1 @@\n-one\n-two\n+three"
+	out := bytes.Buffer{}
+	err := Colorize(&out, strings.NewReader(input), WithWordDiff())
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"\x1b[36m@@ -1,2 +1,1 @@\x1b[m\n"+
+			"\x1b[31m-one\x1b[m\n"+
+			"\x1b[31m-two\x1b[m\n"+
+			"\x1b[32m+three\x1b[m",
+		out.String())
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"foo", "(", "bar_1", ",", " ", "baz", ")"}, tokenize("foo(bar_1, baz)"))
+	assert.Equal(t, []string(nil), tokenize(""))
+}
+
+func TestHighlightWords(t *testing.T) {
+	before, after := highlightWords("return a + b", "return a - b")
+	assert.Equal(t, "return a \x1b[7m+\x1b[27m b", before)
+	assert.Equal(t, "return a \x1b[7m-\x1b[27m b", after)
+}
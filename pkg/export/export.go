@@ -0,0 +1,154 @@
+// Package export facilitates writing structured records in a format chosen by the user, such as
+// through a "--format" flag. Formats are looked up by name in a registry that holds the built-in
+// "table", "json", "csv", "yaml", and "template" formats and that applications can extend.
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Row is a single record to be exported, keyed by field name.
+type Row map[string]interface{}
+
+// Exporter writes rows in a particular format. Write may be called any number of times;
+// Flush must be called once all rows have been written.
+type Exporter interface {
+	Write(rows []Row) error
+	Flush() error
+}
+
+// Options configures an Exporter.
+type Options struct {
+	// Fields are the fields to export, in order. If empty, all fields of the first row
+	// are exported in alphabetical order.
+	Fields []string
+	// IsTTY enables human-readable output for formats that support it.
+	IsTTY bool
+	// Width is the width of the terminal, used to fit tables.
+	Width int
+	// ColorEnabled enables colored output for formats that support it.
+	ColorEnabled bool
+	// Template is the Go template used by the "template" format.
+	Template string
+}
+
+// Factory initializes an Exporter that writes to w.
+type Factory func(w io.Writer, opts Options) (Exporter, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{
+		"csv":      newCSVExporter,
+		"json":     newJSONExporter,
+		"table":    newTableExporter,
+		"template": newTemplateExporter,
+		"yaml":     newYAMLExporter,
+	}
+)
+
+// Register makes a format available under the given name, replacing any format
+// previously registered with that name, including built-in formats.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	factories[name] = factory
+}
+
+// Formats returns the names of all registered formats in alphabetical order.
+func Formats() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New initializes an Exporter for the named format writing to w.
+func New(format string, w io.Writer, opts Options) (Exporter, error) {
+	mu.RLock()
+	factory, ok := factories[format]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q; valid formats: %s", format, strings.Join(Formats(), ", "))
+	}
+	return factory(w, opts)
+}
+
+// fields returns the fields to export for the given rows.
+func fields(opts Options, rows []Row) []string {
+	if len(opts.Fields) > 0 || len(rows) == 0 {
+		return opts.Fields
+	}
+	names := make([]string, 0, len(rows[0]))
+	for name := range rows[0] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// orderedRow marshals a row as a JSON object with only the given fields, in that order,
+// which encoding/json does not keep for maps. All fields of the row are marshaled in
+// alphabetical order if none are given.
+type orderedRow struct {
+	row    Row
+	fields []string
+}
+
+func (r orderedRow) MarshalJSON() ([]byte, error) {
+	if len(r.fields) == 0 {
+		return json.Marshal(r.row)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	seen := make(map[string]bool, len(r.fields))
+	for _, name := range r.fields {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.row[name])
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// stringify formats a value for tabular output. Scalars are formatted plainly and
+// other values as JSON.
+func stringify(v interface{}) string {
+	switch tt := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return tt
+	case bool, int, int64, float64, json.Number:
+		return fmt.Sprintf("%v", tt)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,166 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRows = []Row{
+	{"number": 1, "title": "Fix the bug", "labels": []string{"bug"}, "draft": false},
+	{"number": 2, "title": "Add a feature", "labels": nil, "draft": true},
+}
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		opts    Options
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "table",
+			format: "table",
+			opts:   Options{Fields: []string{"number", "title"}, IsTTY: true, Width: 80},
+			want: heredoc.Doc(`
+				NUMBER  TITLE
+				1       Fix the bug
+				2       Add a feature
+			`),
+		},
+		{
+			name:   "table without terminal",
+			format: "table",
+			opts:   Options{Fields: []string{"number", "title"}},
+			want:   "1\tFix the bug\n2\tAdd a feature\n",
+		},
+		{
+			name:   "csv",
+			format: "csv",
+			opts:   Options{Fields: []string{"number", "title", "labels"}},
+			want: heredoc.Doc(`
+				number,title,labels
+				1,Fix the bug,"[""bug""]"
+				2,Add a feature,
+			`),
+		},
+		{
+			name:   "csv with all fields",
+			format: "csv",
+			want: heredoc.Doc(`
+				draft,labels,number,title
+				false,"[""bug""]",1,Fix the bug
+				true,,2,Add a feature
+			`),
+		},
+		{
+			name:   "json",
+			format: "json",
+			opts:   Options{Fields: []string{"number", "draft"}},
+			want:   `[{"number":1,"draft":false},{"number":2,"draft":true}]` + "\n",
+		},
+		{
+			name:   "json with all fields",
+			format: "json",
+			want:   `[{"draft":false,"labels":["bug"],"number":1,"title":"Fix the bug"},{"draft":true,"labels":null,"number":2,"title":"Add a feature"}]` + "\n",
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			opts:   Options{Fields: []string{"title", "number", "title"}},
+			want: heredoc.Doc(`
+				- title: Fix the bug
+				  number: 1
+				- title: Add a feature
+				  number: 2
+			`),
+		},
+		{
+			name:   "template",
+			format: "template",
+			opts:   Options{Template: `{{range .}}#{{.number}} {{.title}}{{"\n"}}{{end}}`},
+			want:   "#1 Fix the bug\n#2 Add a feature\n",
+		},
+		{
+			name:    "invalid template",
+			format:  "template",
+			opts:    Options{Template: `{{`},
+			wantErr: "template: :1: unclosed action",
+		},
+		{
+			name:    "unknown format",
+			format:  "xml",
+			wantErr: `unknown format "xml"; valid formats: csv, json, table, template, yaml`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			e, err := New(tt.format, w, tt.opts)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			// Rows may be written in several batches.
+			assert.NoError(t, e.Write(testRows[:1]))
+			assert.NoError(t, e.Write(testRows[1:]))
+			assert.NoError(t, e.Flush())
+			assert.Equal(t, tt.want, w.String())
+		})
+	}
+}
+
+func TestJSONEmpty(t *testing.T) {
+	w := &bytes.Buffer{}
+	e, err := New("json", w, Options{})
+	assert.NoError(t, err)
+	assert.NoError(t, e.Flush())
+	assert.Equal(t, "[]\n", w.String())
+}
+
+type markdownExporter struct {
+	w    io.Writer
+	opts Options
+}
+
+func (e *markdownExporter) Write(rows []Row) error {
+	for _, row := range rows {
+		values := make([]string, len(e.opts.Fields))
+		for i, f := range e.opts.Fields {
+			values[i] = fmt.Sprint(row[f])
+		}
+		fmt.Fprintf(e.w, "| %s |\n", strings.Join(values, " | "))
+	}
+	return nil
+}
+
+func (e *markdownExporter) Flush() error {
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	Register("markdown", func(w io.Writer, opts Options) (Exporter, error) {
+		return &markdownExporter{w: w, opts: opts}, nil
+	})
+	t.Cleanup(func() {
+		mu.Lock()
+		delete(factories, "markdown")
+		mu.Unlock()
+	})
+
+	assert.Equal(t, []string{"csv", "json", "markdown", "table", "template", "yaml"}, Formats())
+
+	w := &bytes.Buffer{}
+	e, err := New("markdown", w, Options{Fields: []string{"number", "title"}})
+	assert.NoError(t, err)
+	assert.NoError(t, e.Write(testRows))
+	assert.NoError(t, e.Flush())
+	assert.Equal(t, "| 1 | Fix the bug |\n| 2 | Add a feature |\n", w.String())
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/jsonpretty"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/tableprinter"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/template"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/yamlpretty"
+)
+
+type tableExporter struct {
+	tp     tableprinter.TablePrinter
+	opts   Options
+	fields []string
+}
+
+func newTableExporter(w io.Writer, opts Options) (Exporter, error) {
+	return &tableExporter{tp: tableprinter.New(w, opts.IsTTY, opts.Width), opts: opts}, nil
+}
+
+func (e *tableExporter) Write(rows []Row) error {
+	if e.fields == nil {
+		e.fields = fields(e.opts, rows)
+		headers := make([]string, len(e.fields))
+		for i, f := range e.fields {
+			headers[i] = strings.ToUpper(f)
+		}
+		e.tp.AddHeader(headers)
+	}
+	for _, row := range rows {
+		for _, f := range e.fields {
+			e.tp.AddField(stringify(row[f]))
+		}
+		e.tp.EndRow()
+	}
+	return nil
+}
+
+func (e *tableExporter) Flush() error {
+	return e.tp.Render()
+}
+
+type csvExporter struct {
+	w      *csv.Writer
+	opts   Options
+	fields []string
+}
+
+func newCSVExporter(w io.Writer, opts Options) (Exporter, error) {
+	return &csvExporter{w: csv.NewWriter(w), opts: opts}, nil
+}
+
+func (e *csvExporter) Write(rows []Row) error {
+	if e.fields == nil {
+		e.fields = fields(e.opts, rows)
+		if err := e.w.Write(e.fields); err != nil {
+			return err
+		}
+	}
+	record := make([]string, len(e.fields))
+	for _, row := range rows {
+		for i, f := range e.fields {
+			record[i] = stringify(row[f])
+		}
+		if err := e.w.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *csvExporter) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// bufferedExporter collects all rows and writes them as a single document on Flush.
+type bufferedExporter struct {
+	opts  Options
+	rows  []orderedRow
+	write func(data []byte) error
+}
+
+func (e *bufferedExporter) Write(rows []Row) error {
+	for _, row := range rows {
+		e.rows = append(e.rows, orderedRow{row: row, fields: e.opts.Fields})
+	}
+	return nil
+}
+
+func (e *bufferedExporter) Flush() error {
+	rows := e.rows
+	if rows == nil {
+		rows = []orderedRow{}
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+	return e.write(data)
+}
+
+func newJSONExporter(w io.Writer, opts Options) (Exporter, error) {
+	return &bufferedExporter{opts: opts, write: func(data []byte) error {
+		if opts.IsTTY {
+			return jsonpretty.Format(w, bytes.NewReader(data), "  ", opts.ColorEnabled)
+		}
+		_, err := w.Write(append(data, '\n'))
+		return err
+	}}, nil
+}
+
+func newYAMLExporter(w io.Writer, opts Options) (Exporter, error) {
+	return &bufferedExporter{opts: opts, write: func(data []byte) error {
+		return yamlpretty.Format(w, bytes.NewReader(data))
+	}}, nil
+}
+
+func newTemplateExporter(w io.Writer, opts Options) (Exporter, error) {
+	tmpl := template.New(w, opts.Width, opts.ColorEnabled)
+	if err := tmpl.Parse(opts.Template); err != nil {
+		return nil, err
+	}
+	return &bufferedExporter{opts: opts, write: func(data []byte) error {
+		if err := tmpl.Execute(bytes.NewReader(data)); err != nil {
+			return err
+		}
+		return tmpl.Flush()
+	}}, nil
+}
@@ -0,0 +1,196 @@
+// Package extensions discovers and runs installed goctl extensions.
+package extensions
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/internal/git"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+)
+
+// prefix is the prefix of the repository and executable name of every extension.
+const prefix = "goctl-"
+
+// Kind describes how an extension was installed.
+type Kind string
+
+const (
+	// GitKind extensions are script extensions installed by cloning their repository.
+	GitKind Kind = "git"
+	// BinaryKind extensions are precompiled executables downloaded from a release.
+	BinaryKind Kind = "binary"
+	// LocalKind extensions are symbolic links to a local directory, typically used
+	// while developing an extension.
+	LocalKind Kind = "local"
+)
+
+// Extension is an installed goctl extension.
+type Extension struct {
+	// Name is the name the extension is invoked with, without the "goctl-" prefix.
+	Name string
+	// Kind is how the extension was installed.
+	Kind Kind
+	// Path is the location of the executable of the extension.
+	Path string
+	// Version is the release tag of binary extensions or the commit of git extensions.
+	// It is empty for local extensions.
+	Version string
+	// Pinned reports whether the extension is pinned to its version and skipped by upgrades.
+	Pinned bool
+	// Owner is the owner of the repository the extension was installed from, if known.
+	Owner string
+	// Host is the host of the repository the extension was installed from, if known.
+	Host string
+	// LoadError is why the installation of the extension could not be read, such as a
+	// malformed manifest. When it is set, only Name, Kind, and Path are reliable.
+	LoadError error
+}
+
+// Dir returns the directory extensions are installed in.
+func Dir() string {
+	return filepath.Join(config.DataDir(), "extensions")
+}
+
+// List returns the installed extensions sorted by name. An extension whose installation
+// cannot be read is still listed, with the reason in its LoadError field, so that it does
+// not hide the other extensions.
+func List() ([]Extension, error) {
+	return list(Dir())
+}
+
+func list(dir string) ([]Extension, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []Extension{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	exts := []Extension{}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		exts = append(exts, load(dir, entry))
+	}
+	sort.Slice(exts, func(i, j int) bool {
+		return exts[i].Name < exts[j].Name
+	})
+	return exts, nil
+}
+
+func load(dir string, entry os.DirEntry) Extension {
+	fullName := entry.Name()
+	ext := Extension{Name: strings.TrimPrefix(fullName, prefix)}
+	extDir := filepath.Join(dir, fullName)
+
+	if entry.Type()&os.ModeSymlink != 0 {
+		ext.Kind = LocalKind
+		target, err := os.Readlink(extDir)
+		if err != nil {
+			ext.LoadError = err
+			return ext
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(dir, target)
+		}
+		ext.Path = executablePath(target, fullName)
+		return ext
+	}
+
+	m, err := ReadManifest(filepath.Join(extDir, "manifest.yml"))
+	if err == nil {
+		ext.Kind = BinaryKind
+		ext.Path = m.Path
+		if ext.Path == "" {
+			ext.Path = executablePath(extDir, fullName)
+		}
+		ext.Version = m.Tag
+		ext.Pinned = m.IsPinned
+		ext.Owner = m.Owner
+		ext.Host = m.Host
+		return ext
+	} else if !errors.Is(err, os.ErrNotExist) {
+		ext.Kind = BinaryKind
+		ext.Path = executablePath(extDir, fullName)
+		ext.LoadError = err
+		return ext
+	}
+
+	ext.Kind = GitKind
+	ext.Path = filepath.Join(extDir, fullName)
+	gitDir := filepath.Join(extDir, ".git")
+	ext.Version = headCommit(gitDir)
+	if pins, _ := filepath.Glob(filepath.Join(extDir, ".pin-*")); len(pins) > 0 {
+		ext.Pinned = true
+	}
+	if u, err := git.ParseURL(originURL(gitDir)); err == nil {
+		ext.Host, ext.Owner, _, _ = git.RepoInfoFromURL(u)
+	}
+	return ext
+}
+
+func executablePath(dir, name string) string {
+	if runtime.GOOS == "windows" {
+		if _, err := os.Stat(filepath.Join(dir, name+".exe")); err == nil {
+			return filepath.Join(dir, name+".exe")
+		}
+	}
+	return filepath.Join(dir, name)
+}
+
+// headCommit returns the commit checked out in a git repository by reading its
+// metadata directly, or an empty string if it cannot be determined.
+func headCommit(gitDir string) string {
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	ref, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: ")
+	if !ok {
+		return strings.TrimSpace(string(head))
+	}
+	if sha, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref))); err == nil {
+		return strings.TrimSpace(string(sha))
+	}
+	f, err := os.Open(filepath.Join(gitDir, "packed-refs"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if sha, name, ok := strings.Cut(s.Text(), " "); ok && name == ref {
+			return sha
+		}
+	}
+	return ""
+}
+
+// originURL returns the URL of the origin remote of a git repository, or an empty
+// string if it has none.
+func originURL(gitDir string) string {
+	f, err := os.Open(filepath.Join(gitDir, "config"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	inOrigin := false
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && inOrigin && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package extensions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0755))
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+
+	// A git extension on a branch with a packed ref, pinned to its commit.
+	gitExt := filepath.Join(dir, "goctl-hello")
+	writeFile(t, filepath.Join(gitExt, "goctl-hello"), "#!/bin/sh\necho hello\n")
+	writeFile(t, filepath.Join(gitExt, ".git", "HEAD"), "ref: refs/heads/main\n")
+	writeFile(t, filepath.Join(gitExt, ".git", "packed-refs"), heredoc.Doc(`
+		# pack-refs with: peeled fully-peeled sorted
+		8b0f0a4c1e5ed2c1a4b9c1b4b4c4c56d7a1b2c3d refs/heads/main
+	`))
+	writeFile(t, filepath.Join(gitExt, ".git", "config"), heredoc.Doc(`
+		[core]
+			bare = false
+		[remote "origin"]
+			url = https://github.com/monalisa/goctl-hello.git
+			fetch = +refs/heads/*:refs/remotes/origin/*
+	`))
+	writeFile(t, filepath.Join(gitExt, ".pin-8b0f0a4"), "")
+
+	// A binary extension.
+	binExt := filepath.Join(dir, "goctl-bin")
+	writeFile(t, filepath.Join(binExt, "goctl-bin"), "")
+	writeFile(t, filepath.Join(binExt, "manifest.yml"), heredoc.Docf(`
+		owner: hubot
+		name: goctl-bin
+		host: github.com
+		tag: v1.2.3
+		ispinned: false
+		path: %s
+	`, filepath.Join(binExt, "goctl-bin")))
+
+	// A local extension.
+	localDir := filepath.Join(t.TempDir(), "goctl-local")
+	writeFile(t, filepath.Join(localDir, "goctl-local"), "#!/bin/sh\n")
+	if err := os.Symlink(localDir, filepath.Join(dir, "goctl-local")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	// Entries without the prefix are ignored.
+	writeFile(t, filepath.Join(dir, "README"), "")
+
+	exts, err := list(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []Extension{
+		{
+			Name:    "bin",
+			Kind:    BinaryKind,
+			Path:    filepath.Join(binExt, "goctl-bin"),
+			Version: "v1.2.3",
+			Owner:   "hubot",
+			Host:    "github.com",
+		},
+		{
+			Name:    "hello",
+			Kind:    GitKind,
+			Path:    filepath.Join(gitExt, "goctl-hello"),
+			Version: "8b0f0a4c1e5ed2c1a4b9c1b4b4c4c56d7a1b2c3d",
+			Pinned:  true,
+			Owner:   "monalisa",
+			Host:    "github.com",
+		},
+		{
+			Name: "local",
+			Kind: LocalKind,
+			Path: filepath.Join(localDir, "goctl-local"),
+		},
+	}, exts)
+}
+
+func TestListMissingDir(t *testing.T) {
+	exts, err := list(filepath.Join(t.TempDir(), "extensions"))
+	assert.NoError(t, err)
+	assert.Equal(t, []Extension{}, exts)
+}
+
+func TestHeadCommit(t *testing.T) {
+	gitDir := t.TempDir()
+	assert.Equal(t, "", headCommit(gitDir))
+
+	writeFile(t, filepath.Join(gitDir, "HEAD"), "ref: refs/heads/main\n")
+	writeFile(t, filepath.Join(gitDir, "refs", "heads", "main"), "1234567890abcdef\n")
+	assert.Equal(t, "1234567890abcdef", headCommit(gitDir))
+
+	writeFile(t, filepath.Join(gitDir, "HEAD"), "fedcba0987654321\n")
+	assert.Equal(t, "fedcba0987654321", headCommit(gitDir))
+}
@@ -0,0 +1,134 @@
+package extensions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestSchemaVersion is the latest version of the manifest schema that is supported.
+// Manifests written before the schema was versioned have no version and are treated as
+// version 1.
+const ManifestSchemaVersion = 1
+
+// Manifest describes an installed binary extension. It is stored as manifest.yml in
+// the directory of the extension.
+type Manifest struct {
+	// SchemaVersion is the version of the manifest schema.
+	SchemaVersion int `yaml:"schema_version,omitempty"`
+	// Owner is the owner of the repository the extension was installed from.
+	Owner string `yaml:"owner"`
+	// Name is the name of the repository, including the "goctl-" prefix.
+	Name string `yaml:"name"`
+	// Host is the host of the repository. It defaults to github.com.
+	Host string `yaml:"host"`
+	// Tag is the tag of the installed release.
+	Tag string `yaml:"tag"`
+	// IsPinned reports whether the extension is pinned to its release.
+	IsPinned bool `yaml:"ispinned"`
+	// Path is the location of the executable. It defaults to an executable named
+	// after the extension in the directory of the manifest.
+	Path string `yaml:"path,omitempty"`
+}
+
+// ManifestError is returned when a manifest cannot be parsed or is invalid.
+type ManifestError struct {
+	// Path is the location of the manifest, if it was read from a file.
+	Path string
+	Err  error
+}
+
+func (e *ManifestError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("invalid extension manifest: %s", e.Err)
+	}
+	return fmt.Sprintf("invalid extension manifest %s: %s", e.Path, e.Err)
+}
+
+func (e *ManifestError) Unwrap() error {
+	return e.Err
+}
+
+// FieldError describes an invalid field of a manifest.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+var (
+	ownerRE = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9_.-]*[A-Za-z0-9_])?$`)
+	nameRE  = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+// ReadManifest reads and validates the manifest at path.
+func ReadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := ParseManifest(data)
+	var manifestErr *ManifestError
+	if errors.As(err, &manifestErr) {
+		manifestErr.Path = path
+	}
+	return m, err
+}
+
+// ParseManifest parses and validates a manifest. Any errors are returned as a
+// *ManifestError, which wraps a *FieldError for each invalid field.
+func ParseManifest(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, &ManifestError{Err: err}
+	}
+	if m.Host == "" {
+		m.Host = "github.com"
+	}
+	if err := m.Validate(); err != nil {
+		return nil, &ManifestError{Err: err}
+	}
+	return &m, nil
+}
+
+// Validate reports the invalid fields of the manifest, joining a *FieldError for each.
+// Extension authors can use it to check the manifests their tooling writes.
+func (m *Manifest) Validate() error {
+	var errs []error
+	invalid := func(field, format string, args ...interface{}) {
+		errs = append(errs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	if m.SchemaVersion < 0 || m.SchemaVersion > ManifestSchemaVersion {
+		invalid("schema_version", "unsupported version %d", m.SchemaVersion)
+	}
+	switch {
+	case m.Name == "":
+		invalid("name", "is required")
+	case !strings.HasPrefix(m.Name, prefix) || m.Name == prefix:
+		invalid("name", "%q does not start with %q", m.Name, prefix)
+	case !nameRE.MatchString(m.Name):
+		invalid("name", "%q is not a valid repository name", m.Name)
+	}
+	switch {
+	case m.Owner == "":
+		invalid("owner", "is required")
+	case !ownerRE.MatchString(m.Owner):
+		invalid("owner", "%q is not a valid owner", m.Owner)
+	}
+	if m.Host == "" {
+		invalid("host", "is required")
+	} else if strings.ContainsAny(m.Host, "/: ") {
+		invalid("host", "%q is not a hostname", m.Host)
+	}
+	if m.Tag == "" {
+		invalid("tag", "is required")
+	}
+	return errors.Join(errs...)
+}
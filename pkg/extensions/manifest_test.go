@@ -0,0 +1,151 @@
+package extensions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		want       *Manifest
+		wantErrs   []string
+		wantFields []string
+	}{
+		{
+			name: "valid manifest",
+			data: heredoc.Doc(`
+				schema_version: 1
+				owner: hubot
+				name: goctl-bin
+				host: ghe.example.com
+				tag: v1.2.3
+				ispinned: true
+				path: /extensions/goctl-bin/goctl-bin
+			`),
+			want: &Manifest{
+				SchemaVersion: 1,
+				Owner:         "hubot",
+				Name:          "goctl-bin",
+				Host:          "ghe.example.com",
+				Tag:           "v1.2.3",
+				IsPinned:      true,
+				Path:          "/extensions/goctl-bin/goctl-bin",
+			},
+		},
+		{
+			name: "unversioned manifest defaults host",
+			data: heredoc.Doc(`
+				owner: hubot
+				name: goctl-bin
+				tag: v1.2.3
+			`),
+			want: &Manifest{
+				Owner: "hubot",
+				Name:  "goctl-bin",
+				Host:  "github.com",
+				Tag:   "v1.2.3",
+			},
+		},
+		{
+			name:     "invalid yaml",
+			data:     "owner: [hubot",
+			wantErrs: []string{"invalid extension manifest: yaml:"},
+		},
+		{
+			name:       "missing fields",
+			data:       "ispinned: true\n",
+			wantFields: []string{"name", "owner", "tag"},
+		},
+		{
+			name: "invalid fields",
+			data: heredoc.Doc(`
+				schema_version: 2
+				owner: -hubot
+				name: bin
+				host: https://github.com
+				tag: v1.2.3
+			`),
+			wantFields: []string{"schema_version", "name", "owner", "host"},
+			wantErrs: []string{
+				"schema_version: unsupported version 2",
+				`name: "bin" does not start with "goctl-"`,
+				`owner: "-hubot" is not a valid owner`,
+				`host: "https://github.com" is not a hostname`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseManifest([]byte(tt.data))
+			if tt.want != nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, m)
+				return
+			}
+			var manifestErr *ManifestError
+			assert.ErrorAs(t, err, &manifestErr)
+			for _, e := range tt.wantErrs {
+				assert.Contains(t, err.Error(), e)
+			}
+			var fields []string
+			for _, e := range unwrapAll(err) {
+				if fieldErr, ok := e.(*FieldError); ok {
+					fields = append(fields, fieldErr.Field)
+				}
+			}
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
+
+func TestReadManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.yml")
+	assert.NoError(t, os.WriteFile(path, []byte("owner: hubot\nname: goctl-bin\n"), 0600))
+
+	_, err := ReadManifest(path)
+	assert.EqualError(t, err, "invalid extension manifest "+path+": tag: is required")
+
+	_, err = ReadManifest(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestListInvalidManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "goctl-bin", "manifest.yml"), "owner: hubot\nname: goctl-bin\n")
+	writeFile(t, filepath.Join(dir, "goctl-script", "goctl-script"), "#!/bin/sh\n")
+
+	exts, err := list(dir)
+	assert.NoError(t, err)
+	assert.Len(t, exts, 2)
+	assert.Equal(t, "bin", exts[0].Name)
+	assert.Equal(t, BinaryKind, exts[0].Kind)
+	var manifestErr *ManifestError
+	assert.ErrorAs(t, exts[0].LoadError, &manifestErr)
+	assert.Equal(t, "script", exts[1].Name)
+	assert.NoError(t, exts[1].LoadError)
+
+	_, err = find(dir, "bin")
+	assert.ErrorAs(t, err, &manifestErr)
+	ext, err := find(dir, "script")
+	assert.NoError(t, err)
+	assert.Equal(t, GitKind, ext.Kind)
+}
+
+func unwrapAll(err error) []error {
+	errs := []error{err}
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		errs = append(errs, unwrapAll(e.Unwrap())...)
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			errs = append(errs, unwrapAll(inner)...)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,129 @@
+package extensions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/execsafer"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/logging"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/middleware"
+)
+
+// NotFoundError is returned when no extension with the requested name is installed.
+type NotFoundError struct {
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("extension %q is not installed", e.Name)
+}
+
+// ExitError is returned when an extension runs but exits with a non-zero status.
+type ExitError struct {
+	Name     string
+	ExitCode int
+	Err      error
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("extension %q exited with status %d", e.Name, e.ExitCode)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// IOOptions configures the process an extension runs in.
+type IOOptions struct {
+	// Stdin, Stdout, and Stderr are connected to the extension. Nil streams are
+	// connected to those of the parent process.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Env holds additional environment variables in the "key=value" form, which
+	// are added to the environment of the parent process.
+	Env []string
+}
+
+// Find returns the installed extension with the given name, which may include the
+// "goctl-" prefix. If the installation of the extension cannot be read, the extension
+// is returned along with its LoadError.
+func Find(name string) (Extension, error) {
+	return find(Dir(), name)
+}
+
+func find(dir, name string) (Extension, error) {
+	name = strings.TrimPrefix(name, prefix)
+	exts, err := list(dir)
+	if err != nil {
+		return Extension{}, err
+	}
+	for _, ext := range exts {
+		if ext.Name == name {
+			return ext, ext.LoadError
+		}
+	}
+	return Extension{}, &NotFoundError{Name: name}
+}
+
+// Run locates the extension with the given name and runs it with args, waiting for it
+// to exit. Output is streamed to the writers of opts as it is produced. It returns a
+// *NotFoundError if the extension is not installed and an *ExitError if it fails.
+func Run(ctx context.Context, name string, args []string, opts IOOptions) error {
+	ext, err := Find(name)
+	if err != nil {
+		return err
+	}
+	return run(ctx, ext, args, opts)
+}
+
+func run(ctx context.Context, ext Extension, args []string, opts IOOptions) error {
+	exe := ext.Path
+	if runtime.GOOS == "windows" && ext.Kind != BinaryKind && !strings.HasSuffix(strings.ToLower(exe), ".exe") {
+		// Script extensions rely on a shebang line, which Windows does not support.
+		shExe, err := execsafer.LookPath("sh")
+		if err != nil {
+			return fmt.Errorf("the %q extension requires sh to run: %w", ext.Name, err)
+		}
+		args = append([]string{exe}, args...)
+		exe = shExe
+	}
+
+	cmd := middleware.Cmd{Path: exe, Args: args}
+	cmd.Stdin = opts.Stdin
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	cmd.Stdout = opts.Stdout
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	cmd.Stderr = opts.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
+
+	start := time.Now()
+	err := middleware.Run(ctx, cmd)
+	logger := logging.Logger().With("extension", ext.Name, "path", exe, "args", args, "duration", time.Since(start))
+	if err != nil {
+		logger.DebugContext(ctx, "extension execution failed", "error", err)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && ctx.Err() == nil {
+			return &ExitError{Name: ext.Name, ExitCode: exitErr.ExitCode(), Err: err}
+		}
+		return fmt.Errorf("could not run extension %q: %w", ext.Name, err)
+	}
+	logger.DebugContext(ctx, "extension executed")
+	return nil
+}
@@ -0,0 +1,76 @@
+package extensions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GOCTL_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args[3:]
+	if len(args) > 0 && args[0] == "fail" {
+		fmt.Fprint(os.Stderr, "failed")
+		os.Exit(3)
+	}
+	fmt.Fprintf(os.Stdout, "%s %s", strings.Join(args, " "), os.Getenv("GOCTL_EXTENSION_TEST"))
+	os.Exit(0)
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir+"/goctl-hello/manifest.yml", "owner: hubot\nname: goctl-hello\ntag: v1.0.0\n")
+
+	ext, err := find(dir, "hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.0", ext.Version)
+
+	ext, err = find(dir, "goctl-hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", ext.Name)
+
+	_, err = find(dir, "missing")
+	var notFound *NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.EqualError(t, err, `extension "missing" is not installed`)
+}
+
+func TestRun(t *testing.T) {
+	ext := Extension{Name: "hello", Kind: BinaryKind, Path: os.Args[0]}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	opts := IOOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+		Env:    []string{"GOCTL_WANT_HELPER_PROCESS=1", "GOCTL_EXTENSION_TEST=injected"},
+	}
+
+	err := run(context.Background(), ext, []string{"-test.run=TestHelperProcess", "--", "world"}, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "world injected", stdout.String())
+
+	err = run(context.Background(), ext, []string{"-test.run=TestHelperProcess", "--", "fail"}, opts)
+	var exitErr *ExitError
+	assert.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.ExitCode)
+	assert.EqualError(t, err, `extension "hello" exited with status 3`)
+	assert.Equal(t, "failed", stderr.String())
+}
+
+func TestRunCancelled(t *testing.T) {
+	ext := Extension{Name: "hello", Kind: BinaryKind, Path: os.Args[0]}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := run(ctx, ext, []string{"-test.run=TestHelperProcess", "--"}, IOOptions{Env: []string{"GOCTL_WANT_HELPER_PROCESS=1"}})
+	assert.ErrorIs(t, err, context.Canceled)
+	var exitErr *ExitError
+	assert.False(t, errors.As(err, &exitErr))
+}
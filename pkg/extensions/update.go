@@ -0,0 +1,121 @@
+package extensions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/semver"
+)
+
+// updateCheckTTL is how long the results of checking an extension for updates are cached.
+const updateCheckTTL = 24 * time.Hour
+
+// Update is an available update of an installed extension.
+type Update struct {
+	Extension Extension
+	// LatestVersion is the latest release tag of binary extensions or the latest
+	// commit of git extensions.
+	LatestVersion string
+}
+
+// restClient is the subset of [api.RESTClient] used to check for updates.
+type restClient interface {
+	DoWithContext(ctx context.Context, method string, path string, body io.Reader, response interface{}) error
+}
+
+// CheckUpdates returns the available updates of the extensions. Pinned extensions,
+// local extensions, and extensions whose repository is unknown are never updated.
+// API responses are cached for a day, so wrapper CLIs can check for updates on
+// every invocation without exhausting the API rate limit.
+//
+// Extensions that cannot be checked, for example because their repository has been
+// deleted, are skipped, and the errors are returned along with the updates found.
+func CheckUpdates(exts []Extension) ([]Update, error) {
+	return CheckUpdatesContext(context.Background(), exts)
+}
+
+// CheckUpdatesContext is like CheckUpdates but stops checking when the context is done.
+func CheckUpdatesContext(ctx context.Context, exts []Extension) ([]Update, error) {
+	clients := map[string]restClient{}
+	return checkUpdates(ctx, exts, func(host string) (restClient, error) {
+		if c, ok := clients[host]; ok {
+			return c, nil
+		}
+		c, err := api.NewRESTClient(api.ClientOptions{
+			Host:        host,
+			EnableCache: true,
+			CacheTTL:    updateCheckTTL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		clients[host] = c
+		return c, nil
+	})
+}
+
+func checkUpdates(ctx context.Context, exts []Extension, clientForHost func(string) (restClient, error)) ([]Update, error) {
+	updates := []Update{}
+	var errs []error
+	for _, ext := range exts {
+		if ext.LoadError != nil || ext.Pinned || ext.Kind == LocalKind || ext.Owner == "" || ext.Host == "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		client, err := clientForHost(ext.Host)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		latest, err := latestVersion(ctx, client, ext)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not check %q for updates: %w", ext.Name, err))
+			continue
+		}
+		if isNewer(ext, latest) {
+			updates = append(updates, Update{Extension: ext, LatestVersion: latest})
+		}
+	}
+	return updates, errors.Join(errs...)
+}
+
+func latestVersion(ctx context.Context, client restClient, ext Extension) (string, error) {
+	repo := fmt.Sprintf("repos/%s/%s%s", ext.Owner, prefix, ext.Name)
+	if ext.Kind == BinaryKind {
+		var release struct {
+			TagName string `json:"tag_name"`
+		}
+		err := client.DoWithContext(ctx, "GET", repo+"/releases/latest", nil, &release)
+		return release.TagName, err
+	}
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	err := client.DoWithContext(ctx, "GET", repo+"/commits/HEAD", nil, &commit)
+	return commit.SHA, err
+}
+
+// isNewer reports whether latest is a newer version than the installed version of an
+// extension. Release tags that are semantic versions are compared by precedence, so that
+// an extension installed from a newer prerelease is not downgraded; other versions, such
+// as commits, are newer whenever they differ.
+func isNewer(ext Extension, latest string) bool {
+	if latest == "" || latest == ext.Version {
+		return false
+	}
+	if ext.Kind == BinaryKind {
+		current, err1 := semver.Parse(ext.Version)
+		next, err2 := semver.Parse(latest)
+		if err1 == nil && err2 == nil {
+			return current.Less(next)
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package extensions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRESTClient struct {
+	responses map[string]string
+	requests  []string
+}
+
+func (c *fakeRESTClient) DoWithContext(ctx context.Context, method string, path string, _ io.Reader, resp interface{}) error {
+	c.requests = append(c.requests, path)
+	body, ok := c.responses[path]
+	if !ok {
+		return errors.New("HTTP 404: Not Found")
+	}
+	return json.Unmarshal([]byte(body), resp)
+}
+
+func TestCheckUpdates(t *testing.T) {
+	client := &fakeRESTClient{responses: map[string]string{
+		"repos/hubot/goctl-bin/releases/latest":     `{"tag_name": "v1.3.0"}`,
+		"repos/hubot/goctl-current/releases/latest": `{"tag_name": "v2.0.0"}`,
+		"repos/monalisa/goctl-hello/commits/HEAD":   `{"sha": "bbbb"}`,
+	}}
+	exts := []Extension{
+		{Name: "bin", Kind: BinaryKind, Version: "v1.2.3", Owner: "hubot", Host: "github.com"},
+		{Name: "current", Kind: BinaryKind, Version: "v2.0.0", Owner: "hubot", Host: "github.com"},
+		{Name: "hello", Kind: GitKind, Version: "aaaa", Owner: "monalisa", Host: "github.com"},
+		{Name: "pinned", Kind: BinaryKind, Version: "v0.1.0", Pinned: true, Owner: "hubot", Host: "github.com"},
+		{Name: "local", Kind: LocalKind},
+		{Name: "deleted", Kind: BinaryKind, Version: "v1.0.0", Owner: "hubot", Host: "github.com"},
+	}
+	var hosts []string
+	updates, err := checkUpdates(context.Background(), exts, func(host string) (restClient, error) {
+		hosts = append(hosts, host)
+		return client, nil
+	})
+	assert.EqualError(t, err, `could not check "deleted" for updates: HTTP 404: Not Found`)
+	assert.Equal(t, []Update{
+		{Extension: exts[0], LatestVersion: "v1.3.0"},
+		{Extension: exts[2], LatestVersion: "bbbb"},
+	}, updates)
+	assert.Equal(t, []string{
+		"repos/hubot/goctl-bin/releases/latest",
+		"repos/hubot/goctl-current/releases/latest",
+		"repos/monalisa/goctl-hello/commits/HEAD",
+		"repos/hubot/goctl-deleted/releases/latest",
+	}, client.requests)
+	assert.Len(t, hosts, 4)
+}
+
+func TestCheckUpdatesClientError(t *testing.T) {
+	exts := []Extension{{Name: "bin", Kind: BinaryKind, Owner: "hubot", Host: "ghe.io"}}
+	updates, err := checkUpdates(context.Background(), exts, func(host string) (restClient, error) {
+		return nil, errors.New("authentication token not found for host ghe.io")
+	})
+	assert.EqualError(t, err, "authentication token not found for host ghe.io")
+	assert.Equal(t, []Update{}, updates)
+}
+
+func TestCheckUpdatesContextCancelled(t *testing.T) {
+	exts := []Extension{{Name: "bin", Kind: BinaryKind, Owner: "hubot", Host: "github.com"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	updates, err := checkUpdates(ctx, exts, func(host string) (restClient, error) {
+		t.Fatal("unexpected client for " + host)
+		return nil, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []Update{}, updates)
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		name   string
+		ext    Extension
+		latest string
+		want   bool
+	}{
+		{name: "newer release", ext: Extension{Kind: BinaryKind, Version: "v1.2.3"}, latest: "v1.10.0", want: true},
+		{name: "same release without prefix", ext: Extension{Kind: BinaryKind, Version: "1.2.3"}, latest: "v1.2.3"},
+		{name: "installed prerelease is newer", ext: Extension{Kind: BinaryKind, Version: "v2.0.0-rc.1"}, latest: "v1.9.0"},
+		{name: "release of prerelease", ext: Extension{Kind: BinaryKind, Version: "v2.0.0-rc.1"}, latest: "v2.0.0", want: true},
+		{name: "non-semver tag", ext: Extension{Kind: BinaryKind, Version: "nightly-1"}, latest: "nightly-2", want: true},
+		{name: "git commit", ext: Extension{Kind: GitKind, Version: "aaaa"}, latest: "bbbb", want: true},
+		{name: "unknown latest", ext: Extension{Kind: BinaryKind, Version: "v1.0.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isNewer(tt.ext, tt.latest))
+		})
+	}
+}
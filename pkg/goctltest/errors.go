@@ -0,0 +1,54 @@
+package goctltest
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	goctl "github.com/khulnasoft-lab/go-goctl/v2"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/extensions"
+)
+
+// RequireHTTPStatus fails the test immediately unless err is, or wraps, an [api.HTTPError]
+// with the given status code.
+func RequireHTTPStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var httpErr *api.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected an HTTP %d error, got %v", status, err)
+	}
+	if httpErr.StatusCode != status {
+		t.Fatalf("expected an HTTP %d error, got %v", status, err)
+	}
+}
+
+// RequireExitCode fails the test immediately unless err is, or wraps, the error of a
+// command that exited with the given code: a [goctl.ExecError], an [extensions.ExitError],
+// or an [exec.ExitError].
+func RequireExitCode(t *testing.T, err error, code int) {
+	t.Helper()
+	got, ok := exitCode(err)
+	if !ok {
+		t.Fatalf("expected exit code %d, got %v", code, err)
+	}
+	if got != code {
+		t.Fatalf("expected exit code %d, got %d: %v", code, got, err)
+	}
+}
+
+func exitCode(err error) (int, bool) {
+	var goctlErr *goctl.ExecError
+	if errors.As(err, &goctlErr) {
+		return goctlErr.ExitCode, true
+	}
+	var extErr *extensions.ExitError
+	if errors.As(err, &extErr) {
+		return extErr.ExitCode, true
+	}
+	var execErr *exec.ExitError
+	if errors.As(err, &execErr) {
+		return execErr.ExitCode(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,44 @@
+package goctltest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	goctl "github.com/khulnasoft-lab/go-goctl/v2"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/extensions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireHTTPStatus(t *testing.T) {
+	err := fmt.Errorf("could not fetch: %w", &api.HTTPError{StatusCode: 404})
+	RequireHTTPStatus(t, err, 404)
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantOK   bool
+	}{
+		{name: "goctl", err: &goctl.ExecError{ExitCode: 2}, wantCode: 2, wantOK: true},
+		{name: "extension", err: fmt.Errorf("wrapped: %w", &extensions.ExitError{ExitCode: 3}), wantCode: 3, wantOK: true},
+		{name: "other", err: errors.New("boom"), wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, ok := exitCode(tt.err)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantCode, code)
+		})
+	}
+}
+
+func TestRequireExitCodeStub(t *testing.T) {
+	stub := NewExecStub(t)
+	stub.Register(`^pr merge`, ExecResult{ExitCode: 4})
+	_, _, err := goctl.Exec("pr", "merge")
+	RequireExitCode(t, err, 4)
+}
@@ -0,0 +1,190 @@
+// Package goctltest provides helpers for testing code built with go-goctl.
+package goctltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// execStubEnv holds the address of the ExecStub that a stubbed goctl process reports to.
+const execStubEnv = "GOCTLTEST_EXEC_STUB"
+
+// execStubReady records whether RunExecStubIfRequested has been called.
+var execStubReady bool
+
+// ExecResult is the canned outcome of a stubbed goctl command.
+type ExecResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// ExecStub intercepts the goctl commands run with [goctl.Exec] and related functions, and
+// answers them with registered results instead of running goctl. It is the subprocess
+// analog of stubbing HTTP requests.
+//
+// The stub points GOCTL_PATH at the test binary, which acts as the stubbed goctl when it is
+// run by the code under test, so tests using an ExecStub cannot run in parallel. For the
+// test binary to act as goctl, the TestMain of the package must call RunExecStubIfRequested
+// before running the tests:
+//
+//	func TestMain(m *testing.M) {
+//		goctltest.RunExecStubIfRequested()
+//		os.Exit(m.Run())
+//	}
+//
+//	func TestListIssues(t *testing.T) {
+//		stub := goctltest.NewExecStub(t)
+//		stub.Register(`^issue list --json number`, goctltest.ExecResult{Stdout: `[{"number":1}]`})
+//		issues, err := ListIssues()
+//		...
+//	}
+type ExecStub struct {
+	t      *testing.T
+	stubs  stubRegistry
+	mu     sync.Mutex
+	calls  [][]string
+	server *httptest.Server
+}
+
+type execStub struct {
+	pattern *regexp.Regexp
+	result  ExecResult
+	matched bool
+}
+
+// stubRegistry holds the results registered with an ExecStub or a FakeExecutor, each of
+// which answers a single goctl command.
+type stubRegistry struct {
+	t     *testing.T
+	mu    sync.Mutex
+	stubs []*execStub
+}
+
+func (r *stubRegistry) register(pattern string, result ExecResult) {
+	r.t.Helper()
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		r.t.Fatalf("invalid pattern %q: %v", pattern, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stubs = append(r.stubs, &execStub{pattern: re, result: result})
+}
+
+// match returns the result of the first unused stub matching args, or fails the test and
+// returns the result of a missing command if there is none.
+func (r *stubRegistry) match(args []string) ExecResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	command := strings.Join(args, " ")
+	for _, stub := range r.stubs {
+		if !stub.matched && stub.pattern.MatchString(command) {
+			stub.matched = true
+			return stub.result
+		}
+	}
+	r.t.Errorf("unexpected goctl command: %q", command)
+	return ExecResult{Stderr: fmt.Sprintf("goctltest: no stub for goctl %s\n", command), ExitCode: 127}
+}
+
+// verify fails the test if any stub has not been used.
+func (r *stubRegistry) verify() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unmatched []string
+	for _, stub := range r.stubs {
+		if !stub.matched {
+			unmatched = append(unmatched, stub.pattern.String())
+		}
+	}
+	if len(unmatched) > 0 {
+		r.t.Errorf("%d unmatched goctl stubs: %s", len(unmatched), strings.Join(unmatched, ", "))
+	}
+}
+
+// NewExecStub starts intercepting goctl commands until the end of the test, when it also
+// fails the test if any registered stub has not been matched.
+func NewExecStub(t *testing.T) *ExecStub {
+	t.Helper()
+	if !execStubReady {
+		t.Fatal("goctltest.RunExecStubIfRequested must be called from TestMain to use an ExecStub")
+	}
+	s := &ExecStub{t: t, stubs: stubRegistry{t: t}}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Setenv("GOCTL_PATH", os.Args[0])
+	t.Setenv(execStubEnv, s.server.URL)
+	t.Cleanup(func() {
+		s.server.Close()
+		s.stubs.verify()
+	})
+	return s
+}
+
+// Register adds a stub answering the first goctl command whose arguments, joined by spaces,
+// match the regular expression pattern. Each stub answers a single command, and stubs are
+// matched in the order they were registered.
+func (s *ExecStub) Register(pattern string, result ExecResult) {
+	s.t.Helper()
+	s.stubs.register(pattern, result)
+}
+
+// Calls returns the arguments of the goctl commands that have been run, in order.
+func (s *ExecStub) Calls() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.calls...)
+}
+
+func (s *ExecStub) serve(w http.ResponseWriter, r *http.Request) {
+	var args []string
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.calls = append(s.calls, args)
+	s.mu.Unlock()
+	_ = json.NewEncoder(w).Encode(s.stubs.match(args))
+}
+
+// RunExecStubIfRequested turns the test binary into the stubbed goctl when it is run by
+// the code under test on behalf of an ExecStub, in which case it exits instead of returning.
+// It must be called from the TestMain of packages that use NewExecStub.
+func RunExecStubIfRequested() {
+	execStubReady = true
+	addr := os.Getenv(execStubEnv)
+	if addr == "" {
+		return
+	}
+	os.Exit(runStubbedCommand(addr, os.Args[1:]))
+}
+
+func runStubbedCommand(addr string, args []string) int {
+	if args == nil {
+		args = []string{}
+	}
+	body, _ := json.Marshal(args)
+	resp, err := http.Post(addr, "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goctltest: %v\n", err)
+		return 127
+	}
+	defer resp.Body.Close()
+	var result ExecResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		fmt.Fprintf(os.Stderr, "goctltest: %v\n", err)
+		return 127
+	}
+	fmt.Fprint(os.Stdout, result.Stdout)
+	fmt.Fprint(os.Stderr, result.Stderr)
+	return result.ExitCode
+}
@@ -0,0 +1,65 @@
+package goctltest
+
+import (
+	"os"
+	"testing"
+
+	goctl "github.com/khulnasoft-lab/go-goctl/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	RunExecStubIfRequested()
+	os.Exit(m.Run())
+}
+
+func TestExecStub(t *testing.T) {
+	stub := NewExecStub(t)
+	stub.Register(`^issue list`, ExecResult{Stdout: `[{"number":1}]`})
+	stub.Register(`^pr view 2$`, ExecResult{Stderr: "no pull requests found\n", ExitCode: 1})
+	stub.Register(`^issue list`, ExecResult{Stdout: `[]`})
+
+	stdout, stderr, err := goctl.Exec("issue", "list", "--json", "number")
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"number":1}]`, stdout.String())
+	assert.Equal(t, "", stderr.String())
+
+	stdout, stderr, err = goctl.Exec("pr", "view", "2")
+	assert.EqualError(t, err, "goctl execution failed: exit status 1")
+	assert.Equal(t, "", stdout.String())
+	assert.Equal(t, "no pull requests found\n", stderr.String())
+
+	stdout, _, err = goctl.Exec("issue", "list")
+	assert.NoError(t, err)
+	assert.Equal(t, `[]`, stdout.String())
+
+	assert.Equal(t, [][]string{
+		{"issue", "list", "--json", "number"},
+		{"pr", "view", "2"},
+		{"issue", "list"},
+	}, stub.Calls())
+}
+
+func TestStubRegistry(t *testing.T) {
+	fakeT := &testing.T{}
+	r := &stubRegistry{t: fakeT}
+	r.register(`^repo clone`, ExecResult{Stdout: "cloned"})
+	r.register(`^repo`, ExecResult{Stdout: "other"})
+	assert.Equal(t, "cloned", r.match([]string{"repo", "clone", "cli/cli"}).Stdout)
+	assert.Equal(t, "other", r.match([]string{"repo", "clone", "cli/cli"}).Stdout)
+	r.verify()
+	assert.False(t, fakeT.Failed())
+
+	fakeT = &testing.T{}
+	r = &stubRegistry{t: fakeT}
+	r.register(`^repo clone`, ExecResult{})
+	r.verify()
+	assert.True(t, fakeT.Failed())
+
+	fakeT = &testing.T{}
+	r = &stubRegistry{t: fakeT}
+	result := r.match([]string{"repo", "clone"})
+	assert.True(t, fakeT.Failed())
+	assert.Equal(t, 127, result.ExitCode)
+	assert.Equal(t, "goctltest: no stub for goctl repo clone\n", result.Stderr)
+}
@@ -0,0 +1,96 @@
+package goctltest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	goctl "github.com/khulnasoft-lab/go-goctl/v2"
+)
+
+// Invocation is a goctl command run with a FakeExecutor.
+type Invocation struct {
+	Args  []string
+	Dir   string
+	Env   []string
+	Stdin string
+}
+
+// FakeExecutor is a [goctl.Executor] that answers goctl commands with registered results
+// and records them, for testing code that accepts an Executor. Unlike ExecStub it runs no
+// processes, so tests using it can run in parallel.
+//
+//	func TestListIssues(t *testing.T) {
+//		exec := goctltest.NewFakeExecutor(t)
+//		exec.Register(`^issue list --json number`, goctltest.ExecResult{Stdout: `[{"number":1}]`})
+//		issues, err := ListIssues(exec)
+//		...
+//	}
+type FakeExecutor struct {
+	t           *testing.T
+	stubs       stubRegistry
+	mu          sync.Mutex
+	invocations []Invocation
+}
+
+// NewFakeExecutor returns a FakeExecutor that fails the test at its end if any registered
+// result has not been used.
+func NewFakeExecutor(t *testing.T) *FakeExecutor {
+	t.Helper()
+	f := &FakeExecutor{t: t, stubs: stubRegistry{t: t}}
+	t.Cleanup(f.stubs.verify)
+	return f
+}
+
+// Register adds a result answering the first goctl command whose arguments, joined by
+// spaces, match the regular expression pattern. Each result answers a single command, and
+// results are matched in the order they were registered.
+func (f *FakeExecutor) Register(pattern string, result ExecResult) {
+	f.t.Helper()
+	f.stubs.register(pattern, result)
+}
+
+// Invocations returns the goctl commands that have been run, in order.
+func (f *FakeExecutor) Invocations() []Invocation {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Invocation(nil), f.invocations...)
+}
+
+// ExecWith records the command and writes the output of the matching result to the
+// streams of opts. A result with a non-zero exit code is returned as a [goctl.ExecError].
+// Commands without a matching result fail the test and exit with code 127.
+func (f *FakeExecutor) ExecWith(ctx context.Context, opts goctl.ExecOptions, args ...string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("goctl execution failed: %w", err)
+	}
+	inv := Invocation{Args: args, Dir: opts.Dir, Env: opts.Env}
+	if opts.Stdin != nil {
+		stdin, err := io.ReadAll(opts.Stdin)
+		if err != nil {
+			return fmt.Errorf("goctl execution failed: %w", err)
+		}
+		inv.Stdin = string(stdin)
+	}
+	f.mu.Lock()
+	f.invocations = append(f.invocations, inv)
+	f.mu.Unlock()
+	result := f.stubs.match(args)
+	if opts.Stdout != nil {
+		_, _ = io.WriteString(opts.Stdout, result.Stdout)
+	}
+	if opts.Stderr != nil {
+		_, _ = io.WriteString(opts.Stderr, result.Stderr)
+	}
+	if result.ExitCode != 0 {
+		return &goctl.ExecError{
+			Args:     args,
+			ExitCode: result.ExitCode,
+			Err:      fmt.Errorf("exit status %d", result.ExitCode),
+			Stderr:   result.Stderr,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package goctltest
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	goctl "github.com/khulnasoft-lab/go-goctl/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeExecutor(t *testing.T) {
+	t.Parallel()
+	fake := NewFakeExecutor(t)
+	fake.Register(`^issue list`, ExecResult{Stdout: `[{"number":1}]`})
+	fake.Register(`^pr view 2$`, ExecResult{Stderr: "GraphQL: Could not resolve to a PullRequest with the number of 2.\n", ExitCode: 1})
+
+	var e goctl.Executor = fake
+	var stdout, stderr bytes.Buffer
+	err := e.ExecWith(context.Background(), goctl.ExecOptions{
+		Dir:    "/src/repo",
+		Env:    []string{"GOCTL_HOST=ghe.io"},
+		Stdin:  strings.NewReader("input"),
+		Stdout: &stdout,
+	}, "issue", "list", "--json", "number")
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"number":1}]`, stdout.String())
+
+	err = e.ExecWith(context.Background(), goctl.ExecOptions{Stderr: &stderr}, "pr", "view", "2")
+	assert.EqualError(t, err, "goctl execution failed: exit status 1")
+	RequireExitCode(t, err, 1)
+	assert.True(t, goctl.IsNotFound(err))
+	assert.Equal(t, "GraphQL: Could not resolve to a PullRequest with the number of 2.\n", stderr.String())
+
+	assert.Equal(t, []Invocation{
+		{Args: []string{"issue", "list", "--json", "number"}, Dir: "/src/repo", Env: []string{"GOCTL_HOST=ghe.io"}, Stdin: "input"},
+		{Args: []string{"pr", "view", "2"}},
+	}, fake.Invocations())
+}
+
+func TestFakeExecutorUnexpectedCommand(t *testing.T) {
+	fakeT := &testing.T{}
+	fake := &FakeExecutor{t: fakeT, stubs: stubRegistry{t: fakeT}}
+	err := fake.ExecWith(context.Background(), goctl.ExecOptions{}, "repo", "clone")
+	assert.True(t, fakeT.Failed())
+	RequireExitCode(t, err, 127)
+}
@@ -0,0 +1,132 @@
+package goctltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// update reports whether AssertGolden should rewrite golden files instead of comparing
+// against them, which is enabled with the -goctltest.update flag or the GOCTLTEST_UPDATE
+// environment variable.
+var update = optIn("goctltest.update", "GOCTLTEST_UPDATE", "update golden files in testdata")
+
+// optIn registers the boolean flag name, unless it is already registered, and returns a
+// function that reports whether the flag or the environment variable env is set to true.
+// The flag is namespaced so that it does not clash with the flags of the package under test.
+func optIn(name, env, usage string) func() bool {
+	if flag.Lookup(name) == nil {
+		flag.Bool(name, false, usage)
+	}
+	return func() bool {
+		if f := flag.Lookup(name); f != nil {
+			if on, _ := strconv.ParseBool(f.Value.String()); on {
+				return true
+			}
+		}
+		on, _ := strconv.ParseBool(os.Getenv(env))
+		return on
+	}
+}
+
+// FixtureVars are the values of the placeholders in a fixture. Host defaults to
+// "github.com" when it is not set.
+type FixtureVars map[string]interface{}
+
+// Fixture returns the contents of the file name in the testdata directory of the package
+// under test, with placeholders expanded using text/template syntax, for example:
+//
+//	{"html_url": "https://{{.Host}}/monalisa/octo-cat", "created_at": "{{ago "48h"}}"}
+//
+// Besides vars, fixtures can use the "now" and "ago" functions, which format the current
+// time, or the current time minus a duration, in RFC 3339 format as the API does.
+func Fixture(t *testing.T, name string, vars FixtureVars) []byte {
+	t.Helper()
+	path := filepath.Join("testdata", filepath.FromSlash(name))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read fixture: %v", err)
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(fixtureFuncs).Parse(string(data))
+	if err != nil {
+		t.Fatalf("could not parse fixture: %v", err)
+	}
+	values := FixtureVars{"Host": "github.com"}
+	for k, v := range vars {
+		values[k] = v
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		t.Fatalf("could not expand fixture: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// JSONFixture decodes the JSON fixture name, as returned by Fixture, into v. GraphQL
+// responses are JSON fixtures too, typically with a top-level "data" field.
+func JSONFixture(t *testing.T, name string, vars FixtureVars, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(Fixture(t, name, vars), v); err != nil {
+		t.Fatalf("could not decode fixture %s: %v", name, err)
+	}
+}
+
+var fixtureFuncs = template.FuncMap{
+	"now": func() string {
+		return time.Now().UTC().Format(time.RFC3339)
+	},
+	"ago": func(d string) (string, error) {
+		duration, err := time.ParseDuration(d)
+		if err != nil {
+			return "", err
+		}
+		return time.Now().UTC().Add(-duration).Format(time.RFC3339), nil
+	},
+}
+
+// AssertGolden compares got with the contents of the golden file name in the testdata
+// directory of the package under test. When the tests are run with the -goctltest.update
+// flag or with GOCTLTEST_UPDATE=1, the golden file is written with got instead:
+//
+//	go test ./... -goctltest.update
+func AssertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", filepath.FromSlash(name))
+	if update() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("could not update golden file: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("could not update golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("golden file %s does not exist; run the tests with -goctltest.update to create it", path)
+	} else if err != nil {
+		t.Fatalf("could not read golden file: %v", err)
+	}
+	assert.Equal(t, string(want), string(got), "output differs from %s; run the tests with -goctltest.update to update it", path)
+}
+
+// AssertGoldenJSON encodes v as indented JSON and compares it with the golden file name
+// as AssertGolden does.
+func AssertGoldenJSON(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("could not encode JSON: %v", err)
+	}
+	AssertGolden(t, name, append(data, '\n'))
+}
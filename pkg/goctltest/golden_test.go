@@ -0,0 +1,50 @@
+package goctltest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixtureRepo struct {
+	FullName string    `json:"full_name"`
+	HTMLURL  string    `json:"html_url"`
+	PushedAt time.Time `json:"pushed_at,omitempty"`
+	Stars    int       `json:"stargazers_count"`
+}
+
+func TestJSONFixture(t *testing.T) {
+	var repo fixtureRepo
+	JSONFixture(t, "repo.json", FixtureVars{"Stars": 42}, &repo)
+	assert.Equal(t, "monalisa/octo-cat", repo.FullName)
+	assert.Equal(t, "https://github.com/monalisa/octo-cat", repo.HTMLURL)
+	assert.Equal(t, 42, repo.Stars)
+	assert.WithinDuration(t, time.Now().Add(-48*time.Hour), repo.PushedAt, time.Minute)
+
+	JSONFixture(t, "repo.json", FixtureVars{"Host": "ghe.io", "Stars": 0}, &repo)
+	assert.Equal(t, "https://ghe.io/monalisa/octo-cat", repo.HTMLURL)
+}
+
+func TestAssertGoldenJSON(t *testing.T) {
+	AssertGoldenJSON(t, "repo.golden.json", map[string]interface{}{
+		"full_name":        "monalisa/octo-cat",
+		"html_url":         "https://ghe.io/monalisa/octo-cat",
+		"stargazers_count": 42,
+	})
+}
+
+func TestAssertGoldenUpdate(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	t.Setenv("GOCTLTEST_UPDATE", "1")
+
+	AssertGolden(t, "nested/output.golden", []byte("hello\n"))
+	data, err := os.ReadFile(filepath.Join("testdata", "nested", "output.golden"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n", string(data))
+}
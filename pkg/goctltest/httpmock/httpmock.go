@@ -0,0 +1,271 @@
+// Package httpmock stubs the HTTP requests of API clients in tests. Requests are answered
+// by the first registered stub whose matcher accepts them, with canned responses such as
+// JSON bodies or fixture files.
+//
+// To record real interactions and replay them from fixture files, use a
+// [goctltest.Recorder], optionally as the fallback of a Registry so that only some
+// requests are stubbed.
+package httpmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+)
+
+var operationRE = regexp.MustCompile(`^\s*(?:query|mutation|subscription)\s+(\w+)`)
+
+// Matcher reports whether a stub answers a request.
+type Matcher func(req *http.Request) bool
+
+// Responder produces the response of a stub.
+type Responder func(req *http.Request) (*http.Response, error)
+
+// Registry is an [http.RoundTripper] that answers requests with registered stubs.
+//
+//	func TestListIssues(t *testing.T) {
+//		reg := httpmock.New(t)
+//		reg.Register(httpmock.REST("GET", "repos/OWNER/REPO/issues"), httpmock.JSONResponse([]Issue{{Number: 1}}))
+//		client, _ := api.NewRESTClient(reg.ClientOptions("github.com"))
+//		...
+//	}
+type Registry struct {
+	t        *testing.T
+	mu       sync.Mutex
+	stubs    []*stub
+	requests []*http.Request
+	fallback http.RoundTripper
+}
+
+type stub struct {
+	matcher   Matcher
+	responder Responder
+	matched   bool
+}
+
+// New returns a Registry that fails the test at its end if any registered stub has not
+// been matched.
+func New(t *testing.T) *Registry {
+	t.Helper()
+	r := &Registry{t: t}
+	t.Cleanup(r.verify)
+	return r
+}
+
+// Register adds a stub answering the first request accepted by m with the response of
+// resp. Each stub answers a single request, and stubs are matched in the order they were
+// registered.
+func (r *Registry) Register(m Matcher, resp Responder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stubs = append(r.stubs, &stub{matcher: m, responder: resp})
+}
+
+// Fallback sets the transport of requests that no stub answers, such as a
+// [goctltest.Recorder]. By default such requests fail the test.
+func (r *Registry) Fallback(rt http.RoundTripper) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = rt
+}
+
+// Requests returns the requests the Registry has received, in order.
+func (r *Registry) Requests() []*http.Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*http.Request(nil), r.requests...)
+}
+
+// ClientOptions returns options for API clients that send their requests for host to the
+// Registry.
+func (r *Registry) ClientOptions(host string) api.ClientOptions {
+	return api.ClientOptions{
+		Host:         host,
+		AuthToken:    "httpmock-token",
+		Transport:    r,
+		LogIgnoreEnv: true,
+	}
+}
+
+// RoundTrip answers req with the first matching stub.
+func (r *Registry) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.requests = append(r.requests, req)
+	var match *stub
+	for _, s := range r.stubs {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if !s.matched && s.matcher(req) {
+			s.matched = true
+			match = s
+			break
+		}
+	}
+	fallback := r.fallback
+	r.mu.Unlock()
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if match != nil {
+		return match.responder(req)
+	}
+	if fallback != nil {
+		return fallback.RoundTrip(req)
+	}
+	r.t.Errorf("httpmock: no stub for %s %s", req.Method, req.URL)
+	return nil, fmt.Errorf("httpmock: no stub for %s %s", req.Method, req.URL)
+}
+
+func (r *Registry) verify() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	unmatched := 0
+	for _, s := range r.stubs {
+		if !s.matched {
+			unmatched++
+		}
+	}
+	if unmatched > 0 {
+		r.t.Errorf("httpmock: %d unmatched stubs", unmatched)
+	}
+}
+
+// REST matches requests with the method and path, such as "repos/OWNER/REPO". The path
+// is relative to the REST API root, so it also matches requests to GitHub Enterprise
+// Server under "/api/v3/". The query string is not part of the path.
+func REST(method, path string) Matcher {
+	path = strings.Trim(path, "/")
+	return func(req *http.Request) bool {
+		return strings.EqualFold(req.Method, method) && restPath(req.URL) == path
+	}
+}
+
+// Query matches REST requests like REST that also have the given query parameters.
+// Parameters that are not listed are ignored.
+func Query(method, path string, params url.Values) Matcher {
+	rest := REST(method, path)
+	return func(req *http.Request) bool {
+		if !rest(req) {
+			return false
+		}
+		query := req.URL.Query()
+		for name, values := range params {
+			if strings.Join(query[name], ",") != strings.Join(values, ",") {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// GraphQL matches GraphQL requests for the operation with the given name, such as
+// "RepositoryInfo" for "query RepositoryInfo { ... }".
+func GraphQL(operation string) Matcher {
+	return func(req *http.Request) bool {
+		if req.Method != "POST" || !strings.HasSuffix(req.URL.Path, "/graphql") {
+			return false
+		}
+		var body struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return false
+		}
+		m := operationRE.FindStringSubmatch(body.Query)
+		return m != nil && m[1] == operation
+	}
+}
+
+// StringResponse responds with status 200 and the body.
+func StringResponse(body string) Responder {
+	return StatusStringResponse(http.StatusOK, body)
+}
+
+// StatusStringResponse responds with the status and body.
+func StatusStringResponse(status int, body string) Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		return newResponse(req, status, "text/plain; charset=utf-8", []byte(body)), nil
+	}
+}
+
+// JSONResponse responds with status 200 and the body encoded as JSON.
+func JSONResponse(body interface{}) Responder {
+	return StatusJSONResponse(http.StatusOK, body)
+}
+
+// StatusJSONResponse responds with the status and the body encoded as JSON.
+func StatusJSONResponse(status int, body interface{}) Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		return newResponse(req, status, "application/json; charset=utf-8", b), nil
+	}
+}
+
+// GraphQLResponse responds to a GraphQL request with the data encoded as JSON.
+func GraphQLResponse(data interface{}) Responder {
+	return JSONResponse(map[string]interface{}{"data": data})
+}
+
+// GraphQLErrorResponse responds to a GraphQL request with errors that have the given
+// messages.
+func GraphQLErrorResponse(messages ...string) Responder {
+	errs := make([]map[string]string, 0, len(messages))
+	for _, m := range messages {
+		errs = append(errs, map[string]string{"message": m})
+	}
+	return JSONResponse(map[string]interface{}{"errors": errs})
+}
+
+// FileResponse responds with status 200 and the contents of the fixture file at path,
+// such as "testdata/issues.json". Files with the .json extension are served as JSON.
+func FileResponse(path string) Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		contentType := "text/plain; charset=utf-8"
+		if strings.HasSuffix(path, ".json") {
+			contentType = "application/json; charset=utf-8"
+		}
+		return newResponse(req, http.StatusOK, contentType, b), nil
+	}
+}
+
+func newResponse(req *http.Request, status int, contentType string, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     http.Header{"Content-Type": {contentType}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}
+}
+
+// restPath returns the path of a REST API URL relative to the API root.
+func restPath(u *url.URL) string {
+	return strings.Trim(strings.TrimPrefix(u.Path, "/api/v3"), "/")
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
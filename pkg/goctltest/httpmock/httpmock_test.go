@@ -0,0 +1,99 @@
+package httpmock
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryREST(t *testing.T) {
+	reg := New(t)
+	reg.Register(Query("GET", "repos/OWNER/REPO/issues", url.Values{"state": {"open"}}), FileResponse("testdata/issues.json"))
+	reg.Register(REST("GET", "repos/OWNER/REPO/issues"), JSONResponse([]map[string]interface{}{}))
+	reg.Register(REST("DELETE", "repos/OWNER/REPO"), StatusJSONResponse(403, map[string]string{"message": "Must have admin rights"}))
+	client, err := api.NewRESTClient(reg.ClientOptions("ghe.io"))
+	assert.NoError(t, err)
+
+	var issues []struct {
+		Number int
+		Title  string
+	}
+	assert.NoError(t, client.Get("repos/OWNER/REPO/issues?state=open", &issues))
+	assert.Equal(t, 1, len(issues))
+	assert.Equal(t, "Bug", issues[0].Title)
+
+	assert.NoError(t, client.Get("repos/OWNER/REPO/issues?state=closed", &issues))
+	assert.Empty(t, issues)
+
+	err = client.Delete("repos/OWNER/REPO", nil)
+	var httpErr *api.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, 403, httpErr.StatusCode)
+	assert.Equal(t, "Must have admin rights", httpErr.Message)
+
+	requests := reg.Requests()
+	assert.Equal(t, 3, len(requests))
+	assert.Equal(t, "https://ghe.io/api/v3/repos/OWNER/REPO/issues?state=open", requests[0].URL.String())
+	assert.Equal(t, "token httpmock-token", requests[0].Header.Get("Authorization"))
+}
+
+func TestRegistryGraphQL(t *testing.T) {
+	reg := New(t)
+	reg.Register(GraphQL("RepositoryInfo"), GraphQLResponse(map[string]interface{}{
+		"repository": map[string]string{"name": "REPO"},
+	}))
+	reg.Register(GraphQL("RepositoryInfo"), GraphQLErrorResponse("Could not resolve to a Repository"))
+	client, err := api.NewGraphQLClient(reg.ClientOptions("github.com"))
+	assert.NoError(t, err)
+
+	var resp struct {
+		Repository struct{ Name string }
+	}
+	query := `query RepositoryInfo { repository(owner: "OWNER", name: "REPO") { name } }`
+	assert.NoError(t, client.Do(query, nil, &resp))
+	assert.Equal(t, "REPO", resp.Repository.Name)
+
+	err = client.Do(query, nil, &resp)
+	assert.EqualError(t, err, "GraphQL: Could not resolve to a Repository")
+}
+
+func TestRegistryFallback(t *testing.T) {
+	reg := New(t)
+	reg.Fallback(roundTripper(func(req *http.Request) (*http.Response, error) {
+		return StringResponse("from fallback")(req)
+	}))
+	client, err := api.NewRESTClient(reg.ClientOptions("github.com"))
+	assert.NoError(t, err)
+	resp, err := client.Request("GET", "zen", nil)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "from fallback", string(body))
+}
+
+func TestRegistryUnmatched(t *testing.T) {
+	fakeT := &testing.T{}
+	reg := &Registry{t: fakeT}
+	reg.Register(REST("GET", "user"), JSONResponse(nil))
+	req, _ := http.NewRequest("GET", "https://api.github.com/zen", nil)
+	_, err := reg.RoundTrip(req)
+	assert.EqualError(t, err, "httpmock: no stub for GET https://api.github.com/zen")
+	assert.True(t, fakeT.Failed())
+
+	fakeT = &testing.T{}
+	reg = &Registry{t: fakeT}
+	reg.Register(REST("GET", "user"), JSONResponse(nil))
+	reg.verify()
+	assert.True(t, fakeT.Failed())
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt(req)
+}
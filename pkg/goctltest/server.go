@@ -0,0 +1,195 @@
+package goctltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+)
+
+var operationRE = regexp.MustCompile(`^\s*(?:query|mutation|subscription)\s+(\w+)`)
+
+// Server is a local fixture API server for hermetic end-to-end tests. It serves the REST
+// and GraphQL endpoints of github.com over a Unix domain socket, and API clients created
+// with its ClientOptions send their requests to it instead of GitHub.
+//
+//	func TestStar(t *testing.T) {
+//		s := goctltest.NewServer(t)
+//		s.RespondJSON("PUT", "/user/starred/monalisa/octo-cat", 204, nil)
+//		client, _ := api.NewRESTClient(s.ClientOptions())
+//		...
+//	}
+type Server struct {
+	t        *testing.T
+	socket   string
+	mu       sync.Mutex
+	rest     map[string]http.HandlerFunc
+	graphql  map[string]GraphQLHandler
+	requests []RecordedRequest
+}
+
+// GraphQLHandler answers a GraphQL operation given its variables.
+type GraphQLHandler func(variables map[string]interface{}) GraphQLResponse
+
+// GraphQLResponse is the response to a GraphQL operation.
+type GraphQLResponse struct {
+	Data   interface{}            `json:"data,omitempty"`
+	Errors []api.GraphQLErrorItem `json:"errors,omitempty"`
+}
+
+// Scenario registers a coherent set of handlers on a Server, such as the responses of a
+// repository with open pull requests, so that it can be shared between tests.
+type Scenario func(s *Server)
+
+// NewServer starts a Server with the given scenarios loaded. It is stopped at the end of
+// the test, and requests that no handler answers fail the test.
+func NewServer(t *testing.T, scenarios ...Scenario) *Server {
+	t.Helper()
+	// Socket paths are limited to about 100 bytes, which the test temporary directory
+	// can exceed, so a short directory is used instead.
+	dir, err := os.MkdirTemp("", "goctltest")
+	if err != nil {
+		t.Fatalf("could not create server socket directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	s := &Server{
+		t:       t,
+		socket:  filepath.Join(dir, "api.sock"),
+		rest:    map[string]http.HandlerFunc{},
+		graphql: map[string]GraphQLHandler{},
+	}
+	l, err := net.Listen("unix", s.socket)
+	if err != nil {
+		t.Fatalf("could not start server: %v", err)
+	}
+	server := &http.Server{Handler: s}
+	go func() { _ = server.Serve(l) }()
+	t.Cleanup(func() { _ = server.Close() })
+	s.Load(scenarios...)
+	return s
+}
+
+// Load registers the handlers of scenarios. Handlers registered later replace earlier
+// handlers for the same request.
+func (s *Server) Load(scenarios ...Scenario) {
+	for _, scenario := range scenarios {
+		scenario(s)
+	}
+}
+
+// ClientOptions returns options for API clients that send their requests for github.com
+// to the Server.
+func (s *Server) ClientOptions() api.ClientOptions {
+	return api.ClientOptions{
+		Host:             "github.com",
+		AuthToken:        "goctltest-token",
+		UnixDomainSocket: s.socket,
+		LogIgnoreEnv:     true,
+	}
+}
+
+// HandleREST registers the handler of REST requests with the method and path, such as
+// "/repos/monalisa/octo-cat". The query string is not part of the path.
+func (s *Server) HandleREST(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rest[method+" "+path] = handler
+}
+
+// RespondJSON registers a REST handler that responds with the status and body encoded as
+// JSON. A nil body results in an empty response.
+func (s *Server) RespondJSON(method, path string, status int, body interface{}) {
+	s.HandleREST(method, path, func(w http.ResponseWriter, r *http.Request) {
+		if body == nil {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(body)
+	})
+}
+
+// HandleGraphQL registers the handler of the GraphQL operation with the given name, such
+// as "RepositoryInfo" for "query RepositoryInfo { ... }".
+func (s *Server) HandleGraphQL(operation string, handler GraphQLHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.graphql[operation] = handler
+}
+
+// Requests returns the requests the Server has received, in order.
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// ServeHTTP dispatches requests to the registered handlers.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, RecordedRequest{
+		Method:  r.Method,
+		URL:     r.URL.String(),
+		Headers: r.Header.Clone(),
+		Body:    string(body),
+	})
+	restHandler := s.rest[r.Method+" "+r.URL.Path]
+	s.mu.Unlock()
+
+	if r.Method == "POST" && r.URL.Path == "/graphql" {
+		s.serveGraphQL(w, body)
+		return
+	}
+	if restHandler == nil {
+		s.notFound(w, fmt.Sprintf("unexpected request %s %s", r.Method, r.URL))
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	restHandler(w, r)
+}
+
+func (s *Server) serveGraphQL(w http.ResponseWriter, body []byte) {
+	var req struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var operation string
+	if m := operationRE.FindStringSubmatch(req.Query); m != nil {
+		operation = m[1]
+	}
+	s.mu.Lock()
+	handler := s.graphql[operation]
+	s.mu.Unlock()
+	if handler == nil {
+		s.notFound(w, fmt.Sprintf("unexpected GraphQL operation %q", operation))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(handler(req.Variables))
+}
+
+func (s *Server) notFound(w http.ResponseWriter, message string) {
+	s.t.Errorf("goctltest.Server: %s", message)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
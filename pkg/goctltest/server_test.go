@@ -0,0 +1,91 @@
+package goctltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func repoScenario(s *Server) {
+	s.RespondJSON("GET", "/repos/monalisa/octo-cat", 200, map[string]interface{}{
+		"full_name": "monalisa/octo-cat",
+	})
+	s.HandleREST("POST", "/repos/monalisa/octo-cat/issues", func(w http.ResponseWriter, r *http.Request) {
+		var issue map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&issue)
+		w.WriteHeader(201)
+		_, _ = io.WriteString(w, `{"number":1,"title":"`+issue["title"]+`"}`)
+	})
+	s.HandleGraphQL("RepositoryInfo", func(variables map[string]interface{}) GraphQLResponse {
+		return GraphQLResponse{Data: map[string]interface{}{
+			"repository": map[string]interface{}{"name": variables["name"]},
+		}}
+	})
+	s.HandleGraphQL("Missing", func(map[string]interface{}) GraphQLResponse {
+		return GraphQLResponse{Errors: []api.GraphQLErrorItem{{Message: "Could not resolve", Type: "NOT_FOUND"}}}
+	})
+}
+
+func TestServerREST(t *testing.T) {
+	s := NewServer(t, repoScenario)
+	client, err := api.NewRESTClient(s.ClientOptions())
+	require.NoError(t, err)
+
+	var repo struct {
+		FullName string `json:"full_name"`
+	}
+	require.NoError(t, client.Get("repos/monalisa/octo-cat?per_page=1", &repo))
+	assert.Equal(t, "monalisa/octo-cat", repo.FullName)
+
+	var issue struct {
+		Number int
+		Title  string
+	}
+	err = client.Post("repos/monalisa/octo-cat/issues", bytes.NewBufferString(`{"title":"bug"}`), &issue)
+	require.NoError(t, err)
+	assert.Equal(t, 1, issue.Number)
+	assert.Equal(t, "bug", issue.Title)
+
+	requests := s.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/repos/monalisa/octo-cat?per_page=1", requests[0].URL)
+	assert.Equal(t, "token goctltest-token", requests[0].Headers.Get("Authorization"))
+	assert.Equal(t, `{"title":"bug"}`, requests[1].Body)
+}
+
+func TestServerGraphQL(t *testing.T) {
+	s := NewServer(t, repoScenario)
+	client, err := api.NewGraphQLClient(s.ClientOptions())
+	require.NoError(t, err)
+
+	var resp struct {
+		Repository struct {
+			Name string
+		}
+	}
+	err = client.Do(`query RepositoryInfo($name: String!) { repository(owner: "monalisa", name: $name) { name } }`,
+		map[string]interface{}{"name": "octo-cat"}, &resp)
+	require.NoError(t, err)
+	assert.Equal(t, "octo-cat", resp.Repository.Name)
+
+	err = client.Do(`query Missing { viewer { login } }`, nil, &resp)
+	assert.ErrorIs(t, err, api.ErrNotFound)
+}
+
+func TestServerUnexpectedRequest(t *testing.T) {
+	fakeT := &testing.T{}
+	s := NewServer(t)
+	s.t = fakeT
+	client, err := api.NewRESTClient(s.ClientOptions())
+	require.NoError(t, err)
+
+	err = client.Get("user", nil)
+	RequireHTTPStatus(t, err, 404)
+	assert.True(t, fakeT.Failed())
+}
@@ -0,0 +1,139 @@
+package goctltest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+// targetsEnv holds the path of the file configuring the targets of integration tests.
+const targetsEnv = "GOCTLTEST_TARGETS"
+
+// Target is a GitHub instance that integration tests run against, such as github.com or
+// a GitHub Enterprise Server instance of a given version.
+type Target struct {
+	// Name identifies the target in test names, such as "ghes-3.10".
+	Name string `yaml:"name"`
+	// Host is the hostname of the instance.
+	Host string `yaml:"host"`
+	// TokenEnv is the name of the environment variable holding the authentication token
+	// for the instance, so that tokens are not written to the configuration file.
+	TokenEnv string `yaml:"token_env"`
+	// Version is the expected version of GitHub Enterprise Server, such as "3.10". The
+	// tests of the target fail if the instance reports another version. It is empty for
+	// github.com.
+	Version string `yaml:"version"`
+}
+
+// LoadTargets reads the targets configured in a YAML file, which must list at least one
+// target so that a misconfigured job does not pass without running any test:
+//
+//	targets:
+//	  - name: github.com
+//	    host: github.com
+//	    token_env: GITHUB_TOKEN
+//	  - name: ghes-3.10
+//	    host: ghes-310.example.com
+//	    token_env: GHES_310_TOKEN
+//	    version: "3.10"
+func LoadTargets(path string) ([]Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Targets []Target `yaml:"targets"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid targets file %s: %w", path, err)
+	}
+	if len(file.Targets) == 0 {
+		return nil, fmt.Errorf("invalid targets file %s: no targets", path)
+	}
+	for i, target := range file.Targets {
+		if target.Host == "" || target.TokenEnv == "" {
+			return nil, fmt.Errorf("invalid targets file %s: target %d requires host and token_env", path, i+1)
+		}
+		if target.Name == "" {
+			file.Targets[i].Name = target.Host
+		}
+	}
+	return file.Targets, nil
+}
+
+// ForEachTarget runs fn as a subtest for each target configured in the file named by the
+// GOCTLTEST_TARGETS environment variable, after checking that the target runs the expected
+// version. The test is skipped when the variable is not set, so integration tests can be
+// run by nightly jobs without affecting the regular test suite:
+//
+//	//go:build integration
+//
+//	func TestIntegrationListIssues(t *testing.T) {
+//		goctltest.ForEachTarget(t, func(t *testing.T, target goctltest.Target) {
+//			client, err := api.NewRESTClient(target.ClientOptions())
+//			...
+//		})
+//	}
+func ForEachTarget(t *testing.T, fn func(t *testing.T, target Target)) {
+	t.Helper()
+	path := os.Getenv(targetsEnv)
+	if path == "" {
+		t.Skipf("%s is not set", targetsEnv)
+	}
+	targets, err := LoadTargets(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range targets {
+		target := target
+		t.Run(target.Name, func(t *testing.T) {
+			if os.Getenv(target.TokenEnv) == "" {
+				t.Fatalf("%s is not set", target.TokenEnv)
+			}
+			client, err := api.NewRESTClient(target.ClientOptions())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := target.checkVersion(client); err != nil {
+				t.Fatal(err)
+			}
+			fn(t, target)
+		})
+	}
+}
+
+// ClientOptions returns options for API clients of the target.
+func (t Target) ClientOptions() api.ClientOptions {
+	return api.ClientOptions{
+		Host:         t.Host,
+		AuthToken:    os.Getenv(t.TokenEnv),
+		LogIgnoreEnv: true,
+	}
+}
+
+// restGetter is the subset of [api.RESTClient] used to check the version of a target.
+type restGetter interface {
+	Get(path string, resp interface{}) error
+}
+
+// checkVersion verifies that the instance runs the expected version, comparing only as
+// many components as the expected version has, so that "3.10" matches "3.10.4".
+func (t Target) checkVersion(client restGetter) error {
+	if t.Version == "" {
+		return nil
+	}
+	var meta struct {
+		InstalledVersion string `json:"installed_version"`
+	}
+	if err := client.Get("meta", &meta); err != nil {
+		return fmt.Errorf("could not determine the version of %s: %w", t.Host, err)
+	}
+	if meta.InstalledVersion != t.Version && !strings.HasPrefix(meta.InstalledVersion, t.Version+".") {
+		return fmt.Errorf("expected %s to run version %s, got %q", t.Host, t.Version, meta.InstalledVersion)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package goctltest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTargets(t *testing.T) {
+	targets, err := LoadTargets(filepath.Join("testdata", "targets.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, []Target{
+		{Name: "github.com", Host: "github.com", TokenEnv: "GOCTLTEST_GITHUB_TOKEN"},
+		{Name: "ghes-3.10", Host: "ghes-310.example.com", TokenEnv: "GOCTLTEST_GHES_310_TOKEN", Version: "3.10"},
+	}, targets)
+
+	path := filepath.Join(t.TempDir(), "targets.yml")
+	require.NoError(t, os.WriteFile(path, []byte("targets:\n  - host: github.com\n"), 0600))
+	_, err = LoadTargets(path)
+	assert.EqualError(t, err, "invalid targets file "+path+": target 1 requires host and token_env")
+
+	require.NoError(t, os.WriteFile(path, []byte("\n"), 0600))
+	_, err = LoadTargets(path)
+	assert.EqualError(t, err, "invalid targets file "+path+": no targets")
+}
+
+func TestTargetClientOptions(t *testing.T) {
+	t.Setenv("GOCTLTEST_GHES_310_TOKEN", "abc123")
+	opts := Target{Host: "ghes-310.example.com", TokenEnv: "GOCTLTEST_GHES_310_TOKEN"}.ClientOptions()
+	assert.Equal(t, "ghes-310.example.com", opts.Host)
+	assert.Equal(t, "abc123", opts.AuthToken)
+}
+
+type metaClient struct {
+	version string
+	err     error
+}
+
+func (c metaClient) Get(path string, resp interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+	resp.(*struct {
+		InstalledVersion string `json:"installed_version"`
+	}).InstalledVersion = c.version
+	return nil
+}
+
+func TestTargetCheckVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  Target
+		client  metaClient
+		wantErr string
+	}{
+		{name: "no expected version", target: Target{Host: "github.com"}},
+		{name: "matching minor version", target: Target{Host: "ghes", Version: "3.10"}, client: metaClient{version: "3.10.4"}},
+		{name: "matching patch version", target: Target{Host: "ghes", Version: "3.10.4"}, client: metaClient{version: "3.10.4"}},
+		{
+			name:    "other version",
+			target:  Target{Host: "ghes", Version: "3.1"},
+			client:  metaClient{version: "3.10.4"},
+			wantErr: `expected ghes to run version 3.1, got "3.10.4"`,
+		},
+		{
+			name:    "API error",
+			target:  Target{Host: "ghes", Version: "3.10"},
+			client:  metaClient{err: errors.New("HTTP 401")},
+			wantErr: "could not determine the version of ghes: HTTP 401",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.target.checkVersion(tt.client)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestForEachTargetSkipped(t *testing.T) {
+	t.Setenv("GOCTLTEST_TARGETS", "")
+	ran := false
+	t.Run("skipped", func(t *testing.T) {
+		ForEachTarget(t, func(t *testing.T, target Target) { ran = true })
+	})
+	assert.False(t, ran)
+}
@@ -0,0 +1,221 @@
+package goctltest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/term"
+)
+
+// Keys that can be sent to code under test in a ScriptStep.
+const (
+	KeyEnter     = "\r"
+	KeyUp        = "\x1b[A"
+	KeyDown      = "\x1b[B"
+	KeySpace     = " "
+	KeyBackspace = "\x7f"
+	KeyCtrlC     = "\x03"
+)
+
+// ScriptStep is a step of the input scripted for a FakeTerm: once Expect has been written to
+// standard output, Send becomes readable from standard input. A step with an empty Expect
+// is sent as soon as the previous step has been read.
+type ScriptStep struct {
+	Expect string
+	Send   string
+}
+
+// FakeTerm is a [term.Terminal] whose size and capabilities are set by the test, whose output
+// is captured, and whose input is scripted, so that prompting and rendering code can be
+// exercised deterministically without a terminal.
+type FakeTerm struct {
+	// Width and Height are the size of the terminal. A terminal with no width has no
+	// size, as when output is redirected.
+	Width  int
+	Height int
+	// TTY is whether standard output is a terminal.
+	TTY bool
+	// Color, Color256, and TrueColor are the color support of the terminal.
+	Color     bool
+	Color256  bool
+	TrueColor bool
+	// UTF8 is whether the terminal can display characters outside of ASCII.
+	UTF8 bool
+	// Dark is whether the terminal has a dark background.
+	Dark bool
+	// ScriptTimeout bounds how long reading standard input waits for the expected output
+	// of the next step. It defaults to 5 seconds.
+	ScriptTimeout time.Duration
+
+	mu      sync.Mutex
+	out     bytes.Buffer
+	errOut  bytes.Buffer
+	matched int
+	steps   []ScriptStep
+	pending string
+	changed chan struct{}
+}
+
+var _ term.Terminal = (*FakeTerm)(nil)
+
+// NewFakeTerm returns an 80 by 24 terminal with UTF-8 support, a dark background, and
+// color disabled.
+func NewFakeTerm() *FakeTerm {
+	return &FakeTerm{
+		Width:  80,
+		Height: 24,
+		TTY:    true,
+		UTF8:   true,
+		Dark:   true,
+	}
+}
+
+// Script appends steps to the input of the terminal. Reading standard input returns
+// [io.EOF] once every step has been read.
+func (t *FakeTerm) Script(steps ...ScriptStep) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.steps = append(t.steps, steps...)
+	t.notify()
+}
+
+// In is the reader reading the scripted standard input.
+func (t *FakeTerm) In() io.Reader {
+	return fakeTermReader{t}
+}
+
+// Out is the writer capturing standard output.
+func (t *FakeTerm) Out() io.Writer {
+	return fakeTermWriter{t, &t.out}
+}
+
+// ErrOut is the writer capturing standard error.
+func (t *FakeTerm) ErrOut() io.Writer {
+	return fakeTermWriter{t, &t.errOut}
+}
+
+// OutString returns everything written to standard output so far.
+func (t *FakeTerm) OutString() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.out.String()
+}
+
+// ErrOutString returns everything written to standard error so far.
+func (t *FakeTerm) ErrOutString() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.errOut.String()
+}
+
+// IsTerminalOutput returns true if standard output is a terminal.
+func (t *FakeTerm) IsTerminalOutput() bool {
+	return t.TTY
+}
+
+// IsColorEnabled reports whether color is enabled.
+func (t *FakeTerm) IsColorEnabled() bool {
+	return t.Color
+}
+
+// Is256ColorSupported reports whether the terminal supports ANSI 256 color codes.
+func (t *FakeTerm) Is256ColorSupported() bool {
+	return t.Color256
+}
+
+// IsTrueColorSupported reports whether the terminal supports ANSI true color sequences.
+func (t *FakeTerm) IsTrueColorSupported() bool {
+	return t.TrueColor
+}
+
+// IsUTF8 reports whether the terminal can display characters outside of ASCII.
+func (t *FakeTerm) IsUTF8() bool {
+	return t.UTF8
+}
+
+// Size returns the width and height of the terminal.
+func (t *FakeTerm) Size() (int, int, error) {
+	if t.Width <= 0 {
+		return -1, -1, errors.New("not a terminal")
+	}
+	return t.Width, t.Height, nil
+}
+
+// Theme returns "none" when color is disabled, and otherwise "dark" or "light".
+func (t *FakeTerm) Theme() string {
+	if !t.Color {
+		return "none"
+	}
+	if t.Dark {
+		return "dark"
+	}
+	return "light"
+}
+
+// notify wakes up readers waiting for output or steps. It must be called with mu held.
+func (t *FakeTerm) notify() {
+	if t.changed != nil {
+		close(t.changed)
+		t.changed = nil
+	}
+}
+
+type fakeTermWriter struct {
+	t   *FakeTerm
+	buf *bytes.Buffer
+}
+
+func (w fakeTermWriter) Write(p []byte) (int, error) {
+	w.t.mu.Lock()
+	defer w.t.mu.Unlock()
+	n, err := w.buf.Write(p)
+	w.t.notify()
+	return n, err
+}
+
+type fakeTermReader struct {
+	t *FakeTerm
+}
+
+func (r fakeTermReader) Read(p []byte) (int, error) {
+	t := r.t
+	timeout := t.ScriptTimeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	deadline := time.After(timeout)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.pending == "" {
+		if len(t.steps) == 0 {
+			return 0, io.EOF
+		}
+		step := t.steps[0]
+		if i := strings.Index(t.out.String()[t.matched:], step.Expect); i >= 0 {
+			t.matched += i + len(step.Expect)
+			t.pending = step.Send
+			t.steps = t.steps[1:]
+			continue
+		}
+		if t.changed == nil {
+			t.changed = make(chan struct{})
+		}
+		changed := t.changed
+		t.mu.Unlock()
+		select {
+		case <-changed:
+			t.mu.Lock()
+		case <-deadline:
+			t.mu.Lock()
+			return 0, fmt.Errorf("timed out waiting for output %q", step.Expect)
+		}
+	}
+	n := copy(p, t.pending)
+	t.pending = t.pending[n:]
+	return n, nil
+}
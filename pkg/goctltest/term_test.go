@@ -0,0 +1,79 @@
+package goctltest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/term"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeTermCapabilities(t *testing.T) {
+	ft := NewFakeTerm()
+	w, h, err := ft.Size()
+	assert.NoError(t, err)
+	assert.Equal(t, 80, w)
+	assert.Equal(t, 24, h)
+	assert.True(t, ft.IsTerminalOutput())
+	assert.Equal(t, "none", ft.Theme())
+
+	ft.Color = true
+	assert.Equal(t, "dark", ft.Theme())
+	ft.Dark = false
+	assert.Equal(t, "light", ft.Theme())
+
+	ft.Width = 0
+	_, _, err = ft.Size()
+	assert.EqualError(t, err, "not a terminal")
+}
+
+func askName(t term.Terminal) (string, error) {
+	fmt.Fprint(t.Out(), "? Name: ")
+	answer, err := bufio.NewReader(t.In()).ReadString('\r')
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(t.ErrOut(), "Hello, %s!\n", answer[:len(answer)-1])
+	return answer, nil
+}
+
+func TestFakeTermScript(t *testing.T) {
+	ft := NewFakeTerm()
+	ft.Script(ScriptStep{Expect: "Name:", Send: "monalisa" + KeyEnter})
+
+	answer, err := askName(ft)
+	assert.NoError(t, err)
+	assert.Equal(t, "monalisa\r", answer)
+	assert.Equal(t, "? Name: ", ft.OutString())
+	assert.Equal(t, "Hello, monalisa!\n", ft.ErrOutString())
+
+	_, err = ft.In().Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestFakeTermScriptWaitsForOutput(t *testing.T) {
+	ft := NewFakeTerm()
+	ft.Script(
+		ScriptStep{Expect: "first", Send: "1"},
+		ScriptStep{Expect: "second", Send: "2"},
+	)
+	go func() {
+		fmt.Fprint(ft.Out(), "first")
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(ft.Out(), "second")
+	}()
+	data, err := io.ReadAll(ft.In())
+	assert.NoError(t, err)
+	assert.Equal(t, "12", string(data))
+}
+
+func TestFakeTermScriptTimeout(t *testing.T) {
+	ft := NewFakeTerm()
+	ft.ScriptTimeout = 10 * time.Millisecond
+	ft.Script(ScriptStep{Expect: "never printed", Send: "y"})
+	_, err := ft.In().Read(make([]byte, 1))
+	assert.EqualError(t, err, `timed out waiting for output "never printed"`)
+}
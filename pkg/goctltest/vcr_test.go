@@ -0,0 +1,101 @@
+package goctltest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRecorderReplay(t *testing.T) {
+	rec := NewRecorder(t, "repo", WithMatchBody(), WithIgnoredQueryParams("ts"))
+	client, err := api.NewRESTClient(rec.ClientOptions("github.com"))
+	assert.NoError(t, err)
+
+	var repo struct {
+		FullName string `json:"full_name"`
+	}
+	assert.NoError(t, client.Get("repos/monalisa/octo-cat?per_page=1&ts=1800000000", &repo))
+	assert.Equal(t, "monalisa/octo-cat", repo.FullName)
+
+	var issue struct {
+		Number int `json:"number"`
+	}
+	err = client.Post("repos/monalisa/octo-cat/issues", bytes.NewBufferString(`{"title":"second"}`), &issue)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, issue.Number)
+	err = client.Post("repos/monalisa/octo-cat/issues", bytes.NewBufferString(`{"title":"first"}`), &issue)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, issue.Number)
+}
+
+func TestRecorderUnmatched(t *testing.T) {
+	fakeT := &testing.T{}
+	rec := &Recorder{t: fakeT, cassette: Cassette{Interactions: []*Interaction{
+		{Request: RecordedRequest{Method: "GET", URL: "https://api.github.com/user"}},
+	}}}
+	req, _ := http.NewRequest("GET", "https://api.github.com/octocat", nil)
+	_, err := rec.RoundTrip(req)
+	assert.EqualError(t, err, "no recorded interaction for GET https://api.github.com/octocat")
+	assert.True(t, fakeT.Failed())
+
+	fakeT = &testing.T{}
+	rec.t = fakeT
+	rec.verify()
+	assert.True(t, fakeT.Failed())
+}
+
+func TestRecorderRecord(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	token := "ghp_" + "abcdefghijklmnopqrstuvwxyz0123456789"
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Set-Cookie": {"session=1"}, "X-Request-Id": {"abc"}},
+			Body:       io.NopCloser(bytes.NewBufferString(`{"token":"` + token + `"}`)),
+		}, nil
+	})
+
+	t.Run("record", func(t *testing.T) {
+		t.Setenv("GOCTLTEST_RECORD", "1")
+		rec := NewRecorder(t, "recorded", WithRecordTransport(transport), WithScrubbedHeaders("X-Request-Id"))
+		req, _ := http.NewRequest("GET", "https://api.github.com/user", nil)
+		req.Header.Set("Authorization", "token "+token)
+		resp, err := rec.RoundTrip(req)
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, `{"token":"`+token+`"}`, string(body))
+	})
+
+	data, err := os.ReadFile(filepath.Join("testdata", "cassettes", "recorded.yaml"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), token)
+	assert.NotContains(t, string(data), "session=1")
+	assert.NotContains(t, string(data), "abc")
+
+	t.Run("replay", func(t *testing.T) {
+		rec := NewRecorder(t, "recorded")
+		req, _ := http.NewRequest("GET", "https://api.github.com/user", nil)
+		resp, err := rec.RoundTrip(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "REDACTED", resp.Header.Get("Set-Cookie"))
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, `{"token":"REDACTED"}`, string(body))
+	})
+}
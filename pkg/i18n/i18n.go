@@ -0,0 +1,137 @@
+// Package i18n translates the user-facing strings produced by go-goctl, such as prompts,
+// relative times, and the messages of API and execution errors, so that tools built on it
+// can present their output in other languages. The table, JSON, and YAML renderers only
+// print the data they are given, apart from relative times.
+//
+// Messages are identified by their English text, which is also used when no translation is
+// registered for the selected language. Tools add translations with SetString or Set:
+//
+//	i18n.SetString(language.German, "Enter a number: ", "Nummer eingeben: ")
+//
+// The language of prompts is detected from the "locale" configuration key and the LC_ALL,
+// LC_MESSAGES, and LANG environment variables, and can be overridden with SetLanguage. The
+// text package does not read the configuration, so relative times are in English unless the
+// language is passed to text.SetLanguage:
+//
+//	text.SetLanguage(i18n.Language())
+package i18n
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// localeKey is the configuration key that selects the language of messages.
+const localeKey = "locale"
+
+var (
+	builder = catalog.NewBuilder(catalog.Fallback(language.English))
+
+	mu       sync.RWMutex
+	detected bool
+	current  language.Tag
+)
+
+// SetString registers the translation of the message key for a language. The translation
+// may use the same formatting verbs as the key.
+func SetString(tag language.Tag, key, msg string) error {
+	return builder.SetString(tag, key, msg)
+}
+
+// Set registers the translation of the message key for a language, such as plural forms
+// selected with the golang.org/x/text/feature/plural package.
+func Set(tag language.Tag, key string, msg ...catalog.Message) error {
+	return builder.Set(tag, key, msg...)
+}
+
+// Languages returns the languages for which translations have been registered.
+func Languages() []language.Tag {
+	return builder.Languages()
+}
+
+// SetLanguage selects the language of messages, replacing the detected language.
+func SetLanguage(tag language.Tag) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = tag
+	detected = true
+}
+
+// Language returns the selected language of messages, detecting it from the configuration
+// and environment on first use.
+func Language() language.Tag {
+	mu.RLock()
+	if detected {
+		defer mu.RUnlock()
+		return current
+	}
+	mu.RUnlock()
+
+	cfg, _ := config.Read(nil)
+	tag := Detect(os.Getenv, cfg)
+	mu.Lock()
+	defer mu.Unlock()
+	if !detected {
+		current = tag
+		detected = true
+	}
+	return current
+}
+
+// Detect returns the language selected by the "locale" key of cfg, or else by the first
+// of the LC_ALL, LC_MESSAGES, and LANG environment variables that is set. POSIX locale
+// names such as "de_DE.UTF-8" are accepted. It returns English if no valid locale is set.
+// A nil cfg is treated as empty.
+func Detect(getenv func(string) string, cfg *config.Config) language.Tag {
+	if cfg != nil {
+		if locale, err := cfg.Get([]string{localeKey}); err == nil && locale != "" {
+			if tag, ok := parseLocale(locale); ok {
+				return tag
+			}
+		}
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := getenv(name); locale != "" {
+			if tag, ok := parseLocale(locale); ok {
+				return tag
+			}
+			// The first variable that is set takes precedence even if it is invalid,
+			// as it does for C programs.
+			break
+		}
+	}
+	return language.English
+}
+
+// parseLocale parses a BCP 47 language tag or a POSIX locale name such as
+// "pt_BR.UTF-8@euro". The "C" and "POSIX" locales are English.
+func parseLocale(locale string) (language.Tag, bool) {
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "C" || locale == "POSIX" {
+		return language.English, true
+	}
+	tag, err := language.Parse(strings.ReplaceAll(locale, "_", "-"))
+	if err != nil {
+		return language.Und, false
+	}
+	return tag, true
+}
+
+// NewPrinter returns a printer of messages in the given language, or in the closest
+// language for which translations have been registered.
+func NewPrinter(tag language.Tag) *message.Printer {
+	return message.NewPrinter(tag, message.Catalog(builder))
+}
+
+// Sprintf formats the translation of the message key in the selected language.
+func Sprintf(key string, args ...interface{}) string {
+	return NewPrinter(Language()).Sprintf(key, args...)
+}
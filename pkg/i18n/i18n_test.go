@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		config string
+		want   language.Tag
+	}{
+		{
+			name: "nothing set",
+			want: language.English,
+		},
+		{
+			name: "LANG",
+			env:  map[string]string{"LANG": "de_DE.UTF-8"},
+			want: language.MustParse("de-DE"),
+		},
+		{
+			name: "LC_ALL takes precedence",
+			env:  map[string]string{"LANG": "de_DE.UTF-8", "LC_MESSAGES": "fr_FR", "LC_ALL": "ja_JP.eucJP"},
+			want: language.MustParse("ja-JP"),
+		},
+		{
+			name: "LC_MESSAGES takes precedence over LANG",
+			env:  map[string]string{"LANG": "de_DE.UTF-8", "LC_MESSAGES": "fr_FR@euro"},
+			want: language.MustParse("fr-FR"),
+		},
+		{
+			name: "POSIX locale",
+			env:  map[string]string{"LC_ALL": "C.UTF-8", "LANG": "de_DE"},
+			want: language.English,
+		},
+		{
+			name: "invalid locale",
+			env:  map[string]string{"LC_ALL": "not a locale", "LANG": "de_DE"},
+			want: language.English,
+		},
+		{
+			name:   "config takes precedence",
+			env:    map[string]string{"LC_ALL": "de_DE"},
+			config: "locale: pt-BR\n",
+			want:   language.MustParse("pt-BR"),
+		},
+		{
+			name:   "invalid config falls back to environment",
+			env:    map[string]string{"LANG": "de_DE"},
+			config: "locale: '!!'\n",
+			want:   language.MustParse("de-DE"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg *config.Config
+			if tt.config != "" {
+				cfg = config.ReadFromString(tt.config)
+			}
+			getenv := func(name string) string { return tt.env[name] }
+			assert.Equal(t, tt.want, Detect(getenv, cfg))
+		})
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	t.Cleanup(func() { SetLanguage(language.English) })
+	nl := language.Dutch
+	require.NoError(t, SetString(nl, "Enter a number: ", "Voer een nummer in: "))
+	require.NoError(t, Set(nl, "%d files",
+		plural.Selectf(1, "%d", "one", "1 bestand", "other", "%d bestanden")))
+	assert.Contains(t, Languages(), nl)
+
+	SetLanguage(language.English)
+	assert.Equal(t, "Enter a number: ", Sprintf("Enter a number: "))
+	assert.Equal(t, "3 files", Sprintf("%d files", 3))
+
+	SetLanguage(language.MustParse("nl-BE"))
+	assert.Equal(t, language.MustParse("nl-BE"), Language())
+	assert.Equal(t, "Voer een nummer in: ", Sprintf("Enter a number: "))
+	assert.Equal(t, "1 bestand", Sprintf("%d files", 1))
+	assert.Equal(t, "3 bestanden", Sprintf("%d files", 3))
+	assert.Equal(t, "untranslated 2", Sprintf("untranslated %d", 2))
+}
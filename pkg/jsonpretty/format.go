@@ -2,6 +2,7 @@
 package jsonpretty
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
@@ -19,7 +20,19 @@ const (
 )
 
 // Format reads JSON from r and writes a prettified version of it to w.
-func Format(w io.Writer, r io.Reader, indent string, colorize bool) error {
+// The input is processed token by token and the output is written in bounded chunks,
+// so arbitrarily large documents can be streamed without being held in memory.
+func Format(w io.Writer, r io.Reader, indent string, colorize bool) (err error) {
+	bw := bufio.NewWriter(w)
+	defer func() {
+		if flushErr := bw.Flush(); err == nil {
+			err = flushErr
+		}
+	}()
+	return format(bw, r, indent, colorize)
+}
+
+func format(w *bufio.Writer, r io.Reader, indent string, colorize bool) error {
 	dec := json.NewDecoder(r)
 	dec.UseNumber()
 
@@ -121,6 +134,10 @@ func Format(w io.Writer, r io.Reader, indent string, colorize bool) error {
 			if _, err := fmt.Fprint(w, "\n"); err != nil {
 				return err
 			}
+			// Emit each complete top-level value right away in case more input is slow to arrive.
+			if err := w.Flush(); err != nil {
+				return err
+			}
 		}
 	}
 
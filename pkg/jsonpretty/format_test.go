@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"testing"
+	"time"
 )
 
 func TestWrite(t *testing.T) {
@@ -94,3 +95,44 @@ func TestWrite(t *testing.T) {
 		})
 	}
 }
+
+// notifyWriter signals on a channel when it receives data.
+type notifyWriter struct {
+	written chan struct{}
+}
+
+func (w *notifyWriter) Write(p []byte) (int, error) {
+	select {
+	case w.written <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
+
+func TestFormatStreaming(t *testing.T) {
+	r, pw := io.Pipe()
+	w := &notifyWriter{written: make(chan struct{}, 1)}
+	done := make(chan error)
+	go func() {
+		done <- Format(w, r, "  ", false)
+	}()
+
+	// Write more than a buffer's worth of a document without finishing it.
+	go func() {
+		_, _ = io.WriteString(pw, "[")
+		for i := 0; i < 2000; i++ {
+			_, _ = io.WriteString(pw, `"item",`)
+		}
+	}()
+
+	select {
+	case <-w.written:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected output before the input was complete")
+	}
+
+	_ = pw.CloseWithError(io.ErrUnexpectedEOF)
+	if err := <-done; err == nil {
+		t.Error("expected error for truncated input")
+	}
+}
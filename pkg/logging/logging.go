@@ -0,0 +1,70 @@
+// Package logging provides the structured logger used throughout go-goctl to report
+// diagnostic information such as how an authentication token was resolved, which
+// configuration files were loaded, and which API requests and commands were run.
+//
+// Logging is disabled by default. Setting the GOCTL_DEBUG environment variable enables
+// debug logging to standard error; it may also be set to a level name such as "info" or
+// "warn". Applications can route log records elsewhere with SetHandler.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	mu     sync.Mutex
+	logger *slog.Logger
+)
+
+// Logger returns the logger used by go-goctl packages.
+func Logger() *slog.Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	if logger == nil {
+		logger = slog.New(handlerFromEnv(os.Getenv("GOCTL_DEBUG"), os.Stderr))
+	}
+	return logger
+}
+
+// SetHandler replaces the handler that log records are sent to, overriding the GOCTL_DEBUG
+// environment variable. Passing nil restores the default behavior.
+func SetHandler(h slog.Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	if h == nil {
+		logger = nil
+		return
+	}
+	logger = slog.New(h)
+}
+
+func handlerFromEnv(value string, w io.Writer) slog.Handler {
+	var level slog.Level
+	switch strings.ToLower(value) {
+	case "", "0", "false", "no":
+		return discardHandler{}
+	case "info":
+		level = slog.LevelInfo
+	case "warn", "warning":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	default:
+		// Any other value, including "1", "true" and "api", enables debug logging.
+		level = slog.LevelDebug
+	}
+	return slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})
+}
+
+// discardHandler drops all log records.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
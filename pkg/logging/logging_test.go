@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerFromEnv(t *testing.T) {
+	tests := []struct {
+		value     string
+		wantLevel slog.Level
+		wantOff   bool
+	}{
+		{value: "", wantOff: true},
+		{value: "0", wantOff: true},
+		{value: "false", wantOff: true},
+		{value: "1", wantLevel: slog.LevelDebug},
+		{value: "api", wantLevel: slog.LevelDebug},
+		{value: "INFO", wantLevel: slog.LevelInfo},
+		{value: "warn", wantLevel: slog.LevelWarn},
+		{value: "error", wantLevel: slog.LevelError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			h := handlerFromEnv(tt.value, &bytes.Buffer{})
+			ctx := context.Background()
+			if tt.wantOff {
+				assert.False(t, h.Enabled(ctx, slog.LevelError))
+				return
+			}
+			assert.True(t, h.Enabled(ctx, tt.wantLevel))
+			assert.False(t, h.Enabled(ctx, tt.wantLevel-1))
+		})
+	}
+}
+
+func TestSetHandler(t *testing.T) {
+	t.Setenv("GOCTL_DEBUG", "")
+	buf := &bytes.Buffer{}
+	SetHandler(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	defer SetHandler(nil)
+
+	Logger().Debug("resolved token", "host", "github.com")
+	assert.Equal(t, "level=DEBUG msg=\"resolved token\" host=github.com\n", buf.String())
+
+	SetHandler(nil)
+	assert.False(t, Logger().Enabled(context.Background(), slog.LevelError))
+}
@@ -44,6 +44,17 @@ func WithTheme(theme string) glamour.TermRendererOption {
 	return glamour.WithStylePath(style)
 }
 
+// WithASCII is a rendering option that restricts the rendering to ASCII characters and no colors,
+// for terminals that cannot display Unicode. It takes precedence over WithTheme when given after it.
+// It can be used in conjunction with [term.Term.IsUTF8].
+func WithASCII() glamour.TermRendererOption {
+	// The built-in "ascii" style still uses a few Unicode glyphs.
+	style := glamour.ASCIIStyleConfig
+	style.Item.BlockPrefix = "* "
+	style.ImageText.Format = "Image: {{.text}} ->"
+	return glamour.WithStyles(style)
+}
+
 // WithBaseURL is a rendering option that sets the base URL to use when rendering relative URLs.
 func WithBaseURL(u string) glamour.TermRendererOption {
 	return glamour.WithBaseURL(u)
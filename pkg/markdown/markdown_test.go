@@ -36,3 +36,13 @@ func Test_Render(t *testing.T) {
 		})
 	}
 }
+
+func Test_RenderASCII(t *testing.T) {
+	t.Setenv("GLAMOUR_STYLE", "")
+	out, err := Render("# Title\n\n* one\n* two\n", WithTheme("dark"), WithASCII())
+	assert.NoError(t, err)
+	for _, r := range out {
+		assert.Less(t, r, rune(128), "unexpected non-ASCII character %q in %q", r, out)
+	}
+	assert.Contains(t, out, "one")
+}
@@ -0,0 +1,116 @@
+// Package middleware registers hooks that apply to every API client and subprocess of
+// go-goctl, so that concerns such as metrics, redaction, and retries can be configured once
+// instead of for each subsystem. Programs typically register middleware at startup with
+// goctl.Use.
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os/exec"
+	"sync"
+)
+
+// Middleware hooks into API requests and subprocesses. Either field may be nil.
+type Middleware struct {
+	// HTTP wraps the transport of the clients created by the api package. Requests
+	// reach it after the default headers and the authentication token have been added.
+	HTTP func(http.RoundTripper) http.RoundTripper
+	// Command wraps the execution of goctl, git, and extension subprocesses. The
+	// command is fully configured but not started when the RunFunc is called.
+	Command func(RunFunc) RunFunc
+}
+
+// Cmd describes a subprocess. Unlike an [exec.Cmd], which can only be started once, each
+// call of a RunFunc starts a new process, so that middleware can change the command or
+// run it again, for example to retry it.
+type Cmd struct {
+	// Path is the executable to run.
+	Path string
+	// Args are the arguments of the command, without the executable.
+	Args []string
+	// Env is the environment of the process. If nil, it inherits the environment of the
+	// current process.
+	Env []string
+	// Dir is the working directory of the process. If empty, it is the current directory.
+	Dir string
+	// Stdin, Stdout, and Stderr are the standard streams of the process, as in [exec.Cmd].
+	// Stdin is consumed by the first run, so middleware that runs a command again must
+	// replace it.
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// RunFunc runs a subprocess and waits for it to exit, like [exec.Cmd.Run]. The process is
+// killed if the context is done before it exits.
+type RunFunc func(ctx context.Context, cmd Cmd) error
+
+var (
+	mu         sync.RWMutex
+	registered []Middleware
+)
+
+// Use registers middleware for all API clients created and subprocesses run afterwards.
+// Middleware registered first is outermost: it sees requests and commands first and
+// responses and errors last.
+func Use(mw ...Middleware) {
+	mu.Lock()
+	defer mu.Unlock()
+	registered = append(registered, mw...)
+}
+
+// Reset removes all registered middleware. It is meant for tests.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	registered = nil
+}
+
+func snapshot() []Middleware {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]Middleware(nil), registered...)
+}
+
+// Transport wraps rt with the HTTP middleware registered with Use.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	mws := snapshot()
+	for i := len(mws) - 1; i >= 0; i-- {
+		if mws[i].HTTP != nil {
+			rt = mws[i].HTTP(rt)
+		}
+	}
+	return rt
+}
+
+// Run runs cmd through the Command middleware registered with Use. Errors of the process
+// are returned as by [exec.Cmd.Run], such as an [*exec.ExitError].
+func Run(ctx context.Context, cmd Cmd) error {
+	run := RunFunc(func(ctx context.Context, cmd Cmd) error {
+		c := exec.CommandContext(ctx, cmd.Path, cmd.Args...)
+		c.Env = cmd.Env
+		c.Dir = cmd.Dir
+		c.Stdin = cmd.Stdin
+		c.Stdout = cmd.Stdout
+		c.Stderr = cmd.Stderr
+		return c.Run()
+	})
+	mws := snapshot()
+	for i := len(mws) - 1; i >= 0; i-- {
+		if mws[i].Command != nil {
+			run = mws[i].Command(run)
+		}
+	}
+	return run(ctx, cmd)
+}
+
+// RoundTripperFunc adapts a function to an [http.RoundTripper], which is convenient for
+// writing HTTP middleware.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return Middleware{
+		HTTP: func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				*calls = append(*calls, name+" before")
+				resp, err := next.RoundTrip(req)
+				*calls = append(*calls, name+" after")
+				return resp, err
+			})
+		},
+		Command: func(next RunFunc) RunFunc {
+			return func(ctx context.Context, cmd Cmd) error {
+				*calls = append(*calls, name+" "+cmd.Args[0])
+				return next(ctx, cmd)
+			}
+		},
+	}
+}
+
+func TestTransport(t *testing.T) {
+	t.Cleanup(Reset)
+	var calls []string
+	Use(recordingMiddleware("outer", &calls), Middleware{})
+	Use(recordingMiddleware("inner", &calls))
+
+	rt := Transport(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls = append(calls, "transport")
+		return &http.Response{StatusCode: 204}, nil
+	}))
+	req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, 204, resp.StatusCode)
+	assert.Equal(t, []string{"outer before", "inner before", "transport", "inner after", "outer after"}, calls)
+
+	Reset()
+	calls = nil
+	_, err = Transport(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("offline")
+	})).RoundTrip(req)
+	assert.EqualError(t, err, "offline")
+	assert.Empty(t, calls)
+}
+
+func TestRun(t *testing.T) {
+	t.Cleanup(Reset)
+	var calls []string
+	Use(recordingMiddleware("outer", &calls))
+	Use(recordingMiddleware("inner", &calls))
+	Use(Middleware{
+		Command: func(next RunFunc) RunFunc {
+			return func(ctx context.Context, cmd Cmd) error {
+				if cmd.Args[0] == "blocked" {
+					return errors.New("command not allowed")
+				}
+				return next(ctx, cmd)
+			}
+		},
+	})
+
+	err := Run(context.Background(), Cmd{Path: "go", Args: []string{"version"}})
+	assert.NoError(t, err)
+	err = Run(context.Background(), Cmd{Path: "go", Args: []string{"blocked"}})
+	assert.EqualError(t, err, "command not allowed")
+	assert.Equal(t, []string{"outer version", "inner version", "outer blocked", "inner blocked"}, calls)
+}
+
+func TestRunRetry(t *testing.T) {
+	t.Cleanup(Reset)
+	attempts := 0
+	Use(Middleware{
+		Command: func(next RunFunc) RunFunc {
+			return func(ctx context.Context, cmd Cmd) error {
+				var err error
+				for attempts = 1; attempts <= 3; attempts++ {
+					if err = next(ctx, cmd); err == nil {
+						break
+					}
+					cmd.Args = []string{"version"}
+				}
+				return err
+			}
+		},
+	})
+
+	var stdout bytes.Buffer
+	err := Run(context.Background(), Cmd{Path: "go", Args: []string{"not-a-command"}, Stdout: &stdout})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Contains(t, stdout.String(), "go version")
+
+	Reset()
+	err = Run(context.Background(), Cmd{Path: "go", Args: []string{"not-a-command"}})
+	var exitErr *exec.ExitError
+	assert.ErrorAs(t, err, &exitErr)
+}
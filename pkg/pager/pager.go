@@ -0,0 +1,164 @@
+// Package pager facilitates piping long output through the user's preferred pager.
+package pager
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/google/shlex"
+	"github.com/khulnasoft-lab/execsafer"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+)
+
+// ErrClosedPagerPipe is returned when writing to a pager that has already exited,
+// for example because the user quit it before reading all of the output.
+// Callers will usually want to treat this as a successful termination.
+var ErrClosedPagerPipe = errors.New("pager pipe closed")
+
+// Pager represents a pager program that output can be piped through.
+type Pager struct {
+	command string
+	isTTY   bool
+	stdout  io.Writer
+	stderr  io.Writer
+
+	cmd  *exec.Cmd
+	pipe io.WriteCloser
+}
+
+// New initializes a Pager. If a command is not specified one is
+// determined based on environment variables or from the configuration file.
+// The order of precedence for determining a command is:
+// - Specified command;
+// - GOCTL_PAGER environment variable;
+// - pager option from configuration file;
+// - PAGER environment variable.
+//
+// Setting GOCTL_PAGER to an empty string disables paging. The pager is only
+// used when isTTY is true; otherwise output is written directly to stdout.
+func New(command string, isTTY bool, stdout, stderr io.Writer) *Pager {
+	if command == "" {
+		command = resolveCommand()
+	}
+	return &Pager{
+		command: command,
+		isTTY:   isTTY,
+		stdout:  stdout,
+		stderr:  stderr,
+	}
+}
+
+// Start launches the pager and returns a writer that is connected to its
+// standard input. If paging is not applicable the writer is stdout itself.
+// Writes that fail because the pager has exited return ErrClosedPagerPipe.
+func (p *Pager) Start() (io.Writer, error) {
+	return p.start(nil)
+}
+
+func (p *Pager) start(env []string) (io.Writer, error) {
+	if !p.isTTY || p.command == "" || p.command == "cat" {
+		return p.stdout, nil
+	}
+
+	pagerArgs, err := shlex.Split(p.command)
+	if err != nil {
+		return nil, err
+	}
+	if len(pagerArgs) == 0 {
+		return p.stdout, nil
+	}
+	pagerExe, err := execsafer.LookPath(pagerArgs[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if env == nil {
+		env = os.Environ()
+	}
+	if !hasEnv(env, "LESS") {
+		env = append(env, "LESS=FRX")
+	}
+	if !hasEnv(env, "LV") {
+		env = append(env, "LV=-c")
+	}
+
+	cmd := exec.Command(pagerExe, pagerArgs[1:]...)
+	cmd.Env = env
+	cmd.Stdout = p.stdout
+	cmd.Stderr = p.stderr
+	pipe, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	p.cmd = cmd
+	p.pipe = pipe
+	return &pagerWriter{pipe}, nil
+}
+
+// Stop closes the pager's input and waits for it to exit.
+// It is a noop if the pager was never started.
+func (p *Pager) Stop() error {
+	if p.cmd == nil {
+		return nil
+	}
+	_ = p.pipe.Close()
+	err := p.cmd.Wait()
+	p.cmd = nil
+	p.pipe = nil
+	if err != nil {
+		return fmt.Errorf("pager exited with error: %w", err)
+	}
+	return nil
+}
+
+// pagerWriter translates broken pipe errors into ErrClosedPagerPipe.
+type pagerWriter struct {
+	io.Writer
+}
+
+func (w *pagerWriter) Write(d []byte) (int, error) {
+	n, err := w.Writer.Write(d)
+	if err != nil && isPipeClosedError(err) {
+		return n, fmt.Errorf("%w: %v", ErrClosedPagerPipe, err)
+	}
+	return n, err
+}
+
+func isPipeClosedError(err error) bool {
+	return errors.Is(err, io.ErrClosedPipe) ||
+		errors.Is(err, os.ErrClosed) ||
+		errors.Is(err, syscall.EPIPE) ||
+		// Windows reports ERROR_NO_DATA when the reading end of a pipe has gone away.
+		strings.Contains(err.Error(), "pipe is being closed")
+}
+
+func resolveCommand() string {
+	if goctlPager, ok := os.LookupEnv("GOCTL_PAGER"); ok {
+		return goctlPager
+	}
+	cfg, err := config.Read(nil)
+	if err == nil {
+		if cfgPager, _ := cfg.Get([]string{"pager"}); cfgPager != "" {
+			return cfgPager
+		}
+	}
+	return os.Getenv("PAGER")
+}
+
+// hasEnv reports whether the variable key is set in env, even if it is empty.
+func hasEnv(env []string, key string) bool {
+	for _, kv := range env {
+		if k, _, ok := strings.Cut(kv, "="); ok && k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package pager
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GOCTL_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	data, _ := io.ReadAll(os.Stdin)
+	fmt.Fprintf(os.Stdout, "paged: %s", data)
+	os.Exit(0)
+}
+
+func TestPagerStart(t *testing.T) {
+	command := fmt.Sprintf("%q -test.run=TestHelperProcess --", os.Args[0])
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	p := New(command, true, stdout, stderr)
+	w, err := p.start([]string{"GOCTL_WANT_HELPER_PROCESS=1"})
+	assert.NoError(t, err)
+	fmt.Fprint(w, "hello")
+	assert.NoError(t, p.Stop())
+	assert.Equal(t, "paged: hello", stdout.String())
+	assert.Equal(t, "", stderr.String())
+}
+
+func TestHelperProcessEnv(t *testing.T) {
+	if os.Getenv("GOCTL_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	_, _ = io.ReadAll(os.Stdin)
+	less, _ := os.LookupEnv("LESS")
+	lv, _ := os.LookupEnv("LV")
+	fmt.Fprintf(os.Stdout, "LESS=%s LV=%s", less, lv)
+	os.Exit(0)
+}
+
+func TestPagerStartEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  []string
+		want string
+	}{
+		{
+			name: "defaults",
+			want: "LESS=FRX LV=-c",
+		},
+		{
+			name: "set",
+			env:  []string{"LESS=-R", "LV=-a"},
+			want: "LESS=-R LV=-a",
+		},
+		{
+			name: "set to empty",
+			env:  []string{"LESS=", "LV="},
+			want: "LESS= LV=",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LESS", "from-parent")
+			t.Setenv("LV", "from-parent")
+			command := fmt.Sprintf("%q -test.run=TestHelperProcessEnv --", os.Args[0])
+			stdout := &bytes.Buffer{}
+			p := New(command, true, stdout, &bytes.Buffer{})
+			_, err := p.start(append([]string{"GOCTL_WANT_HELPER_PROCESS=1"}, tt.env...))
+			assert.NoError(t, err)
+			assert.NoError(t, p.Stop())
+			assert.Equal(t, tt.want, stdout.String())
+		})
+	}
+}
+
+func TestPagerStartNotTTY(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	p := New("less", false, stdout, &bytes.Buffer{})
+	w, err := p.Start()
+	assert.NoError(t, err)
+	assert.Equal(t, stdout, w)
+	assert.NoError(t, p.Stop())
+}
+
+func TestPagerStartCat(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	p := New("cat", true, stdout, &bytes.Buffer{})
+	w, err := p.Start()
+	assert.NoError(t, err)
+	assert.Equal(t, stdout, w)
+}
+
+func TestPagerWriterClosedPipe(t *testing.T) {
+	r, w := io.Pipe()
+	_ = r.Close()
+	pw := &pagerWriter{w}
+	_, err := pw.Write([]byte("data"))
+	assert.True(t, errors.Is(err, ErrClosedPagerPipe))
+}
+
+func TestResolveCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		config      *config.Config
+		wantCommand string
+	}{
+		{
+			name: "GOCTL_PAGER set",
+			env: map[string]string{
+				"GOCTL_PAGER": "GOCTL_PAGER",
+			},
+			wantCommand: "GOCTL_PAGER",
+		},
+		{
+			name: "GOCTL_PAGER set to empty string",
+			env: map[string]string{
+				"GOCTL_PAGER": "",
+				"PAGER":       "PAGER",
+			},
+			config:      config.ReadFromString("pager: CONFIG_PAGER"),
+			wantCommand: "",
+		},
+		{
+			name:        "config pager set",
+			config:      config.ReadFromString("pager: CONFIG_PAGER"),
+			wantCommand: "CONFIG_PAGER",
+		},
+		{
+			name: "PAGER set",
+			env: map[string]string{
+				"PAGER": "PAGER",
+			},
+			wantCommand: "PAGER",
+		},
+		{
+			name: "config pager and PAGER set",
+			env: map[string]string{
+				"PAGER": "PAGER",
+			},
+			config:      config.ReadFromString("pager: CONFIG_PAGER"),
+			wantCommand: "CONFIG_PAGER",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := tt.env["GOCTL_PAGER"]; !ok {
+				if old, ok := os.LookupEnv("GOCTL_PAGER"); ok {
+					os.Unsetenv("GOCTL_PAGER")
+					t.Cleanup(func() { os.Setenv("GOCTL_PAGER", old) })
+				}
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			old := config.Read
+			config.Read = func(_ *config.Config) (*config.Config, error) {
+				if tt.config == nil {
+					return config.ReadFromString(""), nil
+				}
+				return tt.config, nil
+			}
+			defer func() { config.Read = old }()
+			assert.Equal(t, tt.wantCommand, resolveCommand())
+		})
+	}
+}
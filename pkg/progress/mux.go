@@ -0,0 +1,94 @@
+package progress
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Mux coordinates writes to a terminal that is shared between a live display and
+// other output, such as log lines written by concurrent goroutines. Text written
+// to the Mux is printed above the display, which is erased before the text is
+// written and redrawn after it, so the two are never garbled together.
+//
+// Displays take part by being created with the WithMux option. Only one display
+// should be running on a Mux at a time; use Multi to show several tasks at once.
+type Mux struct {
+	mu      sync.Mutex
+	out     io.Writer
+	display *display
+	buf     []byte
+}
+
+// NewMux initializes a Mux writing to w, which should be the same writer that
+// displays using the Mux are created with.
+func NewMux(w io.Writer) *Mux {
+	return &Mux{out: w}
+}
+
+// WithMux makes a display coordinate its output with m.
+func WithMux(m *Mux) option {
+	return func(d *display) {
+		d.mux = m
+	}
+}
+
+// Write prints every complete line in p above the running display. A trailing
+// partial line is held back until it is completed or Flush is called.
+func (m *Mux) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.buf = append(m.buf, p...)
+	i := bytes.LastIndexByte(m.buf, '\n')
+	if i < 0 {
+		return len(p), nil
+	}
+	if err := m.write(m.buf[:i+1]); err != nil {
+		return 0, err
+	}
+	m.buf = append(m.buf[:0], m.buf[i+1:]...)
+	return len(p), nil
+}
+
+// Flush prints any partial line held back by Write, terminating it with a newline.
+func (m *Mux) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.buf) == 0 {
+		return nil
+	}
+	err := m.write(append(m.buf, '\n'))
+	m.buf = m.buf[:0]
+	return err
+}
+
+// write must be called with m.mu held.
+func (m *Mux) write(b []byte) error {
+	d := m.display
+	if d == nil {
+		_, err := m.out.Write(b)
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.clear()
+	_, err := m.out.Write(b)
+	if d.running {
+		d.draw()
+	}
+	return err
+}
+
+func (m *Mux) attach(d *display) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.display = d
+}
+
+func (m *Mux) detach(d *display) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.display == d {
+		m.display = nil
+	}
+}
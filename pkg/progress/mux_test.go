@@ -0,0 +1,77 @@
+package progress
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMux(t *testing.T) {
+	buf := bytes.Buffer{}
+	m := NewMux(&buf)
+	s := NewSpinner(&buf, true, WithFrames([]string{"-"}), WithInterval(time.Hour), WithMux(m))
+	s.Start("working")
+	fmt.Fprint(m, "first ")
+	fmt.Fprint(m, "line\nsecond line\n")
+	s.Stop()
+	fmt.Fprint(m, "after\n")
+	assert.Equal(t,
+		"- working"+
+			"\r\x1b[J"+
+			"first line\nsecond line\n"+
+			"- working"+
+			"\r\x1b[J"+
+			"after\n",
+		buf.String())
+}
+
+func TestMuxFlush(t *testing.T) {
+	buf := bytes.Buffer{}
+	m := NewMux(&buf)
+	fmt.Fprint(m, "partial")
+	assert.Equal(t, "", buf.String())
+	assert.NoError(t, m.Flush())
+	assert.Equal(t, "partial\n", buf.String())
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func TestMuxConcurrentWrites(t *testing.T) {
+	out := &lockedBuffer{}
+	m := NewMux(out)
+	b := NewBar(out, true, "upload", 100, WithInterval(time.Millisecond), WithMux(m))
+	b.Start()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				fmt.Fprintf(m, "worker %d step %d\n", i, j)
+				b.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	b.Finish()
+
+	// Every log line is written whole, directly after the display was erased.
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			assert.Contains(t, out.buf.String(), fmt.Sprintf("\x1b[Jworker %d step %d\n", i, j))
+		}
+	}
+}
@@ -0,0 +1,468 @@
+// Package progress renders spinners, progress bars, and multi-task displays to a terminal.
+// All displays are disabled when the output is not a terminal, in which case they render nothing,
+// so they can be used unconditionally by commands that may be piped to a script or a file.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/text"
+)
+
+const (
+	defaultInterval = 120 * time.Millisecond
+	defaultBarWidth = 30
+)
+
+var defaultFrames = []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}
+
+var asciiFrames = []string{"|", "/", "-", "\\"}
+
+type option func(*display)
+
+// WithFrames overrides the glyphs used to animate spinners.
+func WithFrames(frames []string) option {
+	return func(d *display) {
+		if len(frames) > 0 {
+			d.frames = frames
+		}
+	}
+}
+
+// WithASCII restricts a display to ASCII characters, for terminals that cannot display
+// Unicode glyphs. It can be combined with [term.Term.IsUTF8] to pick the mode automatically.
+func WithASCII() option {
+	return func(d *display) {
+		d.ascii = true
+		d.frames = asciiFrames
+	}
+}
+
+// WithInterval overrides the interval at which a display is redrawn.
+func WithInterval(interval time.Duration) option {
+	return func(d *display) {
+		if interval > 0 {
+			d.interval = interval
+		}
+	}
+}
+
+// WithMaxWidth truncates every rendered line to fit within the given display width,
+// which prevents long lines from wrapping and corrupting the redraw.
+func WithMaxWidth(width int) option {
+	return func(d *display) {
+		d.maxWidth = width
+	}
+}
+
+// WithBytes formats progress bar counts as byte sizes, e.g. "1.5 MiB/10.0 MiB".
+func WithBytes() option {
+	return func(d *display) {
+		d.bytes = true
+	}
+}
+
+// display is the shared rendering engine behind Spinner, Bar, and Multi. It redraws
+// the lines returned by render on every tick, erasing the lines drawn previously.
+type display struct {
+	mu       sync.Mutex
+	out      io.Writer
+	enabled  bool
+	interval time.Duration
+	frames   []string
+	maxWidth int
+	bytes    bool
+	ascii    bool
+	render   func(frame string) []string
+	mux      *Mux
+
+	frame   int
+	drawn   []int
+	running bool
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+func newDisplay(w io.Writer, isTTY bool, opts []option) *display {
+	d := &display{
+		out:      w,
+		enabled:  isTTY,
+		interval: defaultInterval,
+		frames:   defaultFrames,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+func (d *display) start() {
+	d.mu.Lock()
+	if !d.enabled || d.running {
+		d.mu.Unlock()
+		return
+	}
+	d.running = true
+	d.stopCh = make(chan struct{})
+	d.doneCh = make(chan struct{})
+	d.draw()
+	go d.loop(d.stopCh, d.doneCh)
+	d.mu.Unlock()
+
+	if d.mux != nil {
+		d.mux.attach(d)
+	}
+}
+
+func (d *display) loop(stopCh, doneCh chan struct{}) {
+	defer close(doneCh)
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			d.mu.Lock()
+			d.frame++
+			d.draw()
+			d.mu.Unlock()
+		}
+	}
+}
+
+// stop halts the animation and erases the display. If keep is true the final
+// state of the display is drawn once more and left on screen.
+func (d *display) stop(keep bool) {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return
+	}
+	d.running = false
+	close(d.stopCh)
+	doneCh := d.doneCh
+	d.mu.Unlock()
+
+	<-doneCh
+
+	d.mu.Lock()
+	if keep {
+		d.draw()
+		if len(d.drawn) > 0 {
+			fmt.Fprint(d.out, "\n")
+		}
+		d.drawn = nil
+	} else {
+		d.clear()
+	}
+	d.mu.Unlock()
+
+	if d.mux != nil {
+		d.mux.detach(d)
+	}
+}
+
+// draw must be called with d.mu held.
+func (d *display) draw() {
+	lines := d.render(d.frames[d.frame%len(d.frames)])
+	d.clear()
+	if len(lines) == 0 {
+		return
+	}
+	if d.maxWidth > 0 {
+		for i, l := range lines {
+			lines[i] = text.Truncate(d.maxWidth, l)
+		}
+	}
+	fmt.Fprint(d.out, strings.Join(lines, "\n"))
+	d.drawn = make([]int, len(lines))
+	for i, l := range lines {
+		d.drawn[i] = text.DisplayWidth(l)
+	}
+}
+
+// setMaxWidth changes the maximum width and redraws the display at the new width.
+func (d *display) setMaxWidth(width int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.maxWidth = width
+	if d.running {
+		d.draw()
+	}
+}
+
+// clear erases the lines drawn previously and leaves the cursor at the start of
+// the first of them. It must be called with d.mu held.
+func (d *display) clear() {
+	if len(d.drawn) == 0 {
+		return
+	}
+	// Lines drawn before the terminal was narrowed may now wrap onto several rows.
+	rows := 0
+	for _, w := range d.drawn {
+		if d.maxWidth > 0 && w > d.maxWidth {
+			rows += (w + d.maxWidth - 1) / d.maxWidth
+		} else {
+			rows++
+		}
+	}
+	fmt.Fprint(d.out, "\r")
+	if rows > 1 {
+		fmt.Fprintf(d.out, "\x1b[%dA", rows-1)
+	}
+	fmt.Fprint(d.out, "\x1b[J")
+	d.drawn = nil
+}
+
+// Spinner is an indeterminate progress indicator with an optional message.
+type Spinner struct {
+	d   *display
+	msg string
+}
+
+// NewSpinner initializes a Spinner writing to w. When isTTY is false the spinner is
+// disabled and renders nothing.
+func NewSpinner(w io.Writer, isTTY bool, opts ...option) *Spinner {
+	s := &Spinner{}
+	s.d = newDisplay(w, isTTY, opts)
+	s.d.render = func(frame string) []string {
+		if s.msg == "" {
+			return []string{frame}
+		}
+		return []string{frame + " " + s.msg}
+	}
+	return s
+}
+
+// Start begins animating the spinner with the given message.
+func (s *Spinner) Start(msg string) {
+	s.SetMessage(msg)
+	s.d.start()
+}
+
+// SetMessage changes the message displayed next to the spinner.
+func (s *Spinner) SetMessage(msg string) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.msg = msg
+}
+
+// SetWidth changes the maximum display width of the spinner, for example after the
+// terminal has been resized, and redraws it to fit.
+func (s *Spinner) SetWidth(width int) {
+	s.d.setMaxWidth(width)
+}
+
+// Stop halts the spinner and erases it from the terminal.
+func (s *Spinner) Stop() {
+	s.d.stop(false)
+}
+
+// Bar is a determinate progress indicator. Its Update method matches the
+// signature of the progress callbacks accepted by transfer helpers, so a Bar
+// can be passed to them directly.
+type Bar struct {
+	d       *display
+	label   string
+	current int64
+	total   int64
+}
+
+// NewBar initializes a Bar writing to w for an operation of the given total size.
+// When isTTY is false the bar is disabled and renders nothing.
+func NewBar(w io.Writer, isTTY bool, label string, total int64, opts ...option) *Bar {
+	b := &Bar{label: label, total: total}
+	b.d = newDisplay(w, isTTY, opts)
+	b.d.render = func(frame string) []string {
+		return []string{renderBar(frame, b.label, b.current, b.total, b.d.bytes)}
+	}
+	return b
+}
+
+// Start begins rendering the bar.
+func (b *Bar) Start() {
+	b.d.start()
+}
+
+// Update sets the current progress and total size of the bar.
+// A total of zero or less renders the bar as indeterminate.
+func (b *Bar) Update(current, total int64) {
+	b.d.mu.Lock()
+	defer b.d.mu.Unlock()
+	b.current = current
+	b.total = total
+}
+
+// Add increments the current progress of the bar by n.
+func (b *Bar) Add(n int64) {
+	b.d.mu.Lock()
+	defer b.d.mu.Unlock()
+	b.current += n
+}
+
+// SetWidth changes the maximum display width of the bar, for example after the
+// terminal has been resized, and redraws it to fit.
+func (b *Bar) SetWidth(width int) {
+	b.d.setMaxWidth(width)
+}
+
+// Finish halts the bar leaving its final state on the terminal.
+func (b *Bar) Finish() {
+	b.d.stop(true)
+}
+
+// Abort halts the bar and erases it from the terminal.
+func (b *Bar) Abort() {
+	b.d.stop(false)
+}
+
+// Multi renders the progress of several concurrent tasks, one task per line.
+type Multi struct {
+	d     *display
+	tasks []*Task
+}
+
+// Task is a single line of a Multi display.
+type Task struct {
+	m       *Multi
+	label   string
+	current int64
+	total   int64
+	state   taskState
+}
+
+type taskState int
+
+const (
+	taskRunning taskState = iota
+	taskDone
+	taskFailed
+)
+
+// NewMulti initializes a Multi writing to w. When isTTY is false the display is
+// disabled and renders nothing.
+func NewMulti(w io.Writer, isTTY bool, opts ...option) *Multi {
+	m := &Multi{}
+	m.d = newDisplay(w, isTTY, opts)
+	m.d.render = func(frame string) []string {
+		lines := make([]string, 0, len(m.tasks))
+		for _, t := range m.tasks {
+			lines = append(lines, t.line(frame))
+		}
+		return lines
+	}
+	return m
+}
+
+// AddTask adds a task to the display. A total of zero or less renders the task
+// with a spinner rather than a bar.
+func (m *Multi) AddTask(label string, total int64) *Task {
+	m.d.mu.Lock()
+	defer m.d.mu.Unlock()
+	t := &Task{m: m, label: label, total: total}
+	m.tasks = append(m.tasks, t)
+	return t
+}
+
+// Start begins rendering the display.
+func (m *Multi) Start() {
+	m.d.start()
+}
+
+// Stop halts the display leaving the final state of every task on the terminal.
+func (m *Multi) Stop() {
+	m.d.stop(true)
+}
+
+// SetWidth changes the maximum display width of all tasks, for example after the
+// terminal has been resized, and redraws them to fit.
+func (m *Multi) SetWidth(width int) {
+	m.d.setMaxWidth(width)
+}
+
+// Update sets the current progress and total size of the task.
+func (t *Task) Update(current, total int64) {
+	t.m.d.mu.Lock()
+	defer t.m.d.mu.Unlock()
+	t.current = current
+	t.total = total
+}
+
+// Done marks the task as successfully completed.
+func (t *Task) Done() {
+	t.setState(taskDone)
+}
+
+// Fail marks the task as failed.
+func (t *Task) Fail() {
+	t.setState(taskFailed)
+}
+
+func (t *Task) setState(s taskState) {
+	t.m.d.mu.Lock()
+	defer t.m.d.mu.Unlock()
+	t.state = s
+}
+
+func (t *Task) line(frame string) string {
+	switch t.state {
+	case taskDone:
+		if t.m.d.ascii {
+			return "+ " + t.label
+		}
+		return "✓ " + t.label
+	case taskFailed:
+		return "X " + t.label
+	}
+	return renderBar(frame, t.label, t.current, t.total, t.m.d.bytes)
+}
+
+func renderBar(frame, label string, current, total int64, bytes bool) string {
+	var sb strings.Builder
+	if label != "" {
+		sb.WriteString(label)
+		sb.WriteString(" ")
+	}
+	if total <= 0 {
+		sb.WriteString(frame)
+		if current > 0 {
+			sb.WriteString(" ")
+			sb.WriteString(formatCount(current, bytes))
+		}
+		return sb.String()
+	}
+	if current > total {
+		current = total
+	}
+	filled := int(float64(defaultBarWidth) * float64(current) / float64(total))
+	sb.WriteString("[")
+	sb.WriteString(strings.Repeat("#", filled))
+	sb.WriteString(strings.Repeat("-", defaultBarWidth-filled))
+	sb.WriteString("] ")
+	fmt.Fprintf(&sb, "%3d%% ", int(100*current/total))
+	sb.WriteString(formatCount(current, bytes))
+	sb.WriteString("/")
+	sb.WriteString(formatCount(total, bytes))
+	return sb.String()
+}
+
+func formatCount(n int64, bytes bool) string {
+	if !bytes {
+		return fmt.Sprintf("%d", n)
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,143 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpinnerDisabledWhenNotTTY(t *testing.T) {
+	buf := bytes.Buffer{}
+	s := NewSpinner(&buf, false)
+	s.Start("working")
+	s.Stop()
+	assert.Equal(t, "", buf.String())
+}
+
+func TestSpinnerStartStop(t *testing.T) {
+	buf := bytes.Buffer{}
+	s := NewSpinner(&buf, true, WithFrames([]string{"-"}), WithInterval(time.Hour))
+	s.Start("working")
+	s.Stop()
+	assert.Equal(t, "- working\r\x1b[J", buf.String())
+}
+
+func TestBarFinish(t *testing.T) {
+	buf := bytes.Buffer{}
+	b := NewBar(&buf, true, "upload", 10, WithInterval(time.Hour))
+	b.Start()
+	b.Update(5, 10)
+	b.Finish()
+	assert.Equal(t,
+		"upload [------------------------------]   0% 0/10"+
+			"\r\x1b[J"+
+			"upload [###############---------------]  50% 5/10\n",
+		buf.String())
+}
+
+func TestBarDisabledWhenNotTTY(t *testing.T) {
+	buf := bytes.Buffer{}
+	b := NewBar(&buf, false, "upload", 10)
+	b.Start()
+	b.Add(10)
+	b.Finish()
+	assert.Equal(t, "", buf.String())
+}
+
+func TestMulti(t *testing.T) {
+	buf := bytes.Buffer{}
+	m := NewMulti(&buf, true, WithFrames([]string{"*"}), WithInterval(time.Hour))
+	one := m.AddTask("one", 0)
+	two := m.AddTask("two", 4)
+	m.Start()
+	one.Done()
+	two.Update(4, 4)
+	m.Stop()
+	assert.Equal(t,
+		"one *\ntwo [------------------------------]   0% 0/4"+
+			"\r\x1b[1A\x1b[J"+
+			"✓ one\ntwo [##############################] 100% 4/4\n",
+		buf.String())
+}
+
+func TestRenderBar(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int64
+		total   int64
+		bytes   bool
+		want    string
+	}{
+		{
+			name:    "indeterminate",
+			current: 0,
+			total:   0,
+			want:    "x *",
+		},
+		{
+			name:    "indeterminate with count",
+			current: 2048,
+			total:   0,
+			bytes:   true,
+			want:    "x * 2.0 KiB",
+		},
+		{
+			name:    "overflow is clamped",
+			current: 20,
+			total:   10,
+			want:    "x [##############################] 100% 10/10",
+		},
+		{
+			name:    "bytes",
+			current: 512,
+			total:   3 * 1024 * 1024,
+			bytes:   true,
+			want:    "x [------------------------------]   0% 512 B/3.0 MiB",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, renderBar("*", "x", tt.current, tt.total, tt.bytes))
+		})
+	}
+}
+
+func TestWithMaxWidth(t *testing.T) {
+	buf := bytes.Buffer{}
+	s := NewSpinner(&buf, true, WithFrames([]string{"-"}), WithInterval(time.Hour), WithMaxWidth(8))
+	s.Start("a very long message")
+	s.Stop()
+	assert.Equal(t, "- a v...\r\x1b[J", buf.String())
+}
+
+func TestSpinnerSetWidth(t *testing.T) {
+	buf := bytes.Buffer{}
+	s := NewSpinner(&buf, true, WithFrames([]string{"-"}), WithInterval(time.Hour), WithMaxWidth(20))
+	s.Start("a very long message")
+	s.SetWidth(8)
+	s.Stop()
+	assert.Equal(t,
+		"- a very long mes..."+
+			// the previous line now wraps onto three rows
+			"\r\x1b[2A\x1b[J"+
+			"- a v..."+
+			"\r\x1b[J",
+		buf.String())
+}
+
+func TestWithASCII(t *testing.T) {
+	buf := bytes.Buffer{}
+	m := NewMulti(&buf, true, WithASCII(), WithInterval(time.Hour))
+	one := m.AddTask("one", 0)
+	m.AddTask("two", 0)
+	m.Start()
+	one.Done()
+	m.Stop()
+	assert.Equal(t,
+		"one |\ntwo |"+
+			"\r\x1b[1A\x1b[J"+
+			"+ one\ntwo |\n",
+		buf.String())
+}
@@ -0,0 +1,207 @@
+package prompter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/i18n"
+	xterm "golang.org/x/term"
+)
+
+// WithAccessible makes all prompts use the accessible mode, regardless of the
+// GOCTL_ACCESSIBLE_PROMPTER environment variable and the "accessible_prompter"
+// configuration setting.
+//
+// Accessible prompts are friendly to screen readers: they are written one line at a time
+// without moving the cursor to redraw the screen, and options are chosen by typing their
+// number rather than navigating with the arrow keys.
+func WithAccessible() option {
+	return func(p *Prompter) {
+		p.accessible = func() bool { return true }
+	}
+}
+
+// accessibleEnabled reports whether the user has enabled accessible prompts, either with
+// the GOCTL_ACCESSIBLE_PROMPTER environment variable or the "accessible_prompter"
+// configuration setting.
+func accessibleEnabled() bool {
+	if v, ok := os.LookupEnv("GOCTL_ACCESSIBLE_PROMPTER"); ok {
+		return v != "" && v != "0" && v != "false"
+	}
+	cfg, err := config.Read(nil)
+	if err != nil {
+		return false
+	}
+	v, _ := cfg.Get([]string{"accessible_prompter"})
+	return v == "enabled"
+}
+
+// readLine reads a single line of input, without the trailing line break. Input is read
+// one byte at a time so that nothing past the line is consumed, leaving it for the next
+// prompt even when that prompt is shown by a copy of the Prompter.
+func (p *Prompter) readLine() (string, error) {
+	if err := p.cancelled(); err != nil {
+		return "", err
+	}
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := p.stdin.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if err != nil {
+			if cerr := p.cancelled(); cerr != nil {
+				return "", cerr
+			}
+			if err != io.EOF || len(line) == 0 {
+				return "", fmt.Errorf("could not prompt: %w", err)
+			}
+			break
+		}
+	}
+	return strings.TrimRight(string(line), "\r"), nil
+}
+
+func (p *Prompter) accessibleSelect(prompt string, defaultIndex int, options []string) (int, error) {
+	fmt.Fprintln(p.stdout, prompt)
+	for i, o := range options {
+		fmt.Fprintf(p.stdout, "  %d. %s\n", i+1, o)
+
+	}
+	for {
+		if defaultIndex >= 0 {
+			fmt.Fprint(p.stdout, i18n.Sprintf("Enter a number (default: %d): ", defaultIndex+1))
+		} else {
+			fmt.Fprint(p.stdout, i18n.Sprintf("Enter a number: "))
+		}
+		answer, err := p.readLine()
+		if err != nil {
+			return 0, err
+		}
+		answer = strings.TrimSpace(answer)
+		if answer == "" && defaultIndex >= 0 {
+			return defaultIndex, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+		fmt.Fprint(p.stdout, i18n.Sprintf("Invalid selection, enter a number between 1 and %d.\n", len(options)))
+	}
+}
+
+func (p *Prompter) accessibleMultiSelect(prompt string, defaultIndexes []int, options []string) ([]int, error) {
+	fmt.Fprintln(p.stdout, prompt)
+	for i, o := range options {
+		fmt.Fprintf(p.stdout, "  %d. %s\n", i+1, o)
+
+	}
+outer:
+	for {
+		if len(defaultIndexes) > 0 {
+			defaults := make([]string, len(defaultIndexes))
+			for i, d := range defaultIndexes {
+				defaults[i] = strconv.Itoa(d + 1)
+			}
+			fmt.Fprint(p.stdout, i18n.Sprintf("Enter numbers separated by commas (default: %s): ", strings.Join(defaults, ",")))
+		} else {
+			fmt.Fprint(p.stdout, i18n.Sprintf("Enter numbers separated by commas, or leave empty for none: "))
+		}
+		answer, err := p.readLine()
+		if err != nil {
+			return nil, err
+		}
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			return defaultIndexes, nil
+		}
+		result := []int{}
+		for _, field := range strings.Split(answer, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || n < 1 || n > len(options) {
+				fmt.Fprint(p.stdout, i18n.Sprintf("Invalid selection %q, enter numbers between 1 and %d.\n", strings.TrimSpace(field), len(options)))
+				continue outer
+			}
+			result = append(result, n-1)
+		}
+		return result, nil
+	}
+}
+
+func (p *Prompter) accessibleInput(prompt, defaultValue string, validators []Validator) (string, error) {
+	for {
+		if defaultValue != "" {
+			fmt.Fprint(p.stdout, i18n.Sprintf("%s (default: %s): ", prompt, defaultValue))
+		} else {
+			fmt.Fprintf(p.stdout, "%s: ", prompt)
+		}
+		answer, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = defaultValue
+		}
+		if err := validate(answer, validators); err != nil {
+			fmt.Fprint(p.stdout, i18n.Sprintf("Invalid answer: %v\n", err))
+			continue
+		}
+		return answer, nil
+	}
+}
+
+func (p *Prompter) accessiblePassword(prompt string, validators []Validator) (string, error) {
+	for {
+		fmt.Fprintf(p.stdout, "%s: ", prompt)
+		var answer string
+		fd := int(p.stdin.Fd())
+		if xterm.IsTerminal(fd) {
+			b, err := xterm.ReadPassword(fd)
+			fmt.Fprintln(p.stdout)
+			if err != nil {
+				return "", fmt.Errorf("could not prompt: %w", err)
+			}
+			answer = string(b)
+		} else {
+			var err error
+			if answer, err = p.readLine(); err != nil {
+				return "", err
+			}
+		}
+		if err := validate(answer, validators); err != nil {
+			fmt.Fprint(p.stdout, i18n.Sprintf("Invalid answer: %v\n", err))
+			continue
+		}
+		return answer, nil
+	}
+}
+
+func (p *Prompter) accessibleConfirm(prompt string, defaultValue bool) (bool, error) {
+	hint := i18n.Sprintf("y/N")
+	if defaultValue {
+		hint = i18n.Sprintf("Y/n")
+	}
+	for {
+		fmt.Fprintf(p.stdout, "%s (%s): ", prompt, hint)
+		answer, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "":
+			return defaultValue, nil
+		case "y", "yes", strings.ToLower(i18n.Sprintf("yes")):
+			return true, nil
+		case "n", "no", strings.ToLower(i18n.Sprintf("no")):
+			return false, nil
+		}
+		fmt.Fprintln(p.stdout, i18n.Sprintf("Invalid answer, enter yes or no."))
+	}
+}
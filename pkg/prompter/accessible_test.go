@@ -0,0 +1,173 @@
+package prompter
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+type fakeReader struct {
+	*strings.Reader
+}
+
+func (fakeReader) Fd() uintptr { return ^uintptr(0) }
+
+type fakeWriter struct {
+	*bytes.Buffer
+}
+
+func (fakeWriter) Fd() uintptr { return ^uintptr(0) }
+
+func newAccessiblePrompter(input string) (*Prompter, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	p := New(fakeReader{strings.NewReader(input)}, fakeWriter{out}, fakeWriter{&bytes.Buffer{}}, WithAccessible())
+	p.isInteractive = func() bool { return true }
+	p.promptDisabled = func() bool { return false }
+	return p, out
+}
+
+func TestAccessibleSelect(t *testing.T) {
+	p, out := newAccessiblePrompter("4\nx\n2\n\n")
+
+	i, err := p.Select("Fruit", "", []string{"apple", "banana", "cherry"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+
+	i, err = p.Select("Fruit", "cherry", []string{"apple", "banana", "cherry"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, i)
+
+	assert.Equal(t, heredoc.Doc(`
+		Fruit
+		  1. apple
+		  2. banana
+		  3. cherry
+		Enter a number: Invalid selection, enter a number between 1 and 3.
+		Enter a number: Invalid selection, enter a number between 1 and 3.
+		Enter a number: Fruit
+		  1. apple
+		  2. banana
+		  3. cherry
+		Enter a number (default: 3): `), out.String())
+}
+
+func TestAccessibleMultiSelect(t *testing.T) {
+	p, _ := newAccessiblePrompter("1, 5\n3,1\n\n")
+
+	is, err := p.MultiSelect("Fruits", nil, []string{"apple", "banana", "cherry"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 0}, is)
+
+	is, err = p.MultiSelect("Fruits", []string{"banana"}, []string{"apple", "banana", "cherry"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, is)
+}
+
+func TestAccessibleInput(t *testing.T) {
+	p, out := newAccessiblePrompter("\nmonalisa\n\n")
+
+	name, err := p.InputWithValidation("Name", "", Required())
+	assert.NoError(t, err)
+	assert.Equal(t, "monalisa", name)
+
+	name, err = p.Input("Name", "hubot")
+	assert.NoError(t, err)
+	assert.Equal(t, "hubot", name)
+
+	assert.Equal(t, "Name: Invalid answer: a value is required\nName: Name (default: hubot): ", out.String())
+}
+
+func TestAccessiblePassword(t *testing.T) {
+	p, _ := newAccessiblePrompter("secret\n")
+	pw, err := p.Password("Token")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", pw)
+}
+
+func TestAccessibleConfirm(t *testing.T) {
+	p, out := newAccessiblePrompter("maybe\nyes\n\n")
+
+	b, err := p.Confirm("Continue", false)
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	b, err = p.Confirm("Continue", false)
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	assert.Equal(t, "Continue (y/N): Invalid answer, enter yes or no.\nContinue (y/N): Continue (y/N): ", out.String())
+}
+
+func TestAccessibleConfirmTranslated(t *testing.T) {
+	t.Cleanup(func() { i18n.SetLanguage(language.English) })
+	fr := language.French
+	assert.NoError(t, i18n.SetString(fr, "y/N", "o/N"))
+	assert.NoError(t, i18n.SetString(fr, "yes", "oui"))
+	i18n.SetLanguage(fr)
+	p, out := newAccessiblePrompter("Oui\n")
+
+	b, err := p.Confirm("Continuer", false)
+	assert.NoError(t, err)
+	assert.True(t, b)
+	assert.Equal(t, "Continuer (o/N): ", out.String())
+}
+
+func TestAccessibleEOF(t *testing.T) {
+	p, _ := newAccessiblePrompter("")
+	_, err := p.Input("Name", "")
+	assert.EqualError(t, err, "could not prompt: EOF")
+}
+
+func TestAccessibleEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    string
+		setEnv bool
+		config string
+		want   bool
+	}{
+		{
+			name: "disabled by default",
+			want: false,
+		},
+		{
+			name:   "enabled by env",
+			env:    "1",
+			setEnv: true,
+			want:   true,
+		},
+		{
+			name:   "env false overrides config",
+			env:    "false",
+			setEnv: true,
+			config: "accessible_prompter: enabled\n",
+			want:   false,
+		},
+		{
+			name:   "enabled by config",
+			config: "accessible_prompter: enabled\n",
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GOCTL_ACCESSIBLE_PROMPTER", tt.env)
+			if !tt.setEnv {
+				os.Unsetenv("GOCTL_ACCESSIBLE_PROMPTER")
+			}
+			oldRead := config.Read
+			t.Cleanup(func() { config.Read = oldRead })
+			config.Read = func(*config.Config) (*config.Config, error) {
+				return config.ReadFromString(tt.config), nil
+			}
+			assert.Equal(t, tt.want, accessibleEnabled())
+		})
+	}
+}
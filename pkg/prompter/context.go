@@ -0,0 +1,144 @@
+package prompter
+
+import (
+	"context"
+	"io"
+)
+
+// CancelledError is returned by prompts that were abandoned because the context of the
+// Prompter was cancelled or its deadline was exceeded before the user answered.
+type CancelledError struct {
+	// Err is the error of the context, such as context.DeadlineExceeded.
+	Err error
+}
+
+func (e *CancelledError) Error() string {
+	return "prompt cancelled: " + e.Err.Error()
+}
+
+func (e *CancelledError) Unwrap() error {
+	return e.Err
+}
+
+// WithContext returns a shallow copy of the Prompter whose prompts are abandoned with a
+// *CancelledError when ctx is done, for example to enforce a deadline or to dismiss a
+// question that has been resolved by other means. Any input the user types after a
+// prompt is abandoned is kept for the next prompt.
+func (p *Prompter) WithContext(ctx context.Context) *Prompter {
+	if p.async == nil {
+		p.async = &asyncReader{r: p.stdin}
+	}
+	p2 := *p
+	p2.ctx = ctx
+	p2.stdin = &contextReader{FileReader: p.stdin, async: p.async, ctx: ctx}
+	return &p2
+}
+
+// cancelled returns a *CancelledError if the context of the Prompter is done.
+func (p *Prompter) cancelled() error {
+	if p.ctx == nil || p.ctx.Err() == nil {
+		return nil
+	}
+	return &CancelledError{Err: p.ctx.Err()}
+}
+
+type readResult struct {
+	data []byte
+	err  error
+}
+
+// asyncReader reads in the background so that a read can be abandoned when a context
+// is done. Data that arrives after a read was abandoned is returned by the next read.
+type asyncReader struct {
+	r       io.Reader
+	pending chan readResult
+	stop    chan struct{}
+	buf     []byte
+	err     error
+}
+
+func (a *asyncReader) read(ctx context.Context, b []byte) (int, error) {
+	if len(a.buf) == 0 && a.err == nil {
+		if a.pending == nil {
+			ch := make(chan readResult, 1)
+			stop := make(chan struct{})
+			go func() {
+				if !waitReadable(a.r, stop) {
+					ch <- readResult{}
+					return
+				}
+				buf := make([]byte, 4096)
+				n, err := a.r.Read(buf)
+				ch <- readResult{data: buf[:n], err: err}
+			}()
+			a.pending, a.stop = ch, stop
+		}
+		select {
+		case res := <-a.pending:
+			a.pending = nil
+			a.buf, a.err = res.data, res.err
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+	n := copy(b, a.buf)
+	a.buf = a.buf[n:]
+	if len(a.buf) == 0 && a.err != nil {
+		err := a.err
+		a.err = nil
+		return n, err
+	}
+	return n, nil
+}
+
+// pause stops the background read left behind by an abandoned read, so that it does not
+// take input meant for another program such as an editor. Input it has already read is
+// kept for the next read, which starts reading in the background again. A read that cannot
+// be stopped is left running.
+func (a *asyncReader) pause() {
+	if a.pending == nil || !canStop(a.r) {
+		return
+	}
+	close(a.stop)
+	res := <-a.pending
+	a.pending, a.stop = nil, nil
+	a.buf = append(a.buf, res.data...)
+	if a.err == nil {
+		a.err = res.err
+	}
+}
+
+// terminalInput returns the standard input of the Prompter as it was before WithContext
+// wrapped it, after pausing the background read of WithContext, so that it can be handed
+// to a program that reads the terminal itself.
+func (p *Prompter) terminalInput() FileReader {
+	stdin := p.stdin
+	for {
+		r, ok := stdin.(*contextReader)
+		if !ok {
+			return stdin
+		}
+		r.async.pause()
+		stdin = r.FileReader
+	}
+}
+
+// contextReader reads from stdin until its context is done. It then reports an interrupt
+// character once, so that a prompt reading raw input is aborted the same way as when the
+// user presses Ctrl-C and restores the terminal, and fails every read after that.
+type contextReader struct {
+	FileReader
+	async       *asyncReader
+	ctx         context.Context
+	interrupted bool
+}
+
+func (r *contextReader) Read(b []byte) (int, error) {
+	n, err := r.async.read(r.ctx, b)
+	if err != nil && err == r.ctx.Err() && !r.interrupted && len(b) > 0 {
+		r.interrupted = true
+		b[0] = '\x03'
+		return 1, nil
+	}
+	return n, err
+}
@@ -0,0 +1,102 @@
+package prompter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pipeReader struct {
+	*io.PipeReader
+}
+
+func (pipeReader) Fd() uintptr { return ^uintptr(0) }
+
+func TestWithContextCancelsPrompt(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	p := New(pipeReader{r}, fakeWriter{&bytes.Buffer{}}, fakeWriter{&bytes.Buffer{}}, WithAccessible())
+	p.isInteractive = func() bool { return true }
+	p.promptDisabled = func() bool { return false }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := p.WithContext(ctx).Input("Name", "")
+	var cancelErr *CancelledError
+	assert.True(t, errors.As(err, &cancelErr))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.EqualError(t, err, "prompt cancelled: context deadline exceeded")
+
+	_, err = p.WithContext(ctx).Confirm("Continue", true)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	go func() {
+		_, _ = w.Write([]byte("monalisa\n"))
+	}()
+	name, err := p.WithContext(context.Background()).Input("Name", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "monalisa", name)
+}
+
+func TestContextReader(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	async := &asyncReader{r: r}
+	ctx, cancel := context.WithCancel(context.Background())
+	cr := &contextReader{FileReader: pipeReader{r}, async: async, ctx: ctx}
+	cancel()
+
+	b := make([]byte, 8)
+	n, err := cr.Read(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "\x03", string(b[:n]))
+
+	_, err = cr.Read(b)
+	assert.Equal(t, context.Canceled, err)
+
+	go func() {
+		_, _ = w.Write([]byte("hello"))
+	}()
+	n, err = async.read(context.Background(), b[:2])
+	assert.NoError(t, err)
+	assert.Equal(t, "he", string(b[:n]))
+	n, err = async.read(context.Background(), b)
+	assert.NoError(t, err)
+	assert.Equal(t, "llo", string(b[:n]))
+}
+
+func TestAsyncReaderPause(t *testing.T) {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+	async := &asyncReader{r: r}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b := make([]byte, 8)
+	_, err = async.read(ctx, b)
+	assert.Equal(t, context.Canceled, err)
+
+	async.pause()
+	if !canStop(r) {
+		t.Skip("background reads cannot be stopped on this platform")
+	}
+	// Input written after the pause goes to the next reader of the file.
+	_, err = w.Write([]byte("vim"))
+	assert.NoError(t, err)
+	n, err := r.Read(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "vim", string(b[:n]))
+
+	_, err = w.Write([]byte("yes"))
+	assert.NoError(t, err)
+	n, err = async.read(context.Background(), b)
+	assert.NoError(t, err)
+	assert.Equal(t, "yes", string(b[:n]))
+}
@@ -0,0 +1,115 @@
+package prompter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/google/shlex"
+	"github.com/khulnasoft-lab/execsafer"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+)
+
+// Editor opens the user's preferred editor on a temporary file named filename that is
+// prefilled with initialContents, and returns the contents of the file once the editor
+// exits. The file name only serves to give the editor a hint about the type of content,
+// such as "body.md" for syntax highlighting.
+//
+// The order of precedence for determining the editor is:
+// - GOCTL_EDITOR environment variable;
+// - editor option from configuration file;
+// - VISUAL environment variable;
+// - EDITOR environment variable;
+// - nano, or notepad on Windows.
+func (p *Prompter) Editor(filename, initialContents string) (string, error) {
+	if !p.interactive() {
+		if p.policy == UseDefault {
+			return initialContents, nil
+		}
+		return "", ErrNonInteractive
+	}
+	return p.edit(resolveEditor(), filename, initialContents, nil)
+}
+
+func (p *Prompter) edit(command, filename, initialContents string, env []string) (string, error) {
+	dir, err := os.MkdirTemp("", "goctl-editor-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, filepath.Base(filename))
+	if err := os.WriteFile(path, []byte(initialContents), 0600); err != nil {
+		return "", err
+	}
+
+	editorArgs, err := shlex.Split(command)
+	if err != nil {
+		return "", err
+	}
+	if len(editorArgs) == 0 {
+		return "", fmt.Errorf("no editor configured")
+	}
+	editorExe, err := execsafer.LookPath(editorArgs[0])
+	if err != nil {
+		return "", err
+	}
+
+	cmd := p.editorCommand(editorExe, append(editorArgs[1:], path), env)
+	if err := cmd.Run(); err != nil {
+		if err := p.cancelled(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("editor exited with error: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// editorCommand returns the command that runs the editor exe with args, connected to the
+// terminal of the Prompter.
+func (p *Prompter) editorCommand(exe string, args []string, env []string) *exec.Cmd {
+	ctx := p.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd := exec.CommandContext(ctx, exe, args...)
+	if env != nil {
+		cmd.Env = env
+	}
+	// The editor reads the terminal itself rather than through the context reader, which
+	// would compete with it for keystrokes.
+	cmd.Stdin = p.terminalInput()
+	cmd.Stdout = p.stdout
+	cmd.Stderr = p.stderr
+	return cmd
+}
+
+func resolveEditor() string {
+	if goctlEditor := os.Getenv("GOCTL_EDITOR"); goctlEditor != "" {
+		return goctlEditor
+	}
+	cfg, err := config.Read(nil)
+	if err == nil {
+		if cfgEditor, _ := cfg.Get([]string{"editor"}); cfgEditor != "" {
+			return cfgEditor
+		}
+	}
+	if visual := os.Getenv("VISUAL"); visual != "" {
+		return visual
+	}
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "nano"
+}
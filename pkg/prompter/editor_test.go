@@ -0,0 +1,117 @@
+package prompter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GOCTL_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	path := os.Args[len(os.Args)-1]
+	data, _ := os.ReadFile(path)
+	_ = os.WriteFile(path, append(data, []byte("edited\n")...), 0600)
+	os.Exit(0)
+}
+
+func TestEdit(t *testing.T) {
+	command := fmt.Sprintf("%q -test.run=TestHelperProcess --", os.Args[0])
+	p := New(os.Stdin, os.Stdout, os.Stderr)
+	env := append(os.Environ(), "GOCTL_WANT_HELPER_PROCESS=1")
+	got, err := p.edit(command, "body.md", "initial\n", env)
+	assert.NoError(t, err)
+	assert.Equal(t, "initial\nedited\n", got)
+}
+
+func TestEditorCommandWithContext(t *testing.T) {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+	p := New(r, os.Stdout, os.Stderr)
+
+	// A prompt abandoned by its context leaves a read in the background.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = p.WithContext(ctx).stdin.Read(make([]byte, 1))
+	assert.NoError(t, err)
+
+	cmd := p.WithContext(context.Background()).editorCommand("vim", []string{"body.md"}, nil)
+	assert.Equal(t, r, cmd.Stdin)
+}
+
+func TestEditorNonInteractive(t *testing.T) {
+	p := New(os.Stdin, os.Stdout, os.Stderr, WithNonInteractivePolicy(UseDefault))
+	p.isInteractive = func() bool { return false }
+	got, err := p.Editor("body.md", "initial")
+	assert.NoError(t, err)
+	assert.Equal(t, "initial", got)
+
+	p = New(os.Stdin, os.Stdout, os.Stderr)
+	p.isInteractive = func() bool { return false }
+	_, err = p.Editor("body.md", "initial")
+	assert.ErrorIs(t, err, ErrNonInteractive)
+}
+
+func TestResolveEditor(t *testing.T) {
+	defaultEditor := "nano"
+	if runtime.GOOS == "windows" {
+		defaultEditor = "notepad"
+	}
+	tests := []struct {
+		name       string
+		env        map[string]string
+		config     *config.Config
+		wantEditor string
+	}{
+		{
+			name:       "GOCTL_EDITOR set",
+			env:        map[string]string{"GOCTL_EDITOR": "GOCTL_EDITOR", "VISUAL": "VISUAL"},
+			config:     config.ReadFromString("editor: CONFIG_EDITOR"),
+			wantEditor: "GOCTL_EDITOR",
+		},
+		{
+			name:       "config editor set",
+			env:        map[string]string{"VISUAL": "VISUAL", "EDITOR": "EDITOR"},
+			config:     config.ReadFromString("editor: CONFIG_EDITOR"),
+			wantEditor: "CONFIG_EDITOR",
+		},
+		{
+			name:       "VISUAL set",
+			env:        map[string]string{"VISUAL": "VISUAL", "EDITOR": "EDITOR"},
+			wantEditor: "VISUAL",
+		},
+		{
+			name:       "EDITOR set",
+			env:        map[string]string{"EDITOR": "EDITOR"},
+			wantEditor: "EDITOR",
+		},
+		{
+			name:       "nothing set",
+			wantEditor: defaultEditor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range []string{"GOCTL_EDITOR", "VISUAL", "EDITOR"} {
+				t.Setenv(k, tt.env[k])
+			}
+			old := config.Read
+			config.Read = func(_ *config.Config) (*config.Config, error) {
+				if tt.config == nil {
+					return config.ReadFromString(""), nil
+				}
+				return tt.config, nil
+			}
+			defer func() { config.Read = old }()
+			assert.Equal(t, tt.wantEditor, resolveEditor())
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package prompter
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/logging"
+)
+
+// WithHistory returns a shallow copy of the Prompter that remembers answers under key
+// in the "prompt_history" section of the configuration file, and offers the answer
+// remembered last time as the default, in place of the default given to the prompt.
+// It is meant to be used for a single prompt, for example:
+//
+//	org, err := p.WithHistory("create.org").Select("Organization", "", orgs)
+//
+// Answers to Select, MultiSelect, SearchSelect, Input, and Confirm prompts are remembered.
+// Answers to Password and Editor prompts are never remembered. A remembered answer that
+// is no longer one of the options of a Select or MultiSelect prompt is ignored.
+func (p *Prompter) WithHistory(key string) *Prompter {
+	p2 := *p
+	p2.historyKey = key
+	return &p2
+}
+
+// recall returns the answer remembered for the history key of the Prompter, if any.
+func (p *Prompter) recall() (string, bool) {
+	if p.historyKey == "" {
+		return "", false
+	}
+	cfg, err := config.Read(nil)
+	if err != nil {
+		return "", false
+	}
+	value, err := cfg.Get([]string{"prompt_history", p.historyKey})
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// remember saves the answer under the history key of the Prompter. Failing to save the
+// answer does not fail the prompt, as the user has answered it successfully.
+func (p *Prompter) remember(value string) {
+	if p.historyKey == "" {
+		return
+	}
+	cfg, err := config.Read(nil)
+	if err == nil {
+		cfg.Set([]string{"prompt_history", p.historyKey}, value)
+		err = config.Write(cfg)
+	}
+	if err != nil {
+		logging.Logger().Debug("failed to save prompt history", "key", p.historyKey, "error", err)
+	}
+}
+
+// recallList returns the list of answers remembered for a MultiSelect prompt.
+func (p *Prompter) recallList() ([]string, bool) {
+	value, ok := p.recall()
+	if !ok {
+		return nil, false
+	}
+	if value == "" {
+		return []string{}, true
+	}
+	return strings.Split(value, "\n"), true
+}
+
+func (p *Prompter) rememberList(values []string) {
+	p.remember(strings.Join(values, "\n"))
+}
+
+// recallBool returns the answer remembered for a Confirm prompt.
+func (p *Prompter) recallBool() (bool, bool) {
+	value, ok := p.recall()
+	if !ok {
+		return false, false
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, false
+	}
+	return b, true
+}
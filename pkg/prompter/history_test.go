@@ -0,0 +1,80 @@
+package prompter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func stubConfig(t *testing.T, cfgStr string) *config.Config {
+	t.Helper()
+	t.Setenv("GOCTL_CONFIG_DIR", t.TempDir())
+	cfg := config.ReadFromString(cfgStr)
+	oldRead := config.Read
+	t.Cleanup(func() { config.Read = oldRead })
+	config.Read = func(*config.Config) (*config.Config, error) {
+		return cfg, nil
+	}
+	return cfg
+}
+
+func TestWithHistory(t *testing.T) {
+	cfg := stubConfig(t, "")
+	p, _ := newAccessiblePrompter("2\n\nmonalisa\n\nn\n\n1,3\n\n")
+
+	// Answers are remembered and offered as defaults next time.
+	i, err := p.WithHistory("fruit").Select("Fruit", "apple", []string{"apple", "banana"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+	i, err = p.WithHistory("fruit").Select("Fruit", "apple", []string{"apple", "banana"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+
+	name, err := p.WithHistory("name").Input("Name", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "monalisa", name)
+	name, err = p.WithHistory("name").Input("Name", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "monalisa", name)
+
+	b, err := p.WithHistory("clone").Confirm("Clone", true)
+	assert.NoError(t, err)
+	assert.False(t, b)
+	b, err = p.WithHistory("clone").Confirm("Clone", true)
+	assert.NoError(t, err)
+	assert.False(t, b)
+
+	is, err := p.WithHistory("fruits").MultiSelect("Fruits", nil, []string{"apple", "banana", "cherry"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, is)
+	is, err = p.WithHistory("fruits").MultiSelect("Fruits", nil, []string{"apple", "banana", "cherry"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, is)
+
+	fruit, _ := cfg.Get([]string{"prompt_history", "fruit"})
+	assert.Equal(t, "banana", fruit)
+	data, err := os.ReadFile(filepath.Join(config.ConfigDir(), "config.yml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "prompt_history:")
+}
+
+func TestWithHistoryIgnoresStaleAnswer(t *testing.T) {
+	_ = stubConfig(t, "prompt_history:\n    fruit: durian\n")
+	p, out := newAccessiblePrompter("\n")
+	i, err := p.WithHistory("fruit").Select("Fruit", "banana", []string{"apple", "banana"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+	assert.Contains(t, out.String(), "(default: 2)")
+}
+
+func TestWithoutHistory(t *testing.T) {
+	cfg := stubConfig(t, "")
+	p, _ := newAccessiblePrompter("monalisa\n")
+	_, err := p.Input("Name", "")
+	assert.NoError(t, err)
+	_, err = cfg.Get([]string{"prompt_history"})
+	assert.Error(t, err)
+}
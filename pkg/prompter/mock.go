@@ -47,9 +47,11 @@ type PrompterMock struct {
 	t                *testing.T
 	selectStubs      []selectStub
 	multiSelectStubs []multiSelectStub
+	searchStubs      []searchSelectStub
 	inputStubs       []inputStub
 	passwordStubs    []passwordStub
 	confirmStubs     []confirmStub
+	editorStubs      []editorStub
 }
 
 type selectStub struct {
@@ -64,6 +66,11 @@ type multiSelectStub struct {
 	fn              func(string, []string, []string) ([]int, error)
 }
 
+type searchSelectStub struct {
+	prompt string
+	fn     func(string, func(string) ([]string, error)) (string, error)
+}
+
 type inputStub struct {
 	prompt string
 	fn     func(string, string) (string, error)
@@ -79,15 +86,22 @@ type confirmStub struct {
 	Fn     func(string, bool) (bool, error)
 }
 
+type editorStub struct {
+	filename string
+	fn       func(string, string) (string, error)
+}
+
 // NewMock instantiates a new PrompterMock.
 func NewMock(t *testing.T) *PrompterMock {
 	m := &PrompterMock{
 		t:                t,
 		selectStubs:      []selectStub{},
 		multiSelectStubs: []multiSelectStub{},
+		searchStubs:      []searchSelectStub{},
 		inputStubs:       []inputStub{},
 		passwordStubs:    []passwordStub{},
 		confirmStubs:     []confirmStub{},
+		editorStubs:      []editorStub{},
 	}
 	t.Cleanup(m.verify)
 	return m
@@ -123,6 +137,20 @@ func (m *PrompterMock) MultiSelect(prompt string, defaultValues, options []strin
 	return s.fn(prompt, defaultValues, options)
 }
 
+// SearchSelect prompts the user to select an option from a list that is loaded lazily.
+func (m *PrompterMock) SearchSelect(prompt string, load func(string) ([]string, error)) (string, error) {
+	var s searchSelectStub
+	if len(m.searchStubs) == 0 {
+		return "", noSuchPromptErr(prompt)
+	}
+	s = m.searchStubs[0]
+	m.searchStubs = m.searchStubs[1:len(m.searchStubs)]
+	if s.prompt != prompt {
+		return "", noSuchPromptErr(prompt)
+	}
+	return s.fn(prompt, load)
+}
+
 // Input prompts the user to input a single-line string.
 func (m *PrompterMock) Input(prompt, defaultValue string) (string, error) {
 	var s inputStub
@@ -137,6 +165,11 @@ func (m *PrompterMock) Input(prompt, defaultValue string) (string, error) {
 	return s.fn(prompt, defaultValue)
 }
 
+// InputWithValidation is like Input. Validators are ignored.
+func (m *PrompterMock) InputWithValidation(prompt, defaultValue string, _ ...Validator) (string, error) {
+	return m.Input(prompt, defaultValue)
+}
+
 // Password prompts the user to input a single-line string without echoing the input.
 func (m *PrompterMock) Password(prompt string) (string, error) {
 	var s passwordStub
@@ -151,6 +184,11 @@ func (m *PrompterMock) Password(prompt string) (string, error) {
 	return s.fn(prompt)
 }
 
+// PasswordWithValidation is like Password. Validators are ignored.
+func (m *PrompterMock) PasswordWithValidation(prompt string, _ ...Validator) (string, error) {
+	return m.Password(prompt)
+}
+
 // Confirm prompts the user to confirm a yes/no question.
 func (m *PrompterMock) Confirm(prompt string, defaultValue bool) (bool, error) {
 	var s confirmStub
@@ -165,6 +203,20 @@ func (m *PrompterMock) Confirm(prompt string, defaultValue bool) (bool, error) {
 	return s.Fn(prompt, defaultValue)
 }
 
+// Editor opens the user's preferred editor on a file with the given contents.
+func (m *PrompterMock) Editor(filename, initialContents string) (string, error) {
+	var s editorStub
+	if len(m.editorStubs) == 0 {
+		return "", noSuchPromptErr(filename)
+	}
+	s = m.editorStubs[0]
+	m.editorStubs = m.editorStubs[1:len(m.editorStubs)]
+	if s.filename != filename {
+		return "", noSuchPromptErr(filename)
+	}
+	return s.fn(filename, initialContents)
+}
+
 // RegisterSelect records that a Select prompt should be called.
 func (m *PrompterMock) RegisterSelect(prompt string, opts []string, stub func(_, _ string, _ []string) (int, error)) {
 	m.selectStubs = append(m.selectStubs, selectStub{
@@ -181,6 +233,11 @@ func (m *PrompterMock) RegisterMultiSelect(prompt string, d, opts []string, stub
 		fn:              stub})
 }
 
+// RegisterSearchSelect records that a SearchSelect prompt should be called.
+func (m *PrompterMock) RegisterSearchSelect(prompt string, stub func(string, func(string) ([]string, error)) (string, error)) {
+	m.searchStubs = append(m.searchStubs, searchSelectStub{prompt: prompt, fn: stub})
+}
+
 // RegisterInput records that an Input prompt should be called.
 func (m *PrompterMock) RegisterInput(prompt string, stub func(_, _ string) (string, error)) {
 	m.inputStubs = append(m.inputStubs, inputStub{prompt: prompt, fn: stub})
@@ -196,6 +253,11 @@ func (m *PrompterMock) RegisterConfirm(prompt string, stub func(_ string, _ bool
 	m.confirmStubs = append(m.confirmStubs, confirmStub{Prompt: prompt, Fn: stub})
 }
 
+// RegisterEditor records that an Editor prompt should be called for the given file name.
+func (m *PrompterMock) RegisterEditor(filename string, stub func(_, _ string) (string, error)) {
+	m.editorStubs = append(m.editorStubs, editorStub{filename: filename, fn: stub})
+}
+
 func (m *PrompterMock) verify() {
 	errs := []string{}
 	if len(m.selectStubs) > 0 {
@@ -204,6 +266,9 @@ func (m *PrompterMock) verify() {
 	if len(m.multiSelectStubs) > 0 {
 		errs = append(errs, "Select")
 	}
+	if len(m.searchStubs) > 0 {
+		errs = append(errs, "SearchSelect")
+	}
 	if len(m.inputStubs) > 0 {
 		errs = append(errs, "Input")
 	}
@@ -213,6 +278,9 @@ func (m *PrompterMock) verify() {
 	if len(m.confirmStubs) > 0 {
 		errs = append(errs, "Confirm")
 	}
+	if len(m.editorStubs) > 0 {
+		errs = append(errs, "Editor")
+	}
 	if len(errs) > 0 {
 		m.t.Helper()
 		m.t.Errorf("%d unmatched calls to %s", len(errs), strings.Join(errs, ","))
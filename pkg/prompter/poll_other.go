@@ -0,0 +1,17 @@
+//go:build !unix
+
+package prompter
+
+import "io"
+
+// waitReadable reports r as readable right away, as input cannot be polled on this
+// platform without a read.
+func waitReadable(r io.Reader, stop <-chan struct{}) bool {
+	return true
+}
+
+// canStop reports whether a background read of r can be stopped, which it cannot on this
+// platform once it blocks in Read.
+func canStop(r io.Reader) bool {
+	return false
+}
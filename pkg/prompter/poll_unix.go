@@ -0,0 +1,46 @@
+//go:build unix
+
+package prompter
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// pollInterval is how often, in milliseconds, a background read checks whether it has
+// been stopped while it waits for input.
+const pollInterval = 50
+
+// waitReadable waits until r has input to read, so that a background read does not block
+// on the terminal where it cannot be stopped. It returns false if stop is closed first.
+// Readers other than files are not polled and are reported as readable right away.
+func waitReadable(r io.Reader, stop <-chan struct{}) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return true
+	}
+	fds := []unix.PollFd{{Fd: int32(f.Fd()), Events: unix.POLLIN}}
+	for {
+		select {
+		case <-stop:
+			return false
+		default:
+		}
+		n, err := unix.Poll(fds, pollInterval)
+		if errors.Is(err, unix.EINTR) || (err == nil && n == 0) {
+			continue
+		}
+		// Errors and hang-ups are left for Read to report.
+		return true
+	}
+}
+
+// canStop reports whether a background read of r that waits with waitReadable returns
+// once it is stopped.
+func canStop(r io.Reader) bool {
+	_, ok := r.(*os.File)
+	return ok
+}
@@ -3,19 +3,62 @@
 package prompter
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/i18n"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/term"
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/text"
+	xterm "golang.org/x/term"
+)
+
+// ErrNonInteractive is returned by prompts that cannot be answered because
+// the Prompter is not connected to a terminal or prompting has been disabled.
+var ErrNonInteractive = errors.New("cannot prompt in a non-interactive context")
+
+// NonInteractivePolicy determines how prompts behave when they cannot be shown.
+type NonInteractivePolicy int
+
+const (
+	// ReturnError makes prompts fail with ErrNonInteractive. This is the default policy.
+	ReturnError NonInteractivePolicy = iota
+	// UseDefault makes prompts answer with their default value. Prompts without
+	// a usable default, such as Password, still fail with ErrNonInteractive.
+	UseDefault
 )
 
 // Prompter provides methods for prompting the user.
 type Prompter struct {
-	stdin  FileReader
-	stdout FileWriter
-	stderr FileWriter
+	stdin          FileReader
+	stdout         FileWriter
+	stderr         FileWriter
+	policy         NonInteractivePolicy
+	filter         func(filter, value string, index int) bool
+	isInteractive  func() bool
+	promptDisabled func() bool
+	accessible     func() bool
+	theme          *Theme
+	ctx            context.Context
+	async          *asyncReader
+	historyKey     string
+}
+
+type option func(*Prompter)
+
+// WithNonInteractivePolicy sets how prompts behave when the Prompter is not connected
+// to a terminal, or when prompting has been disabled with the GOCTL_PROMPT_DISABLED
+// environment variable or the "prompt: disabled" configuration setting.
+func WithNonInteractivePolicy(policy NonInteractivePolicy) option {
+	return func(p *Prompter) {
+		p.policy = policy
+	}
 }
 
 // FileWriter provides a minimal writable interface for stdout and stderr.
@@ -30,98 +73,311 @@ type FileReader interface {
 	Fd() uintptr
 }
 
+// WithFuzzyFilter makes the type-ahead filter of Select, MultiSelect, and SearchSelect
+// prompts match options that contain the typed characters in order, though not
+// necessarily adjacent, so that "gcl" matches "go-goctl-cli". This is useful for long
+// lists of options such as repositories or branches.
+func WithFuzzyFilter() option {
+	return func(p *Prompter) {
+		p.filter = fuzzyMatchingFilter
+	}
+}
+
 // New instantiates a new Prompter.
-func New(stdin FileReader, stdout FileWriter, stderr FileWriter) *Prompter {
-	return &Prompter{
-		stdin:  stdin,
-		stdout: stdout,
-		stderr: stderr,
+func New(stdin FileReader, stdout FileWriter, stderr FileWriter, opts ...option) *Prompter {
+	p := &Prompter{
+		stdin:          stdin,
+		stdout:         stdout,
+		stderr:         stderr,
+		filter:         latinMatchingFilter,
+		promptDisabled: promptDisabled,
+		accessible:     accessibleEnabled,
 	}
+	p.isInteractive = func() bool {
+		return xterm.IsTerminal(int(p.stdin.Fd())) && xterm.IsTerminal(int(p.stdout.Fd()))
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// FromTerm instantiates a new Prompter connected to the standard streams of the given terminal,
+// such as one obtained from [term.FromEnv].
+func FromTerm(t term.Term, opts ...option) (*Prompter, error) {
+	stdin, ok := t.In().(*os.File)
+	if !ok || stdin == nil {
+		return nil, errors.New("standard input is not a file")
+	}
+	stdout, ok := t.Out().(*os.File)
+	if !ok || stdout == nil {
+		return nil, errors.New("standard output is not a file")
+	}
+	stderr, ok := t.ErrOut().(*os.File)
+	if !ok || stderr == nil {
+		return nil, errors.New("standard error is not a file")
+	}
+	return New(stdin, stdout, stderr, opts...), nil
 }
 
 // Select prompts the user to select an option from a list of options.
 func (p *Prompter) Select(prompt, defaultValue string, options []string) (int, error) {
+	if remembered, ok := p.recall(); ok && containsString(options, remembered) {
+		defaultValue = remembered
+	}
 	var result int
 	q := &survey.Select{
 		Message:  prompt,
 		Options:  options,
 		PageSize: 20,
-		Filter:   latinMatchingFilter,
+		Filter:   p.filter,
 	}
+	defaultIndex := -1
 	if defaultValue != "" {
-		for _, o := range options {
+		for i, o := range options {
 			if o == defaultValue {
 				q.Default = defaultValue
+				defaultIndex = i
 				break
 			}
 		}
 	}
-	err := p.ask(q, &result)
+	if !p.interactive() {
+		if p.policy == UseDefault && defaultIndex >= 0 {
+			return defaultIndex, nil
+		}
+		return 0, ErrNonInteractive
+	}
+	var err error
+	if p.accessible() {
+		result, err = p.accessibleSelect(prompt, defaultIndex, options)
+	} else {
+		err = p.ask(q, &result)
+	}
+	if err == nil {
+		p.remember(options[result])
+	}
 	return result, err
 }
 
 // MultiSelect prompts the user to select multiple options from a list of options.
 func (p *Prompter) MultiSelect(prompt string, defaultValues, options []string) ([]int, error) {
+	if remembered, ok := p.recallList(); ok {
+		defaultValues = remembered
+	}
 	var result []int
 	q := &survey.MultiSelect{
 		Message:  prompt,
 		Options:  options,
 		PageSize: 20,
-		Filter:   latinMatchingFilter,
+		Filter:   p.filter,
 	}
+	defaultIndexes := []int{}
 	if len(defaultValues) > 0 {
 		validatedDefault := []string{}
 		for _, x := range defaultValues {
-			for _, y := range options {
+			for i, y := range options {
 				if x == y {
 					validatedDefault = append(validatedDefault, x)
+					defaultIndexes = append(defaultIndexes, i)
 				}
 			}
 		}
 		q.Default = validatedDefault
 	}
-	err := p.ask(q, &result)
+	if !p.interactive() {
+		if p.policy == UseDefault {
+			return defaultIndexes, nil
+		}
+		return nil, ErrNonInteractive
+	}
+	var err error
+	if p.accessible() {
+		result, err = p.accessibleMultiSelect(prompt, defaultIndexes, options)
+	} else {
+		err = p.ask(q, &result)
+	}
+	if err == nil {
+		selected := make([]string, len(result))
+		for i, r := range result {
+			selected[i] = options[r]
+		}
+		p.rememberList(selected)
+	}
 	return result, err
 }
 
+// searchAgainOption is appended to the options of a SearchSelect prompt to let the
+// user change the search query.
+const searchAgainOption = "Search again"
+
+// SearchSelect prompts the user to select an option from a list that is loaded lazily.
+// The load function is called with an empty query to fetch the initial options, and
+// again with each query the user enters after choosing to search again, so options
+// can be fetched from an API as the user narrows down the list.
+func (p *Prompter) SearchSelect(prompt string, load func(query string) ([]string, error)) (string, error) {
+	if !p.interactive() {
+		return "", ErrNonInteractive
+	}
+	remembered, _ := p.recall()
+	inner := *p
+	inner.historyKey = ""
+	query := ""
+	for {
+		options, err := load(query)
+		if err != nil {
+			return "", err
+		}
+		choices := make([]string, 0, len(options)+1)
+		choices = append(choices, options...)
+		choices = append(choices, i18n.Sprintf(searchAgainOption))
+		selected, err := inner.Select(prompt, remembered, choices)
+		if err != nil {
+			return "", err
+		}
+		if selected < len(options) {
+			p.remember(options[selected])
+			return options[selected], nil
+		}
+		query, err = inner.Input(i18n.Sprintf("Search"), query)
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
 // Input prompts the user to input a single-line string.
 func (p *Prompter) Input(prompt, defaultValue string) (string, error) {
+	return p.InputWithValidation(prompt, defaultValue)
+}
+
+// InputWithValidation is like Input, but answers rejected by any of the validators are
+// reported inline and the user is asked again.
+func (p *Prompter) InputWithValidation(prompt, defaultValue string, validators ...Validator) (string, error) {
+	if remembered, ok := p.recall(); ok {
+		defaultValue = remembered
+	}
+	if !p.interactive() {
+		if p.policy == UseDefault {
+			if err := validate(defaultValue, validators); err != nil {
+				return "", fmt.Errorf("invalid default value: %w", err)
+			}
+			return defaultValue, nil
+		}
+		return "", ErrNonInteractive
+	}
 	var result string
-	err := p.ask(&survey.Input{
-		Message: prompt,
-		Default: defaultValue,
-	}, &result)
+	var err error
+	if p.accessible() {
+		result, err = p.accessibleInput(prompt, defaultValue, validators)
+	} else {
+		err = p.ask(&survey.Input{
+			Message: prompt,
+			Default: defaultValue,
+		}, &result, validatorOpts(validators)...)
+	}
+	if err == nil {
+		p.remember(result)
+	}
 	return result, err
 }
 
 // Password prompts the user to input a single-line string without echoing the input.
+// Passwords have no default, so it always fails with ErrNonInteractive when the prompt
+// cannot be shown.
 func (p *Prompter) Password(prompt string) (string, error) {
+	return p.PasswordWithValidation(prompt)
+}
+
+// PasswordWithValidation is like Password, but answers rejected by any of the validators
+// are reported inline and the user is asked again.
+func (p *Prompter) PasswordWithValidation(prompt string, validators ...Validator) (string, error) {
+	if !p.interactive() {
+		return "", ErrNonInteractive
+	}
+	if p.accessible() {
+		return p.accessiblePassword(prompt, validators)
+	}
 	var result string
 	err := p.ask(&survey.Password{
 		Message: prompt,
-	}, &result)
+	}, &result, validatorOpts(validators)...)
 	return result, err
 }
 
 // Confirm prompts the user to confirm a yes/no question.
 func (p *Prompter) Confirm(prompt string, defaultValue bool) (bool, error) {
+	if remembered, ok := p.recallBool(); ok {
+		defaultValue = remembered
+	}
+	if !p.interactive() {
+		if p.policy == UseDefault {
+			return defaultValue, nil
+		}
+		return false, ErrNonInteractive
+	}
 	var result bool
-	err := p.ask(&survey.Confirm{
-		Message: prompt,
-		Default: defaultValue,
-	}, &result)
+	var err error
+	if p.accessible() {
+		result, err = p.accessibleConfirm(prompt, defaultValue)
+	} else {
+		err = p.ask(&survey.Confirm{
+			Message: prompt,
+			Default: defaultValue,
+		}, &result)
+	}
+	if err == nil {
+		p.remember(strconv.FormatBool(result))
+	}
 	return result, err
 }
 
 func (p *Prompter) ask(q survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+	if err := p.cancelled(); err != nil {
+		return err
+	}
 	opts = append(opts, survey.WithStdio(p.stdin, p.stdout, p.stderr))
+	if p.theme != nil {
+		opts = append(opts, p.theme.askOpt())
+	}
 	err := survey.AskOne(q, response, opts...)
 	if err == nil {
 		return nil
 	}
+	if err := p.cancelled(); err != nil {
+		return err
+	}
 	return fmt.Errorf("could not prompt: %w", err)
 }
 
+// interactive reports whether prompts can be shown to the user.
+func (p *Prompter) interactive() bool {
+	return !p.promptDisabled() && p.isInteractive()
+}
+
+// promptDisabled reports whether prompting has been disabled by the user, either with
+// the GOCTL_PROMPT_DISABLED environment variable or the "prompt" configuration setting.
+func promptDisabled() bool {
+	if v, ok := os.LookupEnv("GOCTL_PROMPT_DISABLED"); ok {
+		return v != "" && v != "0" && v != "false"
+	}
+	cfg, err := config.Read(nil)
+	if err != nil {
+		return false
+	}
+	v, _ := cfg.Get([]string{"prompt"})
+	return v == "disabled"
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 // latinMatchingFilter returns whether the value matches the input filter.
 // The strings are compared normalized in case.
 // The filter's diactritics are kept as-is, but the value's are normalized,
@@ -132,3 +388,28 @@ func latinMatchingFilter(filter, value string, index int) bool {
 	// include this option if it matches.
 	return strings.Contains(value, filter) || strings.Contains(text.RemoveDiacritics(value), filter)
 }
+
+// fuzzyMatchingFilter returns whether every character of the filter appears in the
+// value in the same order. Like latinMatchingFilter, the strings are compared
+// normalized in case and the value's diacritics are normalized.
+func fuzzyMatchingFilter(filter, value string, index int) bool {
+	filter = strings.ToLower(filter)
+	value = strings.ToLower(value)
+	return isSubsequence(filter, value) || isSubsequence(filter, text.RemoveDiacritics(value))
+}
+
+func isSubsequence(sub, s string) bool {
+	rs := []rune(sub)
+	if len(rs) == 0 {
+		return true
+	}
+	for _, r := range s {
+		if r == rs[0] {
+			rs = rs[1:]
+			if len(rs) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -6,25 +6,16 @@ import (
 	"os"
 	"testing"
 
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/term"
 	"github.com/stretchr/testify/assert"
 )
 
 func ExamplePrompter() {
-	term := term.FromEnv()
-	in, ok := term.In().(*os.File)
-	if !ok {
-		log.Fatal("error casting to file")
-	}
-	out, ok := term.Out().(*os.File)
-	if !ok {
-		log.Fatal("error casting to file")
-	}
-	errOut, ok := term.ErrOut().(*os.File)
-	if !ok {
-		log.Fatal("error casting to file")
+	prompter, err := FromTerm(term.FromEnv())
+	if err != nil {
+		log.Fatal(err)
 	}
-	prompter := New(in, out, errOut)
 	response, err := prompter.Confirm("Shall we play a game", true)
 	if err != nil {
 		log.Fatal(err)
@@ -32,6 +23,179 @@ func ExamplePrompter() {
 	fmt.Println(response)
 }
 
+func TestFromTerm(t *testing.T) {
+	p, err := FromTerm(term.FromEnv())
+	assert.NoError(t, err)
+	assert.Equal(t, os.Stdin, p.stdin)
+	assert.Equal(t, os.Stdout, p.stdout)
+	assert.Equal(t, os.Stderr, p.stderr)
+
+	_, err = FromTerm(term.Term{})
+	assert.EqualError(t, err, "standard input is not a file")
+}
+
+func TestNonInteractive(t *testing.T) {
+	options := []string{"apple", "banana", "cherry"}
+
+	p := New(os.Stdin, os.Stdout, os.Stderr, WithNonInteractivePolicy(UseDefault))
+	p.isInteractive = func() bool { return false }
+
+	i, err := p.Select("Fruit", "banana", options)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+
+	_, err = p.Select("Fruit", "durian", options)
+	assert.ErrorIs(t, err, ErrNonInteractive)
+
+	is, err := p.MultiSelect("Fruits", []string{"apple", "cherry"}, options)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, is)
+
+	s, err := p.Input("Name", "monalisa")
+	assert.NoError(t, err)
+	assert.Equal(t, "monalisa", s)
+
+	b, err := p.Confirm("Continue", true)
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	_, err = p.Password("Token")
+	assert.ErrorIs(t, err, ErrNonInteractive)
+
+	p = New(os.Stdin, os.Stdout, os.Stderr)
+	p.isInteractive = func() bool { return false }
+
+	_, err = p.Select("Fruit", "banana", options)
+	assert.ErrorIs(t, err, ErrNonInteractive)
+	_, err = p.MultiSelect("Fruits", nil, options)
+	assert.ErrorIs(t, err, ErrNonInteractive)
+	_, err = p.Input("Name", "monalisa")
+	assert.ErrorIs(t, err, ErrNonInteractive)
+	_, err = p.Confirm("Continue", true)
+	assert.ErrorIs(t, err, ErrNonInteractive)
+}
+
+func TestPromptDisabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    string
+		setEnv bool
+		config string
+		want   bool
+	}{
+		{
+			name: "enabled by default",
+			want: false,
+		},
+		{
+			name:   "disabled by env",
+			env:    "1",
+			setEnv: true,
+			want:   true,
+		},
+		{
+			name:   "env false overrides config",
+			env:    "false",
+			setEnv: true,
+			config: "prompt: disabled\n",
+			want:   false,
+		},
+		{
+			name:   "disabled by config",
+			config: "prompt: disabled\n",
+			want:   true,
+		},
+		{
+			name:   "enabled by config",
+			config: "prompt: enabled\n",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setEnv {
+				t.Setenv("GOCTL_PROMPT_DISABLED", tt.env)
+			} else {
+				t.Setenv("GOCTL_PROMPT_DISABLED", "")
+				os.Unsetenv("GOCTL_PROMPT_DISABLED")
+			}
+			oldRead := config.Read
+			t.Cleanup(func() { config.Read = oldRead })
+			config.Read = func(*config.Config) (*config.Config, error) {
+				return config.ReadFromString(tt.config), nil
+			}
+			assert.Equal(t, tt.want, promptDisabled())
+		})
+	}
+}
+
+func TestSearchSelectNonInteractive(t *testing.T) {
+	p := New(os.Stdin, os.Stdout, os.Stderr, WithNonInteractivePolicy(UseDefault))
+	p.isInteractive = func() bool { return false }
+	_, err := p.SearchSelect("Repository", func(string) ([]string, error) {
+		t.Error("unexpected call to load")
+		return nil, nil
+	})
+	assert.ErrorIs(t, err, ErrNonInteractive)
+}
+
+func TestFuzzyMatchingFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		value  string
+		want   bool
+	}{
+		{
+			name:   "empty filter",
+			filter: "",
+			value:  "go-goctl",
+			want:   true,
+		},
+		{
+			name:   "exact match",
+			filter: "go-goctl",
+			value:  "go-goctl",
+			want:   true,
+		},
+		{
+			name:   "subsequence match",
+			filter: "ggctl",
+			value:  "go-goctl",
+			want:   true,
+		},
+		{
+			name:   "case insensitive",
+			filter: "GGC",
+			value:  "go-goctl",
+			want:   true,
+		},
+		{
+			name:   "diacritics in value",
+			filter: "mkls",
+			value:  "Miķelis",
+			want:   true,
+		},
+		{
+			name:   "out of order",
+			filter: "ltcg",
+			value:  "go-goctl",
+			want:   false,
+		},
+		{
+			name:   "missing character",
+			filter: "gox",
+			value:  "go-goctl",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fuzzyMatchingFilter(tt.filter, tt.value, 0))
+		})
+	}
+}
+
 func TestLatinMatchingFilter(t *testing.T) {
 	tests := []struct {
 		name   string
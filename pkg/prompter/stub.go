@@ -0,0 +1,251 @@
+package prompter
+
+import (
+	"fmt"
+	"testing"
+)
+
+// PrompterStub answers prompts with a script of answers given up front, in order,
+// regardless of the kind of prompt. Unlike PrompterMock, which matches stubs per kind
+// of prompt, PrompterStub verifies the exact sequence of an interactive flow. Every
+// prompt is recorded so that tests can make assertions about the prompts shown, and
+// any prompt that does not match the next answer in the script fails the test.
+// PrompterStub has the same methods as Prompter so they both can satisfy the same
+// interface.
+//
+// A basic example of how PrompterStub can be used:
+//
+//	func TestCreateRepo(t *testing.T) {
+//		stub := NewStub(t,
+//			AnswerInput("Repository name", "hello-world"),
+//			AnswerSelect("Visibility", "Private"),
+//			AnswerConfirm("Create repository", true),
+//		)
+//		err := CreateRepo(stub)
+//		if err != nil {
+//			t.Fatalf("unexpected error: %v", err)
+//		}
+//	}
+type PrompterStub struct {
+	t           *testing.T
+	answers     []Answer
+	invocations []Invocation
+}
+
+// Answer is a scripted answer to a single prompt, created with one of the
+// AnswerX functions.
+type Answer struct {
+	method string
+	prompt string
+	value  interface{}
+	err    error
+}
+
+// Invocation records a prompt shown through a PrompterStub.
+type Invocation struct {
+	// Method is the name of the Prompter method that was called, such as "Select".
+	Method string
+	// Prompt is the prompt message, or the file name for Editor.
+	Prompt string
+	// Default is the default value passed to the prompt, if any.
+	Default interface{}
+	// Options are the options passed to Select and MultiSelect prompts.
+	Options []string
+}
+
+// AnswerSelect scripts the answer to a Select prompt by the value of the selected option.
+func AnswerSelect(prompt, option string) Answer {
+	return Answer{method: "Select", prompt: prompt, value: option}
+}
+
+// AnswerMultiSelect scripts the answer to a MultiSelect prompt by the values of the selected options.
+func AnswerMultiSelect(prompt string, options ...string) Answer {
+	return Answer{method: "MultiSelect", prompt: prompt, value: options}
+}
+
+// AnswerSearchSelect scripts the answer to a SearchSelect prompt. The load function
+// passed to the prompt is not called.
+func AnswerSearchSelect(prompt, option string) Answer {
+	return Answer{method: "SearchSelect", prompt: prompt, value: option}
+}
+
+// AnswerInput scripts the answer to an Input prompt.
+func AnswerInput(prompt, value string) Answer {
+	return Answer{method: "Input", prompt: prompt, value: value}
+}
+
+// AnswerPassword scripts the answer to a Password prompt.
+func AnswerPassword(prompt, value string) Answer {
+	return Answer{method: "Password", prompt: prompt, value: value}
+}
+
+// AnswerConfirm scripts the answer to a Confirm prompt.
+func AnswerConfirm(prompt string, value bool) Answer {
+	return Answer{method: "Confirm", prompt: prompt, value: value}
+}
+
+// AnswerEditor scripts the contents returned by an Editor prompt for the given file name.
+func AnswerEditor(filename, contents string) Answer {
+	return Answer{method: "Editor", prompt: filename, value: contents}
+}
+
+// WithError makes the prompt return err instead of an answer, for example to
+// simulate the user interrupting the prompt.
+func (a Answer) WithError(err error) Answer {
+	a.err = err
+	return a
+}
+
+// NewStub instantiates a new PrompterStub that answers prompts with the given answers in order.
+// The test fails if any answers remain unused when it completes.
+func NewStub(t *testing.T, answers ...Answer) *PrompterStub {
+	s := &PrompterStub{t: t, answers: answers}
+	t.Cleanup(s.verify)
+	return s
+}
+
+// Invocations returns every prompt shown so far, in order.
+func (s *PrompterStub) Invocations() []Invocation {
+	return s.invocations
+}
+
+// Select prompts the user to select an option from a list of options.
+func (s *PrompterStub) Select(prompt, defaultValue string, options []string) (int, error) {
+	a, err := s.next(Invocation{Method: "Select", Prompt: prompt, Default: defaultValue, Options: options})
+	if err != nil {
+		return -1, err
+	}
+	option := a.value.(string)
+	for i, o := range options {
+		if o == option {
+			return i, nil
+		}
+	}
+	s.t.Helper()
+	s.t.Errorf("scripted answer %q to Select prompt %q is not one of the options %q", option, prompt, options)
+	return -1, fmt.Errorf("no such option %q", option)
+}
+
+// MultiSelect prompts the user to select multiple options from a list of options.
+func (s *PrompterStub) MultiSelect(prompt string, defaultValues, options []string) ([]int, error) {
+	a, err := s.next(Invocation{Method: "MultiSelect", Prompt: prompt, Default: defaultValues, Options: options})
+	if err != nil {
+		return []int{}, err
+	}
+	result := []int{}
+outer:
+	for _, option := range a.value.([]string) {
+		for i, o := range options {
+			if o == option {
+				result = append(result, i)
+				continue outer
+			}
+		}
+		s.t.Helper()
+		s.t.Errorf("scripted answer %q to MultiSelect prompt %q is not one of the options %q", option, prompt, options)
+		return []int{}, fmt.Errorf("no such option %q", option)
+	}
+	return result, nil
+}
+
+// SearchSelect prompts the user to select an option from a list that is loaded lazily.
+func (s *PrompterStub) SearchSelect(prompt string, load func(string) ([]string, error)) (string, error) {
+	a, err := s.next(Invocation{Method: "SearchSelect", Prompt: prompt})
+	if err != nil {
+		return "", err
+	}
+	return a.value.(string), nil
+}
+
+// Input prompts the user to input a single-line string.
+func (s *PrompterStub) Input(prompt, defaultValue string) (string, error) {
+	s.t.Helper()
+	return s.InputWithValidation(prompt, defaultValue)
+}
+
+// InputWithValidation is like Input. The test fails if the scripted answer is rejected
+// by any of the validators. It is answered by AnswerInput.
+func (s *PrompterStub) InputWithValidation(prompt, defaultValue string, validators ...Validator) (string, error) {
+	a, err := s.next(Invocation{Method: "Input", Prompt: prompt, Default: defaultValue})
+	if err != nil {
+		return "", err
+	}
+	s.t.Helper()
+	return s.validated(a, validators)
+}
+
+// Password prompts the user to input a single-line string without echoing the input.
+func (s *PrompterStub) Password(prompt string) (string, error) {
+	s.t.Helper()
+	return s.PasswordWithValidation(prompt)
+}
+
+// PasswordWithValidation is like Password. The test fails if the scripted answer is
+// rejected by any of the validators. It is answered by AnswerPassword.
+func (s *PrompterStub) PasswordWithValidation(prompt string, validators ...Validator) (string, error) {
+	a, err := s.next(Invocation{Method: "Password", Prompt: prompt})
+	if err != nil {
+		return "", err
+	}
+	s.t.Helper()
+	return s.validated(a, validators)
+}
+
+func (s *PrompterStub) validated(a Answer, validators []Validator) (string, error) {
+	answer := a.value.(string)
+	if err := validate(answer, validators); err != nil {
+		s.t.Helper()
+		s.t.Errorf("scripted answer %q to %s prompt %q is invalid: %v", answer, a.method, a.prompt, err)
+		return "", err
+	}
+	return answer, nil
+}
+
+// Confirm prompts the user to confirm a yes/no question.
+func (s *PrompterStub) Confirm(prompt string, defaultValue bool) (bool, error) {
+	a, err := s.next(Invocation{Method: "Confirm", Prompt: prompt, Default: defaultValue})
+	if err != nil {
+		return false, err
+	}
+	return a.value.(bool), nil
+}
+
+// Editor opens the user's preferred editor on a file with the given contents.
+func (s *PrompterStub) Editor(filename, initialContents string) (string, error) {
+	a, err := s.next(Invocation{Method: "Editor", Prompt: filename, Default: initialContents})
+	if err != nil {
+		return "", err
+	}
+	return a.value.(string), nil
+}
+
+// next records the invocation and returns the answer scripted for it. The test is
+// failed if the invocation does not match the next answer in the script.
+func (s *PrompterStub) next(inv Invocation) (Answer, error) {
+	s.invocations = append(s.invocations, inv)
+	s.t.Helper()
+	if len(s.answers) == 0 {
+		s.t.Errorf("unexpected %s prompt %q: no scripted answers left", inv.Method, inv.Prompt)
+		return Answer{}, noSuchPromptErr(inv.Prompt)
+	}
+	a := s.answers[0]
+	s.answers = s.answers[1:]
+	if a.method != inv.Method || a.prompt != inv.Prompt {
+		s.t.Errorf("unexpected %s prompt %q: expected %s prompt %q", inv.Method, inv.Prompt, a.method, a.prompt)
+		return Answer{}, noSuchPromptErr(inv.Prompt)
+	}
+	if a.err != nil {
+		return Answer{}, a.err
+	}
+	return a, nil
+}
+
+func (s *PrompterStub) verify() {
+	if len(s.answers) == 0 {
+		return
+	}
+	s.t.Helper()
+	for _, a := range s.answers {
+		s.t.Errorf("scripted answer to %s prompt %q was never used", a.method, a.prompt)
+	}
+}
@@ -0,0 +1,119 @@
+package prompter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrompterStub(t *testing.T) {
+	errInterrupted := errors.New("interrupted")
+	s := NewStub(t,
+		AnswerInput("Name", "hello-world"),
+		AnswerSelect("Visibility", "Private"),
+		AnswerMultiSelect("Topics", "go", "cli"),
+		AnswerSearchSelect("Owner", "monalisa"),
+		AnswerPassword("Token", "secret"),
+		AnswerEditor("body.md", "Hello"),
+		AnswerConfirm("Create", true),
+		AnswerConfirm("Clone", false).WithError(errInterrupted),
+	)
+
+	name, err := s.Input("Name", "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello-world", name)
+
+	visibility, err := s.Select("Visibility", "Public", []string{"Public", "Private"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, visibility)
+
+	topics, err := s.MultiSelect("Topics", nil, []string{"cli", "go", "rust"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 0}, topics)
+
+	owner, err := s.SearchSelect("Owner", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "monalisa", owner)
+
+	token, err := s.Password("Token")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", token)
+
+	body, err := s.Editor("body.md", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", body)
+
+	create, err := s.Confirm("Create", false)
+	assert.NoError(t, err)
+	assert.True(t, create)
+
+	_, err = s.Confirm("Clone", true)
+	assert.Equal(t, errInterrupted, err)
+
+	invocations := s.Invocations()
+	assert.Len(t, invocations, 8)
+	assert.Equal(t, Invocation{Method: "Input", Prompt: "Name", Default: "default"}, invocations[0])
+	assert.Equal(t, Invocation{
+		Method:  "Select",
+		Prompt:  "Visibility",
+		Default: "Public",
+		Options: []string{"Public", "Private"},
+	}, invocations[1])
+}
+
+func TestPrompterStubUnexpectedPrompt(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers []Answer
+		prompt  func(*PrompterStub) error
+	}{
+		{
+			name: "no answers left",
+			prompt: func(s *PrompterStub) error {
+				_, err := s.Input("Name", "")
+				return err
+			},
+		},
+		{
+			name:    "wrong method",
+			answers: []Answer{AnswerConfirm("Name", true)},
+			prompt: func(s *PrompterStub) error {
+				_, err := s.Input("Name", "")
+				return err
+			},
+		},
+		{
+			name:    "wrong prompt",
+			answers: []Answer{AnswerInput("Title", "")},
+			prompt: func(s *PrompterStub) error {
+				_, err := s.Input("Name", "")
+				return err
+			},
+		},
+		{
+			name:    "invalid answer",
+			answers: []Answer{AnswerInput("Name", "")},
+			prompt: func(s *PrompterStub) error {
+				_, err := s.InputWithValidation("Name", "", Required())
+				return err
+			},
+		},
+		{
+			name:    "option not available",
+			answers: []Answer{AnswerSelect("Visibility", "Internal")},
+			prompt: func(s *PrompterStub) error {
+				_, err := s.Select("Visibility", "", []string{"Public", "Private"})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeT := &testing.T{}
+			s := &PrompterStub{t: fakeT, answers: tt.answers}
+			assert.Error(t, tt.prompt(s))
+			assert.True(t, fakeT.Failed())
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package prompter
+
+import (
+	"github.com/AlecAivazis/survey/v2"
+)
+
+// Icon is a glyph shown by prompts along with the style it is rendered in.
+// Format is a style specification such as "cyan", "green+hb" or "red:white",
+// naming a foreground color, optionally followed by "+" and attributes
+// (b for bold, h for high intensity, u for underline, i for inverse)
+// and by ":" and a background color.
+type Icon struct {
+	Text   string
+	Format string
+}
+
+// Theme customizes the appearance of prompts. Fields that are left empty keep
+// their default appearance, and a theme never changes how prompts behave.
+// Themes do not apply to accessible prompts, which are always rendered as plain text.
+type Theme struct {
+	// Question precedes every prompt message. Defaults to a green "?".
+	Question Icon
+	// SelectFocus marks the focused option of Select and MultiSelect prompts. Defaults to a cyan ">".
+	SelectFocus Icon
+	// MarkedOption marks selected options of MultiSelect prompts. Defaults to a green "[x]".
+	MarkedOption Icon
+	// UnmarkedOption marks unselected options of MultiSelect prompts. Defaults to "[ ]".
+	UnmarkedOption Icon
+	// Error precedes validation errors. Defaults to a red "X".
+	Error Icon
+	// Help precedes help text. Defaults to a cyan "?".
+	Help Icon
+	// HelpInput is the key that reveals help text. Defaults to "?".
+	HelpInput Icon
+}
+
+// WithTheme applies the theme to all prompts.
+func WithTheme(theme Theme) option {
+	return func(p *Prompter) {
+		p.theme = &theme
+	}
+}
+
+// askOpt returns the survey option that applies the theme.
+func (t *Theme) askOpt() survey.AskOpt {
+	return survey.WithIcons(t.apply)
+}
+
+func (t *Theme) apply(icons *survey.IconSet) {
+	applyIcon(&icons.Question, t.Question)
+	applyIcon(&icons.SelectFocus, t.SelectFocus)
+	applyIcon(&icons.MarkedOption, t.MarkedOption)
+	applyIcon(&icons.UnmarkedOption, t.UnmarkedOption)
+	applyIcon(&icons.Error, t.Error)
+	applyIcon(&icons.Help, t.Help)
+	applyIcon(&icons.HelpInput, t.HelpInput)
+}
+
+func applyIcon(dst *survey.Icon, src Icon) {
+	if src.Text != "" {
+		dst.Text = src.Text
+	}
+	if src.Format != "" {
+		dst.Format = src.Format
+	}
+}
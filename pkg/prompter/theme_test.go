@@ -0,0 +1,29 @@
+package prompter
+
+import (
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThemeApply(t *testing.T) {
+	theme := Theme{
+		Question:    Icon{Text: "❯", Format: "magenta+b"},
+		SelectFocus: Icon{Text: "→"},
+		Error:       Icon{Format: "yellow"},
+	}
+	icons := survey.IconSet{
+		Question:    survey.Icon{Text: "?", Format: "green+hb"},
+		SelectFocus: survey.Icon{Text: ">", Format: "cyan+b"},
+		Error:       survey.Icon{Text: "X", Format: "red"},
+		Help:        survey.Icon{Text: "?", Format: "cyan"},
+	}
+	theme.apply(&icons)
+	assert.Equal(t, survey.IconSet{
+		Question:    survey.Icon{Text: "❯", Format: "magenta+b"},
+		SelectFocus: survey.Icon{Text: "→", Format: "cyan+b"},
+		Error:       survey.Icon{Text: "X", Format: "yellow"},
+		Help:        survey.Icon{Text: "?", Format: "cyan"},
+	}, icons)
+}
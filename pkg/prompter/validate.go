@@ -0,0 +1,79 @@
+package prompter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AlecAivazis/survey/v2"
+)
+
+// Validator checks an answer to an InputWithValidation or PasswordWithValidation prompt.
+// When it returns an error the error is shown inline and the user is asked to answer again.
+type Validator func(answer string) error
+
+// Required is a Validator that rejects empty or whitespace-only answers.
+func Required() Validator {
+	return func(answer string) error {
+		if strings.TrimSpace(answer) == "" {
+			return errors.New("a value is required")
+		}
+		return nil
+	}
+}
+
+// MinLength is a Validator that rejects answers shorter than n characters.
+func MinLength(n int) Validator {
+	return func(answer string) error {
+		if utf8.RuneCountInString(answer) < n {
+			return fmt.Errorf("must be at least %d characters long", n)
+		}
+		return nil
+	}
+}
+
+// MaxLength is a Validator that rejects answers longer than n characters.
+func MaxLength(n int) Validator {
+	return func(answer string) error {
+		if utf8.RuneCountInString(answer) > n {
+			return fmt.Errorf("must be at most %d characters long", n)
+		}
+		return nil
+	}
+}
+
+// MatchRegexp is a Validator that rejects answers not matching re. The message is shown
+// to the user when an answer is rejected, and defaults to describing the pattern.
+func MatchRegexp(re *regexp.Regexp, message string) Validator {
+	if message == "" {
+		message = fmt.Sprintf("must match the pattern %s", re)
+	}
+	return func(answer string) error {
+		if !re.MatchString(answer) {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// validate runs the validators in order and returns the first error.
+func validate(answer string, validators []Validator) error {
+	for _, v := range validators {
+		if err := v(answer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatorOpts(validators []Validator) []survey.AskOpt {
+	if len(validators) == 0 {
+		return nil
+	}
+	return []survey.AskOpt{survey.WithValidator(func(ans interface{}) error {
+		s, _ := ans.(string)
+		return validate(s, validators)
+	})}
+}
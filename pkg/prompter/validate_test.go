@@ -0,0 +1,94 @@
+package prompter
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Validator
+		answer    string
+		wantErr   string
+	}{
+		{
+			name:      "required with value",
+			validator: Required(),
+			answer:    "monalisa",
+		},
+		{
+			name:      "required with blank value",
+			validator: Required(),
+			answer:    "  ",
+			wantErr:   "a value is required",
+		},
+		{
+			name:      "min length counts characters",
+			validator: MinLength(3),
+			answer:    "Miķ",
+		},
+		{
+			name:      "min length too short",
+			validator: MinLength(3),
+			answer:    "ab",
+			wantErr:   "must be at least 3 characters long",
+		},
+		{
+			name:      "max length too long",
+			validator: MaxLength(3),
+			answer:    "abcd",
+			wantErr:   "must be at most 3 characters long",
+		},
+		{
+			name:      "regexp match",
+			validator: MatchRegexp(regexp.MustCompile(`^[a-z-]+$`), ""),
+			answer:    "go-goctl",
+		},
+		{
+			name:      "regexp default message",
+			validator: MatchRegexp(regexp.MustCompile(`^[a-z-]+$`), ""),
+			answer:    "Go Goctl",
+			wantErr:   "must match the pattern ^[a-z-]+$",
+		},
+		{
+			name:      "regexp custom message",
+			validator: MatchRegexp(regexp.MustCompile(`^[a-z-]+$`), "must be a valid repository name"),
+			answer:    "Go Goctl",
+			wantErr:   "must be a valid repository name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator(tt.answer)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	custom := func(string) error { return errors.New("custom") }
+	assert.EqualError(t, validate("", []Validator{Required(), custom}), "a value is required")
+	assert.EqualError(t, validate("x", []Validator{Required(), custom}), "custom")
+	assert.NoError(t, validate("x", nil))
+}
+
+func TestInputNonInteractiveValidatesDefault(t *testing.T) {
+	p := New(os.Stdin, os.Stdout, os.Stderr, WithNonInteractivePolicy(UseDefault))
+	p.isInteractive = func() bool { return false }
+
+	_, err := p.InputWithValidation("Name", "", Required())
+	assert.EqualError(t, err, "invalid default value: a value is required")
+
+	name, err := p.InputWithValidation("Name", "monalisa", Required())
+	assert.NoError(t, err)
+	assert.Equal(t, "monalisa", name)
+}
@@ -0,0 +1,118 @@
+// Package redact facilitates masking secrets such as authentication tokens in output
+// before it is written to a terminal or a log.
+package redact
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mask is the text that redacted values are replaced with.
+const Mask = "***"
+
+// Lines longer than this are written out even if they are not terminated,
+// to bound the amount of data held in memory.
+const maxBufferSize = 64 * 1024
+
+// Writer is an io.Writer that masks registered secrets and patterns in everything
+// written through it, as well as any string with the format of a GitHub token (see Scan).
+// Output is buffered line by line so that a secret split across several writes is still
+// masked; call Flush to write out a final unterminated line.
+//
+// Tokens obtained from pkg/auth can be registered with AddSecret:
+//
+//	w := redact.NewWriter(os.Stderr)
+//	token, _ := auth.TokenForHost(host)
+//	w.AddSecret(token)
+type Writer struct {
+	mu       sync.Mutex
+	out      io.Writer
+	secrets  []string
+	patterns []*regexp.Regexp
+	buf      []byte
+}
+
+// NewWriter initializes a Writer that writes redacted output to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{out: w}
+}
+
+// AddSecret registers a literal value to be masked. Empty values are ignored.
+func (w *Writer) AddSecret(secret string) {
+	if secret == "" {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.secrets = append(w.secrets, secret)
+	// Replace longer secrets first so that a secret containing another is fully masked.
+	sort.SliceStable(w.secrets, func(i, j int) bool {
+		return len(w.secrets[i]) > len(w.secrets[j])
+	})
+}
+
+// AddPattern registers a regular expression whose matches are to be masked.
+// Patterns are matched within a single line.
+func (w *Writer) AddPattern(pattern *regexp.Regexp) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.patterns = append(w.patterns, pattern)
+}
+
+// Write buffers p and writes out every complete line with secrets masked.
+// It always reports len(p) bytes written unless the underlying writer fails.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	i := bytes.LastIndexByte(w.buf, '\n')
+	if i < 0 {
+		if len(w.buf) < maxBufferSize {
+			return len(p), nil
+		}
+		i = len(w.buf) - 1
+	}
+	if err := w.writeRedacted(w.buf[:i+1]); err != nil {
+		return 0, err
+	}
+	w.buf = append(w.buf[:0], w.buf[i+1:]...)
+	return len(p), nil
+}
+
+// Flush writes out any buffered data that is not terminated by a newline.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.writeRedacted(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+// Redact returns s with all registered secrets and patterns masked.
+func (w *Writer) Redact(s string) string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.redact(s)
+}
+
+func (w *Writer) writeRedacted(b []byte) error {
+	_, err := io.WriteString(w.out, w.redact(string(b)))
+	return err
+}
+
+func (w *Writer) redact(s string) string {
+	for _, secret := range w.secrets {
+		s = strings.ReplaceAll(s, secret, Mask)
+	}
+	for _, pattern := range w.patterns {
+		s = pattern.ReplaceAllLiteralString(s, Mask)
+	}
+	return MaskTokens(s)
+}
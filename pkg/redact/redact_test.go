@@ -0,0 +1,96 @@
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		secrets  []string
+		patterns []*regexp.Regexp
+		writes   []string
+		want     string
+	}{
+		{
+			name:   "no secrets",
+			writes: []string{"hello\n"},
+			want:   "hello\n",
+		},
+		{
+			name:    "secret",
+			secrets: []string{"goctl_abc123"},
+			writes:  []string{"Authorization: token goctl_abc123\n"},
+			want:    "Authorization: token ***\n",
+		},
+		{
+			name:    "secret split across writes",
+			secrets: []string{"goctl_abc123"},
+			writes:  []string{"token goctl_a", "bc123 used\n"},
+			want:    "token *** used\n",
+		},
+		{
+			name:    "overlapping secrets",
+			secrets: []string{"abc", "abcdef"},
+			writes:  []string{"abcdef abc\n"},
+			want:    "*** ***\n",
+		},
+		{
+			name:    "empty secret is ignored",
+			secrets: []string{""},
+			writes:  []string{"hello\n"},
+			want:    "hello\n",
+		},
+		{
+			name:     "pattern",
+			patterns: []*regexp.Regexp{regexp.MustCompile(`password=\S+`)},
+			writes:   []string{"url?password=hunter2&x=1 done\n"},
+			want:     "url?*** done\n",
+		},
+		{
+			name:    "unterminated line is written on flush",
+			secrets: []string{"s3cret"},
+			writes:  []string{"one\ntwo s3cret"},
+			want:    "one\ntwo ***",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := NewWriter(buf)
+			for _, s := range tt.secrets {
+				w.AddSecret(s)
+			}
+			for _, p := range tt.patterns {
+				w.AddPattern(p)
+			}
+			for _, s := range tt.writes {
+				n, err := fmt.Fprint(w, s)
+				assert.NoError(t, err)
+				assert.Equal(t, len(s), n)
+			}
+			assert.NoError(t, w.Flush())
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestWriterBuffersUntilNewline(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf)
+	fmt.Fprint(w, "partial")
+	assert.Equal(t, "", buf.String())
+	fmt.Fprint(w, " line\nnext")
+	assert.Equal(t, "partial line\n", buf.String())
+}
+
+func TestRedact(t *testing.T) {
+	w := NewWriter(&bytes.Buffer{})
+	w.AddSecret("abc123")
+	assert.Equal(t, "token ***", w.Redact("token abc123"))
+}
@@ -0,0 +1,95 @@
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tokenKind is a format of GitHub token that Scan detects.
+type tokenKind struct {
+	name   string
+	prefix string
+	// pattern matches the token after the prefix.
+	pattern string
+}
+
+// tokenKinds are the formats of GitHub tokens, as documented in
+// https://github.blog/2021-04-05-behind-githubs-new-authentication-token-formats/.
+// Lengths are only bounded from below since GitHub may lengthen tokens.
+var tokenKinds = []tokenKind{
+	{name: "personal access token", prefix: "ghp_", pattern: `[A-Za-z0-9]{36,}`},
+	{name: "fine-grained personal access token", prefix: "github_pat_", pattern: `[A-Za-z0-9]{22,}_[A-Za-z0-9]{59,}`},
+	{name: "OAuth access token", prefix: "gho_", pattern: `[A-Za-z0-9]{36,}`},
+	{name: "user-to-server token", prefix: "ghu_", pattern: `[A-Za-z0-9]{36,}`},
+	{name: "server-to-server token", prefix: "ghs_", pattern: `[A-Za-z0-9]{36,}`},
+	{name: "refresh token", prefix: "ghr_", pattern: `[A-Za-z0-9]{36,}`},
+}
+
+var tokenRE = func() *regexp.Regexp {
+	alternatives := make([]string, len(tokenKinds))
+	for i, kind := range tokenKinds {
+		alternatives[i] = "(" + regexp.QuoteMeta(kind.prefix) + kind.pattern + ")"
+	}
+	// A token must not follow a letter or digit. \b cannot express this because it does
+	// not treat "_" as a boundary, so the preceding byte is matched as the first group
+	// instead. The end of a token needs no check as the patterns match greedily.
+	return regexp.MustCompile(`(^|[^A-Za-z0-9])(?:` + strings.Join(alternatives, "|") + `)`)
+}()
+
+// Finding is a token-shaped string found by Scan.
+type Finding struct {
+	// Kind describes the format of the token, such as "personal access token".
+	Kind string
+	// Line is the 1-based line number of the token.
+	Line int
+	// Start and End are the byte offsets of the token in the scanned data.
+	Start int
+	End   int
+	// Prefix is the prefix identifying the format of the token, such as "ghp_". The
+	// token itself is not kept so that findings can be reported safely.
+	Prefix string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("line %d: %s %s%s", f.Line, f.Kind, f.Prefix, Mask)
+}
+
+// Scan reports the strings in data that have the format of a GitHub token, such as
+// "ghp_" personal access tokens and "github_pat_" fine-grained personal access tokens.
+// It lets programs check that their output and logs do not leak credentials, for
+// example in tests:
+//
+//	if findings := redact.Scan(out.Bytes()); len(findings) > 0 {
+//		t.Errorf("output contains tokens: %v", findings)
+//	}
+func Scan(data []byte) []Finding {
+	var findings []Finding
+	line, lineStart := 1, 0
+	for _, m := range tokenRE.FindAllSubmatchIndex(data, -1) {
+		// The match includes the byte before the token, if any.
+		start := m[3]
+		line += bytes.Count(data[lineStart:start], []byte("\n"))
+		lineStart = start
+		for i, kind := range tokenKinds {
+			if m[4+2*i] < 0 {
+				continue
+			}
+			findings = append(findings, Finding{
+				Kind:   kind.name,
+				Line:   line,
+				Start:  start,
+				End:    m[1],
+				Prefix: kind.prefix,
+			})
+			break
+		}
+	}
+	return findings
+}
+
+// MaskTokens returns s with every string that has the format of a GitHub token masked.
+func MaskTokens(s string) string {
+	return tokenRE.ReplaceAllString(s, "${1}"+Mask)
+}
@@ -0,0 +1,90 @@
+package redact
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	classicToken     = "ghp_" + strings.Repeat("a1B2", 9)
+	fineGrainedToken = "github_pat_" + strings.Repeat("x", 22) + "_" + strings.Repeat("Y9", 30)
+	oauthToken       = "gho_" + strings.Repeat("c3", 18)
+)
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Finding
+	}{
+		{
+			name: "no tokens",
+			data: "ghp_short gho_\nAuthorization: token ***\n",
+		},
+		{
+			name: "classic token",
+			data: "token " + classicToken + "\n",
+			want: []Finding{
+				{Kind: "personal access token", Line: 1, Start: 6, End: 6 + len(classicToken), Prefix: "ghp_"},
+			},
+		},
+		{
+			name: "tokens on several lines",
+			data: "one\ntwo " + fineGrainedToken + "\n\nthree=" + oauthToken,
+			want: []Finding{
+				{Kind: "fine-grained personal access token", Line: 2, Start: 8, End: 8 + len(fineGrainedToken), Prefix: "github_pat_"},
+				{Kind: "OAuth access token", Line: 4, Start: 16 + len(fineGrainedToken), End: 16 + len(fineGrainedToken) + len(oauthToken), Prefix: "gho_"},
+			},
+		},
+		{
+			name: "token within an identifier",
+			data: "xghp_" + strings.Repeat("a", 36),
+		},
+		{
+			name: "token after an underscore",
+			data: "GOCTL_TOKEN_" + classicToken,
+			want: []Finding{
+				{Kind: "personal access token", Line: 1, Start: 12, End: 12 + len(classicToken), Prefix: "ghp_"},
+			},
+		},
+		{
+			name: "adjacent tokens",
+			data: classicToken + "," + oauthToken,
+			want: []Finding{
+				{Kind: "personal access token", Line: 1, Start: 0, End: len(classicToken), Prefix: "ghp_"},
+				{Kind: "OAuth access token", Line: 1, Start: len(classicToken) + 1, End: len(classicToken) + 1 + len(oauthToken), Prefix: "gho_"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Scan([]byte(tt.data))
+			assert.Equal(t, tt.want, findings)
+			for _, f := range findings {
+				assert.True(t, strings.HasPrefix(tt.data[f.Start:f.End], f.Prefix))
+			}
+		})
+	}
+}
+
+func TestFindingString(t *testing.T) {
+	f := Scan([]byte(classicToken))[0]
+	assert.Equal(t, "line 1: personal access token ghp_***", f.String())
+}
+
+func TestWriterMasksTokens(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := NewWriter(out)
+	_, err := w.Write([]byte("GOCTL_TOKEN=" + classicToken + " " + oauthToken + "\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "GOCTL_TOKEN=*** ***\n", out.String())
+	assert.Empty(t, Scan(out.Bytes()))
+}
+
+func TestMaskTokens(t *testing.T) {
+	assert.Equal(t, "GOCTL_TOKEN_***", MaskTokens("GOCTL_TOKEN_"+classicToken))
+	assert.Equal(t, "***,***", MaskTokens(classicToken+","+oauthToken))
+}
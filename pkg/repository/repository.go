@@ -3,6 +3,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -27,21 +28,7 @@ func Parse(s string) (Repository, error) {
 	var r Repository
 
 	if git.IsURL(s) {
-		u, err := git.ParseURL(s)
-		if err != nil {
-			return r, err
-		}
-
-		host, owner, name, err := git.RepoInfoFromURL(u)
-		if err != nil {
-			return r, err
-		}
-
-		r.Host = host
-		r.Name = name
-		r.Owner = owner
-
-		return r, nil
+		return FromURL(s)
 	}
 
 	parts := strings.SplitN(s, "/", 4)
@@ -67,28 +54,14 @@ func Parse(s string) (Repository, error) {
 	}
 }
 
-// Parse extracts the repository information from the following
+// ParseWithHost extracts the repository information from the following
 // string formats: "OWNER/REPO", "HOST/OWNER/REPO", and a full URL.
 // If the format does not specify a host, use the host provided.
 func ParseWithHost(s, host string) (Repository, error) {
 	var r Repository
 
 	if git.IsURL(s) {
-		u, err := git.ParseURL(s)
-		if err != nil {
-			return r, err
-		}
-
-		host, owner, name, err := git.RepoInfoFromURL(u)
-		if err != nil {
-			return r, err
-		}
-
-		r.Host = host
-		r.Owner = owner
-		r.Name = name
-
-		return r, nil
+		return FromURL(s)
 	}
 
 	parts := strings.SplitN(s, "/", 4)
@@ -114,9 +87,38 @@ func ParseWithHost(s, host string) (Repository, error) {
 	}
 }
 
+// FromURL extracts the repository information from a git remote URL, such as
+// "https://github.com/OWNER/REPO.git", "ssh://git@github.com/OWNER/REPO.git",
+// or the scp-like "git@github.com:OWNER/REPO.git".
+func FromURL(rawURL string) (Repository, error) {
+	var r Repository
+
+	u, err := git.ParseURL(rawURL)
+	if err != nil {
+		return r, err
+	}
+
+	host, owner, name, err := git.RepoInfoFromURL(u)
+	if err != nil {
+		return r, err
+	}
+
+	r.Host = host
+	r.Owner = owner
+	r.Name = name
+
+	return r, nil
+}
+
 // Current uses git remotes to determine the GitHub repository
 // the current directory is tracking.
 func Current() (Repository, error) {
+	return CurrentContext(context.Background())
+}
+
+// CurrentContext is like Current but kills the git processes it runs if the context
+// is done before they exit.
+func CurrentContext(ctx context.Context) (Repository, error) {
 	var r Repository
 
 	override := os.Getenv("GOCTL_REPO")
@@ -124,7 +126,7 @@ func Current() (Repository, error) {
 		return Parse(override)
 	}
 
-	remotes, err := git.Remotes()
+	remotes, err := git.RemotesContext(ctx)
 	if err != nil {
 		return r, err
 	}
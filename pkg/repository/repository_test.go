@@ -190,6 +190,69 @@ func TestParseWithHost(t *testing.T) {
 	}
 }
 
+func TestFromURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantOwner string
+		wantName  string
+		wantHost  string
+		wantErr   string
+	}{
+		{
+			name:      "HTTPS URL",
+			input:     "https://github.com/OWNER/REPO.git",
+			wantHost:  "github.com",
+			wantOwner: "OWNER",
+			wantName:  "REPO",
+		},
+		{
+			name:      "SSH URL",
+			input:     "ssh://git@GitHub.com:22/OWNER/REPO",
+			wantHost:  "github.com",
+			wantOwner: "OWNER",
+			wantName:  "REPO",
+		},
+		{
+			name:      "scp-like URL",
+			input:     "git@www.example.org:OWNER/REPO.git",
+			wantHost:  "example.org",
+			wantOwner: "OWNER",
+			wantName:  "REPO",
+		},
+		{
+			name:    "missing host",
+			input:   "OWNER/REPO",
+			wantErr: "no hostname detected",
+		},
+		{
+			name:    "too many path elements",
+			input:   "https://github.com/OWNER/REPO/pulls",
+			wantErr: "invalid path: /OWNER/REPO/pulls",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := FromURL(tt.input)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHost, r.Host)
+			assert.Equal(t, tt.wantOwner, r.Owner)
+			assert.Equal(t, tt.wantName, r.Name)
+		})
+	}
+}
+
+func TestCurrent_override(t *testing.T) {
+	t.Setenv("GOCTL_REPO", "example.org/OWNER/REPO")
+	r, err := Current()
+	assert.NoError(t, err)
+	assert.Equal(t, Repository{Host: "example.org", Owner: "OWNER", Name: "REPO"}, r)
+}
+
 func stubConfig(t *testing.T, cfgStr string) {
 	t.Helper()
 	old := config.Read
@@ -200,3 +263,20 @@ func stubConfig(t *testing.T, cfgStr string) {
 		config.Read = old
 	})
 }
+
+func FuzzParseWithHost(f *testing.F) {
+	f.Add("OWNER/REPO")
+	f.Add("HOST/OWNER/REPO")
+	f.Add("https://github.com/OWNER/REPO.git")
+	f.Add("git@github.com:OWNER/REPO.git")
+	f.Add("ssh://git@github.com:443/OWNER/REPO")
+	f.Add("file:///OWNER/REPO")
+	f.Fuzz(func(t *testing.T, s string) {
+		r, err := ParseWithHost(s, "github.com")
+		if err != nil {
+			return
+		}
+		assert.NotEmpty(t, r.Owner)
+		assert.NotEmpty(t, r.Name)
+	})
+}
@@ -0,0 +1,237 @@
+// Package semver parses, compares, and increments semantic versions such as the release
+// tags of goctl extensions, following https://semver.org.
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	// Prerelease is the dot separated prerelease identifiers without the leading hyphen,
+	// such as "rc.1", or an empty string for releases.
+	Prerelease string
+	// Build is the build metadata without the leading plus sign. It is ignored when
+	// comparing versions.
+	Build string
+}
+
+// Parse parses a version such as "1.2.3", "1.2.3-rc.1+build.5", or a tag name like "v1.2.3".
+// A leading "v" is ignored, and missing minor and patch numbers, as in "v2" or "v2.1", are
+// treated as zero.
+func Parse(s string) (Version, error) {
+	var v Version
+	rest := s
+	if strings.HasPrefix(rest, "v") || strings.HasPrefix(rest, "V") {
+		rest = rest[1:]
+	}
+	rest, build, hasBuild := strings.Cut(rest, "+")
+	if hasBuild && !validIdentifiers(build, false) {
+		return Version{}, fmt.Errorf("invalid build metadata in version %q", s)
+	}
+	rest, prerelease, hasPrerelease := strings.Cut(rest, "-")
+	if hasPrerelease && !validIdentifiers(prerelease, true) {
+		return Version{}, fmt.Errorf("invalid prerelease in version %q", s)
+	}
+	v.Prerelease, v.Build = prerelease, build
+	parts := strings.Split(rest, ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := parseNumber(p)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// MustParse is like Parse but panics if the version is invalid. It is meant for versions
+// that are known to be valid, such as constants.
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// NormalizeTag returns the canonical tag name of a version, such as "v1.2.0" for "1.2".
+func NormalizeTag(tag string) (string, error) {
+	v, err := Parse(tag)
+	if err != nil {
+		return "", err
+	}
+	return v.Tag(), nil
+}
+
+// String returns the version without a leading "v", such as "1.2.3-rc.1".
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Tag returns the version with a leading "v", as used for release tags.
+func (v Version) Tag() string {
+	return "v" + v.String()
+}
+
+// IsPrerelease reports whether the version is a prerelease.
+func (v Version) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Compare returns -1, 0, or 1 if v is lower than, equal to, or greater than w. Build
+// metadata is ignored, and prereleases are lower than the release of the same version.
+func (v Version) Compare(w Version) int {
+	for _, d := range []int{v.Major - w.Major, v.Minor - w.Minor, v.Patch - w.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	return comparePrerelease(v.Prerelease, w.Prerelease)
+}
+
+// Less reports whether v is lower than w.
+func (v Version) Less(w Version) bool {
+	return v.Compare(w) < 0
+}
+
+// BumpMajor returns the next major version. Prerelease and build metadata are dropped.
+func (v Version) BumpMajor() Version {
+	return Version{Major: v.Major + 1}
+}
+
+// BumpMinor returns the next minor version. Prerelease and build metadata are dropped.
+func (v Version) BumpMinor() Version {
+	return Version{Major: v.Major, Minor: v.Minor + 1}
+}
+
+// BumpPatch returns the next patch version, or the release of v if it is a prerelease.
+// Build metadata is dropped.
+func (v Version) BumpPatch() Version {
+	if v.IsPrerelease() {
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	}
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+// BumpPrerelease returns the next prerelease with the given identifier, such as
+// "1.2.4-rc.0" for "1.2.3" and "1.2.4-rc.1" for "1.2.4-rc.0". Build metadata is dropped.
+func (v Version) BumpPrerelease(id string) Version {
+	next := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	if !v.IsPrerelease() {
+		next.Patch++
+		next.Prerelease = id + ".0"
+		return next
+	}
+	if prefix, n, ok := cutLastNumber(v.Prerelease); ok && prefix == id {
+		next.Prerelease = fmt.Sprintf("%s.%d", id, n+1)
+		return next
+	}
+	next.Prerelease = id + ".0"
+	return next
+}
+
+// cutLastNumber splits a prerelease such as "rc.3" into "rc" and 3.
+func cutLastNumber(prerelease string) (string, int, bool) {
+	i := strings.LastIndexByte(prerelease, '.')
+	if i < 0 {
+		return "", 0, false
+	}
+	n, err := parseNumber(prerelease[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return prerelease[:i], n, true
+}
+
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareIdentifier(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+// compareIdentifier compares prerelease identifiers. Numeric identifiers are compared
+// numerically and are lower than alphanumeric identifiers, which are compared in ASCII
+// order.
+func compareIdentifier(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		if an == bn {
+			return 0
+		} else if an < bn {
+			return -1
+		}
+		return 1
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// parseNumber parses a version number, which must not have leading zeros.
+func parseNumber(s string) (int, error) {
+	if s == "" || (len(s) > 1 && s[0] == '0') || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return strconv.Atoi(s)
+}
+
+// validIdentifiers reports whether s is a non-empty list of dot separated identifiers
+// made of ASCII alphanumerics and hyphens. Numeric prerelease identifiers must not have
+// leading zeros.
+func validIdentifiers(s string, prerelease bool) bool {
+	if s == "" {
+		return false
+	}
+	for _, id := range strings.Split(s, ".") {
+		if id == "" || strings.Trim(id, "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-") != "" {
+			return false
+		}
+		if prerelease && len(id) > 1 && id[0] == '0' && strings.TrimLeft(id, "0123456789") == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,117 @@
+package semver
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Version
+		wantErr string
+	}{
+		{input: "1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{input: "v1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{input: "v2", want: Version{Major: 2}},
+		{input: "2.1", want: Version{Major: 2, Minor: 1}},
+		{input: "1.0.0-rc.1", want: Version{Major: 1, Prerelease: "rc.1"}},
+		{input: "1.0.0-alpha-1+build.5", want: Version{Major: 1, Prerelease: "alpha-1", Build: "build.5"}},
+		{input: "1.0.0+001", want: Version{Major: 1, Build: "001"}},
+		{input: "", wantErr: `invalid version ""`},
+		{input: "1.2.3.4", wantErr: `invalid version "1.2.3.4"`},
+		{input: "01.2.3", wantErr: `invalid version "01.2.3"`},
+		{input: "1.x", wantErr: `invalid version "1.x"`},
+		{input: "1.0.0-", wantErr: `invalid prerelease in version "1.0.0-"`},
+		{input: "1.0.0-rc..1", wantErr: `invalid prerelease in version "1.0.0-rc..1"`},
+		{input: "1.0.0-01", wantErr: `invalid prerelease in version "1.0.0-01"`},
+		{input: "1.0.0+bad_build", wantErr: `invalid build metadata in version "1.0.0+bad_build"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := Parse(tt.input)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+}
+
+func TestVersionString(t *testing.T) {
+	v := MustParse("v1.2.3-rc.1+build.5")
+	assert.Equal(t, "1.2.3-rc.1+build.5", v.String())
+	assert.Equal(t, "v1.2.3-rc.1+build.5", v.Tag())
+	assert.True(t, v.IsPrerelease())
+
+	tag, err := NormalizeTag("1.2")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", tag)
+	_, err = NormalizeTag("latest")
+	assert.EqualError(t, err, `invalid version "latest"`)
+
+	assert.Panics(t, func() { MustParse("latest") })
+}
+
+func TestCompare(t *testing.T) {
+	// Precedence examples from the SemVer specification, in ascending order.
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"1.10.0",
+		"2.0.0",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			a, b := MustParse(ordered[i]), MustParse(ordered[j])
+			want := sign(i - j)
+			assert.Equal(t, want, a.Compare(b), "%s <=> %s", a, b)
+		}
+	}
+
+	assert.Equal(t, 0, MustParse("v1.0.0+a").Compare(MustParse("1.0.0+b")))
+
+	versions := []Version{MustParse("1.10.0"), MustParse("1.2.0"), MustParse("1.2.0-rc.1")}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Less(versions[j]) })
+	assert.Equal(t, []Version{MustParse("1.2.0-rc.1"), MustParse("1.2.0"), MustParse("1.10.0")}, versions)
+}
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		name string
+		bump func(Version) Version
+		from string
+		want string
+	}{
+		{name: "major", bump: Version.BumpMajor, from: "1.2.3-rc.1+b", want: "2.0.0"},
+		{name: "minor", bump: Version.BumpMinor, from: "1.2.3", want: "1.3.0"},
+		{name: "patch", bump: Version.BumpPatch, from: "1.2.3+b", want: "1.2.4"},
+		{name: "patch of prerelease", bump: Version.BumpPatch, from: "1.2.4-rc.2", want: "1.2.4"},
+		{name: "first prerelease", bump: prerelease("rc"), from: "1.2.3", want: "1.2.4-rc.0"},
+		{name: "next prerelease", bump: prerelease("rc"), from: "1.2.4-rc.0", want: "1.2.4-rc.1"},
+		{name: "other prerelease", bump: prerelease("rc"), from: "1.2.4-beta.3", want: "1.2.4-rc.0"},
+		{name: "prerelease without number", bump: prerelease("rc"), from: "1.2.4-rc", want: "1.2.4-rc.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.bump(MustParse(tt.from)).String())
+		})
+	}
+}
+
+func prerelease(id string) func(Version) Version {
+	return func(v Version) Version { return v.BumpPrerelease(id) }
+}
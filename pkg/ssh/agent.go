@@ -0,0 +1,99 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// ErrNoAgent is returned when no SSH agent is running or it cannot be reached.
+var ErrNoAgent = errors.New("could not connect to an SSH agent")
+
+// AgentKey is a public key loaded in the SSH agent.
+type AgentKey struct {
+	// Type is the key algorithm, such as "ssh-ed25519".
+	Type string
+	// Key is the base64 encoded public key.
+	Key string
+	// Comment is usually the path of the private key or the email address of its owner.
+	Comment string
+}
+
+// Fingerprint returns the SHA256 fingerprint of the key in the format shown by OpenSSH.
+func (k AgentKey) Fingerprint() string {
+	return HostKey{Key: k.Key}.Fingerprint()
+}
+
+// AgentRunning reports whether an SSH agent is running and can be reached.
+func (m *KeyManager) AgentRunning() bool {
+	_, err := m.AgentKeys()
+	return err == nil
+}
+
+// AgentKeys returns the public keys loaded in the SSH agent. It returns ErrNoAgent if
+// no agent is running.
+func (m *KeyManager) AgentKeys() ([]AgentKey, error) {
+	return m.AgentKeysContext(context.Background())
+}
+
+// AgentKeysContext is like AgentKeys but kills ssh-add if the context is done before it
+// exits.
+func (m *KeyManager) AgentKeysContext(ctx context.Context) ([]AgentKey, error) {
+	sshAddPath, err := m.findExecutable("ssh-add")
+	if err != nil {
+		return nil, err
+	}
+	out, err := m.command(ctx, sshAddPath, "-L").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// ssh-add exits with 1 when the agent has no keys and with 2 when it cannot connect.
+		switch exitErr.ExitCode() {
+		case 1:
+			return []AgentKey{}, nil
+		case 2:
+			return nil, ErrNoAgent
+		}
+		return nil, commandError("ssh-add", err, string(exitErr.Stderr))
+	} else if err != nil {
+		return nil, err
+	}
+
+	keys := []AgentKey{}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 3)
+		if len(fields) < 2 {
+			continue
+		}
+		k := AgentKey{Type: fields[0], Key: fields[1]}
+		if len(fields) == 3 {
+			k.Comment = fields[2]
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// MatchRegisteredKeys partitions the agent keys into the keys that are registered as
+// SSH keys of the authenticated user and those that are not, which helps diagnose why
+// pushing over SSH fails.
+func MatchRegisteredKeys(client RESTClient, keys []AgentKey) (registered, unregistered []AgentKey, err error) {
+	existing, err := userKeys(client)
+	if err != nil {
+		return nil, nil, err
+	}
+	known := map[string]bool{}
+	for _, k := range existing {
+		if f := strings.Fields(k); len(f) >= 2 {
+			known[f[0]+" "+f[1]] = true
+		}
+	}
+	for _, k := range keys {
+		if known[k.Type+" "+k.Key] {
+			registered = append(registered, k)
+		} else {
+			unregistered = append(unregistered, k)
+		}
+	}
+	return registered, unregistered, nil
+}
@@ -0,0 +1,80 @@
+package ssh
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentHelperProcess(t *testing.T) {
+	if os.Getenv("GOCTL_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	switch os.Getenv("GOCTL_AGENT_STATE") {
+	case "empty":
+		os.Stdout.WriteString("The agent has no identities.\n")
+		os.Exit(1)
+	case "missing":
+		os.Stderr.WriteString("Could not open a connection to your authentication agent.\n")
+		os.Exit(2)
+	}
+	os.Stdout.WriteString("ssh-ed25519 " + testHostKey + " monalisa@laptop\n")
+	os.Stdout.WriteString("ssh-rsa AAAAB3NzaC1yc2E\n")
+	os.Exit(0)
+}
+
+func newStubAgentManager(state string) *KeyManager {
+	return &KeyManager{
+		lookPath: func(name string) (string, error) {
+			return "/usr/bin/" + name, nil
+		},
+		newCommand: func(ctx context.Context, exe string, args ...string) *exec.Cmd {
+			args = append([]string{"-test.run=TestAgentHelperProcess", "--", exe}, args...)
+			c := exec.CommandContext(ctx, os.Args[0], args...)
+			c.Env = []string{"GOCTL_WANT_HELPER_PROCESS=1", "GOCTL_AGENT_STATE=" + state}
+			return c
+		},
+	}
+}
+
+func TestAgentKeys(t *testing.T) {
+	m := newStubAgentManager("")
+	keys, err := m.AgentKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, []AgentKey{
+		{Type: "ssh-ed25519", Key: testHostKey, Comment: "monalisa@laptop"},
+		{Type: "ssh-rsa", Key: "AAAAB3NzaC1yc2E"},
+	}, keys)
+	assert.Equal(t, "SHA256:+DiY3wvvV6TuJJhbpZisF/zLDA0zPMSvHdkr4UvCOqU", keys[0].Fingerprint())
+	assert.True(t, m.AgentRunning())
+
+	m = newStubAgentManager("empty")
+	keys, err = m.AgentKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, []AgentKey{}, keys)
+	assert.True(t, m.AgentRunning())
+
+	m = newStubAgentManager("missing")
+	_, err = m.AgentKeys()
+	assert.ErrorIs(t, err, ErrNoAgent)
+	assert.False(t, m.AgentRunning())
+}
+
+func TestMatchRegisteredKeys(t *testing.T) {
+	client := &fakeRESTClient{keys: []string{"ssh-rsa AAAAB3NzaC1yc2F", "ssh-ed25519 " + testHostKey}, perPage: 1}
+	keys := []AgentKey{
+		{Type: "ssh-ed25519", Key: testHostKey, Comment: "monalisa@laptop"},
+		{Type: "ssh-rsa", Key: "AAAAB3NzaC1yc2E"},
+	}
+	registered, unregistered, err := MatchRegisteredKeys(client, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, keys[:1], registered)
+	assert.Equal(t, keys[1:], unregistered)
+	assert.Equal(t, []string{
+		"GET user/keys?per_page=100",
+		"GET https://api.github.com/user/keys?per_page=100&page=2",
+	}, client.requests)
+}
@@ -0,0 +1,187 @@
+package ssh
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config is the subset of an OpenSSH client configuration needed to resolve
+// hostname aliases: the Hostname directive of Host blocks.
+type Config struct {
+	hosts []hostBlock
+}
+
+type hostBlock struct {
+	patterns []string
+	hostname string
+}
+
+// ParseConfig reads an OpenSSH client configuration file, following Include
+// directives. Relative Include paths are resolved against ~/.ssh, and may
+// contain glob patterns. A missing file results in an empty configuration.
+func ParseConfig(filename string) (*Config, error) {
+	c := &Config{}
+	// Directives before the first Host block apply to all hosts.
+	c.hosts = append(c.hosts, hostBlock{patterns: []string{"*"}})
+	if err := c.parseFile(filename, 0); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// maxIncludeDepth guards against Include cycles.
+const maxIncludeDepth = 16
+
+func (c *Config) parseFile(filename string, depth int) error {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		keyword, args := splitDirective(s.Text())
+		switch keyword {
+		case "host":
+			c.hosts = append(c.hosts, hostBlock{patterns: args})
+		case "match":
+			// Match criteria are not evaluated; the block never applies.
+			c.hosts = append(c.hosts, hostBlock{})
+		case "hostname":
+			block := &c.hosts[len(c.hosts)-1]
+			if block.hostname == "" && len(args) > 0 {
+				block.hostname = args[0]
+			}
+		case "include":
+			if depth >= maxIncludeDepth {
+				continue
+			}
+			for _, pattern := range args {
+				matches, _ := filepath.Glob(includePath(pattern))
+				for _, m := range matches {
+					if err := c.parseFile(m, depth+1); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return s.Err()
+}
+
+// Hostname returns the real hostname configured for the given alias, or the
+// alias itself if no Hostname directive applies to it. As in OpenSSH, the
+// first matching Hostname directive wins and "%h" expands to the alias.
+func (c *Config) Hostname(alias string) string {
+	for _, block := range c.hosts {
+		if block.hostname != "" && matchHost(block.patterns, alias) {
+			return strings.ReplaceAll(block.hostname, "%h", alias)
+		}
+	}
+	return alias
+}
+
+// matchHost reports whether host matches the patterns of a Host directive.
+// A negated pattern that matches excludes the host regardless of other patterns.
+func matchHost(patterns []string, host string) bool {
+	host = strings.ToLower(host)
+	matched := false
+	for _, p := range patterns {
+		negated := strings.HasPrefix(p, "!")
+		p = strings.ToLower(strings.TrimPrefix(p, "!"))
+		if matchWildcard(p, host) {
+			if negated {
+				return false
+			}
+			matched = true
+		}
+	}
+	return matched
+}
+
+// matchWildcard matches s against an OpenSSH pattern, in which "*" matches any
+// sequence of characters and "?" matches exactly one character.
+func matchWildcard(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(s); i >= 0; i-- {
+				if matchWildcard(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+		default:
+			if len(s) == 0 || s[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, s = pattern[1:], s[1:]
+	}
+	return len(s) == 0
+}
+
+// splitDirective splits a configuration line into its lowercased keyword and its
+// arguments, which may be separated from the keyword by "=" and may be quoted.
+func splitDirective(line string) (string, []string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", nil
+	}
+	i := strings.IndexAny(line, " \t=")
+	if i < 0 {
+		return strings.ToLower(line), nil
+	}
+	keyword := strings.ToLower(line[:i])
+	rest := strings.TrimLeft(line[i:], " \t")
+	rest = strings.TrimLeft(strings.TrimPrefix(rest, "="), " \t")
+
+	var args []string
+	for rest != "" {
+		var arg string
+		if rest[0] == '"' {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				arg, rest = rest[1:], ""
+			} else {
+				arg, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				arg, rest = rest, ""
+			} else {
+				arg, rest = rest[:end], rest[end:]
+			}
+		}
+		args = append(args, arg)
+		rest = strings.TrimLeft(rest, " \t")
+	}
+	return keyword, args
+}
+
+func includePath(p string) string {
+	home, _ := os.UserHomeDir()
+	if strings.HasPrefix(p, "~/") {
+		return filepath.Join(home, p[2:])
+	}
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(home, ".ssh", p)
+}
+
+// userConfigFile returns the location of the user's OpenSSH client configuration.
+func userConfigFile() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".ssh", "config")
+}
@@ -0,0 +1,106 @@
+package ssh
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	dir := t.TempDir()
+	confD := filepath.Join(dir, "conf.d")
+	assert.NoError(t, os.Mkdir(confD, 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(confD, "work.conf"), []byte(heredoc.Doc(`
+		Host work-github
+			HostName github.com
+			IdentityFile ~/.ssh/work
+	`)), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(confD, "loop.conf"), []byte(heredoc.Docf(`
+		Include %s
+	`, filepath.Join(confD, "loop.conf"))), 0600))
+
+	configPath := filepath.Join(dir, "config")
+	assert.NoError(t, os.WriteFile(configPath, []byte(heredoc.Docf(`
+		# Personal account
+		Include "%s/*.conf"
+
+		Host gh personal-github
+		  User git
+		  Hostname=github.com
+
+		Host ghe-* !ghe-legacy
+		  Hostname %%h.example.com
+
+		Match exec "true"
+		  Hostname matched.example.com
+
+		Host *
+		  Hostname ignored.example.com
+	`, confD)), 0600))
+
+	cfg, err := ParseConfig(configPath)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		alias string
+		want  string
+	}{
+		{alias: "work-github", want: "github.com"},
+		{alias: "gh", want: "github.com"},
+		{alias: "Personal-GitHub", want: "github.com"},
+		{alias: "ghe-acme", want: "ghe-acme.example.com"},
+		{alias: "ghe-legacy", want: "ignored.example.com"},
+		{alias: "github.com", want: "ignored.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			assert.Equal(t, tt.want, cfg.Hostname(tt.alias))
+		})
+	}
+}
+
+func TestParseConfigMissingFile(t *testing.T) {
+	cfg, err := ParseConfig(filepath.Join(t.TempDir(), "config"))
+	assert.NoError(t, err)
+	assert.Equal(t, "github-work", cfg.Hostname("github-work"))
+}
+
+func TestSplitDirective(t *testing.T) {
+	tests := []struct {
+		line        string
+		wantKeyword string
+		wantArgs    []string
+	}{
+		{line: "  # comment", wantKeyword: "", wantArgs: nil},
+		{line: "Host a b", wantKeyword: "host", wantArgs: []string{"a", "b"}},
+		{line: "HostName = github.com", wantKeyword: "hostname", wantArgs: []string{"github.com"}},
+		{line: "Include \"my dir/*.conf\" other", wantKeyword: "include", wantArgs: []string{"my dir/*.conf", "other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			keyword, args := splitDirective(tt.line)
+			assert.Equal(t, tt.wantKeyword, keyword)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestTranslatorWithoutSSH(t *testing.T) {
+	tr := &Translator{
+		lookPath: func(string) (string, error) {
+			return "", errors.New("not found")
+		},
+		readConfig: func() (*Config, error) {
+			return &Config{hosts: []hostBlock{{patterns: []string{"work-github"}, hostname: "github.com"}}}, nil
+		},
+	}
+	u, _ := url.Parse("ssh://git@work-github/org/repo.git")
+	assert.Equal(t, "ssh://git@github.com/org/repo.git", tr.Translate(u).String())
+	u, _ = url.Parse("ssh://git@gitlab.com/org/repo.git")
+	assert.Equal(t, "ssh://git@gitlab.com/org/repo.git", tr.Translate(u).String())
+}
@@ -0,0 +1,229 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/khulnasoft-lab/execsafer"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+)
+
+// ErrKeyAlreadyExists is returned by GenerateKey when a key already exists at the
+// requested location. The existing key is returned alongside the error so it can be reused.
+var ErrKeyAlreadyExists = errors.New("SSH key already exists")
+
+// KeyPair is the location of an SSH private key and its public key.
+type KeyPair struct {
+	PrivateKeyPath string
+	PublicKeyPath  string
+}
+
+// KeyManager generates SSH keys and adds them to the SSH agent by invoking the
+// ssh-keygen and ssh-add programs.
+type KeyManager struct {
+	lookPath   func(string) (string, error)
+	newCommand func(context.Context, string, ...string) *exec.Cmd
+}
+
+// NewKeyManager initializes a new KeyManager instance.
+func NewKeyManager() *KeyManager {
+	return &KeyManager{}
+}
+
+// GenerateKey generates an ed25519 key pair with the private key written to keyPath and
+// the public key to keyPath with a ".pub" extension. The private key is encrypted with the
+// passphrase unless it is empty. The passphrase is written to the standard input of
+// ssh-keygen rather than passed as an argument, where other users could see it in the
+// process list. The directory of keyPath is created if necessary.
+func (m *KeyManager) GenerateKey(keyPath, passphrase, comment string) (*KeyPair, error) {
+	return m.GenerateKeyContext(context.Background(), keyPath, passphrase, comment)
+}
+
+// GenerateKeyContext is like GenerateKey but kills ssh-keygen if the context is done
+// before it exits.
+func (m *KeyManager) GenerateKeyContext(ctx context.Context, keyPath, passphrase, comment string) (*KeyPair, error) {
+	pair := &KeyPair{
+		PrivateKeyPath: keyPath,
+		PublicKeyPath:  keyPath + ".pub",
+	}
+	if _, err := os.Stat(keyPath); err == nil {
+		return pair, ErrKeyAlreadyExists
+	}
+	if strings.ContainsAny(passphrase, "\r\n") {
+		return nil, errors.New("passphrase must not contain line breaks")
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return nil, err
+	}
+	keygenPath, err := m.findExecutable("ssh-keygen")
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"-t", "ed25519", "-C", comment}
+	if passphrase == "" {
+		args = append(args, "-N", "")
+	}
+	cmd := m.command(ctx, keygenPath, append(args, "-f", keyPath)...)
+	if passphrase != "" {
+		// ssh-keygen asks for the passphrase twice and reads the answers from the terminal if
+		// it has one, and otherwise from standard input unless it is configured to use an
+		// askpass program.
+		detachTerminal(cmd)
+		cmd.Stdin = strings.NewReader(passphrase + "\n" + passphrase + "\n")
+		env := cmd.Env
+		if env == nil {
+			env = os.Environ()
+		}
+		cmd.Env = append(withoutEnv(env, "DISPLAY", "SSH_ASKPASS"), "SSH_ASKPASS_REQUIRE=never")
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, commandError("ssh-keygen", err, stderr.String())
+	}
+	return pair, nil
+}
+
+// AddToAgent adds the private key at keyPath to the running SSH agent. If the key is
+// encrypted ssh-add asks for its passphrase on the terminal.
+func (m *KeyManager) AddToAgent(keyPath string) error {
+	return m.AddToAgentContext(context.Background(), keyPath)
+}
+
+// AddToAgentContext is like AddToAgent but kills ssh-add if the context is done before
+// it exits, such as while it waits for the passphrase.
+func (m *KeyManager) AddToAgentContext(ctx context.Context, keyPath string) error {
+	sshAddPath, err := m.findExecutable("ssh-add")
+	if err != nil {
+		return err
+	}
+	cmd := m.command(ctx, sshAddPath, keyPath)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return commandError("ssh-add", err, stderr.String())
+	}
+	return nil
+}
+
+// withoutEnv returns env without the variables with the given names.
+func withoutEnv(env []string, names ...string) []string {
+	result := make([]string, 0, len(env))
+outer:
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, n := range names {
+			if name == n {
+				continue outer
+			}
+		}
+		result = append(result, kv)
+	}
+	return result
+}
+
+// commandError describes the failure of an SSH program, including its error output.
+func commandError(name string, err error, stderr string) error {
+	if stderr = strings.TrimSpace(stderr); stderr != "" {
+		return fmt.Errorf("%s failed: %w: %s", name, err, stderr)
+	}
+	return fmt.Errorf("%s failed: %w", name, err)
+}
+
+func (m *KeyManager) findExecutable(name string) (string, error) {
+	lookPath := m.lookPath
+	if lookPath == nil {
+		lookPath = execsafer.LookPath
+	}
+	return lookPath(name)
+}
+
+func (m *KeyManager) command(ctx context.Context, exe string, args ...string) *exec.Cmd {
+	newCommand := m.newCommand
+	if newCommand == nil {
+		newCommand = exec.CommandContext
+	}
+	return newCommand(ctx, exe, args...)
+}
+
+// RESTClient is the subset of [api.RESTClient] used to list and upload keys.
+type RESTClient interface {
+	Request(method string, path string, body io.Reader) (*http.Response, error)
+	Post(path string, body io.Reader, resp interface{}) error
+}
+
+// userKeys returns the public keys of the authenticated user, following the Link header
+// of the response through every page of keys.
+func userKeys(client RESTClient) ([]string, error) {
+	var keys []string
+	path := "user/keys?per_page=100"
+	for path != "" {
+		resp, err := client.Request("GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page []struct {
+			Key string `json:"key"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range page {
+			keys = append(keys, k.Key)
+		}
+		path = api.NextLink(resp.Header.Get("Link"))
+	}
+	return keys, nil
+}
+
+// UploadKey adds the public key at publicKeyPath to the SSH keys of the authenticated
+// user with the given title. The key is not uploaded again if the user already has it,
+// in which case uploaded is false.
+func UploadKey(client RESTClient, publicKeyPath, title string) (uploaded bool, err error) {
+	data, err := os.ReadFile(publicKeyPath)
+	if err != nil {
+		return false, err
+	}
+	key := strings.TrimSpace(string(data))
+	fields := strings.Fields(key)
+	if len(fields) < 2 {
+		return false, fmt.Errorf("invalid public key: %s", publicKeyPath)
+	}
+
+	existing, err := userKeys(client)
+	if err != nil {
+		return false, err
+	}
+	for _, k := range existing {
+		// Compare the key type and material, ignoring the comment.
+		f := strings.Fields(k)
+		if len(f) >= 2 && f[0] == fields[0] && f[1] == fields[1] {
+			return false, nil
+		}
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"title": title,
+		"key":   key,
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := client.Post("user/keys", bytes.NewReader(body), nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package ssh
+
+import "os/exec"
+
+// detachTerminal is a no-op on systems without controlling terminals.
+func detachTerminal(cmd *exec.Cmd) {}
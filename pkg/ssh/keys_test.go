@@ -0,0 +1,247 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeygenHelperProcess(t *testing.T) {
+	if os.Getenv("GOCTL_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args[3:]
+	if filepath.Base(args[0]) == "ssh-add" {
+		if args[1] == "missing" {
+			_, _ = os.Stderr.WriteString("No such file or directory\n")
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	var keyPath, comment string
+	for i := 1; i < len(args)-1; i++ {
+		switch args[i] {
+		case "-f":
+			keyPath = args[i+1]
+		case "-C":
+			comment = args[i+1]
+		}
+	}
+	// The private key records the passphrase read from standard input for the tests to check.
+	stdin, _ := io.ReadAll(os.Stdin)
+	_ = os.WriteFile(keyPath, append([]byte("PRIVATE KEY\n"), stdin...), 0600)
+	_ = os.WriteFile(keyPath+".pub", []byte("ssh-ed25519 AAAAC3Nza "+comment+"\n"), 0644)
+	os.Exit(0)
+}
+
+func newStubKeyManager(calls *[][]string) *KeyManager {
+	return &KeyManager{
+		lookPath: func(name string) (string, error) {
+			return "/usr/bin/" + name, nil
+		},
+		newCommand: func(ctx context.Context, exe string, args ...string) *exec.Cmd {
+			*calls = append(*calls, append([]string{exe}, args...))
+			args = append([]string{"-test.run=TestKeygenHelperProcess", "--", exe}, args...)
+			c := exec.CommandContext(ctx, os.Args[0], args...)
+			c.Env = []string{"GOCTL_WANT_HELPER_PROCESS=1"}
+			return c
+		},
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	var calls [][]string
+	m := newStubKeyManager(&calls)
+	keyPath := filepath.Join(t.TempDir(), ".ssh", "id_ed25519")
+
+	pair, err := m.GenerateKey(keyPath, "secret", "monalisa@laptop")
+	assert.NoError(t, err)
+	assert.Equal(t, &KeyPair{PrivateKeyPath: keyPath, PublicKeyPath: keyPath + ".pub"}, pair)
+	assert.Equal(t, [][]string{
+		{"/usr/bin/ssh-keygen", "-t", "ed25519", "-C", "monalisa@laptop", "-f", keyPath},
+	}, calls)
+	key, err := os.ReadFile(pair.PrivateKeyPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "PRIVATE KEY\nsecret\nsecret\n", string(key))
+	pub, err := os.ReadFile(pair.PublicKeyPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "ssh-ed25519 AAAAC3Nza monalisa@laptop\n", string(pub))
+
+	pair, err = m.GenerateKey(keyPath, "", "")
+	assert.ErrorIs(t, err, ErrKeyAlreadyExists)
+	assert.Equal(t, keyPath+".pub", pair.PublicKeyPath)
+	assert.Len(t, calls, 1)
+}
+
+func TestGenerateKeyWithoutPassphrase(t *testing.T) {
+	var calls [][]string
+	m := newStubKeyManager(&calls)
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+
+	_, err := m.GenerateKey(keyPath, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"/usr/bin/ssh-keygen", "-t", "ed25519", "-C", "", "-N", "", "-f", keyPath},
+	}, calls)
+
+	_, err = m.GenerateKey(filepath.Join(t.TempDir(), "id_ed25519"), "a\nb", "")
+	assert.EqualError(t, err, "passphrase must not contain line breaks")
+	assert.Len(t, calls, 1)
+}
+
+func TestGenerateKeyContextCancelled(t *testing.T) {
+	var calls [][]string
+	m := newStubKeyManager(&calls)
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := m.GenerateKeyContext(ctx, keyPath, "", "")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NoFileExists(t, keyPath)
+}
+
+func TestGenerateKeyWithSSHKeygen(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not installed")
+	}
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	// ssh-keygen reads the passphrase from the terminal if it has one, so this hangs until
+	// the timeout if it is not detached from the terminal of the test.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pair, err := NewKeyManager().GenerateKeyContext(ctx, keyPath, "secret", "monalisa@laptop")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	pub, err := os.ReadFile(pair.PublicKeyPath)
+	assert.NoError(t, err)
+	derived, err := exec.Command("ssh-keygen", "-y", "-P", "secret", "-f", pair.PrivateKeyPath).Output()
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Fields(string(pub))[:2], strings.Fields(string(derived))[:2])
+	err = exec.Command("ssh-keygen", "-y", "-P", "wrong", "-f", pair.PrivateKeyPath).Run()
+	assert.Error(t, err)
+}
+
+func TestAddToAgent(t *testing.T) {
+	var calls [][]string
+	m := newStubKeyManager(&calls)
+	assert.NoError(t, m.AddToAgent("/home/monalisa/.ssh/id_ed25519"))
+	assert.Equal(t, [][]string{{"/usr/bin/ssh-add", "/home/monalisa/.ssh/id_ed25519"}}, calls)
+
+	err := m.AddToAgent("missing")
+	assert.EqualError(t, err, "ssh-add failed: exit status 1: No such file or directory")
+}
+
+type fakeRESTClient struct {
+	keys []string
+	// perPage splits the keys into pages linked by the Link header, if not zero.
+	perPage  int
+	requests []string
+	posts    []map[string]string
+	err      error
+}
+
+func (c *fakeRESTClient) Request(method string, path string, body io.Reader) (*http.Response, error) {
+	c.requests = append(c.requests, method+" "+path)
+	if c.err != nil {
+		return nil, c.err
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	keys := c.keys
+	header := http.Header{}
+	if c.perPage > 0 {
+		page, _ := strconv.Atoi(u.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		start, end := (page-1)*c.perPage, page*c.perPage
+		if end < len(keys) {
+			header.Set("Link", fmt.Sprintf(`<https://api.github.com/user/keys?per_page=100&page=%d>; rel="next"`, page+1))
+		} else {
+			end = len(keys)
+		}
+		keys = keys[start:end]
+	}
+	page := []map[string]string{}
+	for _, k := range keys {
+		page = append(page, map[string]string{"key": k})
+	}
+	data, _ := json.Marshal(page)
+	return &http.Response{StatusCode: 200, Header: header, Body: io.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func (c *fakeRESTClient) Post(path string, body io.Reader, resp interface{}) error {
+	var payload map[string]string
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return err
+	}
+	c.posts = append(c.posts, payload)
+	return nil
+}
+
+func TestUploadKey(t *testing.T) {
+	pubPath := filepath.Join(t.TempDir(), "id_ed25519.pub")
+	err := os.WriteFile(pubPath, []byte("ssh-ed25519 AAAAC3Nza monalisa@laptop\n"), 0644)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		client       *fakeRESTClient
+		wantUploaded bool
+		wantPosts    []map[string]string
+		wantErr      string
+	}{
+		{
+			name:         "new key",
+			client:       &fakeRESTClient{keys: []string{"ssh-rsa AAAAB3Nza"}},
+			wantUploaded: true,
+			wantPosts: []map[string]string{
+				{"title": "laptop", "key": "ssh-ed25519 AAAAC3Nza monalisa@laptop"},
+			},
+		},
+		{
+			name:   "duplicate key with different comment",
+			client: &fakeRESTClient{keys: []string{"ssh-ed25519 AAAAC3Nza"}},
+		},
+		{
+			name:   "duplicate key on a later page",
+			client: &fakeRESTClient{keys: []string{"ssh-rsa AAAAB3Nza", "ssh-rsa AAAAB3Nzb", "ssh-ed25519 AAAAC3Nza"}, perPage: 2},
+		},
+		{
+			name:    "API error",
+			client:  &fakeRESTClient{err: errors.New("HTTP 401")},
+			wantErr: "HTTP 401",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploaded, err := UploadKey(tt.client, pubPath, "laptop")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantUploaded, uploaded)
+			assert.Equal(t, tt.wantPosts, tt.client.posts)
+		})
+	}
+}
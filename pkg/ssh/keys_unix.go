@@ -0,0 +1,17 @@
+//go:build unix
+
+package ssh
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachTerminal starts cmd in a new session, without a controlling terminal, so that
+// ssh-keygen reads the passphrase from standard input instead of /dev/tty.
+func detachTerminal(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+}
@@ -0,0 +1,215 @@
+package ssh
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HostKey is a public key of an SSH server.
+type HostKey struct {
+	// Host is the hostname the key belongs to, in the "[host]:port" form for non-standard ports.
+	Host string
+	// Type is the key algorithm, such as "ssh-ed25519".
+	Type string
+	// Key is the base64 encoded public key.
+	Key string
+}
+
+// Fingerprint returns the SHA256 fingerprint of the key in the format shown by OpenSSH,
+// e.g. "SHA256:+DiY3wvvV6TuJJhbpZisF/zLDA0zPMSvHdkr4UvCOqU".
+func (k HostKey) Fingerprint() string {
+	blob, err := base64.StdEncoding.DecodeString(k.Key)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(blob)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// String returns the key as a line of a known_hosts file.
+func (k HostKey) String() string {
+	return fmt.Sprintf("%s %s %s", k.Host, k.Type, k.Key)
+}
+
+// KnownHostsFile returns the location of the user's known_hosts file.
+func KnownHostsFile() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".ssh", "known_hosts")
+}
+
+// LookupKnownHost returns the keys recorded for host in the known_hosts file, including
+// keys recorded under hashed host names and wildcard patterns. A missing file has no keys.
+// Keys marked @revoked for host are not returned, even if they are also recorded without
+// the marker, and @cert-authority lines are skipped since they hold certificate authorities
+// rather than host keys.
+func LookupKnownHost(knownHostsFile, host string) ([]HostKey, error) {
+	f, err := os.Open(knownHostsFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var keys []HostKey
+	revoked := map[HostKey]bool{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		marker := ""
+		if strings.HasPrefix(fields[0], "@") {
+			marker, fields = fields[0], fields[1:]
+		}
+		if len(fields) < 3 || !matchKnownHost(fields[0], host) {
+			continue
+		}
+		key := HostKey{Host: host, Type: fields[1], Key: fields[2]}
+		switch marker {
+		case "":
+			keys = append(keys, key)
+		case "@revoked":
+			revoked[key] = true
+		case "@cert-authority":
+			// Certificate authorities sign host keys but are not host keys themselves.
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	valid := keys[:0]
+	for _, k := range keys {
+		if !revoked[k] {
+			valid = append(valid, k)
+		}
+	}
+	if len(valid) == 0 {
+		return nil, nil
+	}
+	return valid, nil
+}
+
+// IsKnownHostKey reports whether the key is recorded for its host in the known_hosts file.
+func IsKnownHostKey(knownHostsFile string, key HostKey) (bool, error) {
+	keys, err := LookupKnownHost(knownHostsFile, key.Host)
+	if err != nil {
+		return false, err
+	}
+	for _, k := range keys {
+		if k.Type == key.Type && k.Key == key.Key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AddKnownHosts appends the keys to the known_hosts file, creating it if necessary.
+// It should only be called with keys the user has accepted, for example after
+// confirming their fingerprints.
+func AddKnownHosts(knownHostsFile string, keys []HostKey) error {
+	if err := os.MkdirAll(filepath.Dir(knownHostsFile), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(knownHostsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, k := range keys {
+		buf.WriteString(k.String())
+		buf.WriteByte('\n')
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ScanHostKeys fetches the public keys of the SSH server at host using ssh-keyscan.
+// The keys are not verified in any way, so their fingerprints should be confirmed by
+// the user before they are trusted.
+func (m *KeyManager) ScanHostKeys(host string) ([]HostKey, error) {
+	return m.ScanHostKeysContext(context.Background(), host)
+}
+
+// ScanHostKeysContext is like ScanHostKeys but kills ssh-keyscan if the context is done
+// before it exits.
+func (m *KeyManager) ScanHostKeysContext(ctx context.Context, host string) ([]HostKey, error) {
+	keyscanPath, err := m.findExecutable("ssh-keyscan")
+	if err != nil {
+		return nil, err
+	}
+	name, args := splitHostPort(host)
+	// The host is passed after "--" so that a host starting with "-" is not taken for an option.
+	args = append(args, "--", name)
+	cmd := m.command(ctx, keyscanPath, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, commandError("ssh-keyscan", err, stderr.String())
+	}
+	var keys []HostKey
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		keys = append(keys, HostKey{Host: host, Type: fields[1], Key: fields[2]})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no SSH host keys found for %s", host)
+	}
+	return keys, nil
+}
+
+// splitHostPort splits a host in the "[name]:port" form of known_hosts into the host
+// name and the arguments that select the port for the SSH programs.
+func splitHostPort(host string) (string, []string) {
+	if strings.HasPrefix(host, "[") {
+		if i := strings.Index(host, "]:"); i > 0 {
+			return host[1:i], []string{"-p", host[i+2:]}
+		}
+	}
+	return host, []string{}
+}
+
+// matchKnownHost reports whether the host field of a known_hosts line matches host.
+func matchKnownHost(field, host string) bool {
+	if strings.HasPrefix(field, "|1|") {
+		return matchHashedHost(field, host)
+	}
+	return matchHost(strings.Split(field, ","), host)
+}
+
+// matchHashedHost matches a host name hashed by "ssh-keygen -H", which has the form
+// "|1|base64(salt)|base64(HMAC-SHA1(salt, host))".
+func matchHashedHost(field, host string) bool {
+	parts := strings.Split(field, "|")
+	if len(parts) != 4 {
+		return false
+	}
+	salt, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(host))
+	return hmac.Equal(mac.Sum(nil), want)
+}
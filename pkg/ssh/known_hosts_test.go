@@ -0,0 +1,145 @@
+package ssh
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+const testHostKey = "AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"
+
+func hashHost(salt []byte, host string) string {
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(host))
+	return "|1|" + base64.StdEncoding.EncodeToString(salt) + "|" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestHostKeyFingerprint(t *testing.T) {
+	k := HostKey{Host: "github.com", Type: "ssh-ed25519", Key: testHostKey}
+	assert.Equal(t, "SHA256:+DiY3wvvV6TuJJhbpZisF/zLDA0zPMSvHdkr4UvCOqU", k.Fingerprint())
+	assert.Equal(t, "github.com ssh-ed25519 "+testHostKey, k.String())
+	assert.Equal(t, "", HostKey{Key: "not base64!"}.Fingerprint())
+}
+
+func TestLookupKnownHost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	content := heredoc.Docf(`
+		# comment
+		github.com,140.82.112.3 ssh-ed25519 %[1]s
+		%[2]s ssh-rsa AAAAB3NzaC1yc2E
+		*.example.com ssh-ed25519 AAAAwildcard
+		@revoked gitlab.com ssh-rsa AAAArevoked
+		gitlab.com ssh-rsa AAAArevoked
+		@cert-authority *.example.com ssh-ed25519 AAAAca
+		[ghe.example.com]:2222 ssh-ed25519 AAAAport
+		@revoked * ssh-ed25519 AAAAport
+	`, testHostKey, hashHost([]byte("0123456789abcdefghij"), "gitlab.com"))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	tests := []struct {
+		host     string
+		wantKeys []string
+	}{
+		{host: "github.com", wantKeys: []string{testHostKey}},
+		{host: "gitlab.com", wantKeys: []string{"AAAAB3NzaC1yc2E"}},
+		{host: "ghe.example.com", wantKeys: []string{"AAAAwildcard"}},
+		{host: "[ghe.example.com]:2222", wantKeys: nil},
+		{host: "bitbucket.org", wantKeys: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			keys, err := LookupKnownHost(path, tt.host)
+			assert.NoError(t, err)
+			var got []string
+			for _, k := range keys {
+				got = append(got, k.Key)
+			}
+			assert.Equal(t, tt.wantKeys, got)
+		})
+	}
+
+	known, err := IsKnownHostKey(path, HostKey{Host: "github.com", Type: "ssh-ed25519", Key: testHostKey})
+	assert.NoError(t, err)
+	assert.True(t, known)
+	known, err = IsKnownHostKey(path, HostKey{Host: "github.com", Type: "ssh-ed25519", Key: "AAAAother"})
+	assert.NoError(t, err)
+	assert.False(t, known)
+	known, err = IsKnownHostKey(path, HostKey{Host: "gitlab.com", Type: "ssh-rsa", Key: "AAAArevoked"})
+	assert.NoError(t, err)
+	assert.False(t, known)
+
+	keys, err := LookupKnownHost(filepath.Join(t.TempDir(), "missing"), "github.com")
+	assert.NoError(t, err)
+	assert.Nil(t, keys)
+}
+
+func TestAddKnownHosts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".ssh", "known_hosts")
+	key := HostKey{Host: "github.com", Type: "ssh-ed25519", Key: testHostKey}
+	assert.NoError(t, AddKnownHosts(path, []HostKey{key}))
+	assert.NoError(t, AddKnownHosts(path, []HostKey{{Host: "gitlab.com", Type: "ssh-rsa", Key: "AAAA"}}))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "github.com ssh-ed25519 "+testHostKey+"\ngitlab.com ssh-rsa AAAA\n", string(data))
+
+	known, err := IsKnownHostKey(path, key)
+	assert.NoError(t, err)
+	assert.True(t, known)
+}
+
+func TestKeyscanHelperProcess(t *testing.T) {
+	if os.Getenv("GOCTL_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args[4:]
+	host := args[len(args)-1]
+	if host == "unreachable" {
+		os.Exit(1)
+	}
+	os.Stdout.WriteString("# " + host + ":22 SSH-2.0-babeld\n")
+	os.Stdout.WriteString(host + " ssh-ed25519 " + testHostKey + "\n")
+	if args[len(args)-2] != "--" {
+		os.Exit(2)
+	}
+	if len(args) == 4 && args[0] == "-p" {
+		os.Stdout.WriteString(host + " ssh-rsa AAAAport" + args[1] + "\n")
+	}
+	os.Exit(0)
+}
+
+func TestScanHostKeys(t *testing.T) {
+	m := &KeyManager{
+		lookPath: func(name string) (string, error) {
+			return "/usr/bin/" + name, nil
+		},
+		newCommand: func(ctx context.Context, exe string, args ...string) *exec.Cmd {
+			args = append([]string{"-test.run=TestKeyscanHelperProcess", "--", exe}, args...)
+			c := exec.CommandContext(ctx, os.Args[0], args...)
+			c.Env = []string{"GOCTL_WANT_HELPER_PROCESS=1"}
+			return c
+		},
+	}
+
+	keys, err := m.ScanHostKeys("github.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []HostKey{{Host: "github.com", Type: "ssh-ed25519", Key: testHostKey}}, keys)
+
+	keys, err = m.ScanHostKeys("[ghe.example.com]:2222")
+	assert.NoError(t, err)
+	assert.Equal(t, []HostKey{
+		{Host: "[ghe.example.com]:2222", Type: "ssh-ed25519", Key: testHostKey},
+		{Host: "[ghe.example.com]:2222", Type: "ssh-rsa", Key: "AAAAport2222"},
+	}, keys)
+
+	_, err = m.ScanHostKeys("unreachable")
+	assert.EqualError(t, err, "ssh-keyscan failed: exit status 1")
+}
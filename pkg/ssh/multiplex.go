@@ -0,0 +1,121 @@
+package ssh
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/internal/git"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+)
+
+const defaultControlPersist = 60 * time.Second
+
+type multiplexOptions struct {
+	controlDir string
+	persist    time.Duration
+}
+
+type multiplexOption func(*multiplexOptions)
+
+// WithControlDir sets the directory holding the control sockets of shared connections.
+// It defaults to a directory in [config.StateDir]. Keep the path short, as the length
+// of socket paths is limited to about 100 bytes on most systems.
+func WithControlDir(dir string) multiplexOption {
+	return func(o *multiplexOptions) {
+		o.controlDir = dir
+	}
+}
+
+// WithControlPersist sets how long a shared connection stays open after the last git
+// command using it has exited. It defaults to 60 seconds.
+func WithControlPersist(d time.Duration) multiplexOption {
+	return func(o *multiplexOptions) {
+		if d > 0 {
+			o.persist = d
+		}
+	}
+}
+
+// MultiplexEnv returns a copy of env, or of the environment of the current process if
+// env is nil, with GIT_SSH_COMMAND set so that git commands run with it share a single
+// SSH connection per host using the ControlMaster and ControlPersist options of OpenSSH.
+// This saves the cost of establishing a connection for each of many sequential git
+// commands that talk to the same remote, such as fetches across several repositories.
+//
+// An SSH command configured with GIT_SSH_COMMAND or the core.sshCommand git setting is
+// kept and given the additional options. The environment is returned unchanged if GIT_SSH
+// is set, since it names a program that cannot be passed options, and on Windows, where
+// OpenSSH does not support connection sharing.
+func MultiplexEnv(env []string, opts ...multiplexOption) ([]string, error) {
+	if env == nil {
+		env = os.Environ()
+	}
+	env = append([]string(nil), env...)
+	if runtime.GOOS == "windows" || lookupEnv(env, "GIT_SSH") != "" {
+		return env, nil
+	}
+
+	o := multiplexOptions{
+		controlDir: filepath.Join(config.StateDir(), "ssh"),
+		persist:    defaultControlPersist,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := os.MkdirAll(o.controlDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create SSH control directory: %w", err)
+	}
+
+	sshCommand := lookupEnv(env, "GIT_SSH_COMMAND")
+	if sshCommand == "" {
+		sshCommand = gitSSHCommand()
+	}
+	if sshCommand == "" {
+		sshCommand = "ssh"
+	}
+	sshCommand = fmt.Sprintf("%s -o ControlMaster=auto -o ControlPath=%s -o ControlPersist=%d",
+		sshCommand, shellQuote(filepath.Join(o.controlDir, "%C")), int(o.persist.Seconds()))
+
+	return setEnv(env, "GIT_SSH_COMMAND", sshCommand), nil
+}
+
+// gitSSHCommand returns the core.sshCommand git setting, if any.
+var gitSSHCommand = func() string {
+	stdout, _, err := git.Exec("config", "--get", "core.sshCommand")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(stdout.String())
+}
+
+func lookupEnv(env []string, key string) string {
+	value := ""
+	for _, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == key {
+			value = v
+		}
+	}
+	return value
+}
+
+func setEnv(env []string, key, value string) []string {
+	result := env[:0]
+	for _, kv := range env {
+		if k, _, _ := strings.Cut(kv, "="); k != key {
+			result = append(result, kv)
+		}
+	}
+	return append(result, key+"="+value)
+}
+
+// shellQuote quotes s for the shell that git runs GIT_SSH_COMMAND with.
+func shellQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\n'\"\\$`!*?[]{}()<>|&;#~") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,98 @@
+package ssh
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiplexEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("connection sharing is not supported on Windows")
+	}
+	dir := t.TempDir()
+	spaceDir := filepath.Join(t.TempDir(), "it's here")
+
+	tests := []struct {
+		name           string
+		env            []string
+		gitSSHCommand  string
+		opts           []multiplexOption
+		wantSSHCommand string
+		wantUnchanged  bool
+	}{
+		{
+			name:           "default command",
+			env:            []string{"HOME=/home/monalisa"},
+			opts:           []multiplexOption{WithControlDir(dir)},
+			wantSSHCommand: "ssh -o ControlMaster=auto -o ControlPath=" + dir + "/%C -o ControlPersist=60",
+		},
+		{
+			name:           "GIT_SSH_COMMAND is extended",
+			env:            []string{"GIT_SSH_COMMAND=ssh -i ~/.ssh/deploy"},
+			opts:           []multiplexOption{WithControlDir(dir), WithControlPersist(5 * time.Minute)},
+			wantSSHCommand: "ssh -i ~/.ssh/deploy -o ControlMaster=auto -o ControlPath=" + dir + "/%C -o ControlPersist=300",
+		},
+		{
+			name:           "core.sshCommand is extended",
+			env:            []string{},
+			gitSSHCommand:  "ssh -p 2222",
+			opts:           []multiplexOption{WithControlDir(dir)},
+			wantSSHCommand: "ssh -p 2222 -o ControlMaster=auto -o ControlPath=" + dir + "/%C -o ControlPersist=60",
+		},
+		{
+			name:           "control directory is quoted",
+			env:            []string{},
+			opts:           []multiplexOption{WithControlDir(spaceDir)},
+			wantSSHCommand: "ssh -o ControlMaster=auto -o ControlPath='" + filepath.Dir(spaceDir) + `/it'\''s here/%C' -o ControlPersist=60`,
+		},
+		{
+			name:          "GIT_SSH is left alone",
+			env:           []string{"GIT_SSH=/usr/bin/plink"},
+			opts:          []multiplexOption{WithControlDir(dir)},
+			wantUnchanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldGitSSHCommand := gitSSHCommand
+			t.Cleanup(func() { gitSSHCommand = oldGitSSHCommand })
+			gitSSHCommand = func() string { return tt.gitSSHCommand }
+
+			env, err := MultiplexEnv(tt.env, tt.opts...)
+			assert.NoError(t, err)
+			if tt.wantUnchanged {
+				assert.Equal(t, tt.env, env)
+				return
+			}
+			assert.Equal(t, tt.wantSSHCommand, lookupEnv(env, "GIT_SSH_COMMAND"))
+			for _, kv := range tt.env {
+				if k, _, _ := strings.Cut(kv, "="); k != "GIT_SSH_COMMAND" {
+					assert.Contains(t, env, kv)
+				}
+			}
+		})
+	}
+}
+
+func TestMultiplexEnvCreatesControlDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("connection sharing is not supported on Windows")
+	}
+	oldGitSSHCommand := gitSSHCommand
+	t.Cleanup(func() { gitSSHCommand = oldGitSSHCommand })
+	gitSSHCommand = func() string { return "" }
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	_, err := MultiplexEnv([]string{})
+	assert.NoError(t, err)
+	info, err := os.Stat(filepath.Join(os.Getenv("XDG_STATE_HOME"), "goctl", "ssh"))
+	assert.NoError(t, err)
+	assert.True(t, info.IsDir())
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+}
@@ -0,0 +1,75 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+)
+
+// Git protocols returned by GitProtocol.
+const (
+	ProtocolHTTPS = "https"
+	ProtocolSSH   = "ssh"
+)
+
+// CanAuthenticate reports whether the user can authenticate to the git SSH server of host
+// with their SSH keys and agent. Host aliases and options from the SSH configuration are
+// applied, and ssh runs in batch mode so that it fails instead of asking for passphrases
+// or confirming unknown host keys.
+func (m *KeyManager) CanAuthenticate(host string) (bool, error) {
+	return m.CanAuthenticateContext(context.Background(), host)
+}
+
+// CanAuthenticateContext is like CanAuthenticate but kills ssh if the context is done
+// before it exits.
+func (m *KeyManager) CanAuthenticateContext(ctx context.Context, host string) (bool, error) {
+	sshPath, err := m.findExecutable("ssh")
+	if err != nil {
+		return false, err
+	}
+	name, args := splitHostPort(host)
+	args = append(args, "-T", "-o", "BatchMode=yes", "-o", "ConnectTimeout=10", "git@"+name)
+	cmd := m.command(ctx, sshPath, args...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err = cmd.Run()
+	// The server greets authenticated users and then exits with a non-zero status
+	// because it provides no shell.
+	if strings.Contains(output.String(), "successfully authenticated") {
+		return true, nil
+	}
+	var exitErr *exec.ExitError
+	if err == nil || (errors.As(err, &exitErr) && strings.Contains(output.String(), "Permission denied")) {
+		return false, nil
+	}
+	return false, commandError("ssh", err, output.String())
+}
+
+// GitProtocol returns the protocol, ProtocolSSH or ProtocolHTTPS, that git should use to
+// clone and push repositories of host. The git_protocol set for the host in cfg takes
+// precedence, followed by the global git_protocol. Without either, ProtocolSSH is
+// returned if the user can authenticate to host over SSH. cfg may be nil.
+func (m *KeyManager) GitProtocol(cfg *config.Config, host string) string {
+	return m.GitProtocolContext(context.Background(), cfg, host)
+}
+
+// GitProtocolContext is like GitProtocol but stops checking SSH authentication when the
+// context is done.
+func (m *KeyManager) GitProtocolContext(ctx context.Context, cfg *config.Config, host string) string {
+	if cfg != nil {
+		for _, keys := range [][]string{{"hosts", host, "git_protocol"}, {"git_protocol"}} {
+			if protocol, err := cfg.Get(keys); err == nil && protocol != "" {
+				return protocol
+			}
+		}
+	}
+	if ok, _ := m.CanAuthenticateContext(ctx, host); ok {
+		return ProtocolSSH
+	}
+	return ProtocolHTTPS
+}
@@ -0,0 +1,121 @@
+package ssh
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSHHelperProcess(t *testing.T) {
+	if os.Getenv("GOCTL_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args[4:]
+	switch args[len(args)-1] {
+	case "git@github.com":
+		os.Stderr.WriteString("Hi monalisa! You've successfully authenticated, but GitHub does not provide shell access.\n")
+		os.Exit(1)
+	case "git@ghe.example.com":
+		if strings.Join(args[:2], " ") != "-p 2222" {
+			os.Exit(2)
+		}
+		os.Stderr.WriteString("Hi hubot! You've successfully authenticated, but GitHub does not provide shell access.\n")
+		os.Exit(1)
+	case "git@nokeys.example.com":
+		os.Stderr.WriteString("git@nokeys.example.com: Permission denied (publickey).\n")
+		os.Exit(255)
+	default:
+		os.Stderr.WriteString("Host key verification failed.\n")
+		os.Exit(255)
+	}
+}
+
+func newSSHKeyManager(calls *int) *KeyManager {
+	return &KeyManager{
+		lookPath: func(name string) (string, error) {
+			return "/usr/bin/" + name, nil
+		},
+		newCommand: func(ctx context.Context, exe string, args ...string) *exec.Cmd {
+			*calls++
+			args = append([]string{"-test.run=TestSSHHelperProcess", "--", exe}, args...)
+			c := exec.CommandContext(ctx, os.Args[0], args...)
+			c.Env = []string{"GOCTL_WANT_HELPER_PROCESS=1"}
+			return c
+		},
+	}
+}
+
+func TestCanAuthenticate(t *testing.T) {
+	tests := []struct {
+		host    string
+		want    bool
+		wantErr string
+	}{
+		{host: "github.com", want: true},
+		{host: "[ghe.example.com]:2222", want: true},
+		{host: "nokeys.example.com", want: false},
+		{host: "unknown.example.com", wantErr: "ssh failed: exit status 255: Host key verification failed."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			var calls int
+			ok, err := newSSHKeyManager(&calls).CanAuthenticate(tt.host)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
+
+func TestGitProtocol(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *config.Config
+		host      string
+		want      string
+		wantCalls int
+	}{
+		{
+			name:      "host config",
+			cfg:       config.ReadFromString("git_protocol: ssh\nhosts:\n  github.com:\n    git_protocol: https\n"),
+			host:      "github.com",
+			want:      ProtocolHTTPS,
+			wantCalls: 0,
+		},
+		{
+			name:      "global config",
+			cfg:       config.ReadFromString("git_protocol: ssh\n"),
+			host:      "nokeys.example.com",
+			want:      ProtocolSSH,
+			wantCalls: 0,
+		},
+		{
+			name:      "SSH authentication works",
+			host:      "github.com",
+			want:      ProtocolSSH,
+			wantCalls: 1,
+		},
+		{
+			name:      "SSH authentication fails",
+			cfg:       config.ReadFromString(""),
+			host:      "nokeys.example.com",
+			want:      ProtocolHTTPS,
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			assert.Equal(t, tt.want, newSSHKeyManager(&calls).GitProtocol(tt.cfg, tt.host))
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
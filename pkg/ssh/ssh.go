@@ -1,4 +1,4 @@
-// Package ssh resolves local SSH hostname aliases.
+// Package ssh resolves local SSH hostname aliases and manages SSH keys.
 package ssh
 
 import (
@@ -20,6 +20,7 @@ type Translator struct {
 
 	lookPath   func(string) (string, error)
 	newCommand func(string, ...string) *exec.Cmd
+	readConfig func() (*Config, error)
 }
 
 // NewTranslator initializes a new Translator instance.
@@ -28,6 +29,8 @@ func NewTranslator() *Translator {
 }
 
 // Translate applies applicable SSH hostname aliases to the specified URL and returns the resulting URL.
+// Aliases are resolved with "ssh -G" when ssh is installed, and otherwise by reading the Host and
+// Hostname directives of ~/.ssh/config and the files it includes.
 func (t *Translator) Translate(u *url.URL) *url.URL {
 	if u.Scheme != "ssh" {
 		return u
@@ -57,13 +60,13 @@ func (t *Translator) resolve(hostname string) (string, error) {
 	if t.sshPath == "" && t.sshPathErr == nil {
 		lookPath := t.lookPath
 		if lookPath == nil {
-			lookPath = safeexec.LookPath
+			lookPath = execsafer.LookPath
 		}
 		t.sshPath, t.sshPathErr = lookPath("ssh")
 	}
 	if t.sshPathErr != nil {
-		defer t.sshPathMu.Unlock()
-		return t.sshPath, t.sshPathErr
+		t.sshPathMu.Unlock()
+		return t.resolveFromConfig(hostname)
 	}
 	sshPath = t.sshPath
 	t.sshPathMu.Unlock()
@@ -107,3 +110,27 @@ func (t *Translator) resolve(hostname string) (string, error) {
 	t.cacheMap[strings.ToLower(hostname)] = resolvedHost
 	return resolvedHost, nil
 }
+
+// resolveFromConfig resolves the hostname by parsing the SSH configuration directly,
+// for systems where the ssh program is not available.
+func (t *Translator) resolveFromConfig(hostname string) (string, error) {
+	readConfig := t.readConfig
+	if readConfig == nil {
+		readConfig = func() (*Config, error) {
+			return ParseConfig(userConfigFile())
+		}
+	}
+	cfg, err := readConfig()
+	if err != nil {
+		return "", err
+	}
+	resolvedHost := cfg.Hostname(hostname)
+
+	t.cacheMu.Lock()
+	defer t.cacheMu.Unlock()
+	if t.cacheMap == nil {
+		t.cacheMap = map[string]string{}
+	}
+	t.cacheMap[strings.ToLower(hostname)] = resolvedHost
+	return resolvedHost, nil
+}
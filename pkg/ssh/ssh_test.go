@@ -13,7 +13,7 @@ import (
 )
 
 func TestTranslator(t *testing.T) {
-	if _, err := safeexec.LookPath("ssh"); err != nil {
+	if _, err := execsafer.LookPath("ssh"); err != nil {
 		t.Skip("no ssh found on system")
 	}
 
@@ -7,12 +7,17 @@ package tableprinter
 import (
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/text"
 )
 
 type fieldOption func(*tableField)
 
+type tableOption func(*rowOptions)
+
 type TablePrinter interface {
 	AddHeader([]string, ...fieldOption)
 	AddField(string, ...fieldOption)
@@ -20,6 +25,22 @@ type TablePrinter interface {
 	Render() error
 }
 
+// TimeFieldAdder is implemented by table printers that can add time fields, which are displayed relative
+// to the current time in terminal mode and sort chronologically. The printers returned by New implement it.
+type TimeFieldAdder interface {
+	AddTimeField(now, t time.Time, c func(string) string)
+}
+
+// AddTimeField adds a field with the time t to tp. If tp implements TimeFieldAdder, the field is added with
+// its AddTimeField method. Otherwise it is added with AddField, formatted as RFC 3339 and colored with c.
+func AddTimeField(tp TablePrinter, now, t time.Time, c func(string) string) {
+	if adder, ok := tp.(TimeFieldAdder); ok {
+		adder.AddTimeField(now, t, c)
+		return
+	}
+	tp.AddField(t.Format(time.RFC3339), WithColor(c))
+}
+
 // WithTruncate overrides the truncation function for the field. The function should transform a string
 // argument into a string that fits within the given display width. The default behavior is to truncate the
 // value by adding "..." in the end. The truncation function will be called before padding and coloring.
@@ -49,20 +70,112 @@ func WithColor(fn func(string) string) fieldOption {
 	}
 }
 
+// WithSortBy sorts rows by the values in the given zero-based column before rendering. Values that are
+// numbers are compared numerically, times added with [AddTimeField] are compared chronologically, and all
+// other values are compared as strings. Rows with equal values keep the order in which they were added.
+// Headers are never sorted.
+func WithSortBy(column int, descending bool) tableOption {
+	return func(o *rowOptions) {
+		o.sortColumn = column
+		o.sortDescending = descending
+	}
+}
+
+// WithFilter omits rows for which fn returns false. The function receives the uncolored text of each
+// field in the row. Headers are never filtered.
+func WithFilter(fn func([]string) bool) tableOption {
+	return func(o *rowOptions) {
+		o.filter = fn
+	}
+}
+
 // New initializes a table printer with terminal mode and terminal width. When terminal mode is enabled, the
 // output will be human-readable, column-formatted to fit available width, and rendered with color support.
 // In non-terminal mode, the output is tab-separated and all truncation of values is disabled.
-func New(w io.Writer, isTTY bool, maxWidth int) TablePrinter {
+// When sorting or filtering is requested, non-terminal output is buffered until Render is called.
+func New(w io.Writer, isTTY bool, maxWidth int, opts ...tableOption) TablePrinter {
+	o := rowOptions{sortColumn: -1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	if isTTY {
 		return &ttyTablePrinter{
 			out:      w,
 			maxWidth: maxWidth,
+			opts:     o,
 		}
 	}
 
 	return &tsvTablePrinter{
-		out: w,
+		out:  w,
+		opts: o,
+	}
+}
+
+type rowOptions struct {
+	sortColumn     int
+	sortDescending bool
+	filter         func([]string) bool
+}
+
+func (o rowOptions) enabled() bool {
+	return o.sortColumn >= 0 || o.filter != nil
+}
+
+// apply returns rows with the filter and sort order applied.
+func (o rowOptions) apply(rows [][]tableField) [][]tableField {
+	if o.filter != nil {
+		filtered := make([][]tableField, 0, len(rows))
+		for _, row := range rows {
+			if len(row) == 0 {
+				filtered = append(filtered, row)
+				continue
+			}
+			values := make([]string, len(row))
+			for i, f := range row {
+				values[i] = f.text
+			}
+			if o.filter(values) {
+				filtered = append(filtered, row)
+			}
+		}
+		rows = filtered
+	}
+	if o.sortColumn >= 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			a, b := sortValue(rows[i], o.sortColumn), sortValue(rows[j], o.sortColumn)
+			if o.sortDescending {
+				return lessValue(b, a)
+			}
+			return lessValue(a, b)
+		})
 	}
+	return rows
+}
+
+func sortValue(row []tableField, col int) string {
+	if col >= len(row) {
+		return ""
+	}
+	if row[col].sortKey != "" {
+		return row[col].sortKey
+	}
+	return row[col].text
+}
+
+func lessValue(a, b string) bool {
+	na, errA := strconv.ParseFloat(a, 64)
+	nb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return na < nb
+	}
+	return a < b
+}
+
+// timeSortKey formats t so that keys compare chronologically as strings.
+func timeSortKey(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000000Z")
 }
 
 type tableField struct {
@@ -70,6 +183,7 @@ type tableField struct {
 	truncateFunc func(int, string) string
 	paddingFunc  func(int, string) string
 	colorFunc    func(string) string
+	sortKey      string
 }
 
 type ttyTablePrinter struct {
@@ -77,6 +191,7 @@ type ttyTablePrinter struct {
 	maxWidth   int
 	hasHeaders bool
 	rows       [][]tableField
+	opts       rowOptions
 }
 
 func (t *ttyTablePrinter) AddHeader(columns []string, opts ...fieldOption) {
@@ -106,6 +221,17 @@ func (t *ttyTablePrinter) AddField(s string, opts ...fieldOption) {
 	t.rows[rowI] = append(t.rows[rowI], field)
 }
 
+// AddTimeField adds a field with the time t relative to now, such as "about 3 hours ago".
+func (t *ttyTablePrinter) AddTimeField(now, ts time.Time, c func(string) string) {
+	var opts []fieldOption
+	if c != nil {
+		opts = append(opts, WithColor(c))
+	}
+	t.AddField(text.RelativeTime(ts, now), opts...)
+	row := t.rows[len(t.rows)-1]
+	row[len(row)-1].sortKey = timeSortKey(ts)
+}
+
 func (t *ttyTablePrinter) EndRow() {
 	t.rows = append(t.rows, []tableField{})
 }
@@ -115,6 +241,21 @@ func (t *ttyTablePrinter) Render() error {
 		return nil
 	}
 
+	if t.opts.enabled() {
+		start := 0
+		if t.hasHeaders {
+			start = 1
+		}
+		end := len(t.rows)
+		if len(t.rows[end-1]) == 0 {
+			end--
+		}
+		if start < end {
+			body := t.opts.apply(append([][]tableField{}, t.rows[start:end]...))
+			t.rows = append(append(t.rows[:start:start], body...), t.rows[end:]...)
+		}
+	}
+
 	delim := "  "
 	numCols := len(t.rows[0])
 	colWidths := t.calculateColumnWidths(len(delim))
@@ -237,11 +378,17 @@ func (t *ttyTablePrinter) calculateColumnWidths(delimSize int) []int {
 type tsvTablePrinter struct {
 	out        io.Writer
 	currentCol int
+	opts       rowOptions
+	rows       [][]tableField
 }
 
 func (t *tsvTablePrinter) AddHeader(_ []string, _ ...fieldOption) {}
 
 func (t *tsvTablePrinter) AddField(text string, _ ...fieldOption) {
+	if t.opts.enabled() {
+		t.bufferField(tableField{text: text})
+		return
+	}
 	if t.currentCol > 0 {
 		fmt.Fprint(t.out, "\t")
 	}
@@ -249,11 +396,65 @@ func (t *tsvTablePrinter) AddField(text string, _ ...fieldOption) {
 	t.currentCol++
 }
 
+// AddTimeField adds a field with the time t formatted as RFC 3339 so that scripts can parse it.
+func (t *tsvTablePrinter) AddTimeField(_, ts time.Time, _ func(string) string) {
+	if t.opts.enabled() {
+		t.bufferField(tableField{text: text.FormatTime(ts), sortKey: timeSortKey(ts)})
+		return
+	}
+	t.AddField(text.FormatTime(ts))
+}
+
+func (t *tsvTablePrinter) bufferField(f tableField) {
+	if t.rows == nil {
+		t.rows = make([][]tableField, 1)
+	}
+	t.rows[len(t.rows)-1] = append(t.rows[len(t.rows)-1], f)
+}
+
 func (t *tsvTablePrinter) EndRow() {
+	if t.opts.enabled() {
+		if t.rows == nil {
+			t.rows = make([][]tableField, 1)
+		}
+		t.rows = append(t.rows, []tableField{})
+		return
+	}
 	fmt.Fprint(t.out, "\n")
 	t.currentCol = 0
 }
 
 func (t *tsvTablePrinter) Render() error {
+	if !t.opts.enabled() || len(t.rows) == 0 {
+		return nil
+	}
+
+	// The last row has not been ended, so it is written as is without a trailing newline.
+	pending := t.rows[len(t.rows)-1]
+	rows := t.opts.apply(t.rows[:len(t.rows)-1])
+	t.rows = nil
+
+	for _, row := range rows {
+		if err := t.writeFields(row); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprint(t.out, "\n"); err != nil {
+			return err
+		}
+	}
+	return t.writeFields(pending)
+}
+
+func (t *tsvTablePrinter) writeFields(fields []tableField) error {
+	for col, field := range fields {
+		if col > 0 {
+			if _, err := fmt.Fprint(t.out, "\t"); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprint(t.out, field.text); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 )
@@ -184,3 +186,192 @@ func Test_tsvTablePrinter_AddHeader(t *testing.T) {
 		t.Errorf("expected: %q, got: %q", expected, buf.String())
 	}
 }
+
+func Test_ttyTablePrinter_AddTimeField(t *testing.T) {
+	buf := bytes.Buffer{}
+	tp := New(&buf, true, 80)
+
+	now := time.Date(2020, time.November, 22, 14, 0, 0, 0, time.UTC)
+	tp.AddField("created")
+	AddTimeField(tp, now, now.Add(-3*time.Hour), nil)
+	tp.EndRow()
+	tp.AddField("due")
+	AddTimeField(tp, now, now.Add(49*time.Hour), func(s string) string { return "<" + s + ">" })
+	tp.EndRow()
+
+	err := tp.Render()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "created  about 3 hours ago\ndue      <in about 2 days>\n"
+	if buf.String() != expected {
+		t.Errorf("expected: %q, got: %q", expected, buf.String())
+	}
+}
+
+type basicPrinter struct {
+	fields []string
+}
+
+func (p *basicPrinter) AddHeader([]string, ...fieldOption) {}
+
+func (p *basicPrinter) AddField(s string, opts ...fieldOption) {
+	f := tableField{text: s}
+	for _, opt := range opts {
+		opt(&f)
+	}
+	if f.colorFunc != nil {
+		s = f.colorFunc(s)
+	}
+	p.fields = append(p.fields, s)
+}
+
+func (p *basicPrinter) EndRow()       {}
+func (p *basicPrinter) Render() error { return nil }
+
+func Test_AddTimeField_fallback(t *testing.T) {
+	tp := &basicPrinter{}
+	now := time.Date(2020, time.November, 22, 14, 0, 0, 0, time.UTC)
+	AddTimeField(tp, now, now.Add(-3*time.Hour), nil)
+	AddTimeField(tp, now, now.Add(-3*time.Hour), func(s string) string { return "<" + s + ">" })
+
+	expected := []string{"2020-11-22T11:00:00Z", "<2020-11-22T11:00:00Z>"}
+	if !reflect.DeepEqual(tp.fields, expected) {
+		t.Errorf("expected: %q, got: %q", expected, tp.fields)
+	}
+}
+
+func Test_tsvTablePrinter_AddTimeField(t *testing.T) {
+	buf := bytes.Buffer{}
+	tp := New(&buf, false, 0)
+
+	now := time.Date(2020, time.November, 22, 14, 0, 0, 0, time.UTC)
+	tp.AddField("created")
+	AddTimeField(tp, now, now.Add(-3*time.Hour), func(s string) string { return "<" + s + ">" })
+	tp.EndRow()
+
+	err := tp.Render()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "created\t2020-11-22T11:00:00Z\n"
+	if buf.String() != expected {
+		t.Errorf("expected: %q, got: %q", expected, buf.String())
+	}
+}
+
+func Test_ttyTablePrinter_WithSortBy(t *testing.T) {
+	buf := bytes.Buffer{}
+	tp := New(&buf, true, 80, WithSortBy(1, true))
+
+	tp.AddHeader([]string{"NAME", "STARS"})
+	tp.AddField("cli")
+	tp.AddField("9")
+	tp.EndRow()
+	tp.AddField("goctl")
+	tp.AddField("120")
+	tp.EndRow()
+	tp.AddField("browser")
+	tp.AddField("15")
+	tp.EndRow()
+
+	err := tp.Render()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := heredoc.Doc(`
+		NAME     STARS
+		goctl    120
+		browser  15
+		cli      9
+	`)
+	if buf.String() != expected {
+		t.Errorf("expected: %q, got: %q", expected, buf.String())
+	}
+}
+
+func Test_ttyTablePrinter_WithSortBy_time(t *testing.T) {
+	buf := bytes.Buffer{}
+	tp := New(&buf, true, 80, WithSortBy(1, false))
+
+	now := time.Date(2020, time.November, 22, 14, 0, 0, 0, time.UTC)
+	tp.AddField("recent")
+	AddTimeField(tp, now, now.Add(-5*time.Minute), nil)
+	tp.EndRow()
+	tp.AddField("old")
+	AddTimeField(tp, now, now.Add(-48*time.Hour), nil)
+	tp.EndRow()
+
+	err := tp.Render()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "old     about 2 days ago\nrecent  about 5 minutes ago\n"
+	if buf.String() != expected {
+		t.Errorf("expected: %q, got: %q", expected, buf.String())
+	}
+}
+
+func Test_ttyTablePrinter_WithFilter(t *testing.T) {
+	buf := bytes.Buffer{}
+	tp := New(&buf, true, 80, WithFilter(func(row []string) bool {
+		return row[1] == "open"
+	}))
+
+	tp.AddHeader([]string{"NUMBER", "STATE"})
+	tp.AddField("1")
+	tp.AddField("open")
+	tp.EndRow()
+	tp.AddField("2")
+	tp.AddField("closed")
+	tp.EndRow()
+	tp.AddField("3")
+	tp.AddField("open")
+	tp.EndRow()
+
+	err := tp.Render()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "NUMBER  STATE\n1       open\n3       open\n"
+	if buf.String() != expected {
+		t.Errorf("expected: %q, got: %q", expected, buf.String())
+	}
+}
+
+func Test_tsvTablePrinter_WithSortByAndFilter(t *testing.T) {
+	buf := bytes.Buffer{}
+	tp := New(&buf, false, 0, WithSortBy(0, false), WithFilter(func(row []string) bool {
+		return row[0] != "beta"
+	}))
+
+	tp.AddHeader([]string{"NAME", "STATE"})
+	tp.AddField("gamma")
+	tp.AddField("3")
+	tp.EndRow()
+	tp.AddField("beta")
+	tp.AddField("2")
+	tp.EndRow()
+	tp.AddField("alpha")
+	tp.AddField("1")
+	tp.EndRow()
+
+	if buf.Len() > 0 {
+		t.Fatalf("expected output to be buffered until Render, got: %q", buf.String())
+	}
+
+	err := tp.Render()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "alpha\t1\ngamma\t3\n"
+	if buf.String() != expected {
+		t.Errorf("expected: %q, got: %q", expected, buf.String())
+	}
+}
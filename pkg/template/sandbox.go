@@ -0,0 +1,276 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// ErrTimeout is returned when a sandboxed template does not finish executing in time.
+var ErrTimeout = errors.New("template execution timed out")
+
+// ErrOutputLimit is returned when a sandboxed template produces more output than allowed.
+var ErrOutputLimit = errors.New("template output limit exceeded")
+
+// ErrIterationLimit is returned when a sandboxed template runs more range loop iterations than allowed.
+var ErrIterationLimit = errors.New("template iteration limit exceeded")
+
+// Functions that are not available to sandboxed templates. The table functions accumulate
+// rows in memory outside of the output limit, and hyperlink emits terminal escape sequences.
+var sandboxedFuncs = []string{"hyperlink", "tablerender", "tablerow"}
+
+// stepFunc is called at the start of each iteration of a range loop in sandboxed templates.
+const stepFunc = "__sandboxStep"
+
+// maxFormatWidth is the largest width or precision sandboxed templates can pass to printf.
+const maxFormatWidth = 1000
+
+var formatVerbRE = regexp.MustCompile(`%[-+# 0]*(?:\[\d+\])?(\*|\d*)(?:\.(?:\[\d+\])?(\*|\d*))?`)
+
+// SandboxOptions bounds the resources a sandboxed template may use.
+type SandboxOptions struct {
+	// Timeout is the maximum duration of a single execution. Zero means no limit.
+	Timeout time.Duration
+	// MaxOutputBytes is the maximum size of the output of a single execution. It also bounds
+	// the size of the strings built with print, printf and println. Zero means no limit.
+	MaxOutputBytes int
+	// MaxIterations is the maximum number of range loop iterations, counted across all loops,
+	// of a single execution. Zero means no limit.
+	MaxIterations int
+}
+
+// Sandbox restricts the template so that templates from untrusted sources can be evaluated safely.
+// Functions added with Funcs are ignored and the table and hyperlink functions are unavailable, so
+// templates can only transform the data they are executed with. Templates may not define or invoke
+// other templates, and printf rejects widths and precisions larger than 1000. Each execution is
+// bounded by the given options and its output is only written once it has completed successfully.
+//
+// The limits are enforced while the template executes: range loops are counted as they iterate and
+// output is counted as it is written. When the timeout expires, ExecuteData returns immediately and
+// the execution stops at its next loop iteration, write or print function call. A single call to a
+// function that is already running, such as join over a large list, cannot be interrupted and runs
+// to completion in the background.
+// It must be called before the template is parsed.
+// The return value is the template, so calls can be chained.
+func (t *Template) Sandbox(opts SandboxOptions) *Template {
+	t.sandbox = &opts
+	return t
+}
+
+// prepareSandboxed rejects templates that define or invoke other templates, which could recurse
+// indefinitely, and inserts a call to stepFunc at the start of every range loop body so that
+// loops can be counted and cancelled while the template executes.
+func prepareSandboxed(tmpl *template.Template) error {
+	if len(tmpl.Templates()) > 1 {
+		return errors.New("template: sandboxed templates cannot define templates")
+	}
+	return prepareSandboxedNode(tmpl.Tree, tmpl.Tree.Root)
+}
+
+func prepareSandboxedNode(tree *parse.Tree, node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Nodes {
+			if err := prepareSandboxedNode(tree, c); err != nil {
+				return err
+			}
+		}
+	case *parse.TemplateNode:
+		return newParseError(fmt.Errorf("template: :%d: sandboxed templates cannot invoke templates", n.Line))
+	case *parse.RangeNode:
+		if err := prepareSandboxedBranch(tree, &n.BranchNode); err != nil {
+			return err
+		}
+		if n.List == nil {
+			n.List = &parse.ListNode{NodeType: parse.NodeList, Pos: n.Pos}
+		}
+		step := &parse.ActionNode{
+			NodeType: parse.NodeAction,
+			Pos:      n.Pos,
+			Line:     n.Line,
+			Pipe: &parse.PipeNode{
+				NodeType: parse.NodePipe,
+				Pos:      n.Pos,
+				Line:     n.Line,
+				Cmds: []*parse.CommandNode{{
+					NodeType: parse.NodeCommand,
+					Pos:      n.Pos,
+					Args:     []parse.Node{parse.NewIdentifier(stepFunc).SetTree(tree).SetPos(n.Pos)},
+				}},
+			},
+		}
+		n.List.Nodes = append([]parse.Node{step}, n.List.Nodes...)
+	case *parse.IfNode:
+		return prepareSandboxedBranch(tree, &n.BranchNode)
+	case *parse.WithNode:
+		return prepareSandboxedBranch(tree, &n.BranchNode)
+	}
+	return nil
+}
+
+func prepareSandboxedBranch(tree *parse.Tree, n *parse.BranchNode) error {
+	if err := prepareSandboxedNode(tree, n.List); err != nil {
+		return err
+	}
+	return prepareSandboxedNode(tree, n.ElseList)
+}
+
+func (t *Template) executeSandboxed(data interface{}) error {
+	run := &sandboxRun{opts: t.sandbox}
+	lw := &limitedWriter{limit: t.sandbox.MaxOutputBytes}
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	tmpl.Funcs(run.funcs())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tmpl.Execute(lw, data)
+	}()
+
+	var timeout <-chan time.Time
+	if t.sandbox.Timeout > 0 {
+		timer := time.NewTimer(t.sandbox.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			for _, sentinel := range []error{ErrOutputLimit, ErrIterationLimit} {
+				if errors.Is(err, sentinel) {
+					return sentinel
+				}
+			}
+			return err
+		}
+	case <-timeout:
+		// The execution fails at its next loop iteration, write or print function call.
+		run.cancelled.Store(true)
+		lw.close()
+		return ErrTimeout
+	}
+
+	_, err = t.output.Write(lw.buf.Bytes())
+	return err
+}
+
+// sandboxRun holds the state of a single execution of a sandboxed template.
+type sandboxRun struct {
+	opts       *SandboxOptions
+	cancelled  atomic.Bool
+	iterations int
+}
+
+func (r *sandboxRun) funcs() template.FuncMap {
+	return template.FuncMap{
+		stepFunc:  r.step,
+		"print":   r.print,
+		"printf":  r.printf,
+		"println": r.println,
+	}
+}
+
+func (r *sandboxRun) step() (string, error) {
+	if r.cancelled.Load() {
+		return "", ErrTimeout
+	}
+	r.iterations++
+	if r.opts.MaxIterations > 0 && r.iterations > r.opts.MaxIterations {
+		return "", ErrIterationLimit
+	}
+	return "", nil
+}
+
+func (r *sandboxRun) print(args ...interface{}) (string, error) {
+	if err := r.checkArgs(args); err != nil {
+		return "", err
+	}
+	return fmt.Sprint(args...), nil
+}
+
+func (r *sandboxRun) println(args ...interface{}) (string, error) {
+	if err := r.checkArgs(args); err != nil {
+		return "", err
+	}
+	return fmt.Sprintln(args...), nil
+}
+
+func (r *sandboxRun) printf(format string, args ...interface{}) (string, error) {
+	for _, m := range formatVerbRE.FindAllStringSubmatch(format, -1) {
+		for _, width := range m[1:] {
+			if width == "" {
+				continue
+			}
+			if n, err := strconv.Atoi(width); err != nil || n > maxFormatWidth {
+				return "", fmt.Errorf("printf: width or precision %q exceeds %d", width, maxFormatWidth)
+			}
+		}
+	}
+	if err := r.checkArgs(append([]interface{}{format}, args...)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(format, args...), nil
+}
+
+// checkArgs stops print functions from building strings that could never be written, such as
+// by repeatedly doubling a variable, before they are allocated.
+func (r *sandboxRun) checkArgs(args []interface{}) error {
+	if r.cancelled.Load() {
+		return ErrTimeout
+	}
+	if r.opts.MaxOutputBytes <= 0 {
+		return nil
+	}
+	size := 0
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			size += len(v)
+		case []byte:
+			size += len(v)
+		}
+	}
+	if size > r.opts.MaxOutputBytes {
+		return ErrOutputLimit
+	}
+	return nil
+}
+
+// limitedWriter buffers output up to a limit.
+type limitedWriter struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	limit  int
+	closed bool
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrTimeout
+	}
+	if w.limit > 0 && w.buf.Len()+len(p) > w.limit {
+		return 0, ErrOutputLimit
+	}
+	return w.buf.Write(p)
+}
+
+func (w *limitedWriter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+}
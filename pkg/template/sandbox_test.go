@@ -0,0 +1,181 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandbox(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     SandboxOptions
+		template string
+		data     interface{}
+		wantW    string
+		wantErr  error
+	}{
+		{
+			name:     "allowed functions",
+			template: `{{.title | truncate 8}} {{join ", " .labels}}`,
+			data: map[string]interface{}{
+				"title":  "a long title",
+				"labels": []interface{}{"bug", "ui"},
+			},
+			wantW: "a lon... bug, ui",
+		},
+		{
+			name:     "output limit",
+			opts:     SandboxOptions{MaxOutputBytes: 10},
+			template: `{{range .}}{{.}}{{end}}`,
+			data:     []interface{}{"12345", "67890", "x"},
+			wantErr:  ErrOutputLimit,
+		},
+		{
+			name:     "output within limit",
+			opts:     SandboxOptions{MaxOutputBytes: 10},
+			template: `{{range .}}{{.}}{{end}}`,
+			data:     []interface{}{"12345", "67890"},
+			wantW:    "1234567890",
+		},
+		{
+			name: "timeout",
+			opts: SandboxOptions{Timeout: time.Millisecond},
+			// Nested ranges keep the template busy without producing output.
+			template: `{{range .}}{{range $}}{{range $}}{{end}}{{end}}{{end}}`,
+			data:     make([]interface{}, 100),
+			wantErr:  ErrTimeout,
+		},
+		{
+			name:     "timeout ranging over number",
+			opts:     SandboxOptions{Timeout: time.Millisecond},
+			template: `{{with .}}{{range 1000000000000}}{{end}}{{end}}`,
+			data:     true,
+			wantErr:  ErrTimeout,
+		},
+		{
+			name:     "iteration limit",
+			opts:     SandboxOptions{MaxIterations: 1000},
+			template: `{{range (len (printf "%0999d" 1))}}{{range $}}{{end}}{{end}}`,
+			data:     make([]interface{}, 2),
+			wantErr:  ErrIterationLimit,
+		},
+		{
+			name:     "within iteration limit",
+			opts:     SandboxOptions{MaxIterations: 6},
+			template: `{{range .}}{{range $}}{{end}}{{end}}ok`,
+			data:     make([]interface{}, 2),
+			wantW:    "ok",
+		},
+		{
+			name:     "print output limit",
+			opts:     SandboxOptions{MaxOutputBytes: 1000},
+			template: `{{$s := "0123456789"}}{{range .}}{{$s = printf "%s%s" $s $s}}{{end}}`,
+			data:     make([]interface{}, 40),
+			wantErr:  ErrOutputLimit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			tmpl := New(w, 80, false).Sandbox(tt.opts)
+			assert.NoError(t, tmpl.Parse(tt.template))
+			err := tmpl.ExecuteData(tt.data)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, "", w.String())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantW, w.String())
+		})
+	}
+}
+
+func TestSandboxRestrictsFunctions(t *testing.T) {
+	for _, fn := range []string{"tablerow", "tablerender", "hyperlink", "custom"} {
+		t.Run(fn, func(t *testing.T) {
+			tmpl := New(&bytes.Buffer{}, 80, false)
+			tmpl.Funcs(map[string]interface{}{
+				"custom": strings.ToUpper,
+			})
+			tmpl.Sandbox(SandboxOptions{})
+			err := tmpl.Parse(fmt.Sprintf(`{{%s}}`, fn))
+			assert.EqualError(t, err, fmt.Sprintf(`template: :1: function %q not defined`, fn))
+		})
+	}
+}
+
+func TestSandboxRejectsUnboundedTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{
+			name:     "define",
+			template: `{{define "x"}}x{{end}}`,
+			wantErr:  "template: sandboxed templates cannot define templates",
+		},
+		{
+			name:     "template",
+			template: "{{if .}}\n{{template \"x\"}}{{end}}",
+			wantErr:  "template: :2: sandboxed templates cannot invoke templates",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := New(&bytes.Buffer{}, 80, false).Sandbox(SandboxOptions{})
+			assert.EqualError(t, tmpl.Parse(tt.template), tt.wantErr)
+		})
+	}
+}
+
+func TestSandboxPrintfWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantW    string
+		wantErr  string
+	}{
+		{
+			name:     "allowed width",
+			template: `{{printf "%05.1f|%-4s|%%|%[1]x" 1.25 "a"}}`,
+			wantW:    "001.2|a   |%|0x1.4p+00",
+		},
+		{
+			name:     "width too large",
+			template: `{{printf "%0999999999d" 1}}`,
+			wantErr:  `printf: width or precision "999999999" exceeds 1000`,
+		},
+		{
+			name:     "precision too large",
+			template: `{{printf "%.1001f" 1.0}}`,
+			wantErr:  `printf: width or precision "1001" exceeds 1000`,
+		},
+		{
+			name:     "width from argument",
+			template: `{{printf "%*d" 999999999 1}}`,
+			wantErr:  `printf: width or precision "*" exceeds 1000`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			tmpl := New(w, 80, false).Sandbox(SandboxOptions{})
+			assert.NoError(t, tmpl.Parse(tt.template))
+			err := tmpl.ExecuteData(nil)
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantW, w.String())
+		})
+	}
+}
@@ -8,11 +8,13 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"regexp"
 	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/tableprinter"
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/text"
 	color "github.com/mgutz/ansi"
@@ -22,6 +24,44 @@ const (
 	ellipsis = "..."
 )
 
+var errorPositionRE = regexp.MustCompile(`^template: [^:]*:(\d+)(?::(\d+))?: `)
+
+// ParseError is returned when a template fails to parse. It reports the position of
+// the problem within the template so that it can be pointed out to the user.
+type ParseError struct {
+	// Line is the 1-based line number of the problem.
+	Line int
+	// Column is the 1-based column of the problem, or 0 if it is not known.
+	Column int
+	// Message describes the problem without the position prefix.
+	Message string
+
+	err error
+}
+
+func (e *ParseError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.err
+}
+
+func newParseError(err error) error {
+	m := errorPositionRE.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(m[1])
+	column, _ := strconv.Atoi(m[2])
+	return &ParseError{
+		Line:    line,
+		Column:  column,
+		Message: strings.TrimPrefix(err.Error(), m[0]),
+		err:     err,
+	}
+}
+
 // Template is the representation of a template.
 type Template struct {
 	colorEnabled bool
@@ -30,6 +70,8 @@ type Template struct {
 	tp           tableprinter.TablePrinter
 	width        int
 	funcs        template.FuncMap
+	sandbox      *SandboxOptions
+	clock        clock.Clock
 }
 
 // New initializes a Template.
@@ -40,9 +82,17 @@ func New(w io.Writer, width int, colorEnabled bool) *Template {
 		tp:           tableprinter.New(w, true, width),
 		width:        width,
 		funcs:        template.FuncMap{},
+		clock:        clock.Real,
 	}
 }
 
+// Clock sets the clock that the timeago function measures time against. It must be called
+// before the template is parsed. The return value is the template, so calls can be chained.
+func (t *Template) Clock(c clock.Clock) *Template {
+	t.clock = c
+	return t
+}
+
 // Funcs adds the elements of the argument map to the template's function map.
 // It must be called before the template is parsed.
 // It is legal to overwrite elements of the map including default functions.
@@ -54,15 +104,34 @@ func (t *Template) Funcs(funcMap map[string]interface{}) *Template {
 	return t
 }
 
-// Parse the given template string for use with Execute.
+// Parse the given template string for use with Execute. The parsed template can be executed
+// any number of times. If the template is invalid the error will be a *ParseError.
 func (t *Template) Parse(tmpl string) error {
-	now := time.Now()
+	parsed, err := t.parse(tmpl)
+	if err != nil {
+		return err
+	}
+	t.tmpl = parsed
+	return nil
+}
+
+// Validate checks that the given template string parses using the template's functions,
+// without replacing the template used by Execute. If the template is invalid the error
+// will be a *ParseError.
+func (t *Template) Validate(tmpl string) error {
+	_, err := t.parse(tmpl)
+	return err
+}
+
+func (t *Template) parse(tmpl string) (*template.Template, error) {
+	c := t.clock
 	templateFuncs := map[string]interface{}{
 		"autocolor": colorFunc,
 		"color":     colorFunc,
 		"hyperlink": hyperlinkFunc,
 		"join":      joinFunc,
 		"pluck":     pluckFunc,
+		"pluralize": pluralizeFunc,
 		"tablerender": func() (string, error) {
 			// After rendering a table, prepare a new table printer incase user wants to output
 			// another table.
@@ -75,7 +144,9 @@ func (t *Template) Parse(tmpl string) error {
 			return tableRowFunc(t.tp, fields...)
 		},
 		"timeago": func(input string) (string, error) {
-			return timeAgoFunc(now, input)
+			// The time is read on each call, so that a template executed repeatedly by a
+			// long-running program does not measure against the time it was parsed.
+			return timeAgoFunc(c.Now(), input)
 		},
 		"timefmt":  timeFormatFunc,
 		"truncate": truncateFunc,
@@ -83,12 +154,29 @@ func (t *Template) Parse(tmpl string) error {
 	if !t.colorEnabled {
 		templateFuncs["autocolor"] = autoColorFunc
 	}
-	for name, f := range t.funcs {
-		templateFuncs[name] = f
+	if t.sandbox != nil {
+		for _, name := range sandboxedFuncs {
+			delete(templateFuncs, name)
+		}
+		// Replaced with the functions of each execution, see executeSandboxed.
+		for name, f := range (&sandboxRun{opts: t.sandbox}).funcs() {
+			templateFuncs[name] = f
+		}
+	} else {
+		for name, f := range t.funcs {
+			templateFuncs[name] = f
+		}
 	}
-	var err error
-	t.tmpl, err = template.New("").Funcs(templateFuncs).Parse(tmpl)
-	return err
+	parsed, err := template.New("").Funcs(templateFuncs).Parse(tmpl)
+	if err != nil {
+		return nil, newParseError(err)
+	}
+	if t.sandbox != nil {
+		if err := prepareSandboxed(parsed); err != nil {
+			return nil, err
+		}
+	}
+	return parsed, nil
 }
 
 // Execute applies the parsed template to the input and writes result to the writer
@@ -104,6 +192,19 @@ func (t *Template) Execute(input io.Reader) error {
 		return err
 	}
 
+	return t.ExecuteData(data)
+}
+
+// ExecuteData applies the parsed template to already decoded data, such as a single
+// row of a larger result set, and writes the result to the writer the template was
+// initialized with. It can be called repeatedly without re-parsing the template.
+func (t *Template) ExecuteData(data interface{}) error {
+	if t.tmpl == nil {
+		return fmt.Errorf("template has not been parsed")
+	}
+	if t.sandbox != nil {
+		return t.executeSandboxed(data)
+	}
 	return t.tmpl.Execute(t.output, data)
 }
 
@@ -147,8 +248,20 @@ func joinFunc(sep string, input []interface{}) (string, error) {
 	return strings.Join(results, sep), nil
 }
 
+func pluralizeFunc(count interface{}, thing string) (string, error) {
+	switch v := count.(type) {
+	case int:
+		return text.Pluralize(v, thing), nil
+	case float64:
+		return text.Pluralize(int(v), thing), nil
+	case []interface{}:
+		return text.Pluralize(len(v), thing), nil
+	}
+	return "", fmt.Errorf("invalid value; expected number or list, got %T", count)
+}
+
 func timeFormatFunc(format, input string) (string, error) {
-	t, err := time.Parse(time.RFC3339, input)
+	t, err := text.ParseTime(input)
 	if err != nil {
 		return "", err
 	}
@@ -156,10 +269,13 @@ func timeFormatFunc(format, input string) (string, error) {
 }
 
 func timeAgoFunc(now time.Time, input string) (string, error) {
-	t, err := time.Parse(time.RFC3339, input)
+	t, err := text.ParseTime(input)
 	if err != nil {
 		return "", err
 	}
+	if t.After(now) {
+		return text.RelativeTime(t, now), nil
+	}
 	return timeAgo(now.Sub(t)), nil
 }
 
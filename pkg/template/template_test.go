@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
 	"github.com/khulnasoft-lab/go-goctl/v2/pkg/text"
 	"github.com/stretchr/testify/assert"
 )
@@ -187,6 +188,30 @@ func TestExecute(t *testing.T) {
 			},
 			wantW: "5 minutes ago",
 		},
+		{
+			name: "timeago in the future",
+			args: args{
+				json:     strings.NewReader(fmt.Sprintf(`{"due_on":"%s"}`, time.Now().Add(3*time.Hour+time.Minute).Format(time.RFC3339))),
+				template: `{{.due_on | timeago}}`,
+			},
+			wantW: "in about 3 hours",
+		},
+		{
+			name: "timeago less than a minute in the future",
+			args: args{
+				json:     strings.NewReader(fmt.Sprintf(`{"due_on":"%s"}`, time.Now().Add(30*time.Second).Format(time.RFC3339))),
+				template: `{{.due_on | timeago}}`,
+			},
+			wantW: "in less than a minute",
+		},
+		{
+			name: "timefmt with git timestamp",
+			args: args{
+				json:     strings.NewReader(`{"date":"2008-02-25 21:18:33 +0100"}`),
+				template: `{{.date | timefmt "2006-01-02"}}`,
+			},
+			wantW: "2008-02-25",
+		},
 		{
 			name: "pluck",
 			args: args{
@@ -377,6 +402,22 @@ func TestExecute(t *testing.T) {
 			},
 			wantW: "\x1b]8;;https://github.com\x1b\\GitHub\x1b]8;;\x1b\\",
 		},
+		{
+			name: "pluralize",
+			args: args{
+				json:     strings.NewReader(`{"comments":{"totalCount":1},"reviews":{"totalCount":3},"labels":[]}`),
+				template: `{{pluralize .comments.totalCount "comment"}}, {{pluralize .reviews.totalCount "review"}}, {{pluralize .labels "label"}}, {{pluralize 2 "issue"}}`,
+			},
+			wantW: "1 comment, 3 reviews, 0 labels, 2 issues",
+		},
+		{
+			name: "pluralize invalid value",
+			args: args{
+				json:     strings.NewReader(`{"title":"bug"}`),
+				template: `{{pluralize .title "issue"}}`,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -509,3 +550,92 @@ func TestFuncs(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "trunc \x1b[0;32mopen\x1b[0m test", w.String())
 }
+
+func TestClock(t *testing.T) {
+	w := &bytes.Buffer{}
+	fakeClock := clock.NewFake(time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC))
+	tmpl := New(w, 80, false).Clock(fakeClock)
+
+	err := tmpl.Parse(`{{ .created_at | timeago }}`)
+	assert.NoError(t, err)
+	err = tmpl.Execute(strings.NewReader(`{"created_at":"2023-11-01T09:00:00Z"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "3 hours ago", w.String())
+
+	w.Reset()
+	fakeClock.Advance(24 * time.Hour)
+	err = tmpl.Execute(strings.NewReader(`{"created_at":"2023-11-01T09:00:00Z"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "1 day ago", w.String())
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		wantLine   int
+		wantColumn int
+		wantMsg    string
+	}{
+		{
+			name:     "valid",
+			template: `{{range .}}{{.name | truncate 10}}{{end}}`,
+		},
+		{
+			name:     "undefined function",
+			template: "{{.name}}\n{{.title | shout}}",
+			wantLine: 2,
+			wantMsg:  `function "shout" not defined`,
+		},
+		{
+			name:     "unclosed action",
+			template: "{{range .}}\n\n{{.name}}",
+			wantLine: 3,
+			wantMsg:  "unexpected EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := New(&bytes.Buffer{}, 80, false)
+			err := tmpl.Validate(tt.template)
+			if tt.wantMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var parseErr *ParseError
+			assert.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, tt.wantLine, parseErr.Line)
+			assert.Equal(t, tt.wantColumn, parseErr.Column)
+			assert.Equal(t, tt.wantMsg, parseErr.Message)
+		})
+	}
+}
+
+func TestValidateWithFuncs(t *testing.T) {
+	tmpl := New(&bytes.Buffer{}, 80, false)
+	tmpl.Funcs(map[string]interface{}{
+		"shout": strings.ToUpper,
+	})
+	assert.NoError(t, tmpl.Validate(`{{.title | shout}}`))
+}
+
+func TestExecuteData(t *testing.T) {
+	w := &bytes.Buffer{}
+	tmpl := New(w, 80, false)
+	err := tmpl.Parse(`{{.number}}: {{.title}}{{"\n"}}`)
+	assert.NoError(t, err)
+
+	rows := []interface{}{
+		map[string]interface{}{"number": 1, "title": "one"},
+		map[string]interface{}{"number": 2, "title": "two"},
+	}
+	for _, row := range rows {
+		assert.NoError(t, tmpl.ExecuteData(row))
+	}
+	assert.Equal(t, "1: one\n2: two\n", w.String())
+}
+
+func TestExecuteDataNotParsed(t *testing.T) {
+	tmpl := New(&bytes.Buffer{}, 80, false)
+	assert.EqualError(t, tmpl.ExecuteData(nil), "template has not been parsed")
+}
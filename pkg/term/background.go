@@ -0,0 +1,83 @@
+package term
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// backgroundQueryTimeout bounds how long to wait for the terminal to report its background
+// color, since some terminals never answer the query.
+const backgroundQueryTimeout = 500 * time.Millisecond
+
+// HasDarkBackground reports whether the terminal appears to use a dark background. The
+// COLORFGBG environment variable set by some terminals is consulted first; otherwise the
+// terminal is queried for its background color with an OSC 11 escape sequence. If neither
+// method succeeds within a short timeout, a dark background is assumed.
+func (t Term) HasDarkBackground() bool {
+	if dark, ok := colorFGBGIsDark(os.Getenv("COLORFGBG")); ok {
+		return dark
+	}
+	if dark, ok := queryDarkBackground(t.out, backgroundQueryTimeout); ok {
+		return dark
+	}
+	return true
+}
+
+// colorFGBGIsDark interprets the COLORFGBG environment variable, formatted as "fg;bg" or
+// "fg;default;bg", where colors are ANSI color numbers.
+func colorFGBGIsDark(value string) (bool, bool) {
+	parts := strings.Split(value, ";")
+	if len(parts) < 2 {
+		return false, false
+	}
+	bg, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil || bg < 0 || bg > 15 {
+		return false, false
+	}
+	// Of the 16 standard colors only white and bright white are light backgrounds.
+	return bg != 7 && bg != 15, true
+}
+
+// backgroundQuery asks the terminal for its background color with OSC 11. It is followed
+// by a primary device attributes query, which terminals answer whether or not they support
+// OSC 11, so that the end of the reply is known without waiting for the timeout.
+const backgroundQuery = "\x1b]11;?\x07\x1b[c"
+
+// replyComplete reports whether reply contains the answer to the device attributes query,
+// such as "\x1b[?62;22c", which terminals send after the answer to the OSC 11 query.
+func replyComplete(reply []byte) bool {
+	i := bytes.Index(reply, []byte("\x1b[?"))
+	return i >= 0 && bytes.IndexByte(reply[i:], 'c') >= 0
+}
+
+// parseBackgroundReply interprets the answer to an OSC 11 query, such as
+// "\x1b]11;rgb:ffff/ffff/ffff\x07", and reports whether the color it contains is dark.
+func parseBackgroundReply(reply string) (bool, bool) {
+	_, color, ok := strings.Cut(reply, "]11;rgb:")
+	if !ok {
+		return false, false
+	}
+	if i := strings.IndexAny(color, "\x07\x1b"); i >= 0 {
+		color = color[:i]
+	}
+	parts := strings.Split(color, "/")
+	if len(parts) != 3 {
+		return false, false
+	}
+	var rgb [3]float64
+	for i, part := range parts {
+		if len(part) == 0 || len(part) > 4 {
+			return false, false
+		}
+		v, err := strconv.ParseUint(part, 16, 16)
+		if err != nil {
+			return false, false
+		}
+		rgb[i] = float64(v) / float64(uint64(1)<<(4*len(part))-1)
+	}
+	luminance := 0.2126*rgb[0] + 0.7152*rgb[1] + 0.0722*rgb[2]
+	return luminance < 0.5, true
+}
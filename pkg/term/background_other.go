@@ -0,0 +1,14 @@
+//go:build !unix
+
+package term
+
+import (
+	"os"
+	"time"
+)
+
+// queryDarkBackground is not supported on this platform, where the terminal cannot be
+// polled for the reply without leaving a read blocked on it.
+func queryDarkBackground(f *os.File, timeout time.Duration) (bool, bool) {
+	return false, false
+}
@@ -0,0 +1,80 @@
+package term
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorFGBGIsDark(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantDark bool
+		wantOK   bool
+	}{
+		{value: "", wantOK: false},
+		{value: "15", wantOK: false},
+		{value: "15;0", wantDark: true, wantOK: true},
+		{value: "0;15", wantDark: false, wantOK: true},
+		{value: "0;7", wantDark: false, wantOK: true},
+		{value: "7;default;0", wantDark: true, wantOK: true},
+		{value: "0;default", wantOK: false},
+		{value: "0;255", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			dark, ok := colorFGBGIsDark(tt.value)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantDark, dark)
+		})
+	}
+}
+
+func TestHasDarkBackground(t *testing.T) {
+	t.Setenv("COLORFGBG", "0;15")
+	assert.False(t, Term{}.HasDarkBackground())
+	t.Setenv("COLORFGBG", "15;0")
+	assert.True(t, Term{}.HasDarkBackground())
+	// Without a hint or a terminal to query, a dark background is assumed.
+	t.Setenv("COLORFGBG", "")
+	assert.True(t, Term{}.HasDarkBackground())
+}
+
+func TestQueryDarkBackgroundNotTerminal(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	assert.NoError(t, err)
+	defer f.Close()
+	_, ok := queryDarkBackground(f, time.Second)
+	assert.False(t, ok)
+}
+
+func TestParseBackgroundReply(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    string
+		wantDark bool
+		wantOK   bool
+	}{
+		{name: "white", reply: "\x1b]11;rgb:ffff/ffff/ffff\x07\x1b[?62;22c", wantDark: false, wantOK: true},
+		{name: "black with ST", reply: "\x1b]11;rgb:0000/0000/0000\x1b\\\x1b[?62c", wantDark: true, wantOK: true},
+		{name: "short components", reply: "\x1b]11;rgb:28/2c/34\x07", wantDark: true, wantOK: true},
+		{name: "light yellow", reply: "\x1b]11;rgb:fdf6/e3e3/d0d0\x07", wantDark: false, wantOK: true},
+		{name: "unsupported", reply: "\x1b[?62;22c", wantOK: false},
+		{name: "malformed", reply: "\x1b]11;rgb:ffff/ffff\x07", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dark, ok := parseBackgroundReply(tt.reply)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantDark, dark)
+		})
+	}
+}
+
+func TestTheme(t *testing.T) {
+	t.Setenv("COLORFGBG", "0;15")
+	assert.Equal(t, "light", Term{colorEnabled: true}.Theme())
+	assert.Equal(t, "none", Term{colorEnabled: false}.Theme())
+}
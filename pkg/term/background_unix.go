@@ -0,0 +1,67 @@
+//go:build unix
+
+package term
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+// queryDarkBackground queries the terminal connected to f for its background color. The
+// terminal is put in raw mode so that the reply is not echoed, and is only read while data is
+// available, so that no read is left blocking on the terminal once raw mode is restored.
+func queryDarkBackground(f *os.File, timeout time.Duration) (bool, bool) {
+	if f == nil || !IsTerminal(f) {
+		return false, false
+	}
+	fd := int(f.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return false, false
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+	if _, err := f.WriteString(backgroundQuery); err != nil {
+		return false, false
+	}
+	reply, err := readReply(fd, timeout)
+	if err != nil {
+		return false, false
+	}
+	return parseBackgroundReply(string(reply))
+}
+
+// readReply reads from fd until the reply to backgroundQuery is complete, waiting for data
+// with poll so that it gives up once the timeout expires.
+func readReply(fd int, timeout time.Duration) ([]byte, error) {
+	deadline := time.Now().Add(timeout)
+	var reply []byte
+	buf := make([]byte, 64)
+	for !replyComplete(reply) {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, errors.New("timed out waiting for the terminal")
+		}
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, int(remaining.Milliseconds())+1)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		} else if err != nil {
+			return nil, err
+		} else if n == 0 {
+			continue
+		}
+		n, err = unix.Read(fd, buf)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, errors.New("terminal closed")
+		}
+		reply = append(reply, buf[:n]...)
+	}
+	return reply, nil
+}
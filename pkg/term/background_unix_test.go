@@ -0,0 +1,33 @@
+//go:build unix
+
+package term
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadReply(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+
+	_, err = w.WriteString("\x1b]11;rgb:ffff/ffff/ffff\x07\x1b[?62;22c")
+	require.NoError(t, err)
+	reply, err := readReply(int(r.Fd()), time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "\x1b]11;rgb:ffff/ffff/ffff\x07\x1b[?62;22c", string(reply))
+
+	// Nothing is read once the timeout expires, so no read is left blocking.
+	_, err = w.WriteString("\x1b]11;rgb:ffff/ffff/ffff\x07")
+	require.NoError(t, err)
+	start := time.Now()
+	_, err = readReply(int(r.Fd()), 50*time.Millisecond)
+	assert.EqualError(t, err, "timed out waiting for the terminal")
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -8,8 +8,18 @@ import (
 	"os"
 )
 
+// errVirtualTerminalUnsupported is returned on platforms where terminals interpret ANSI
+// escape sequences natively and there is nothing to enable.
+var errVirtualTerminalUnsupported = errors.New("not implemented")
+
 func enableVirtualTerminalProcessing(f *os.File) error {
-	return errors.New("not implemented")
+	return errVirtualTerminalUnsupported
+}
+
+// isUnicodeConsole reports whether f is a console that displays Unicode. The second return
+// value is false when this cannot be determined, in which case the locale is consulted.
+func isUnicodeConsole(f *os.File) (bool, bool) {
+	return false, false
 }
 
 func openTTY() (*os.File, error) {
@@ -4,17 +4,50 @@
 package term
 
 import (
+	"errors"
 	"os"
 
 	"golang.org/x/sys/windows"
 )
 
+// errVirtualTerminalUnsupported is never returned on Windows, where consoles have to opt in to
+// interpreting ANSI escape sequences.
+var errVirtualTerminalUnsupported = errors.New("not implemented")
+
+// enableVirtualTerminalProcessing makes the console attached to f interpret ANSI escape sequences.
+// This fails on versions of Windows older than Windows 10, and succeeds without changes in
+// pseudo consoles such as Windows Terminal where processing is already enabled.
 func enableVirtualTerminalProcessing(f *os.File) error {
-	stdout := windows.Handle(f.Fd())
+	handle := windows.Handle(f.Fd())
 
 	var originalMode uint32
-	windows.GetConsoleMode(stdout, &originalMode)
-	return windows.SetConsoleMode(stdout, originalMode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING)
+	if err := windows.GetConsoleMode(handle, &originalMode); err != nil {
+		return err
+	}
+	if originalMode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return nil
+	}
+	return windows.SetConsoleMode(handle, originalMode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING)
+}
+
+const utf8CodePage = 65001
+
+var procGetConsoleOutputCP = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetConsoleOutputCP")
+
+// isUnicodeConsole reports whether f is a console that displays Unicode. Windows Terminal
+// always does; the legacy console host only does when its output code page is UTF-8.
+func isUnicodeConsole(f *os.File) (bool, bool) {
+	if !IsTerminal(f) {
+		return false, false
+	}
+	if os.Getenv("WT_SESSION") != "" {
+		return true, true
+	}
+	if err := procGetConsoleOutputCP.Find(); err != nil {
+		return false, false
+	}
+	cp, _, _ := procGetConsoleOutputCP.Call()
+	return cp == utf8CodePage, true
 }
 
 func openTTY() (*os.File, error) {
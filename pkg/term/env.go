@@ -3,12 +3,12 @@
 package term
 
 import (
+	"errors"
 	"io"
 	"os"
 	"strconv"
 	"strings"
 
-	"github.com/muesli/termenv"
 	"golang.org/x/term"
 )
 
@@ -21,10 +21,28 @@ type Term struct {
 	colorEnabled bool
 	is256enabled bool
 	hasTrueColor bool
+	isUTF8       bool
 	width        int
 	widthPercent int
 }
 
+// Terminal is the method set of Term. Code that accepts a Terminal rather than a Term can
+// be tested with a fake terminal, such as the FakeTerm of the goctltest package.
+type Terminal interface {
+	In() io.Reader
+	Out() io.Writer
+	ErrOut() io.Writer
+	IsTerminalOutput() bool
+	IsColorEnabled() bool
+	Is256ColorSupported() bool
+	IsTrueColorSupported() bool
+	IsUTF8() bool
+	Size() (int, int, error)
+	Theme() string
+}
+
+var _ Terminal = Term{}
+
 // FromEnv initializes a Term from [os.Stdout] and environment variables:
 //   - GOCTL_FORCE_TTY
 //   - NO_COLOR
@@ -32,6 +50,7 @@ type Term struct {
 //   - CLICOLOR_FORCE
 //   - TERM
 //   - COLORTERM
+//   - LC_ALL, LC_CTYPE, and LANG
 func FromEnv() Term {
 	var stdoutIsTTY bool
 	var isColorEnabled bool
@@ -56,9 +75,11 @@ func FromEnv() Term {
 	}
 
 	isVirtualTerminal := false
-	if stdoutIsTTY {
-		if err := enableVirtualTerminalProcessing(os.Stdout); err == nil {
-			isVirtualTerminal = true
+	// A forced TTY may not be a console at all, in which case there is nothing to enable.
+	if stdoutIsTTY && IsTerminal(os.Stdout) {
+		isVirtualTerminal, isColorEnabled = virtualTerminalColor(enableVirtualTerminalProcessing(os.Stdout), isColorEnabled)
+		if IsTerminal(os.Stderr) {
+			_ = enableVirtualTerminalProcessing(os.Stderr)
 		}
 	}
 
@@ -70,6 +91,7 @@ func FromEnv() Term {
 		colorEnabled: isColorEnabled,
 		is256enabled: isVirtualTerminal || is256ColorSupported(),
 		hasTrueColor: isVirtualTerminal || isTrueColorSupported(),
+		isUTF8:       isUTF8Supported(os.Stdout),
 		width:        termWidthOverride,
 		widthPercent: termWidthPercentage,
 	}
@@ -111,6 +133,13 @@ func (t Term) IsTrueColorSupported() bool {
 	return t.hasTrueColor
 }
 
+// IsUTF8 reports whether the terminal can be expected to display characters outside of ASCII,
+// such as box-drawing characters, emoji, and spinner glyphs. When it returns false, output should
+// fall back to plain ASCII.
+func (t Term) IsUTF8() bool {
+	return t.isUTF8
+}
+
 // Size returns the width and height of the terminal that the current process is attached to.
 // In case of errors, the numeric values returned are -1.
 func (t Term) Size() (int, int, error) {
@@ -137,16 +166,30 @@ func (t Term) Size() (int, int, error) {
 }
 
 // Theme returns the theme of the terminal by analyzing the background color of the terminal.
+// See HasDarkBackground for how the background color is detected.
 func (t Term) Theme() string {
 	if !t.IsColorEnabled() {
 		return "none"
 	}
-	if termenv.HasDarkBackground() {
+	if t.HasDarkBackground() {
 		return "dark"
 	}
 	return "light"
 }
 
+// virtualTerminalColor interprets the result of enabling virtual terminal processing. When
+// a console could not be switched to interpreting ANSI escape sequences, they would be printed
+// verbatim, so color is disabled unless explicitly forced.
+func virtualTerminalColor(err error, colorEnabled bool) (isVirtualTerminal bool, isColorEnabled bool) {
+	if err == nil {
+		return true, colorEnabled
+	}
+	if errors.Is(err, errVirtualTerminalUnsupported) {
+		return false, colorEnabled
+	}
+	return false, colorEnabled && IsColorForced()
+}
+
 // IsTerminal reports whether a file descriptor is connected to a terminal.
 func IsTerminal(f *os.File) bool {
 	return term.IsTerminal(int(f.Fd()))
@@ -167,6 +210,25 @@ func IsColorForced() bool {
 	return os.Getenv("CLICOLOR_FORCE") != "" && os.Getenv("CLICOLOR_FORCE") != "0"
 }
 
+func isUTF8Supported(f *os.File) bool {
+	if ok, known := isUnicodeConsole(f); known {
+		return ok
+	}
+	return isUTF8Locale()
+}
+
+// isUTF8Locale inspects the locale environment variables in order of precedence. When no
+// locale is configured, UTF-8 is assumed since it is the default on modern systems.
+func isUTF8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return true
+}
+
 func is256ColorSupported() bool {
 	return isTrueColorSupported() ||
 		strings.Contains(os.Getenv("TERM"), "256") ||
@@ -3,6 +3,7 @@
 package term
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -112,3 +113,112 @@ func TestFromEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestIsUTF8Locale(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{
+			name: "unset",
+			env:  map[string]string{"LC_ALL": "", "LC_CTYPE": "", "LANG": ""},
+			want: true,
+		},
+		{
+			name: "UTF-8 language",
+			env:  map[string]string{"LC_ALL": "", "LC_CTYPE": "", "LANG": "en_US.UTF-8"},
+			want: true,
+		},
+		{
+			name: "utf8 spelling",
+			env:  map[string]string{"LC_ALL": "", "LC_CTYPE": "", "LANG": "de_DE.utf8"},
+			want: true,
+		},
+		{
+			name: "C locale",
+			env:  map[string]string{"LC_ALL": "", "LC_CTYPE": "", "LANG": "C"},
+			want: false,
+		},
+		{
+			name: "LC_ALL takes precedence",
+			env:  map[string]string{"LC_ALL": "POSIX", "LC_CTYPE": "", "LANG": "en_US.UTF-8"},
+			want: false,
+		},
+		{
+			name: "LC_CTYPE takes precedence over LANG",
+			env:  map[string]string{"LC_ALL": "", "LC_CTYPE": "en_US.UTF-8", "LANG": "en_US.ISO-8859-1"},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			if got := isUTF8Locale(); got != tt.want {
+				t.Errorf("expected UTF-8 %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestVirtualTerminalColor(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		colorEnabled bool
+		forceColor   string
+		wantVT       bool
+		wantColor    bool
+	}{
+		{
+			name:         "enabled",
+			colorEnabled: true,
+			wantVT:       true,
+			wantColor:    true,
+		},
+		{
+			name:         "not needed on this platform",
+			err:          errVirtualTerminalUnsupported,
+			colorEnabled: true,
+			wantVT:       false,
+			wantColor:    true,
+		},
+		{
+			name:         "failed",
+			err:          errors.New("the handle is invalid"),
+			colorEnabled: true,
+			wantVT:       false,
+			wantColor:    false,
+		},
+		{
+			name:         "failed with forced color",
+			err:          errors.New("the handle is invalid"),
+			colorEnabled: true,
+			forceColor:   "1",
+			wantVT:       false,
+			wantColor:    true,
+		},
+		{
+			name:         "failed with color disabled",
+			err:          errors.New("the handle is invalid"),
+			colorEnabled: false,
+			forceColor:   "1",
+			wantVT:       false,
+			wantColor:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CLICOLOR_FORCE", tt.forceColor)
+			gotVT, gotColor := virtualTerminalColor(tt.err, tt.colorEnabled)
+			if gotVT != tt.wantVT {
+				t.Errorf("expected virtual terminal %v, got %v", tt.wantVT, gotVT)
+			}
+			if gotColor != tt.wantColor {
+				t.Errorf("expected color %v, got %v", tt.wantColor, gotColor)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package term
+
+import "context"
+
+// Dimensions is the width and height of a terminal in columns and rows.
+type Dimensions struct {
+	Width  int
+	Height int
+}
+
+// NotifyResize returns a channel that receives the new dimensions of the terminal every time it is
+// resized, so that live displays can re-render to fit. Only the latest dimensions are kept if the
+// receiver falls behind. The channel is closed once ctx is done.
+func (t Term) NotifyResize(ctx context.Context) <-chan Dimensions {
+	return notifyResize(ctx, t.Size, watchResize)
+}
+
+func notifyResize(ctx context.Context, size func() (int, int, error), watch func(context.Context, func())) <-chan Dimensions {
+	ch := make(chan Dimensions, 1)
+	var last Dimensions
+	if w, h, err := size(); err == nil {
+		last = Dimensions{Width: w, Height: h}
+	}
+	check := func() {
+		w, h, err := size()
+		if err != nil {
+			return
+		}
+		d := Dimensions{Width: w, Height: h}
+		if d == last {
+			return
+		}
+		last = d
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- d
+	}
+	go func() {
+		defer close(ch)
+		watch(ctx, check)
+	}()
+	return ch
+}
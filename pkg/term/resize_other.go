@@ -0,0 +1,25 @@
+//go:build !unix
+
+package term
+
+import (
+	"context"
+	"time"
+)
+
+// There is no resize signal on this platform, so the terminal size is polled instead.
+const resizePollInterval = 250 * time.Millisecond
+
+// watchResize calls fn periodically until ctx is done.
+func watchResize(ctx context.Context, fn func()) {
+	ticker := time.NewTicker(resizePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fn()
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package term
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyResize(t *testing.T) {
+	width := 80
+	size := func() (int, int, error) {
+		return width, 24, nil
+	}
+	resize := make(chan int)
+	handled := make(chan struct{})
+	watch := func(ctx context.Context, fn func()) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case w := <-resize:
+				width = w
+				fn()
+				handled <- struct{}{}
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := notifyResize(ctx, size, watch)
+	setWidth := func(w int) {
+		resize <- w
+		<-handled
+	}
+
+	// Unchanged dimensions are not reported.
+	setWidth(80)
+	setWidth(100)
+	assert.Equal(t, Dimensions{Width: 100, Height: 24}, <-ch)
+
+	// Only the latest dimensions are kept.
+	setWidth(120)
+	setWidth(60)
+	setWidth(70)
+	assert.Equal(t, Dimensions{Width: 70, Height: 24}, <-ch)
+
+	cancel()
+	_, ok := <-ch
+	assert.False(t, ok)
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package term
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchResize calls fn every time the process receives SIGWINCH until ctx is done.
+func watchResize(ctx context.Context, fn func()) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGWINCH)
+	defer signal.Stop(sig)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+			fn()
+		}
+	}
+}
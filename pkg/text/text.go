@@ -5,11 +5,18 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
-	"github.com/muesli/reflow/ansi"
-	"github.com/muesli/reflow/truncate"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/i18n"
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/wrap"
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
@@ -22,6 +29,9 @@ const (
 
 var indentRE = regexp.MustCompile(`(?m)^`)
 
+// ansiRE matches CSI sequences, such as colors, and OSC sequences, such as hyperlinks.
+var ansiRE = regexp.MustCompile(`^\x1b(?:\[[0-9;?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\))`)
+
 // Indent returns a copy of the string s with indent prefixed to it, will apply indent
 // to each line of the string.
 func Indent(s, indent string) string {
@@ -32,8 +42,30 @@ func Indent(s, indent string) string {
 }
 
 // DisplayWidth calculates what the rendered width of string s will be.
+// ANSI escape sequences are ignored, East Asian wide characters count as two cells,
+// and grapheme clusters such as emoji with skin tone modifiers or joined emoji
+// sequences count as a single glyph.
 func DisplayWidth(s string) int {
-	return ansi.PrintableRuneWidth(s)
+	width := 0
+	for len(s) > 0 {
+		if loc := ansiRE.FindStringIndex(s); loc != nil {
+			s = s[loc[1]:]
+			continue
+		}
+		var cluster string
+		cluster, s, _, _ = uniseg.FirstGraphemeClusterInString(s, -1)
+		width += clusterWidth(cluster)
+	}
+	return width
+}
+
+// clusterWidth returns the number of terminal columns a single grapheme cluster occupies.
+// Flags are pairs of regional indicators which runewidth measures one rune at a time.
+func clusterWidth(cluster string) int {
+	if r, _ := utf8.DecodeRuneInString(cluster); r >= 0x1F1E6 && r <= 0x1F1FF {
+		return 2
+	}
+	return runewidth.StringWidth(cluster)
 }
 
 // Truncate returns a copy of the string s that has been shortened to fit the maximum display width.
@@ -46,13 +78,57 @@ func Truncate(maxWidth int, s string) string {
 	if maxWidth >= minWidthForEllipsis {
 		tail = ellipsis
 	}
-	r := truncate.StringWithTail(s, uint(maxWidth), tail)
+	r := truncateWithTail(s, maxWidth, tail)
 	if DisplayWidth(r) < maxWidth {
 		r += " "
 	}
 	return r
 }
 
+// truncateWithTail cuts s at a grapheme cluster boundary so that it fits within width
+// including tail, leaving ANSI escape sequences intact. Colors and hyperlinks that are
+// still in effect at the cut are terminated after the tail.
+func truncateWithTail(s string, width int, tail string) string {
+	tailWidth := DisplayWidth(tail)
+	if width < tailWidth {
+		return tail
+	}
+	width -= tailWidth
+
+	var sb strings.Builder
+	current := 0
+	colored, linked := false, false
+	for len(s) > 0 {
+		if loc := ansiRE.FindStringIndex(s); loc != nil {
+			seq := s[:loc[1]]
+			if strings.HasPrefix(seq, "\x1b]8;") {
+				linked = !strings.HasPrefix(seq, "\x1b]8;;\x1b") && !strings.HasPrefix(seq, "\x1b]8;;\x07")
+			} else if strings.HasPrefix(seq, "\x1b[") {
+				colored = seq != "\x1b[0m" && seq != "\x1b[m"
+			}
+			sb.WriteString(seq)
+			s = s[loc[1]:]
+			continue
+		}
+		var cluster string
+		cluster, s, _, _ = uniseg.FirstGraphemeClusterInString(s, -1)
+		w := clusterWidth(cluster)
+		if current+w > width {
+			sb.WriteString(tail)
+			if linked {
+				sb.WriteString("\x1b]8;;\x1b\\")
+			}
+			if colored {
+				sb.WriteString("\x1b[0m")
+			}
+			return sb.String()
+		}
+		current += w
+		sb.WriteString(cluster)
+	}
+	return sb.String()
+}
+
 // PadRight returns a copy of the string s that has been padded on the right with whitespace to fit
 // the maximum display width.
 func PadRight(maxWidth int, s string) string {
@@ -62,6 +138,35 @@ func PadRight(maxWidth int, s string) string {
 	return s
 }
 
+// PadLeft returns a copy of the string s that has been padded on the left with whitespace to fit
+// the maximum display width.
+func PadLeft(maxWidth int, s string) string {
+	if padWidth := maxWidth - DisplayWidth(s); padWidth > 0 {
+		s = strings.Repeat(" ", padWidth) + s
+	}
+	return s
+}
+
+// PadCenter returns a copy of the string s that has been padded on both sides with whitespace
+// to center it within the maximum display width. Odd padding is placed on the right.
+func PadCenter(maxWidth int, s string) string {
+	if padWidth := maxWidth - DisplayWidth(s); padWidth > 0 {
+		left := padWidth / 2
+		s = strings.Repeat(" ", left) + s + strings.Repeat(" ", padWidth-left)
+	}
+	return s
+}
+
+// Wrap returns a copy of the string s with lines broken at word boundaries so that
+// no line exceeds the maximum display width. Words longer than the maximum width
+// are broken mid-word. ANSI escape sequences do not count towards the width.
+func Wrap(maxWidth int, s string) string {
+	if maxWidth <= 0 {
+		return s
+	}
+	return wrap.String(wordwrap.String(s, maxWidth), maxWidth)
+}
+
 // Pluralize returns a concatenated string with num and the plural form of thing if necessary.
 func Pluralize(num int, thing string) string {
 	if num == 1 {
@@ -70,32 +175,119 @@ func Pluralize(num int, thing string) string {
 	return fmt.Sprintf("%d %ss", num, thing)
 }
 
-func fmtDuration(amount int, unit string) string {
-	return fmt.Sprintf("about %s ago", Pluralize(amount, unit))
+var (
+	languageMu sync.RWMutex
+	lang       = language.English
+)
+
+// SetLanguage sets the language of the relative times returned by RelativeTime and
+// RelativeTimeAgo, which are translated with the i18n message catalog. The default is
+// English. The package does not detect the language itself; applications that want the
+// language configured by the user can pass [i18n.Language].
+func SetLanguage(tag language.Tag) {
+	languageMu.Lock()
+	defer languageMu.Unlock()
+	lang = tag
+}
+
+func sprintf(key string, args ...interface{}) string {
+	languageMu.RLock()
+	tag := lang
+	languageMu.RUnlock()
+	return i18n.NewPrinter(tag).Sprintf(key, args...)
+}
+
+// timeUnits are the units of relative times. Their plural English messages are registered
+// with the i18n package so that translations can provide their own plural forms.
+var timeUnits = []string{"minute", "hour", "day", "month", "year"}
+
+func init() {
+	for _, unit := range timeUnits {
+		_ = i18n.Set(language.English, "about %d "+unit+"s ago",
+			plural.Selectf(1, "%d", "one", "about 1 "+unit+" ago", "other", "about %d "+unit+"s ago"))
+		_ = i18n.Set(language.English, "in about %d "+unit+"s",
+			plural.Selectf(1, "%d", "one", "in about 1 "+unit, "other", "in about %d "+unit+"s"))
+	}
+}
+
+func fmtDuration(amount int, unit string, future bool) string {
+	if future {
+		return sprintf("in about %d "+unit+"s", amount)
+	}
+	return sprintf("about %d "+unit+"s ago", amount)
 }
 
 // RelativeTimeAgo returns a human readable string of the time duration between a and b that is estimated
 // to the nearest unit of time.
 func RelativeTimeAgo(a, b time.Time) string {
-	ago := a.Sub(b)
+	return relativeTime(a.Sub(b), false)
+}
+
+// RelativeTime returns a human readable string of the time duration between t and now that is
+// estimated to the nearest unit of time, such as "about 3 hours ago" or "in about 2 days".
+func RelativeTime(t, now time.Time) string {
+	if !t.After(now) {
+		return RelativeTimeAgo(now, t)
+	}
+	return relativeTime(t.Sub(now), true)
+}
 
-	if ago < time.Minute {
-		return "less than a minute ago"
+func relativeTime(d time.Duration, future bool) string {
+	if d < time.Minute {
+		if future {
+			return sprintf("in less than a minute")
+		}
+		return sprintf("less than a minute ago")
 	}
-	if ago < time.Hour {
-		return fmtDuration(int(ago.Minutes()), "minute")
+	if d < time.Hour {
+		return fmtDuration(int(d.Minutes()), "minute", future)
 	}
-	if ago < 24*time.Hour {
-		return fmtDuration(int(ago.Hours()), "hour")
+	if d < 24*time.Hour {
+		return fmtDuration(int(d.Hours()), "hour", future)
 	}
-	if ago < 30*24*time.Hour {
-		return fmtDuration(int(ago.Hours())/24, "day")
+	if d < 30*24*time.Hour {
+		return fmtDuration(int(d.Hours())/24, "day", future)
 	}
-	if ago < 365*24*time.Hour {
-		return fmtDuration(int(ago.Hours())/24/30, "month")
+	if d < 365*24*time.Hour {
+		return fmtDuration(int(d.Hours())/24/30, "month", future)
 	}
 
-	return fmtDuration(int(ago.Hours()/24/365), "year")
+	return fmtDuration(int(d.Hours()/24/365), "year", future)
+}
+
+// timeLayouts are the timestamp layouts accepted by ParseTime, in order of preference.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -0700 MST",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+}
+
+// ParseTime parses a timestamp in any of the formats commonly returned by APIs and git,
+// such as RFC 3339 with or without fractional seconds or a colon in the zone offset,
+// a space instead of the "T" separator, a plain date, or RFC 1123.
+// Timestamps that do not specify a zone are interpreted as UTC.
+func ParseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time format: %q", s)
+}
+
+// FormatTime returns t in the RFC 3339 format used for machine-readable output.
+func FormatTime(t time.Time) string {
+	return t.Format(time.RFC3339)
 }
 
 // RemoveDiacritics returns the input value without "diacritics", or accent marks.
@@ -1,10 +1,15 @@
 package text
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/i18n"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
 )
 
 func TestRelativeTimeAgo(t *testing.T) {
@@ -33,6 +38,128 @@ func TestRelativeTimeAgo(t *testing.T) {
 	}
 }
 
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2020, time.November, 22, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{
+			name: "now",
+			t:    now,
+			want: "less than a minute ago",
+		},
+		{
+			name: "past",
+			t:    now.Add(-3 * time.Hour),
+			want: "about 3 hours ago",
+		},
+		{
+			name: "soon",
+			t:    now.Add(30 * time.Second),
+			want: "in less than a minute",
+		},
+		{
+			name: "future",
+			t:    now.Add(49 * time.Hour),
+			want: "in about 2 days",
+		},
+		{
+			name: "far future",
+			t:    now.AddDate(1, 0, 1),
+			want: "in about 1 year",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RelativeTime(tt.t, now))
+		})
+	}
+}
+
+func TestRelativeTimeTranslated(t *testing.T) {
+	t.Cleanup(func() { SetLanguage(language.English) })
+	es := language.Spanish
+	require.NoError(t, i18n.Set(es, "about %d hours ago",
+		plural.Selectf(1, "%d", "one", "hace alrededor de 1 hora", "other", "hace alrededor de %d horas")))
+	require.NoError(t, i18n.SetString(es, "in less than a minute", "en menos de un minuto"))
+	SetLanguage(es)
+
+	now := time.Date(2020, time.November, 22, 14, 0, 0, 0, time.UTC)
+	assert.Equal(t, "hace alrededor de 3 horas", RelativeTime(now.Add(-3*time.Hour), now))
+	assert.Equal(t, "hace alrededor de 1 hora", RelativeTime(now.Add(-time.Hour), now))
+	assert.Equal(t, "en menos de un minuto", RelativeTime(now.Add(time.Second), now))
+	assert.Equal(t, "in about 2 days", RelativeTime(now.Add(49*time.Hour), now))
+}
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2008, time.February, 25, 20, 18, 33, 0, time.UTC)
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "RFC 3339",
+			input: "2008-02-25T20:18:33Z",
+			want:  want,
+		},
+		{
+			name:  "fractional seconds",
+			input: "2008-02-25T20:18:33.000Z",
+			want:  want,
+		},
+		{
+			name:  "zone offset without colon",
+			input: "2008-02-25T21:18:33+0100",
+			want:  want,
+		},
+		{
+			name:  "git format",
+			input: "2008-02-25 21:18:33 +0100",
+			want:  want,
+		},
+		{
+			name:  "no zone",
+			input: "2008-02-25 20:18:33",
+			want:  want,
+		},
+		{
+			name:  "date",
+			input: " 2008-02-25\n",
+			want:  time.Date(2008, time.February, 25, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "RFC 1123",
+			input: "Mon, 25 Feb 2008 20:18:33 GMT",
+			want:  want,
+		},
+		{
+			name:    "invalid",
+			input:   "yesterday",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTime(tt.input)
+			if tt.wantErr {
+				assert.EqualError(t, err, fmt.Sprintf("unrecognized time format: %q", tt.input))
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %v", got)
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	ts := time.Date(2008, time.February, 25, 20, 18, 33, 0, time.UTC)
+	assert.Equal(t, "2008-02-25T20:18:33Z", FormatTime(ts))
+}
+
 func TestTruncate(t *testing.T) {
 	type args struct {
 		max int
@@ -376,6 +503,21 @@ func TestDisplayWidth(t *testing.T) {
 			text: "つのだ☆HIRO",
 			want: 11,
 		},
+		{
+			name: "joined emoji",
+			text: "👨‍👩‍👧",
+			want: 2,
+		},
+		{
+			name: "flag",
+			text: "🇯🇵",
+			want: 2,
+		},
+		{
+			name: "hyperlink",
+			text: "\x1b]8;;https://github.com\x1b\\link\x1b]8;;\x1b\\",
+			want: 4,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -481,3 +623,27 @@ func TestRemoveDiacritics(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncateGraphemeClusters(t *testing.T) {
+	assert.Equal(t, "👨‍👩‍👧👨‍👩‍👧...", Truncate(7, "👨‍👩‍👧👨‍👩‍👧👨‍👩‍👧👨‍👩‍👧"))
+	assert.Equal(t, "\x1b]8;;https://github.com\x1b\\lo...\x1b]8;;\x1b\\", Truncate(5, "\x1b]8;;https://github.com\x1b\\long link\x1b]8;;\x1b\\"))
+}
+
+func TestPadLeft(t *testing.T) {
+	assert.Equal(t, "   hi", PadLeft(5, "hi"))
+	assert.Equal(t, "  日本", PadLeft(6, "日本"))
+	assert.Equal(t, "hello", PadLeft(3, "hello"))
+}
+
+func TestPadCenter(t *testing.T) {
+	assert.Equal(t, " hi  ", PadCenter(5, "hi"))
+	assert.Equal(t, " 日本 ", PadCenter(6, "日本"))
+	assert.Equal(t, "hello", PadCenter(3, "hello"))
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, "hello\nworld", Wrap(7, "hello world"))
+	assert.Equal(t, "abcde\nfgh", Wrap(5, "abcdefgh"))
+	assert.Equal(t, "\x1b[31mhello\x1b[m\nworld", Wrap(7, "\x1b[31mhello\x1b[m world"))
+	assert.Equal(t, "hello world", Wrap(0, "hello world"))
+}
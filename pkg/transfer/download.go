@@ -0,0 +1,235 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+)
+
+// DownloadOptions configures a download.
+type DownloadOptions struct {
+	// Digest is the expected SHA-256 digest of the file, as "sha256:HEX" or HEX.
+	// Default is the digest reported by the API, if any.
+	Digest string
+	// Progress is called as the file is downloaded.
+	Progress ProgressFunc
+}
+
+// errRestart is returned when a partial download cannot be resumed and must start over.
+var errRestart = errors.New("partial download cannot be resumed")
+
+// DownloadReleaseAsset downloads an asset of a release of the repository "OWNER/REPO"
+// to the file at dest. See Download for how the transfer is resumed and verified.
+func (c *Client) DownloadReleaseAsset(ctx context.Context, repo string, assetID int64, dest string, opts DownloadOptions) error {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("repos/%s/%s/releases/assets/%d", owner, name, assetID)
+	var asset Asset
+	if err := c.getJSON(ctx, path, &asset); err != nil {
+		return err
+	}
+	if opts.Digest == "" {
+		opts.Digest = asset.Digest
+	}
+	return c.download(ctx, api.RESTURL(c.host, path), "application/octet-stream", asset.Size, dest, opts)
+}
+
+// DownloadArtifact downloads the zip archive of a workflow run artifact of the repository
+// "OWNER/REPO" to the file at dest. See Download for how the transfer is resumed and
+// verified.
+func (c *Client) DownloadArtifact(ctx context.Context, repo string, artifactID int64, dest string, opts DownloadOptions) error {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("repos/%s/%s/actions/artifacts/%d", owner, name, artifactID)
+	var artifact struct {
+		Name        string `json:"name"`
+		SizeInBytes int64  `json:"size_in_bytes"`
+		Digest      string `json:"digest"`
+		Expired     bool   `json:"expired"`
+	}
+	if err := c.getJSON(ctx, path, &artifact); err != nil {
+		return err
+	}
+	if artifact.Expired {
+		return fmt.Errorf("artifact %s has expired", artifact.Name)
+	}
+	if opts.Digest == "" {
+		opts.Digest = artifact.Digest
+	}
+	return c.download(ctx, api.RESTURL(c.host, path+"/zip"), "", artifact.SizeInBytes, dest, opts)
+}
+
+// Download downloads the file at url, such as the browser download URL of an asset, to
+// the file at dest. The download is written to dest with the ".part" suffix, which is
+// renamed to dest once complete.
+//
+// When a request fails, the download is retried from where it stopped with a range
+// request, and a later Download to the same dest resumes the partial file as well. The
+// ETag of the file is kept next to the partial file, with the ".part.etag" suffix, and
+// sent in an If-Range header so that a file that changed in the meantime is downloaded
+// again from the start. If a digest is expected, the complete file is checked against it
+// and a *ChecksumError is returned after removing the file if they differ.
+func (c *Client) Download(ctx context.Context, url, dest string, opts DownloadOptions) error {
+	return c.download(ctx, url, "", 0, dest, opts)
+}
+
+func (c *Client) download(ctx context.Context, url, accept string, size int64, dest string, opts DownloadOptions) error {
+	part := dest + ".part"
+	err := c.retry(ctx, func(int) error {
+		err := c.fetch(ctx, url, accept, size, part, opts.Progress)
+		if errors.Is(err, errRestart) {
+			// Start over right away rather than counting it as a failed attempt.
+			err = c.fetch(ctx, url, accept, size, part, opts.Progress)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_ = os.Remove(etagPath(part))
+	if expected := normalizeDigest(opts.Digest); expected != "" {
+		actual, err := fileDigest(part)
+		if err != nil {
+			return err
+		}
+		if actual != expected {
+			_ = os.Remove(part)
+			return &ChecksumError{Name: dest, Expected: expected, Actual: actual}
+		}
+	}
+	return os.Rename(part, dest)
+}
+
+// etagPath returns the location of the file that keeps the ETag of the partial file part.
+func etagPath(part string) string {
+	return part + ".etag"
+}
+
+// saveETag records the ETag of a response that the partial file part is written from. Weak
+// ETags cannot be used with If-Range, so they are not recorded.
+func saveETag(part string, resp *http.Response) error {
+	etag := resp.Header.Get("ETag")
+	if etag == "" || strings.HasPrefix(etag, "W/") {
+		err := os.Remove(etagPath(part))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return os.WriteFile(etagPath(part), []byte(etag), 0o644)
+}
+
+// fetch downloads url to the file at part, appending to its contents if the server
+// supports range requests. It returns errRestart after truncating the file if its
+// contents cannot be resumed.
+func (c *Client) fetch(ctx context.Context, url, accept string, size int64, part string, progress ProgressFunc) error {
+	f, err := os.OpenFile(part, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if size > 0 && offset == size {
+		return nil
+	}
+	if size > 0 && offset > size {
+		return restart(f)
+	}
+	etag := ""
+	if offset > 0 {
+		data, err := os.ReadFile(etagPath(part))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		etag = string(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if etag != "" {
+			// The server sends the whole file instead of the range if it has changed.
+			req.Header.Set("If-Range", etag)
+		}
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	total := size
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return restart(f)
+		}
+		if total <= 0 && resp.ContentLength >= 0 {
+			total = offset + resp.ContentLength
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is complete if the range starts at the end of the file.
+		if resp.Header.Get("Content-Range") == fmt.Sprintf("bytes */%d", offset) {
+			return nil
+		}
+		return restart(f)
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		// The server sent the whole file, because it ignores range requests or because the
+		// file changed since the partial file was written.
+		if err := saveETag(part, resp); err != nil {
+			return err
+		}
+		if offset > 0 {
+			if err := f.Truncate(0); err != nil {
+				return err
+			}
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			offset = 0
+		}
+		if total <= 0 {
+			total = resp.ContentLength
+		}
+	default:
+		return api.HandleHTTPError(resp)
+	}
+
+	body := &progressReader{r: resp.Body, current: offset, total: total, progress: progress}
+	if progress != nil {
+		progress(offset, total)
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func restart(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if err := os.Remove(etagPath(f.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return errRestart
+}
@@ -0,0 +1,198 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var blob = bytes.Repeat([]byte("0123456789"), 1000)
+
+func blobDigest() string {
+	sum := sha256.Sum256(blob)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+type downloadServer struct {
+	mu       sync.Mutex
+	requests []string
+	// metadata is the JSON response to requests for the asset or artifact.
+	metadata string
+	// truncate is the number of blob responses cut short after half of the blob.
+	truncate int
+	// ignoreRange serves the whole blob to range requests.
+	ignoreRange bool
+	// etag is the ETag of the blob, if any.
+	etag string
+}
+
+func (s *downloadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, strings.TrimSpace(strings.Join([]string{r.URL.Path, r.Header.Get("Range"), r.Header.Get("If-Range")}, " ")))
+	switch {
+	case r.URL.Path == "/blob":
+		if s.etag != "" {
+			w.Header().Set("ETag", s.etag)
+		}
+		if s.truncate > 0 && r.Header.Get("Range") == "" {
+			s.truncate--
+			w.Header().Set("Content-Length", strconv.Itoa(len(blob)))
+			_, _ = w.Write(blob[:len(blob)/2])
+			return
+		}
+		if s.ignoreRange {
+			r.Header.Del("Range")
+		}
+		http.ServeContent(w, r, "blob", time.Time{}, bytes.NewReader(blob))
+	case r.Header.Get("Accept") == "application/octet-stream" || r.URL.Path == "/repos/octo-org/hello-world/actions/artifacts/5/zip":
+		http.Redirect(w, r, "/blob", http.StatusFound)
+	default:
+		fmt.Fprint(w, s.metadata)
+	}
+}
+
+func TestDownloadReleaseAsset(t *testing.T) {
+	s := &downloadServer{metadata: fmt.Sprintf(`{"id":3,"size":%d,"digest":%q}`, len(blob), blobDigest())}
+	c := newTestClient(t, s)
+	dest := filepath.Join(t.TempDir(), "app.tar.gz")
+
+	var last [2]int64
+	err := c.DownloadReleaseAsset(context.Background(), "octo-org/hello-world", 3, dest, DownloadOptions{
+		Progress: func(current, total int64) {
+			last = [2]int64{current, total}
+		},
+	})
+	require.NoError(t, err)
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, blob, got)
+	assert.NoFileExists(t, dest+".part")
+	assert.Equal(t, [2]int64{int64(len(blob)), int64(len(blob))}, last)
+	assert.Equal(t, []string{
+		"/repos/octo-org/hello-world/releases/assets/3",
+		"/repos/octo-org/hello-world/releases/assets/3",
+		"/blob",
+	}, s.requests)
+}
+
+func TestDownloadArtifactResume(t *testing.T) {
+	s := &downloadServer{
+		metadata: fmt.Sprintf(`{"id":5,"name":"dist","size_in_bytes":%d,"expired":false}`, len(blob)),
+		truncate: 1,
+		etag:     `"v1"`,
+	}
+	c := newTestClient(t, s)
+	dest := filepath.Join(t.TempDir(), "dist.zip")
+
+	err := c.DownloadArtifact(context.Background(), "octo-org/hello-world", 5, dest, DownloadOptions{Digest: blobDigest()})
+	require.NoError(t, err)
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, blob, got)
+	assert.Equal(t, []string{
+		"/repos/octo-org/hello-world/actions/artifacts/5",
+		"/repos/octo-org/hello-world/actions/artifacts/5/zip",
+		"/blob",
+		"/repos/octo-org/hello-world/actions/artifacts/5/zip bytes=5000- \"v1\"",
+		"/blob bytes=5000- \"v1\"",
+	}, s.requests)
+	assert.NoFileExists(t, dest+".part.etag")
+}
+
+func TestDownloadResumesPartialFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		part         []byte
+		partETag     string
+		etag         string
+		ignoreRange  bool
+		wantRequests []string
+	}{
+		{
+			name:         "range request",
+			part:         blob[:1000],
+			wantRequests: []string{"/blob bytes=1000-"},
+		},
+		{
+			name:         "range ignored",
+			part:         []byte("garbage"),
+			ignoreRange:  true,
+			wantRequests: []string{"/blob bytes=7-"},
+		},
+		{
+			name:         "complete",
+			part:         blob,
+			wantRequests: []string{"/blob bytes=10000-"},
+		},
+		{
+			name:         "unchanged file",
+			part:         blob[:1000],
+			partETag:     `"v1"`,
+			etag:         `"v1"`,
+			wantRequests: []string{`/blob bytes=1000- "v1"`},
+		},
+		{
+			name:         "changed file",
+			part:         []byte("garbage"),
+			partETag:     `"v1"`,
+			etag:         `"v2"`,
+			wantRequests: []string{`/blob bytes=7- "v1"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &downloadServer{ignoreRange: tt.ignoreRange, etag: tt.etag}
+			c := newTestClient(t, s)
+			dest := filepath.Join(t.TempDir(), "blob")
+			require.NoError(t, os.WriteFile(dest+".part", tt.part, 0o644))
+			if tt.partETag != "" {
+				require.NoError(t, os.WriteFile(dest+".part.etag", []byte(tt.partETag), 0o644))
+			}
+
+			err := c.Download(context.Background(), "https://example.com/blob", dest, DownloadOptions{Digest: blobDigest()})
+			require.NoError(t, err)
+			got, err := os.ReadFile(dest)
+			require.NoError(t, err)
+			assert.Equal(t, blob, got)
+			assert.Equal(t, tt.wantRequests, s.requests)
+			assert.NoFileExists(t, dest+".part.etag")
+		})
+	}
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	s := &downloadServer{}
+	c := newTestClient(t, s)
+	dest := filepath.Join(t.TempDir(), "blob")
+
+	err := c.Download(context.Background(), "https://example.com/blob", dest, DownloadOptions{Digest: "0000"})
+	var checksumErr *ChecksumError
+	require.ErrorAs(t, err, &checksumErr)
+	assert.Equal(t, "sha256:0000", checksumErr.Expected)
+	assert.Equal(t, blobDigest(), checksumErr.Actual)
+	assert.NoFileExists(t, dest)
+	assert.NoFileExists(t, dest+".part")
+	assert.Len(t, s.requests, 1)
+}
+
+func TestDownloadArtifactExpired(t *testing.T) {
+	s := &downloadServer{metadata: `{"id":5,"name":"dist","size_in_bytes":10,"expired":true}`}
+	c := newTestClient(t, s)
+
+	err := c.DownloadArtifact(context.Background(), "octo-org/hello-world", 5, filepath.Join(t.TempDir(), "dist.zip"), DownloadOptions{})
+	assert.EqualError(t, err, "artifact dist has expired")
+}
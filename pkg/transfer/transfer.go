@@ -0,0 +1,229 @@
+// Package transfer uploads release assets and downloads release assets and workflow run
+// artifacts over HTTP, for binaries too large to be passed through goctl reliably.
+//
+// Transfers report their progress to a ProgressFunc, such as the Update method of a
+// progress.Bar, and are retried when a request fails with a transient network error or a
+// server error. Interrupted downloads resume where they stopped, and transferred files are
+// verified against the SHA-256 digest reported by the API when there is one.
+//
+// Uploads are not resumable: the API has no multipart or resumable upload for release
+// assets, so each attempt uploads the whole file in a single request.
+package transfer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/auth"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
+)
+
+const (
+	// defaultRetries is the number of times a failed transfer is retried.
+	defaultRetries = 3
+	// defaultRetryBackoff is the delay before the first retry, which doubles for each
+	// following retry.
+	defaultRetryBackoff = time.Second
+)
+
+// ProgressFunc is called as a transfer progresses with the number of bytes transferred
+// so far and the total size, which is zero or less when it is not known. The count can
+// go back to zero when a transfer is restarted.
+type ProgressFunc func(current, total int64)
+
+// ChecksumError is returned when a transferred file does not have the expected digest.
+type ChecksumError struct {
+	Name     string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: expected %s, got %s", e.Name, e.Expected, e.Actual)
+}
+
+// Client transfers files to and from the API of a host.
+type Client struct {
+	client  *http.Client
+	host    string
+	retries int
+	backoff time.Duration
+	clock   clock.Clock
+}
+
+type clientOption func(*Client)
+
+// WithRetries sets the number of times a failed transfer is retried. It defaults to 3.
+func WithRetries(n int) clientOption {
+	return func(c *Client) {
+		c.retries = n
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry of a failed transfer, which
+// doubles for each following retry. It defaults to one second.
+func WithRetryBackoff(d time.Duration) clientOption {
+	return func(c *Client) {
+		c.backoff = d
+	}
+}
+
+// WithClock sets the clock that spaces retries. It defaults to the system clock.
+func WithClock(clk clock.Clock) clientOption {
+	return func(c *Client) {
+		c.clock = clk
+	}
+}
+
+// NewClient initializes a Client for the host of opts. Transfers can take long, so the
+// Timeout of opts should be left unset in favor of contexts.
+func NewClient(opts api.ClientOptions, clientOpts ...clientOption) (*Client, error) {
+	if opts.Host == "" {
+		opts.Host, _ = auth.DefaultHost()
+	}
+	client, err := api.NewHTTPClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		client:  client,
+		host:    opts.Host,
+		retries: defaultRetries,
+		backoff: defaultRetryBackoff,
+		clock:   clock.Real,
+	}
+	for _, opt := range clientOpts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// retry calls fn until it succeeds, fails with an error that is not worth retrying, or
+// has been retried c.retries times. Attempts are numbered from zero.
+func (c *Client) retry(ctx context.Context, fn func(attempt int) error) error {
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		err := fn(attempt)
+		if err == nil || attempt >= c.retries || !retryable(ctx, err) {
+			return err
+		}
+		select {
+		case <-c.clock.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// retryable reports whether a transfer that failed with err may succeed if retried. Only
+// server errors, rate limiting, timeouts, and connections that were refused, reset, or cut
+// short are transient. Any other error, such as a client error, a checksum mismatch, or a
+// local file error, is returned right away.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// getJSON fetches a REST API path and decodes the response into v.
+func (c *Client) getJSON(ctx context.Context, path string, v interface{}) error {
+	return c.doJSON(ctx, "GET", api.RESTURL(c.host, path), nil, v)
+}
+
+// doJSON sends a request to url and decodes the response into v, which may be nil.
+func (c *Client) doJSON(ctx context.Context, method, url string, body io.Reader, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return api.HandleHTTPError(resp)
+	}
+	if v == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// progressReader reports the bytes read through it to a ProgressFunc.
+type progressReader struct {
+	r        io.Reader
+	current  int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.current += int64(n)
+		if r.progress != nil {
+			r.progress(r.current, r.total)
+		}
+	}
+	return n, err
+}
+
+// fileDigest returns the SHA-256 digest of the file at path in the "sha256:HEX" format
+// used by the API.
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// normalizeDigest returns digest in the "sha256:HEX" format, accepting a bare
+// hexadecimal SHA-256 digest as well.
+func normalizeDigest(digest string) string {
+	digest = strings.ToLower(strings.TrimSpace(digest))
+	if digest != "" && !strings.Contains(digest, ":") {
+		digest = "sha256:" + digest
+	}
+	return digest
+}
+
+func splitRepo(repo string) (string, string, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("expected the \"OWNER/REPO\" format, got %q", repo)
+	}
+	return owner, name, nil
+}
@@ -0,0 +1,144 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serverTransport sends every request to a test server, keeping the Host header of the
+// original URL so that handlers can tell the API and upload hosts apart.
+type serverTransport struct {
+	url *url.URL
+}
+
+func (t serverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.url.Scheme
+	req.URL.Host = t.url.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	c, err := NewClient(api.ClientOptions{
+		Host:         "github.com",
+		AuthToken:    "abc123",
+		Transport:    serverTransport{url: u},
+		LogIgnoreEnv: true,
+	}, WithRetryBackoff(0))
+	require.NoError(t, err)
+	return c
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantAttempts int
+	}{
+		{
+			name:         "success",
+			wantAttempts: 1,
+		},
+		{
+			name:         "server error",
+			err:          &api.HTTPError{StatusCode: 502},
+			wantAttempts: 4,
+		},
+		{
+			name:         "rate limited",
+			err:          &api.HTTPError{StatusCode: 429},
+			wantAttempts: 4,
+		},
+		{
+			name:         "connection reset",
+			err:          &url.Error{Op: "Get", URL: "https://example.com", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}},
+			wantAttempts: 4,
+		},
+		{
+			name:         "unexpected EOF",
+			err:          fmt.Errorf("reading body: %w", io.ErrUnexpectedEOF),
+			wantAttempts: 4,
+		},
+		{
+			name:         "timeout",
+			err:          &url.Error{Op: "Get", URL: "https://example.com", Err: timeoutError{}},
+			wantAttempts: 4,
+		},
+		{
+			name:         "other error",
+			err:          errors.New("unsupported protocol scheme"),
+			wantAttempts: 1,
+		},
+		{
+			name:         "file error",
+			err:          &fs.PathError{Op: "open", Path: "app.zip", Err: syscall.ENOENT},
+			wantAttempts: 1,
+		},
+		{
+			name:         "client error",
+			err:          &api.HTTPError{StatusCode: 404},
+			wantAttempts: 1,
+		},
+		{
+			name:         "checksum mismatch",
+			err:          &ChecksumError{Name: "app.zip", Expected: "sha256:a", Actual: "sha256:b"},
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, http.NotFoundHandler())
+			attempts := 0
+			err := c.retry(context.Background(), func(attempt int) error {
+				assert.Equal(t, attempts, attempt)
+				attempts++
+				return tt.err
+			})
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantAttempts, attempts)
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestRetryContextDone(t *testing.T) {
+	c := newTestClient(t, http.NotFoundHandler())
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := c.retry(ctx, func(int) error {
+		attempts++
+		cancel()
+		return io.ErrUnexpectedEOF
+	})
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestNormalizeDigest(t *testing.T) {
+	assert.Equal(t, "", normalizeDigest(""))
+	assert.Equal(t, "sha256:abc", normalizeDigest("ABC"))
+	assert.Equal(t, "sha256:abc", normalizeDigest("sha256:abc"))
+}
@@ -0,0 +1,145 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/internal/ghinstance"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+)
+
+// Asset is a release asset.
+type Asset struct {
+	ID                 int64     `json:"id"`
+	Name               string    `json:"name"`
+	Label              string    `json:"label"`
+	State              string    `json:"state"`
+	ContentType        string    `json:"content_type"`
+	Size               int64     `json:"size"`
+	Digest             string    `json:"digest"`
+	BrowserDownloadURL string    `json:"browser_download_url"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// UploadOptions configures the upload of a release asset.
+type UploadOptions struct {
+	// Name is the file name of the asset.
+	// Default is the base name of the uploaded file.
+	Name string
+	// Label is a short description of the asset displayed instead of its name.
+	Label string
+	// ContentType is the media type of the asset.
+	// Default is derived from the extension of the name, or "application/octet-stream".
+	ContentType string
+	// Progress is called as the file is uploaded.
+	Progress ProgressFunc
+}
+
+// UploadReleaseAsset uploads the file at path as an asset of a release of the repository
+// "OWNER/REPO" and returns the created asset.
+//
+// Assets are uploaded in a single request, which is retried from the start when it fails.
+// An asset left behind in the "starter" state by a failed attempt would make the next
+// attempt fail as a duplicate, so it is deleted first. Once uploaded, the asset is checked
+// against the SHA-256 digest of the file if the API reports one, and a *ChecksumError is
+// returned after deleting the asset if they differ.
+func (c *Client) UploadReleaseAsset(ctx context.Context, repo string, releaseID int64, path string, opts UploadOptions) (*Asset, error) {
+	owner, name, err := splitRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Name == "" {
+		opts.Name = filepath.Base(path)
+	}
+	if opts.ContentType == "" {
+		opts.ContentType = mime.TypeByExtension(filepath.Ext(opts.Name))
+		if opts.ContentType == "" {
+			opts.ContentType = "application/octet-stream"
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	digest, err := fileDigest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	releasePath := fmt.Sprintf("repos/%s/%s/releases/%d/assets", owner, name, releaseID)
+	query := url.Values{"name": {opts.Name}}
+	if opts.Label != "" {
+		query.Set("label", opts.Label)
+	}
+	var asset Asset
+	err = c.retry(ctx, func(attempt int) error {
+		if attempt > 0 {
+			if err := c.deleteIncompleteAsset(ctx, owner, name, releaseID, opts.Name); err != nil {
+				return err
+			}
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		body := &progressReader{r: f, total: info.Size(), progress: opts.Progress}
+		req, err := http.NewRequestWithContext(ctx, "POST", ghinstance.UploadsPrefix(c.host)+releasePath+"?"+query.Encode(), io.NopCloser(body))
+		if err != nil {
+			return err
+		}
+		req.ContentLength = info.Size()
+		req.Header.Set("Content-Type", opts.ContentType)
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			return api.HandleHTTPError(resp)
+		}
+		return json.NewDecoder(resp.Body).Decode(&asset)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if actual := normalizeDigest(asset.Digest); actual != "" && actual != digest {
+		if err := c.deleteAsset(ctx, owner, name, asset.ID); err != nil {
+			return nil, err
+		}
+		return nil, &ChecksumError{Name: opts.Name, Expected: digest, Actual: actual}
+	}
+	return &asset, nil
+}
+
+// deleteIncompleteAsset deletes the asset of a release with the given name if its upload
+// did not complete.
+func (c *Client) deleteIncompleteAsset(ctx context.Context, owner, name string, releaseID int64, assetName string) error {
+	var assets []Asset
+	if err := c.getJSON(ctx, fmt.Sprintf("repos/%s/%s/releases/%d/assets?per_page=100", owner, name, releaseID), &assets); err != nil {
+		return err
+	}
+	for _, a := range assets {
+		if a.Name == assetName && a.State != "uploaded" {
+			return c.deleteAsset(ctx, owner, name, a.ID)
+		}
+	}
+	return nil
+}
+
+func (c *Client) deleteAsset(ctx context.Context, owner, name string, assetID int64) error {
+	return c.doJSON(ctx, "DELETE", api.RESTURL(c.host, fmt.Sprintf("repos/%s/%s/releases/assets/%d", owner, name, assetID)), nil, nil)
+}
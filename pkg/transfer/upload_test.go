@@ -0,0 +1,155 @@
+package transfer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type uploadServer struct {
+	mu       sync.Mutex
+	requests []string
+	bodies   []string
+	uploads  []func(w http.ResponseWriter, body []byte)
+	assets   string
+}
+
+func (s *uploadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, fmt.Sprintf("%s %s%s", r.Method, r.Host, r.URL.RequestURI()))
+	switch {
+	case r.Method == "POST" && r.Host == "uploads.github.com":
+		body, _ := io.ReadAll(r.Body)
+		s.bodies = append(s.bodies, r.Header.Get("Content-Type")+" "+string(body))
+		upload := s.uploads[0]
+		s.uploads = s.uploads[1:]
+		upload(w, body)
+	case r.Method == "GET":
+		fmt.Fprint(w, s.assets)
+	case r.Method == "DELETE":
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func created(digest string) func(w http.ResponseWriter, body []byte) {
+	return func(w http.ResponseWriter, body []byte) {
+		if digest == "" {
+			sum := sha256.Sum256(body)
+			digest = "sha256:" + hex.EncodeToString(sum[:])
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":42,"name":"notes.txt","state":"uploaded","size":%d,"digest":%q}`, len(body), digest)
+	}
+}
+
+func failed(status int) func(w http.ResponseWriter, body []byte) {
+	return func(w http.ResponseWriter, body []byte) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"message":"upload failed"}`)
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestUploadReleaseAsset(t *testing.T) {
+	s := &uploadServer{uploads: []func(http.ResponseWriter, []byte){created("")}}
+	c := newTestClient(t, s)
+	path := writeFile(t, "release notes")
+
+	var progress [][2]int64
+	asset, err := c.UploadReleaseAsset(context.Background(), "octo-org/hello-world", 1, path, UploadOptions{
+		Label: "Release notes",
+		Progress: func(current, total int64) {
+			progress = append(progress, [2]int64{current, total})
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), asset.ID)
+	assert.Equal(t, int64(13), asset.Size)
+	assert.Equal(t, []string{
+		"POST uploads.github.com/repos/octo-org/hello-world/releases/1/assets?label=Release+notes&name=notes.txt",
+	}, s.requests)
+	assert.Equal(t, []string{"text/plain; charset=utf-8 release notes"}, s.bodies)
+	require.NotEmpty(t, progress)
+	assert.Equal(t, [2]int64{13, 13}, progress[len(progress)-1])
+}
+
+func TestUploadReleaseAssetRetry(t *testing.T) {
+	s := &uploadServer{
+		uploads: []func(http.ResponseWriter, []byte){failed(http.StatusBadGateway), created("")},
+		assets:  `[{"id":7,"name":"notes.txt","state":"starter"},{"id":8,"name":"other.txt","state":"starter"}]`,
+	}
+	c := newTestClient(t, s)
+	path := writeFile(t, "release notes")
+
+	asset, err := c.UploadReleaseAsset(context.Background(), "octo-org/hello-world", 1, path, UploadOptions{
+		Name:        "notes.txt",
+		ContentType: "application/octet-stream",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), asset.ID)
+	assert.Equal(t, []string{
+		"POST uploads.github.com/repos/octo-org/hello-world/releases/1/assets?name=notes.txt",
+		"GET api.github.com/repos/octo-org/hello-world/releases/1/assets?per_page=100",
+		"DELETE api.github.com/repos/octo-org/hello-world/releases/assets/7",
+		"POST uploads.github.com/repos/octo-org/hello-world/releases/1/assets?name=notes.txt",
+	}, s.requests)
+	assert.Equal(t, []string{
+		"application/octet-stream release notes",
+		"application/octet-stream release notes",
+	}, s.bodies)
+}
+
+func TestUploadReleaseAssetClientError(t *testing.T) {
+	s := &uploadServer{uploads: []func(http.ResponseWriter, []byte){failed(http.StatusUnprocessableEntity)}}
+	c := newTestClient(t, s)
+	path := writeFile(t, "release notes")
+
+	_, err := c.UploadReleaseAsset(context.Background(), "octo-org/hello-world", 1, path, UploadOptions{})
+	var httpErr *api.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, 422, httpErr.StatusCode)
+	assert.Equal(t, "upload failed", httpErr.Message)
+	assert.Len(t, s.requests, 1)
+}
+
+func TestUploadReleaseAssetChecksumMismatch(t *testing.T) {
+	s := &uploadServer{uploads: []func(http.ResponseWriter, []byte){created("sha256:0000")}}
+	c := newTestClient(t, s)
+	path := writeFile(t, "release notes")
+
+	_, err := c.UploadReleaseAsset(context.Background(), "octo-org/hello-world", 1, path, UploadOptions{})
+	var checksumErr *ChecksumError
+	require.ErrorAs(t, err, &checksumErr)
+	assert.Equal(t, "sha256:0000", checksumErr.Actual)
+	assert.Equal(t, []string{
+		"POST uploads.github.com/repos/octo-org/hello-world/releases/1/assets?name=notes.txt",
+		"DELETE api.github.com/repos/octo-org/hello-world/releases/assets/42",
+	}, s.requests)
+}
+
+func TestUploadReleaseAssetInvalidRepo(t *testing.T) {
+	c := newTestClient(t, http.NotFoundHandler())
+	_, err := c.UploadReleaseAsset(context.Background(), "hello-world", 1, "notes.txt", UploadOptions{})
+	assert.EqualError(t, err, `expected the "OWNER/REPO" format, got "hello-world"`)
+}
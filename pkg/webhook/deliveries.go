@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+)
+
+// DeliveriesClient is the subset of [api.RESTClient] used to inspect deliveries.
+type DeliveriesClient interface {
+	RequestWithContext(ctx context.Context, method string, path string, body io.Reader) (*http.Response, error)
+}
+
+// Hook identifies a webhook whose deliveries can be inspected.
+type Hook struct {
+	path string
+}
+
+// RepoHook returns the webhook with the given ID of the repository "OWNER/REPO".
+func RepoHook(repo string, id int64) Hook {
+	return Hook{path: fmt.Sprintf("repos/%s/hooks/%d", repo, id)}
+}
+
+// OrgHook returns the webhook with the given ID of an organization.
+func OrgHook(org string, id int64) Hook {
+	return Hook{path: fmt.Sprintf("orgs/%s/hooks/%d", org, id)}
+}
+
+// AppHook returns the webhook of the GitHub App that the client authenticates as. The
+// client must authenticate with a JSON Web Token of the app.
+func AppHook() Hook {
+	return Hook{path: "app/hook"}
+}
+
+// Delivery is an attempt to deliver an event to a webhook.
+type Delivery struct {
+	ID             int64     `json:"id"`
+	GUID           string    `json:"guid"`
+	DeliveredAt    time.Time `json:"delivered_at"`
+	Redelivery     bool      `json:"redelivery"`
+	Duration       float64   `json:"duration"`
+	Status         string    `json:"status"`
+	StatusCode     int       `json:"status_code"`
+	Event          string    `json:"event"`
+	Action         string    `json:"action"`
+	InstallationID *int64    `json:"installation_id"`
+	RepositoryID   *int64    `json:"repository_id"`
+	URL            string    `json:"url"`
+	// Request and Response are only returned by GetDelivery.
+	Request  *DeliveryMessage `json:"request"`
+	Response *DeliveryMessage `json:"response"`
+}
+
+// DeliveryMessage is the request or response of a delivery.
+type DeliveryMessage struct {
+	Headers map[string]string `json:"headers"`
+	Payload json.RawMessage   `json:"payload"`
+}
+
+// Failed reports whether the webhook did not accept the delivery.
+func (d *Delivery) Failed() bool {
+	return d.StatusCode < 200 || d.StatusCode > 299
+}
+
+// Parse parses the request payload of a delivery returned by GetDelivery like ParseEvent.
+func (d *Delivery) Parse() (Event, error) {
+	if d.Request == nil {
+		return nil, fmt.Errorf("delivery %d has no request payload", d.ID)
+	}
+	return ParseEvent(d.Event, d.Request.Payload)
+}
+
+// ListDeliveries returns up to limit of the latest deliveries of a webhook, newest first.
+// The request and response of each delivery are not included; use GetDelivery for them.
+func ListDeliveries(client DeliveriesClient, hook Hook, limit int) ([]Delivery, error) {
+	return ListDeliveriesContext(context.Background(), client, hook, limit)
+}
+
+// ListDeliveriesContext is like ListDeliveries but stops paginating when the context is done.
+func ListDeliveriesContext(ctx context.Context, client DeliveriesClient, hook Hook, limit int) ([]Delivery, error) {
+	perPage := 100
+	if limit > 0 && limit < perPage {
+		perPage = limit
+	}
+	deliveries := []Delivery{}
+	path := fmt.Sprintf("%s/deliveries?per_page=%d", hook.path, perPage)
+	for path != "" {
+		resp, err := client.RequestWithContext(ctx, "GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page []Delivery
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, page...)
+		if limit > 0 && len(deliveries) >= limit {
+			return deliveries[:limit], nil
+		}
+		path = api.NextLink(resp.Header.Get("Link"))
+	}
+	return deliveries, nil
+}
+
+// GetDelivery returns a delivery of a webhook with its request and response.
+func GetDelivery(client DeliveriesClient, hook Hook, id int64) (*Delivery, error) {
+	return GetDeliveryContext(context.Background(), client, hook, id)
+}
+
+// GetDeliveryContext is like GetDelivery but aborts the request when the context is done.
+func GetDeliveryContext(ctx context.Context, client DeliveriesClient, hook Hook, id int64) (*Delivery, error) {
+	resp, err := client.RequestWithContext(ctx, "GET", fmt.Sprintf("%s/deliveries/%d", hook.path, id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var d Delivery
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Redeliver asks GitHub to deliver the event of a delivery again. The new attempt is a
+// separate delivery with the same GUID and Redelivery set.
+func Redeliver(client DeliveriesClient, hook Hook, id int64) error {
+	return RedeliverContext(context.Background(), client, hook, id)
+}
+
+// RedeliverContext is like Redeliver but aborts the request when the context is done.
+func RedeliverContext(ctx context.Context, client DeliveriesClient, hook Hook, id int64) error {
+	resp, err := client.RequestWithContext(ctx, "POST", fmt.Sprintf("%s/deliveries/%d/attempts", hook.path, id), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDeliveriesClient struct {
+	responses map[string]*http.Response
+	requests  []string
+}
+
+func (c *fakeDeliveriesClient) RequestWithContext(ctx context.Context, method string, path string, body io.Reader) (*http.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.requests = append(c.requests, method+" "+path)
+	resp, ok := c.responses[method+" "+path]
+	if !ok {
+		return nil, errors.New("HTTP 404: Not Found")
+	}
+	return resp, nil
+}
+
+func jsonResponse(body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{StatusCode: 200, Header: header, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func deliveriesJSON(ids ...int) string {
+	var items []string
+	for _, id := range ids {
+		items = append(items, fmt.Sprintf(`{"id":%d,"guid":"g%d","status":"OK","status_code":200,"event":"push"}`, id, id))
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func TestListDeliveries(t *testing.T) {
+	next := "https://api.github.com/repos/octo-org/hello-world/hooks/1/deliveries?cursor=v1_2&per_page=100"
+	client := &fakeDeliveriesClient{responses: map[string]*http.Response{
+		"GET repos/octo-org/hello-world/hooks/1/deliveries?per_page=100": jsonResponse(deliveriesJSON(4, 3),
+			http.Header{"Link": []string{`<` + next + `>; rel="next"`}}),
+		"GET " + next: jsonResponse(deliveriesJSON(2, 1), nil),
+	}}
+
+	deliveries, err := ListDeliveries(client, RepoHook("octo-org/hello-world", 1), 0)
+	require.NoError(t, err)
+	var ids []int64
+	for _, d := range deliveries {
+		ids = append(ids, d.ID)
+	}
+	assert.Equal(t, []int64{4, 3, 2, 1}, ids)
+	assert.False(t, deliveries[0].Failed())
+}
+
+func TestListDeliveriesLimit(t *testing.T) {
+	client := &fakeDeliveriesClient{responses: map[string]*http.Response{
+		"GET orgs/octo-org/hooks/7/deliveries?per_page=1": jsonResponse(deliveriesJSON(4),
+			http.Header{"Link": []string{`<https://api.github.com/next>; rel="next"`}}),
+	}}
+	deliveries, err := ListDeliveries(client, OrgHook("octo-org", 7), 1)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Len(t, client.requests, 1)
+}
+
+func TestGetDelivery(t *testing.T) {
+	client := &fakeDeliveriesClient{responses: map[string]*http.Response{
+		"GET app/hook/deliveries/12": jsonResponse(`{
+			"id": 12, "guid": "0b989ba4", "status": "Invalid HTTP Response: 503", "status_code": 503,
+			"event": "issues", "action": "opened",
+			"request": {"headers": {"X-GitHub-Event": "issues"}, "payload": {"action": "opened", "issue": {"number": 1}}},
+			"response": {"headers": {"Content-Type": "text/plain"}, "payload": "unavailable"}
+		}`, nil),
+	}}
+	d, err := GetDelivery(client, AppHook(), 12)
+	require.NoError(t, err)
+	assert.True(t, d.Failed())
+	assert.Equal(t, "issues", d.Request.Headers["X-GitHub-Event"])
+	assert.Equal(t, `"unavailable"`, string(d.Response.Payload))
+
+	e, err := d.Parse()
+	require.NoError(t, err)
+	assert.Equal(t, 1, e.(*IssuesEvent).Issue.Number)
+
+	_, err = (&Delivery{ID: 3}).Parse()
+	assert.EqualError(t, err, "delivery 3 has no request payload")
+}
+
+func TestRedeliver(t *testing.T) {
+	client := &fakeDeliveriesClient{responses: map[string]*http.Response{
+		"POST repos/octo-org/hello-world/hooks/1/deliveries/12/attempts": jsonResponse(`{}`, nil),
+	}}
+	require.NoError(t, Redeliver(client, RepoHook("octo-org/hello-world", 1), 12))
+	assert.Error(t, Redeliver(client, RepoHook("octo-org/hello-world", 1), 13))
+}
+
+func TestListDeliveriesContextCancelled(t *testing.T) {
+	client := &fakeDeliveriesClient{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ListDeliveriesContext(ctx, client, RepoHook("octo-org/hello-world", 1), 0)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, client.requests)
+}
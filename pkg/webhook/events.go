@@ -0,0 +1,297 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Event is a parsed webhook event. Use a type switch to access the typed payload of the
+// common events, and *RawEvent for the others.
+type Event interface {
+	// EventName returns the name of the event, as sent in the X-GitHub-Event header.
+	EventName() string
+}
+
+// ParseEvent parses the JSON payload of a delivery of the named event. Push, pull_request,
+// issues, workflow_run, release, and check_suite events are parsed into their typed structs,
+// and any other event into a *RawEvent holding the payload as is.
+func ParseEvent(name string, payload []byte) (Event, error) {
+	if name == "" {
+		return nil, errors.New("missing webhook event name")
+	}
+	var event Event
+	switch name {
+	case "push":
+		event = &PushEvent{}
+	case "pull_request":
+		event = &PullRequestEvent{}
+	case "issues":
+		event = &IssuesEvent{}
+	case "workflow_run":
+		event = &WorkflowRunEvent{}
+	case "release":
+		event = &ReleaseEvent{}
+	case "check_suite":
+		event = &CheckSuiteEvent{}
+	default:
+		var action struct {
+			Action string `json:"action"`
+		}
+		if err := json.Unmarshal(payload, &action); err != nil {
+			return nil, fmt.Errorf("failed to parse %s event: %w", name, err)
+		}
+		return &RawEvent{Name: name, Action: action.Action, Payload: json.RawMessage(payload)}, nil
+	}
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, fmt.Errorf("failed to parse %s event: %w", name, err)
+	}
+	return event, nil
+}
+
+// RawEvent is an event without a typed struct.
+type RawEvent struct {
+	Name    string
+	Action  string
+	Payload json.RawMessage
+}
+
+func (e *RawEvent) EventName() string { return e.Name }
+
+// User is a user or organization account.
+type User struct {
+	ID      int64  `json:"id"`
+	Login   string `json:"login"`
+	Type    string `json:"type"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Repository is the repository an event occurred in.
+type Repository struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Owner         User   `json:"owner"`
+	Private       bool   `json:"private"`
+	Fork          bool   `json:"fork"`
+	HTMLURL       string `json:"html_url"`
+	CloneURL      string `json:"clone_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// Installation identifies the GitHub App installation a delivery was sent for.
+type Installation struct {
+	ID int64 `json:"id"`
+}
+
+// Label is a label of an issue or pull request.
+type Label struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// PushEvent is sent when commits or tags are pushed.
+type PushEvent struct {
+	Ref          string        `json:"ref"`
+	Before       string        `json:"before"`
+	After        string        `json:"after"`
+	Created      bool          `json:"created"`
+	Deleted      bool          `json:"deleted"`
+	Forced       bool          `json:"forced"`
+	Compare      string        `json:"compare"`
+	Commits      []PushCommit  `json:"commits"`
+	HeadCommit   *PushCommit   `json:"head_commit"`
+	Pusher       CommitAuthor  `json:"pusher"`
+	Repository   Repository    `json:"repository"`
+	Sender       User          `json:"sender"`
+	Installation *Installation `json:"installation"`
+}
+
+func (e *PushEvent) EventName() string { return "push" }
+
+// PushCommit is a commit of a push.
+type PushCommit struct {
+	ID        string       `json:"id"`
+	TreeID    string       `json:"tree_id"`
+	Message   string       `json:"message"`
+	Timestamp time.Time    `json:"timestamp"`
+	URL       string       `json:"url"`
+	Author    CommitAuthor `json:"author"`
+	Committer CommitAuthor `json:"committer"`
+	Added     []string     `json:"added"`
+	Removed   []string     `json:"removed"`
+	Modified  []string     `json:"modified"`
+}
+
+// CommitAuthor is the git author, committer, or pusher of a commit.
+type CommitAuthor struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+// PullRequestEvent is sent when there is activity on a pull request.
+type PullRequestEvent struct {
+	Action       string        `json:"action"`
+	Number       int           `json:"number"`
+	PullRequest  PullRequest   `json:"pull_request"`
+	Label        *Label        `json:"label"`
+	Repository   Repository    `json:"repository"`
+	Sender       User          `json:"sender"`
+	Installation *Installation `json:"installation"`
+}
+
+func (e *PullRequestEvent) EventName() string { return "pull_request" }
+
+// PullRequest is the pull request of a PullRequestEvent.
+type PullRequest struct {
+	ID        int64      `json:"id"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	State     string     `json:"state"`
+	Draft     bool       `json:"draft"`
+	Merged    bool       `json:"merged"`
+	HTMLURL   string     `json:"html_url"`
+	User      User       `json:"user"`
+	Labels    []Label    `json:"labels"`
+	Head      Branch     `json:"head"`
+	Base      Branch     `json:"base"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+}
+
+// Branch is the head or base branch of a pull request.
+type Branch struct {
+	Label string      `json:"label"`
+	Ref   string      `json:"ref"`
+	SHA   string      `json:"sha"`
+	Repo  *Repository `json:"repo"`
+}
+
+// IssuesEvent is sent when there is activity on an issue.
+type IssuesEvent struct {
+	Action       string        `json:"action"`
+	Issue        Issue         `json:"issue"`
+	Label        *Label        `json:"label"`
+	Assignee     *User         `json:"assignee"`
+	Repository   Repository    `json:"repository"`
+	Sender       User          `json:"sender"`
+	Installation *Installation `json:"installation"`
+}
+
+func (e *IssuesEvent) EventName() string { return "issues" }
+
+// Issue is the issue of an IssuesEvent.
+type Issue struct {
+	ID        int64      `json:"id"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	State     string     `json:"state"`
+	HTMLURL   string     `json:"html_url"`
+	User      User       `json:"user"`
+	Labels    []Label    `json:"labels"`
+	Assignees []User     `json:"assignees"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+}
+
+// WorkflowRunEvent is sent when a GitHub Actions workflow run is requested, in progress, or completed.
+type WorkflowRunEvent struct {
+	Action       string        `json:"action"`
+	WorkflowRun  WorkflowRun   `json:"workflow_run"`
+	Repository   Repository    `json:"repository"`
+	Sender       User          `json:"sender"`
+	Installation *Installation `json:"installation"`
+}
+
+func (e *WorkflowRunEvent) EventName() string { return "workflow_run" }
+
+// WorkflowRun is the workflow run of a WorkflowRunEvent.
+type WorkflowRun struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	WorkflowID int64     `json:"workflow_id"`
+	Path       string    `json:"path"`
+	HeadBranch string    `json:"head_branch"`
+	HeadSHA    string    `json:"head_sha"`
+	Event      string    `json:"event"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"`
+	RunNumber  int       `json:"run_number"`
+	RunAttempt int       `json:"run_attempt"`
+	HTMLURL    string    `json:"html_url"`
+	Actor      User      `json:"actor"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ReleaseEvent is sent when there is activity on a release.
+type ReleaseEvent struct {
+	Action       string        `json:"action"`
+	Release      Release       `json:"release"`
+	Repository   Repository    `json:"repository"`
+	Sender       User          `json:"sender"`
+	Installation *Installation `json:"installation"`
+}
+
+func (e *ReleaseEvent) EventName() string { return "release" }
+
+// Release is the release of a ReleaseEvent.
+type Release struct {
+	ID              int64      `json:"id"`
+	TagName         string     `json:"tag_name"`
+	TargetCommitish string     `json:"target_commitish"`
+	Name            string     `json:"name"`
+	Body            string     `json:"body"`
+	Draft           bool       `json:"draft"`
+	Prerelease      bool       `json:"prerelease"`
+	HTMLURL         string     `json:"html_url"`
+	Author          User       `json:"author"`
+	CreatedAt       time.Time  `json:"created_at"`
+	PublishedAt     *time.Time `json:"published_at"`
+}
+
+// CheckSuiteEvent is sent when there is activity on a check suite.
+type CheckSuiteEvent struct {
+	Action       string        `json:"action"`
+	CheckSuite   CheckSuite    `json:"check_suite"`
+	Repository   Repository    `json:"repository"`
+	Sender       User          `json:"sender"`
+	Installation *Installation `json:"installation"`
+}
+
+func (e *CheckSuiteEvent) EventName() string { return "check_suite" }
+
+// CheckSuite is the check suite of a CheckSuiteEvent.
+type CheckSuite struct {
+	ID           int64     `json:"id"`
+	HeadBranch   string    `json:"head_branch"`
+	HeadSHA      string    `json:"head_sha"`
+	Status       string    `json:"status"`
+	Conclusion   string    `json:"conclusion"`
+	App          App       `json:"app"`
+	PullRequests []PRRef   `json:"pull_requests"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// App is the GitHub App that owns a check suite.
+type App struct {
+	ID   int64  `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// PRRef is a pull request that a check suite ran for.
+type PRRef struct {
+	ID     int64  `json:"id"`
+	Number int    `json:"number"`
+	Head   Branch `json:"head"`
+	Base   Branch `json:"base"`
+}
@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   string
+		payload string
+		check   func(t *testing.T, e Event)
+	}{
+		{
+			name:  "push",
+			event: "push",
+			payload: `{"ref":"refs/heads/main","before":"a1","after":"b2","forced":true,
+				"commits":[{"id":"b2","message":"Fix typo","timestamp":"2023-01-02T15:04:05Z","author":{"name":"Mona","username":"monalisa"},"modified":["README.md"]}],
+				"head_commit":{"id":"b2"},"pusher":{"name":"monalisa"},
+				"repository":{"full_name":"octo/hello","owner":{"login":"octo"}},"sender":{"login":"monalisa"}}`,
+			check: func(t *testing.T, e Event) {
+				push, ok := e.(*PushEvent)
+				require.True(t, ok)
+				assert.Equal(t, "push", push.EventName())
+				assert.Equal(t, "refs/heads/main", push.Ref)
+				assert.True(t, push.Forced)
+				require.Len(t, push.Commits, 1)
+				assert.Equal(t, "Fix typo", push.Commits[0].Message)
+				assert.Equal(t, 2023, push.Commits[0].Timestamp.Year())
+				assert.Equal(t, []string{"README.md"}, push.Commits[0].Modified)
+				assert.Equal(t, "b2", push.HeadCommit.ID)
+				assert.Equal(t, "octo/hello", push.Repository.FullName)
+				assert.Nil(t, push.Installation)
+			},
+		},
+		{
+			name:  "pull_request",
+			event: "pull_request",
+			payload: `{"action":"labeled","number":42,"label":{"name":"bug"},
+				"pull_request":{"number":42,"title":"Add feature","draft":true,"head":{"ref":"feature","sha":"c3"},"base":{"ref":"main"}},
+				"installation":{"id":7}}`,
+			check: func(t *testing.T, e Event) {
+				pr, ok := e.(*PullRequestEvent)
+				require.True(t, ok)
+				assert.Equal(t, "labeled", pr.Action)
+				assert.Equal(t, 42, pr.PullRequest.Number)
+				assert.True(t, pr.PullRequest.Draft)
+				assert.Equal(t, "feature", pr.PullRequest.Head.Ref)
+				assert.Equal(t, "main", pr.PullRequest.Base.Ref)
+				assert.Equal(t, "bug", pr.Label.Name)
+				assert.Equal(t, int64(7), pr.Installation.ID)
+			},
+		},
+		{
+			name:    "issues",
+			event:   "issues",
+			payload: `{"action":"opened","issue":{"number":1,"title":"Broken","labels":[{"name":"bug"}],"closed_at":null}}`,
+			check: func(t *testing.T, e Event) {
+				issue, ok := e.(*IssuesEvent)
+				require.True(t, ok)
+				assert.Equal(t, "opened", issue.Action)
+				assert.Equal(t, "Broken", issue.Issue.Title)
+				assert.Equal(t, "bug", issue.Issue.Labels[0].Name)
+				assert.Nil(t, issue.Issue.ClosedAt)
+			},
+		},
+		{
+			name:    "workflow_run",
+			event:   "workflow_run",
+			payload: `{"action":"completed","workflow_run":{"id":30433642,"name":"CI","status":"completed","conclusion":"failure","run_attempt":2}}`,
+			check: func(t *testing.T, e Event) {
+				run, ok := e.(*WorkflowRunEvent)
+				require.True(t, ok)
+				assert.Equal(t, "completed", run.Action)
+				assert.Equal(t, "CI", run.WorkflowRun.Name)
+				assert.Equal(t, "failure", run.WorkflowRun.Conclusion)
+				assert.Equal(t, 2, run.WorkflowRun.RunAttempt)
+			},
+		},
+		{
+			name:    "release",
+			event:   "release",
+			payload: `{"action":"published","release":{"tag_name":"v1.0.0","prerelease":true,"published_at":"2023-01-02T15:04:05Z"}}`,
+			check: func(t *testing.T, e Event) {
+				release, ok := e.(*ReleaseEvent)
+				require.True(t, ok)
+				assert.Equal(t, "published", release.Action)
+				assert.Equal(t, "v1.0.0", release.Release.TagName)
+				assert.True(t, release.Release.Prerelease)
+				assert.NotNil(t, release.Release.PublishedAt)
+			},
+		},
+		{
+			name:    "check_suite",
+			event:   "check_suite",
+			payload: `{"action":"requested","check_suite":{"head_sha":"d4","app":{"slug":"ci"},"pull_requests":[{"number":42}]}}`,
+			check: func(t *testing.T, e Event) {
+				suite, ok := e.(*CheckSuiteEvent)
+				require.True(t, ok)
+				assert.Equal(t, "requested", suite.Action)
+				assert.Equal(t, "ci", suite.CheckSuite.App.Slug)
+				assert.Equal(t, 42, suite.CheckSuite.PullRequests[0].Number)
+			},
+		},
+		{
+			name:    "other event",
+			event:   "star",
+			payload: `{"action":"created","starred_at":"2023-01-02T15:04:05Z"}`,
+			check: func(t *testing.T, e Event) {
+				raw, ok := e.(*RawEvent)
+				require.True(t, ok)
+				assert.Equal(t, "star", raw.EventName())
+				assert.Equal(t, "created", raw.Action)
+				var payload map[string]string
+				require.NoError(t, json.Unmarshal(raw.Payload, &payload))
+				assert.Equal(t, "2023-01-02T15:04:05Z", payload["starred_at"])
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := ParseEvent(tt.event, []byte(tt.payload))
+			require.NoError(t, err)
+			tt.check(t, e)
+		})
+	}
+}
+
+func TestParseEventErrors(t *testing.T) {
+	_, err := ParseEvent("", []byte(`{}`))
+	assert.EqualError(t, err, "missing webhook event name")
+
+	_, err = ParseEvent("push", []byte(`{"ref":`))
+	assert.EqualError(t, err, "failed to parse push event: unexpected end of JSON input")
+
+	_, err = ParseEvent("star", []byte(`not json`))
+	assert.Error(t, err)
+}
+
+func FuzzParseEvent(f *testing.F) {
+	for _, name := range SampleEvents() {
+		payload, err := SamplePayload(name)
+		require.NoError(f, err)
+		f.Add(name, string(payload))
+	}
+	f.Add("star", `{"action":"created"}`)
+	f.Add("push", `{"commits":[null],"repository":null}`)
+	filter, err := CompileFilter("event:push,pull_request action:opened -sender:*bot* OR label:bug repo:octo-org/*")
+	require.NoError(f, err)
+	f.Fuzz(func(t *testing.T, name, payload string) {
+		event, err := ParseEvent(name, []byte(payload))
+		if err != nil {
+			return
+		}
+		assert.NotEmpty(t, event.EventName())
+		_ = filter.Match(event)
+	})
+}
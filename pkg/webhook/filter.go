@@ -0,0 +1,240 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/shlex"
+)
+
+// Filter is a compiled filter expression that selects the events a consumer handles.
+//
+// An expression is a sequence of terms of the form "key:value", where key is one of event,
+// action, repo, sender, or label. A term matches when the field of the event equals one
+// of its comma-separated values, compared without regard to case; values may contain "*"
+// wildcards, and values with spaces must be quoted. A term prefixed with "-" matches when
+// the field does not match. An event matches a sequence of terms when it matches all of
+// them, and sequences can be combined with the OR keyword:
+//
+//	event:pull_request action:opened,synchronize repo:octo-org/* -sender:*[bot]
+//	event:issues label:bug OR event:issues label:"good first issue"
+//
+// The label key matches any of the labels of the issue or pull request of an event, or the
+// label that was added or removed.
+type Filter struct {
+	groups [][]filterTerm
+}
+
+type filterTerm struct {
+	key    string
+	values []string
+	negate bool
+}
+
+var filterKeys = map[string]bool{"event": true, "action": true, "repo": true, "sender": true, "label": true}
+
+// CompileFilter compiles a filter expression. An empty expression matches every event.
+func CompileFilter(expr string) (*Filter, error) {
+	tokens, err := shlex.Split(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	f := &Filter{}
+	group := []filterTerm{}
+	for _, token := range tokens {
+		if token == "OR" {
+			if len(group) == 0 {
+				return nil, fmt.Errorf("invalid filter: OR must be between terms")
+			}
+			f.groups = append(f.groups, group)
+			group = []filterTerm{}
+			continue
+		}
+		term := filterTerm{}
+		if strings.HasPrefix(token, "-") {
+			term.negate = true
+			token = token[1:]
+		}
+		key, value, ok := strings.Cut(token, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid filter term %q: expected key:value", token)
+		}
+		if !filterKeys[key] {
+			return nil, fmt.Errorf("invalid filter term %q: unknown key %q", token, key)
+		}
+		term.key = key
+		for _, v := range strings.Split(value, ",") {
+			if v == "" {
+				return nil, fmt.Errorf("invalid filter term %q: empty value", token)
+			}
+			term.values = append(term.values, strings.ToLower(v))
+		}
+		group = append(group, term)
+	}
+	if len(group) == 0 && len(f.groups) > 0 {
+		return nil, fmt.Errorf("invalid filter: OR must be between terms")
+	}
+	if len(group) > 0 {
+		f.groups = append(f.groups, group)
+	}
+	return f, nil
+}
+
+// Match reports whether an event matches the filter. It accepts events parsed by
+// ParseEvent as well as events returned by a Poller.
+func (f *Filter) Match(e Event) bool {
+	if len(f.groups) == 0 {
+		return true
+	}
+	fields := eventFields(e)
+	for _, group := range f.groups {
+		if matchGroup(group, fields) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchGroup(group []filterTerm, fields map[string][]string) bool {
+	for _, term := range group {
+		if matchTerm(term, fields[term.key]) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func matchTerm(term filterTerm, fieldValues []string) bool {
+	for _, fv := range fieldValues {
+		fv = strings.ToLower(fv)
+		for _, v := range term.values {
+			if matchWildcard(v, fv) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchWildcard reports whether s matches pattern, in which "*" matches any sequence of characters.
+func matchWildcard(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
+
+// eventFields returns the values of the filter keys for an event.
+func eventFields(e Event) map[string][]string {
+	fields := map[string][]string{"event": {e.EventName()}}
+	add := func(key string, values ...string) {
+		for _, v := range values {
+			if v != "" {
+				fields[key] = append(fields[key], v)
+			}
+		}
+	}
+	labelNames := func(labels []Label) []string {
+		names := make([]string, len(labels))
+		for i, l := range labels {
+			names[i] = l.Name
+		}
+		return names
+	}
+	switch e := e.(type) {
+	case *PushEvent:
+		add("repo", e.Repository.FullName)
+		add("sender", e.Sender.Login)
+	case *PullRequestEvent:
+		add("action", e.Action)
+		add("repo", e.Repository.FullName)
+		add("sender", e.Sender.Login)
+		add("label", labelNames(e.PullRequest.Labels)...)
+		if e.Label != nil {
+			add("label", e.Label.Name)
+		}
+	case *IssuesEvent:
+		add("action", e.Action)
+		add("repo", e.Repository.FullName)
+		add("sender", e.Sender.Login)
+		add("label", labelNames(e.Issue.Labels)...)
+		if e.Label != nil {
+			add("label", e.Label.Name)
+		}
+	case *WorkflowRunEvent:
+		add("action", e.Action)
+		add("repo", e.Repository.FullName)
+		add("sender", e.Sender.Login)
+	case *ReleaseEvent:
+		add("action", e.Action)
+		add("repo", e.Repository.FullName)
+		add("sender", e.Sender.Login)
+	case *CheckSuiteEvent:
+		add("action", e.Action)
+		add("repo", e.Repository.FullName)
+		add("sender", e.Sender.Login)
+	case *RawEvent:
+		addPayloadFields(add, e.Payload)
+	case *PolledEvent:
+		add("repo", e.Repo.Name)
+		add("sender", e.Actor.Login)
+		addPayloadFields(add, e.Payload)
+	}
+	return fields
+}
+
+// addPayloadFields adds the values of the filter keys found in a JSON payload.
+func addPayloadFields(add func(string, ...string), payload json.RawMessage) {
+	var p struct {
+		Action     string `json:"action"`
+		Repository *struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+		Sender *struct {
+			Login string `json:"login"`
+		} `json:"sender"`
+		Label *Label `json:"label"`
+		Issue *struct {
+			Labels []Label `json:"labels"`
+		} `json:"issue"`
+		PullRequest *struct {
+			Labels []Label `json:"labels"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return
+	}
+	add("action", p.Action)
+	if p.Repository != nil {
+		add("repo", p.Repository.FullName)
+	}
+	if p.Sender != nil {
+		add("sender", p.Sender.Login)
+	}
+	if p.Label != nil {
+		add("label", p.Label.Name)
+	}
+	var labels []Label
+	if p.Issue != nil {
+		labels = append(labels, p.Issue.Labels...)
+	}
+	if p.PullRequest != nil {
+		labels = append(labels, p.PullRequest.Labels...)
+	}
+	for _, l := range labels {
+		add("label", l.Name)
+	}
+}
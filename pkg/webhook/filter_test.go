@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleEvent(t *testing.T, name string, opts ...sampleOption) Event {
+	t.Helper()
+	payload, err := SamplePayload(name, opts...)
+	require.NoError(t, err)
+	e, err := ParseEvent(name, payload)
+	require.NoError(t, err)
+	return e
+}
+
+func TestFilterMatch(t *testing.T) {
+	pr := sampleEvent(t, "pull_request")
+	closedPR := sampleEvent(t, "pull_request", WithAction("closed"))
+	issue := sampleEvent(t, "issues")
+	push := sampleEvent(t, "push")
+	star := &RawEvent{Name: "star", Action: "created", Payload: []byte(`{"action":"created","repository":{"full_name":"octo-org/hello-world"},"sender":{"login":"dependabot[bot]"}}`)}
+	polled := &PolledEvent{Type: "IssuesEvent", Actor: User{Login: "monalisa"}, Repo: EventRepo{Name: "octo-org/other"},
+		Payload: []byte(`{"action":"labeled","label":{"name":"good first issue"},"issue":{"labels":[{"name":"good first issue"}]}}`)}
+
+	tests := []struct {
+		name string
+		expr string
+		want map[Event]bool
+	}{
+		{
+			name: "empty expression",
+			expr: "",
+			want: map[Event]bool{pr: true, push: true, star: true},
+		},
+		{
+			name: "event and actions",
+			expr: "event:pull_request action:opened,synchronize",
+			want: map[Event]bool{pr: true, closedPR: false, issue: false, push: false},
+		},
+		{
+			name: "repository glob",
+			expr: "repo:octo-org/hello-*",
+			want: map[Event]bool{pr: true, push: true, star: true, polled: false},
+		},
+		{
+			name: "negated sender with brackets",
+			expr: "-sender:*[bot]",
+			want: map[Event]bool{pr: true, star: false, polled: true},
+		},
+		{
+			name: "case insensitive",
+			expr: "sender:MonaLisa repo:OCTO-ORG/*",
+			want: map[Event]bool{push: true, polled: true, star: false},
+		},
+		{
+			name: "labels",
+			expr: "label:enhancement",
+			want: map[Event]bool{pr: true, issue: false, push: false},
+		},
+		{
+			name: "OR with quoted value",
+			expr: `event:issues label:bug OR event:issues label:"good first issue"`,
+			want: map[Event]bool{issue: true, polled: true, pr: false},
+		},
+		{
+			name: "raw event action",
+			expr: "event:star action:created",
+			want: map[Event]bool{star: true, push: false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := CompileFilter(tt.expr)
+			require.NoError(t, err)
+			for e, want := range tt.want {
+				assert.Equal(t, want, f.Match(e), "event %s", e.EventName())
+			}
+		})
+	}
+}
+
+func TestCompileFilterErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{expr: "pull_request", wantErr: `invalid filter term "pull_request": expected key:value`},
+		{expr: "branch:main", wantErr: `invalid filter term "branch:main": unknown key "branch"`},
+		{expr: "action:opened,", wantErr: `invalid filter term "action:opened,": empty value`},
+		{expr: "OR event:push", wantErr: "invalid filter: OR must be between terms"},
+		{expr: "event:push OR", wantErr: "invalid filter: OR must be between terms"},
+		{expr: `label:"bug`, wantErr: "invalid filter: EOF found when expecting closing quote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := CompileFilter(tt.expr)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// ForwardClient is the subset of [api.RESTClient] used to manage the webhook that
+// events are forwarded from.
+type ForwardClient interface {
+	Post(path string, body io.Reader, resp interface{}) error
+	Patch(path string, body io.Reader, resp interface{}) error
+	Delete(path string, resp interface{}) error
+}
+
+// ForwardOptions configures Forward.
+type ForwardOptions struct {
+	// Repo is the repository "OWNER/REPO" to forward events of. Either Repo or Org is required.
+	Repo string
+	// Org is the organization to forward events of.
+	Org string
+	// Events are the names of the events to forward, e.g. "push" or "*" for all events.
+	Events []string
+	// Secret is used to sign the forwarded deliveries, if not empty.
+	Secret string
+	// Token authenticates the connection to the forwarding service. It must be a token
+	// of the same user as the client, such as the one returned by [auth.TokenForHost].
+	Token string
+	// Handler receives the forwarded deliveries. Either Handler or URL is required.
+	Handler http.Handler
+	// URL is the local address forwarded deliveries are posted to, e.g. "http://localhost:3000/webhook".
+	URL string
+}
+
+// forwardedRequest is a delivery received from the forwarding service.
+type forwardedRequest struct {
+	Header map[string][]string
+	Body   []byte
+}
+
+// forwardedResponse is the response to a delivery sent back to the forwarding service,
+// which records it as the response of the delivery.
+type forwardedResponse struct {
+	Status int
+	Header map[string][]string
+	Body   []byte
+}
+
+// Forward relays the webhook deliveries of a repository or organization to a local handler
+// or URL until ctx is done, so that webhook consumers can be developed without exposing
+// them to the internet. It creates a temporary webhook connected to the forwarding service
+// of GitHub, which is deleted when Forward returns. Only one forwarding webhook can exist
+// per repository or organization at a time.
+func Forward(ctx context.Context, client ForwardClient, opts ForwardOptions) (err error) {
+	var hooksPath string
+	switch {
+	case opts.Repo != "" && opts.Org != "":
+		return errors.New("only one of Repo and Org can be set")
+	case opts.Repo != "":
+		hooksPath = fmt.Sprintf("repos/%s/hooks", opts.Repo)
+	case opts.Org != "":
+		hooksPath = fmt.Sprintf("orgs/%s/hooks", opts.Org)
+	default:
+		return errors.New("either Repo or Org is required")
+	}
+	if (opts.Handler == nil) == (opts.URL == "") {
+		return errors.New("exactly one of Handler and URL is required")
+	}
+	if len(opts.Events) == 0 {
+		return errors.New("at least one event is required")
+	}
+	handler := opts.Handler
+	if handler == nil {
+		handler = urlForwarder(opts.URL)
+	}
+
+	hookConfig := map[string]string{"content_type": "json", "insecure_ssl": "0"}
+	if opts.Secret != "" {
+		hookConfig["secret"] = opts.Secret
+	}
+	var hook struct {
+		ID    int64  `json:"id"`
+		WSURL string `json:"ws_url"`
+	}
+	if err := sendJSON(client.Post, hooksPath, map[string]interface{}{
+		"name":   "cli",
+		"events": opts.Events,
+		"active": false,
+		"config": hookConfig,
+	}, &hook); err != nil {
+		return fmt.Errorf("failed to create forwarding webhook: %w", err)
+	}
+	hookPath := fmt.Sprintf("%s/%d", hooksPath, hook.ID)
+	defer func() {
+		if deleteErr := client.Delete(hookPath, nil); deleteErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to delete forwarding webhook: %w", deleteErr))
+		}
+	}()
+
+	wsConfig, err := websocket.NewConfig(hook.WSURL, "https://github.com")
+	if err != nil {
+		return err
+	}
+	wsConfig.Header.Set("Authorization", opts.Token)
+	wsConfig.Dialer = &net.Dialer{Timeout: 30 * time.Second}
+	conn, err := websocket.DialConfig(wsConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the forwarding service: %w", err)
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	if err := sendJSON(client.Patch, hookPath, map[string]bool{"active": true}, nil); err != nil {
+		return fmt.Errorf("failed to activate forwarding webhook: %w", err)
+	}
+
+	for {
+		var req forwardedRequest
+		if err := websocket.JSON.Receive(conn, &req); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("connection to the forwarding service failed: %w", err)
+		}
+		resp, err := serveForwarded(ctx, handler, req)
+		if err != nil {
+			return err
+		}
+		if err := websocket.JSON.Send(conn, resp); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("connection to the forwarding service failed: %w", err)
+		}
+	}
+}
+
+func serveForwarded(ctx context.Context, handler http.Handler, req forwardedRequest) (forwardedResponse, error) {
+	r, err := http.NewRequestWithContext(ctx, "POST", "/", bytes.NewReader(req.Body))
+	if err != nil {
+		return forwardedResponse{}, err
+	}
+	for k, values := range req.Header {
+		for _, v := range values {
+			r.Header.Add(k, v)
+		}
+	}
+	w := &responseRecorder{header: http.Header{}}
+	handler.ServeHTTP(w, r)
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return forwardedResponse{Status: w.status, Header: w.header, Body: w.body.Bytes()}, nil
+}
+
+// forwardTimeout bounds the time the local URL has to respond to a delivery, like GitHub
+// does for the deliveries it sends.
+const forwardTimeout = 10 * time.Second
+
+// urlForwarder returns a handler posting deliveries to url.
+func urlForwarder(url string) http.Handler {
+	client := &http.Client{Timeout: forwardTimeout}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequestWithContext(r.Context(), "POST", url, r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		req.Header = r.Header.Clone()
+		resp, err := client.Do(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		for k, values := range resp.Header {
+			w.Header()[k] = values
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	})
+}
+
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func sendJSON(method func(string, io.Reader, interface{}) error, path string, body, resp interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return method(path, bytes.NewReader(b), resp)
+}
@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+type fakeForwardClient struct {
+	mu       sync.Mutex
+	wsURL    string
+	requests []string
+	bodies   []map[string]interface{}
+}
+
+func (c *fakeForwardClient) record(method, path string, body io.Reader) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, method+" "+path)
+	var b map[string]interface{}
+	if body != nil {
+		_ = json.NewDecoder(body).Decode(&b)
+	}
+	c.bodies = append(c.bodies, b)
+}
+
+func (c *fakeForwardClient) Post(path string, body io.Reader, resp interface{}) error {
+	c.record("POST", path, body)
+	data := fmt.Sprintf(`{"id": 5, "ws_url": %q}`, c.wsURL)
+	return json.Unmarshal([]byte(data), resp)
+}
+
+func (c *fakeForwardClient) Patch(path string, body io.Reader, resp interface{}) error {
+	c.record("PATCH", path, body)
+	return nil
+}
+
+func (c *fakeForwardClient) Delete(path string, resp interface{}) error {
+	c.record("DELETE", path, nil)
+	return nil
+}
+
+// newForwardingService starts a fake forwarding service that sends req to the first
+// client that connects and reports the response on the returned channel.
+func newForwardingService(t *testing.T, req forwardedRequest) (string, <-chan forwardedResponse) {
+	t.Helper()
+	responses := make(chan forwardedResponse, 1)
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		if conn.Request().Header.Get("Authorization") != "abc123" {
+			return
+		}
+		if err := websocket.JSON.Send(conn, req); err != nil {
+			return
+		}
+		var resp forwardedResponse
+		if err := websocket.JSON.Receive(conn, &resp); err != nil {
+			return
+		}
+		responses <- resp
+		// Wait for the client to disconnect.
+		_ = websocket.JSON.Receive(conn, &resp)
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http"), responses
+}
+
+func TestForwardToHandler(t *testing.T) {
+	payload, err := SamplePayload("push")
+	require.NoError(t, err)
+	wsURL, responses := newForwardingService(t, forwardedRequest{
+		Header: map[string][]string{EventHeader: {"push"}, SignatureHeader: {Sign(payload, "s3cret")}},
+		Body:   payload,
+	})
+	client := &fakeForwardClient{wsURL: wsURL}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := VerifyRequest(r, "s3cret")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		e, _ := ParseEvent(r.Header.Get(EventHeader), payload)
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, e.(*PushEvent).Ref)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- Forward(ctx, client, ForwardOptions{
+			Repo:    "octo-org/hello-world",
+			Events:  []string{"push"},
+			Secret:  "s3cret",
+			Token:   "abc123",
+			Handler: handler,
+		})
+	}()
+
+	resp := <-responses
+	assert.Equal(t, http.StatusAccepted, resp.Status)
+	assert.Equal(t, []string{"text/plain"}, resp.Header["Content-Type"])
+	assert.Equal(t, "refs/heads/main", string(resp.Body))
+
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+	assert.Equal(t, []string{
+		"POST repos/octo-org/hello-world/hooks",
+		"PATCH repos/octo-org/hello-world/hooks/5",
+		"DELETE repos/octo-org/hello-world/hooks/5",
+	}, client.requests)
+	assert.Equal(t, map[string]interface{}{
+		"name":   "cli",
+		"events": []interface{}{"push"},
+		"active": false,
+		"config": map[string]interface{}{"content_type": "json", "insecure_ssl": "0", "secret": "s3cret"},
+	}, client.bodies[0])
+	assert.Equal(t, map[string]interface{}{"active": true}, client.bodies[1])
+}
+
+func TestForwardToURL(t *testing.T) {
+	local := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, "%s %s", r.Header.Get(EventHeader), body)
+	}))
+	defer local.Close()
+	wsURL, responses := newForwardingService(t, forwardedRequest{
+		Header: map[string][]string{EventHeader: {"ping"}},
+		Body:   []byte(`{"zen":"Keep it logically awesome."}`),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- Forward(ctx, &fakeForwardClient{wsURL: wsURL}, ForwardOptions{
+			Org:    "octo-org",
+			Events: []string{"*"},
+			Token:  "abc123",
+			URL:    local.URL,
+		})
+	}()
+
+	resp := <-responses
+	assert.Equal(t, http.StatusCreated, resp.Status)
+	assert.Equal(t, `ping {"zen":"Keep it logically awesome."}`, string(resp.Body))
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+}
+
+func TestForwardOptionsErrors(t *testing.T) {
+	handler := http.NotFoundHandler()
+	tests := []struct {
+		name    string
+		opts    ForwardOptions
+		wantErr string
+	}{
+		{
+			name:    "no target",
+			opts:    ForwardOptions{Events: []string{"push"}, Handler: handler},
+			wantErr: "either Repo or Org is required",
+		},
+		{
+			name:    "repo and org",
+			opts:    ForwardOptions{Repo: "o/r", Org: "o", Events: []string{"push"}, Handler: handler},
+			wantErr: "only one of Repo and Org can be set",
+		},
+		{
+			name:    "handler and URL",
+			opts:    ForwardOptions{Repo: "o/r", Events: []string{"push"}, Handler: handler, URL: "http://localhost"},
+			wantErr: "exactly one of Handler and URL is required",
+		},
+		{
+			name:    "no events",
+			opts:    ForwardOptions{Repo: "o/r", Handler: handler},
+			wantErr: "at least one event is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Forward(context.Background(), &fakeForwardClient{}, tt.opts)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestForwardDeletesHookOnConnectionError(t *testing.T) {
+	client := &fakeForwardClient{wsURL: "ws://127.0.0.1:1/"}
+	err := Forward(context.Background(), client, ForwardOptions{
+		Repo:    "octo-org/hello-world",
+		Events:  []string{"push"},
+		Token:   "abc123",
+		Handler: http.NotFoundHandler(),
+	})
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "failed to connect to the forwarding service"))
+	assert.False(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, "DELETE repos/octo-org/hello-world/hooks/5", client.requests[len(client.requests)-1])
+}
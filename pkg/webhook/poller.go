@@ -0,0 +1,217 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/auth"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
+)
+
+const defaultPollInterval = time.Minute
+
+// PolledEvent is an event returned by the events API. Unlike webhook deliveries, its
+// payload does not include the repository, sender, or installation fields; use Repo,
+// Actor, and Org instead.
+type PolledEvent struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Actor     User            `json:"actor"`
+	Repo      EventRepo       `json:"repo"`
+	Org       *User           `json:"org"`
+	Public    bool            `json:"public"`
+	CreatedAt time.Time       `json:"created_at"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// EventRepo is the repository of a PolledEvent.
+type EventRepo struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// EventName returns the webhook name of the event type, e.g. "pull_request" for "PullRequestEvent".
+func (e *PolledEvent) EventName() string {
+	var sb strings.Builder
+	for i, r := range strings.TrimSuffix(e.Type, "Event") {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// Parse parses the payload like ParseEvent.
+func (e *PolledEvent) Parse() (Event, error) {
+	return ParseEvent(e.EventName(), e.Payload)
+}
+
+// Poller polls the events API of a repository or organization, as an alternative to
+// webhooks for consumers that cannot receive deliveries. Polls are conditional requests
+// on the ETag of the previous response, which do not count against the rate limit when
+// there are no new events, and are spaced by the interval the API requests with the
+// X-Poll-Interval header.
+//
+// Only the latest page of events is fetched, so events can be missed if more than 100
+// events occur between two polls.
+type Poller struct {
+	client   *http.Client
+	url      string
+	interval time.Duration
+	backlog  bool
+	clock    clock.Clock
+
+	etag string
+	seen map[string]bool
+}
+
+type pollerOption func(*Poller)
+
+// WithPollInterval sets the interval between polls. The interval requested by the API
+// is used instead if it is longer. It defaults to one minute.
+func WithPollInterval(d time.Duration) pollerOption {
+	return func(p *Poller) {
+		p.interval = d
+	}
+}
+
+// WithBacklog delivers the events returned by the first poll. By default they are
+// considered seen and only events that occur after the first poll are delivered.
+func WithBacklog() pollerOption {
+	return func(p *Poller) {
+		p.backlog = true
+	}
+}
+
+// WithClock sets the clock that spaces polls. It defaults to the system clock.
+func WithClock(c clock.Clock) pollerOption {
+	return func(p *Poller) {
+		p.clock = c
+	}
+}
+
+// NewRepoPoller initializes a Poller for the events of the repository "owner/name".
+func NewRepoPoller(opts api.ClientOptions, repo string, pollerOpts ...pollerOption) (*Poller, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" {
+		return nil, fmt.Errorf("expected the \"OWNER/REPO\" format, got %q", repo)
+	}
+	return newPoller(opts, fmt.Sprintf("repos/%s/%s/events", url.PathEscape(owner), url.PathEscape(name)), pollerOpts)
+}
+
+// NewOrgPoller initializes a Poller for the public events of an organization.
+func NewOrgPoller(opts api.ClientOptions, org string, pollerOpts ...pollerOption) (*Poller, error) {
+	return newPoller(opts, fmt.Sprintf("orgs/%s/events", url.PathEscape(org)), pollerOpts)
+}
+
+func newPoller(opts api.ClientOptions, path string, pollerOpts []pollerOption) (*Poller, error) {
+	if opts.Host == "" {
+		opts.Host, _ = auth.DefaultHost()
+	}
+	client, err := api.NewHTTPClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	p := &Poller{
+		client:   client,
+		url:      api.RESTURL(opts.Host, path) + "?per_page=100",
+		interval: defaultPollInterval,
+		clock:    clock.Real,
+		seen:     map[string]bool{},
+	}
+	for _, opt := range pollerOpts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// Run polls for events until ctx is done or a request fails, sending each new event to
+// events exactly once, oldest first. It returns the error of the failed request, or the
+// error of ctx.
+func (p *Poller) Run(ctx context.Context, events chan<- PolledEvent) error {
+	first := true
+	for {
+		newEvents, interval, err := p.poll(ctx)
+		if err != nil {
+			return err
+		}
+		if !first || p.backlog {
+			for _, e := range newEvents {
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		first = false
+		select {
+		case <-p.clock.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// poll fetches the latest events and returns those that have not been seen yet, oldest
+// first, along with the interval to wait before the next poll.
+func (p *Poller) poll(ctx context.Context) ([]PolledEvent, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if p.etag != "" {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	interval := p.interval
+	if s, err := strconv.Atoi(resp.Header.Get("X-Poll-Interval")); err == nil {
+		if d := time.Duration(s) * time.Second; d > interval {
+			interval = d
+		}
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, interval, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, 0, api.HandleHTTPError(resp)
+	}
+
+	var latest []PolledEvent
+	if err := json.NewDecoder(resp.Body).Decode(&latest); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse events: %w", err)
+	}
+	p.etag = resp.Header.Get("ETag")
+
+	// Events are returned newest first, and events that are not on the latest page
+	// cannot be returned again, so only their IDs need to be remembered.
+	seen := make(map[string]bool, len(latest))
+	var newEvents []PolledEvent
+	for i := len(latest) - 1; i >= 0; i-- {
+		e := latest[i]
+		seen[e.ID] = true
+		if !p.seen[e.ID] {
+			newEvents = append(newEvents, e)
+		}
+	}
+	p.seen = seen
+	return newEvents, interval, nil
+}
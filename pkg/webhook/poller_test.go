@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/api"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type eventsServer struct {
+	mu          sync.Mutex
+	pages       []string
+	etags       []string
+	ifNoneMatch []string
+}
+
+func (s *eventsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ifNoneMatch = append(s.ifNoneMatch, r.Header.Get("If-None-Match"))
+	if len(s.pages) == 0 {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	page, etag := s.pages[0], s.etags[0]
+	s.pages, s.etags = s.pages[1:], s.etags[1:]
+	if etag != "" && r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("X-Poll-Interval", "0")
+	fmt.Fprint(w, page)
+}
+
+func events(ids ...string) string {
+	var items []string
+	for _, id := range ids {
+		items = append(items, fmt.Sprintf(`{"id":%q,"type":"PushEvent","repo":{"name":"octo-org/hello-world"},"payload":{"ref":"refs/heads/main"}}`, id))
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func newTestPoller(t *testing.T, s *eventsServer, opts ...pollerOption) *Poller {
+	t.Helper()
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	p, err := NewRepoPoller(api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "abc123",
+		Transport: http.DefaultTransport,
+	}, "octo-org/hello-world", opts...)
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.github.com/repos/octo-org/hello-world/events?per_page=100", p.url)
+	p.url = server.URL + "/repos/octo-org/hello-world/events"
+	return p
+}
+
+func TestPollerPoll(t *testing.T) {
+	s := &eventsServer{
+		pages: []string{events("2", "1"), events("2", "1"), events("4", "3", "2")},
+		etags: []string{`"a"`, `"a"`, `"b"`},
+	}
+	p := newTestPoller(t, s, WithPollInterval(time.Second))
+
+	got, interval, err := p.poll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, interval)
+	assert.Equal(t, []string{"1", "2"}, eventIDs(got))
+
+	got, _, err = p.poll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	got, _, err = p.poll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3", "4"}, eventIDs(got))
+
+	assert.Equal(t, []string{"", `"a"`, `"a"`}, s.ifNoneMatch)
+}
+
+func TestPollerRun(t *testing.T) {
+	s := &eventsServer{
+		pages: []string{events("2", "1"), events("3", "2", "1")},
+		etags: []string{`"a"`, `"b"`},
+	}
+	fakeClock := clock.NewFake(time.Now())
+	p := newTestPoller(t, s, WithPollInterval(time.Minute), WithBacklog(), WithClock(fakeClock))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan PolledEvent)
+	errCh := make(chan error)
+	go func() { errCh <- p.Run(ctx, ch) }()
+
+	ids := []string{(<-ch).ID, (<-ch).ID}
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Minute)
+	ids = append(ids, (<-ch).ID)
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+}
+
+func TestPollerRunError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Not Found"}`)
+	}))
+	defer server.Close()
+	p, err := NewOrgPoller(api.ClientOptions{Host: "github.com", AuthToken: "abc123", Transport: http.DefaultTransport}, "octo-org")
+	require.NoError(t, err)
+	p.url = server.URL + "/orgs/octo-org/events"
+
+	err = p.Run(context.Background(), make(chan PolledEvent))
+	var httpErr *api.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+}
+
+func TestPolledEvent(t *testing.T) {
+	tests := []struct {
+		eventType string
+		want      string
+	}{
+		{eventType: "PushEvent", want: "push"},
+		{eventType: "PullRequestEvent", want: "pull_request"},
+		{eventType: "PullRequestReviewCommentEvent", want: "pull_request_review_comment"},
+		{eventType: "WatchEvent", want: "watch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			e := PolledEvent{Type: tt.eventType}
+			assert.Equal(t, tt.want, e.EventName())
+		})
+	}
+
+	e := PolledEvent{Type: "PushEvent", Payload: []byte(`{"ref":"refs/heads/main"}`)}
+	parsed, err := e.Parse()
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/main", parsed.(*PushEvent).Ref)
+}
+
+func TestNewRepoPollerInvalidRepo(t *testing.T) {
+	_, err := NewRepoPoller(api.ClientOptions{}, "hello-world")
+	assert.EqualError(t, err, `expected the "OWNER/REPO" format, got "hello-world"`)
+}
+
+func eventIDs(events []PolledEvent) []string {
+	ids := []string{}
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+//go:embed samples/*.json
+var samples embed.FS
+
+// SampleEvents returns the names of the events that sample payloads are available for.
+func SampleEvents() []string {
+	entries, _ := samples.ReadDir("samples")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+type sampleOptions struct {
+	action     string
+	deliveryID string
+}
+
+type sampleOption func(*sampleOptions)
+
+// WithAction replaces the action of a sample payload, e.g. "closed" for a pull_request event.
+func WithAction(action string) sampleOption {
+	return func(o *sampleOptions) {
+		o.action = action
+	}
+}
+
+// WithDeliveryID sets the delivery ID of a sample request instead of a random one.
+func WithDeliveryID(id string) sampleOption {
+	return func(o *sampleOptions) {
+		o.deliveryID = id
+	}
+}
+
+// SamplePayload returns a realistic payload of the named event, for use in tests of
+// webhook consumers. See SampleEvents for the events that are available.
+func SamplePayload(event string, opts ...sampleOption) ([]byte, error) {
+	o := sampleOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	payload, err := samples.ReadFile("samples/" + event + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("no sample payload for %s event", event)
+	}
+	if o.action == "" {
+		return payload, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	if _, ok := fields["action"]; !ok {
+		return nil, fmt.Errorf("%s event has no action", event)
+	}
+	fields["action"], _ = json.Marshal(o.action)
+	return json.MarshalIndent(fields, "", "  ")
+}
+
+// NewSampleRequest returns a delivery of a sample payload of the named event signed with
+// secret, with the headers GitHub sends, so that tests can pass it to an http.Handler.
+func NewSampleRequest(url, event, secret string, opts ...sampleOption) (*http.Request, error) {
+	o := sampleOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	payload, err := SamplePayload(event, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if o.deliveryID == "" {
+		o.deliveryID = randomDeliveryID()
+	}
+	r, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("User-Agent", "GitHub-Hookshot/sample")
+	r.Header.Set(EventHeader, event)
+	r.Header.Set(DeliveryHeader, o.deliveryID)
+	r.Header.Set(SignatureHeader, Sign(payload, secret))
+	r.Header.Set(LegacySignatureHeader, signSHA1(payload, secret))
+	return r, nil
+}
+
+// Sign returns the value of the X-Hub-Signature-256 header of a payload signed with secret.
+func Sign(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func signSHA1(payload []byte, secret string) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(payload)
+	return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// randomDeliveryID returns a random ID in the UUID format of real delivery IDs.
+func randomDeliveryID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
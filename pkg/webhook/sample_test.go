@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleEvents(t *testing.T) {
+	assert.Equal(t, []string{"check_suite", "issues", "pull_request", "push", "release", "workflow_run"}, SampleEvents())
+}
+
+func TestNewSampleRequest(t *testing.T) {
+	for _, event := range SampleEvents() {
+		t.Run(event, func(t *testing.T) {
+			r, err := NewSampleRequest("http://localhost/webhook", event, "s3cret")
+			require.NoError(t, err)
+			assert.Equal(t, event, r.Header.Get(EventHeader))
+			assert.Len(t, r.Header.Get(DeliveryHeader), 36)
+
+			payload, err := VerifyRequest(r, "s3cret")
+			require.NoError(t, err)
+			assert.NoError(t, Verify(payload, r.Header.Get(LegacySignatureHeader), "s3cret"))
+
+			e, err := ParseEvent(r.Header.Get(EventHeader), payload)
+			require.NoError(t, err)
+			assert.Equal(t, event, e.EventName())
+			_, isRaw := e.(*RawEvent)
+			assert.False(t, isRaw)
+		})
+	}
+}
+
+func TestSampleOptions(t *testing.T) {
+	r, err := NewSampleRequest("http://localhost/webhook", "pull_request", "s3cret",
+		WithAction("closed"), WithDeliveryID("72d3162e-cc78-11e3-81ab-4c9367dc0958"))
+	require.NoError(t, err)
+	assert.Equal(t, "72d3162e-cc78-11e3-81ab-4c9367dc0958", r.Header.Get(DeliveryHeader))
+	payload, err := VerifyRequest(r, "s3cret")
+	require.NoError(t, err)
+	e, err := ParseEvent("pull_request", payload)
+	require.NoError(t, err)
+	pr := e.(*PullRequestEvent)
+	assert.Equal(t, "closed", pr.Action)
+	assert.Equal(t, 1347, pr.Number)
+
+	_, err = SamplePayload("push", WithAction("created"))
+	assert.EqualError(t, err, "push event has no action")
+
+	_, err = NewSampleRequest("http://localhost/webhook", "star", "s3cret")
+	assert.EqualError(t, err, "no sample payload for star event")
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, testSHA256, Sign([]byte(testPayload), testSecret))
+}
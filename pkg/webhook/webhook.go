@@ -0,0 +1,204 @@
+// Package webhook verifies and parses GitHub webhook deliveries, and polls the events
+// API as an alternative for consumers that cannot receive them.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SignatureHeader is the header holding the HMAC-SHA256 signature of a delivery.
+	SignatureHeader = "X-Hub-Signature-256"
+	// LegacySignatureHeader is the header holding the HMAC-SHA1 signature of a delivery,
+	// which is only sent for compatibility with existing integrations.
+	LegacySignatureHeader = "X-Hub-Signature"
+	// DeliveryHeader is the header holding the unique ID of a delivery.
+	DeliveryHeader = "X-GitHub-Delivery"
+	// EventHeader is the header holding the name of the event that triggered a delivery.
+	EventHeader = "X-GitHub-Event"
+	// HookIDHeader is the header holding the ID of the webhook that sent a delivery.
+	HookIDHeader = "X-GitHub-Hook-ID"
+
+	// maxPayloadSize is the largest payload GitHub delivers.
+	maxPayloadSize = 25 << 20
+)
+
+var (
+	// ErrMissingSecret is returned when a delivery is verified without a webhook secret,
+	// since anyone can compute a signature with an empty key.
+	ErrMissingSecret = errors.New("missing webhook secret")
+	// ErrMissingSignature is returned when a delivery is not signed.
+	ErrMissingSignature = errors.New("missing webhook signature")
+	// ErrInvalidSignature is returned when the signature of a delivery does not match its payload.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrReplayed is returned when a delivery has already been received.
+	ErrReplayed = errors.New("webhook delivery has already been received")
+)
+
+// Verify checks that signatureHeader, the value of the X-Hub-Signature-256 or the legacy
+// X-Hub-Signature header, is the signature of payload computed with the webhook secret.
+// Signatures are compared in constant time so that they cannot be guessed by timing.
+// ErrMissingSecret is returned if secret is empty.
+func Verify(payload []byte, signatureHeader, secret string) error {
+	if secret == "" {
+		return ErrMissingSecret
+	}
+	if signatureHeader == "" {
+		return ErrMissingSignature
+	}
+	algorithm, signature, ok := strings.Cut(signatureHeader, "=")
+	if !ok {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+	var newHash func() hash.Hash
+	switch algorithm {
+	case "sha256":
+		newHash = sha256.New
+	case "sha1":
+		newHash = sha1.New
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidSignature, algorithm)
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(payload)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+type verifyOptions struct {
+	guard *ReplayGuard
+}
+
+type option func(*verifyOptions)
+
+// WithReplayGuard rejects deliveries whose ID has already been seen by guard with ErrReplayed.
+func WithReplayGuard(guard *ReplayGuard) option {
+	return func(o *verifyOptions) {
+		o.guard = guard
+	}
+}
+
+// VerifyRequest reads the payload of a delivery from the body of r and verifies its
+// signature with Verify, preferring the SHA-256 signature when both are present. The
+// payload is returned and the body of r is replaced so that it can be read again.
+// ErrMissingSecret is returned without reading the body if secret is empty.
+func VerifyRequest(r *http.Request, secret string, opts ...option) ([]byte, error) {
+	if secret == "" {
+		return nil, ErrMissingSecret
+	}
+	o := verifyOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook payload: %w", err)
+	}
+	if len(payload) > maxPayloadSize {
+		return nil, errors.New("webhook payload is too large")
+	}
+	r.Body = io.NopCloser(bytes.NewReader(payload))
+	signature := r.Header.Get(SignatureHeader)
+	if signature == "" {
+		signature = r.Header.Get(LegacySignatureHeader)
+	}
+	if err := Verify(payload, signature, secret); err != nil {
+		return nil, err
+	}
+	if o.guard != nil {
+		if err := o.guard.Check(r.Header.Get(DeliveryHeader)); err != nil {
+			return nil, err
+		}
+	}
+	return payload, nil
+}
+
+// ReceivedDelivery is a webhook delivery received by a server and parsed by ParseRequest.
+type ReceivedDelivery struct {
+	// ID is the unique ID of the delivery, which is kept when it is redelivered.
+	ID string
+	// HookID is the ID of the webhook that sent the delivery.
+	HookID string
+	// Event is the parsed payload.
+	Event Event
+	// Payload is the raw JSON payload.
+	Payload []byte
+}
+
+// ParseRequest verifies the signature of a delivery with VerifyRequest and parses its
+// payload with ParseEvent, taking the event name from the X-GitHub-Event header.
+func ParseRequest(r *http.Request, secret string, opts ...option) (*ReceivedDelivery, error) {
+	payload, err := VerifyRequest(r, secret, opts...)
+	if err != nil {
+		return nil, err
+	}
+	event, err := ParseEvent(r.Header.Get(EventHeader), payload)
+	if err != nil {
+		return nil, err
+	}
+	return &ReceivedDelivery{
+		ID:      r.Header.Get(DeliveryHeader),
+		HookID:  r.Header.Get(HookIDHeader),
+		Event:   event,
+		Payload: payload,
+	}, nil
+}
+
+// ReplayGuard remembers the IDs of recent deliveries to reject deliveries that are
+// received again within a time window, for example when a signed request has been
+// captured and resent by an attacker. Deliveries that are redelivered on purpose keep
+// their ID, so the window should be shorter than the delay after which redeliveries
+// are expected. It is safe for concurrent use.
+type ReplayGuard struct {
+	mu     sync.Mutex
+	window time.Duration
+	seen   map[string]time.Time
+	now    func() time.Time
+}
+
+// NewReplayGuard initializes a ReplayGuard remembering deliveries for the given window.
+func NewReplayGuard(window time.Duration) *ReplayGuard {
+	return &ReplayGuard{
+		window: window,
+		seen:   map[string]time.Time{},
+		now:    time.Now,
+	}
+}
+
+// Check records the delivery ID and returns ErrReplayed if it was recorded within the window.
+func (g *ReplayGuard) Check(deliveryID string) error {
+	if deliveryID == "" {
+		return errors.New("missing webhook delivery ID")
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	for id, t := range g.seen {
+		if now.Sub(t) > g.window {
+			delete(g.seen, id)
+		}
+	}
+	if _, ok := g.seen[deliveryID]; ok {
+		return ErrReplayed
+	}
+	g.seen[deliveryID] = now
+	return nil
+}
@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Signatures of the example in the GitHub documentation on validating deliveries.
+const (
+	testSecret    = "It's a Secret to Everybody"
+	testPayload   = "Hello, World!"
+	testSHA256    = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+	testSHA1      = "sha1=01dc10d0c83e72ed246219cdd91669667fe2ca59"
+	testSHA256Bad = "sha256=657107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+)
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name      string
+		payload   string
+		signature string
+		secret    string
+		wantErr   error
+	}{
+		{
+			name:      "valid sha256",
+			payload:   testPayload,
+			signature: testSHA256,
+			secret:    testSecret,
+		},
+		{
+			name:      "valid sha1",
+			payload:   testPayload,
+			signature: testSHA1,
+			secret:    testSecret,
+		},
+		{
+			name:      "wrong signature",
+			payload:   testPayload,
+			signature: testSHA256Bad,
+			secret:    testSecret,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "wrong secret",
+			payload:   testPayload,
+			signature: testSHA256,
+			secret:    "secret",
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "tampered payload",
+			payload:   "Hello, World?",
+			signature: testSHA256,
+			secret:    testSecret,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "missing signature",
+			payload:   testPayload,
+			signature: "",
+			secret:    testSecret,
+			wantErr:   ErrMissingSignature,
+		},
+		{
+			name:      "unsupported algorithm",
+			payload:   testPayload,
+			signature: "md5=abc",
+			secret:    testSecret,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "malformed signature",
+			payload:   testPayload,
+			signature: "sha256=xyz",
+			secret:    testSecret,
+			wantErr:   ErrInvalidSignature,
+		},
+		{
+			name:      "empty secret",
+			payload:   testPayload,
+			signature: Sign([]byte(testPayload), ""),
+			secret:    "",
+			wantErr:   ErrMissingSecret,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify([]byte(tt.payload), tt.signature, tt.secret)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyRequest(t *testing.T) {
+	newRequest := func(headers map[string]string) *http.Request {
+		r, _ := http.NewRequest("POST", "/webhook", strings.NewReader(testPayload))
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+		return r
+	}
+
+	r := newRequest(map[string]string{SignatureHeader: testSHA256, LegacySignatureHeader: "sha1=00"})
+	payload, err := VerifyRequest(r, testSecret)
+	assert.NoError(t, err)
+	assert.Equal(t, testPayload, string(payload))
+	body, _ := io.ReadAll(r.Body)
+	assert.Equal(t, testPayload, string(body))
+
+	_, err = VerifyRequest(newRequest(map[string]string{LegacySignatureHeader: testSHA1}), testSecret)
+	assert.NoError(t, err)
+
+	_, err = VerifyRequest(newRequest(nil), testSecret)
+	assert.ErrorIs(t, err, ErrMissingSignature)
+
+	_, err = VerifyRequest(newRequest(map[string]string{SignatureHeader: testSHA256}), "")
+	assert.ErrorIs(t, err, ErrMissingSecret)
+
+	guard := NewReplayGuard(time.Hour)
+	headers := map[string]string{SignatureHeader: testSHA256, DeliveryHeader: "72d3162e-cc78-11e3-81ab-4c9367dc0958"}
+	_, err = VerifyRequest(newRequest(headers), testSecret, WithReplayGuard(guard))
+	assert.NoError(t, err)
+	_, err = VerifyRequest(newRequest(headers), testSecret, WithReplayGuard(guard))
+	assert.ErrorIs(t, err, ErrReplayed)
+}
+
+func TestParseRequest(t *testing.T) {
+	r, err := NewSampleRequest("/webhook", "pull_request", testSecret, WithDeliveryID("72d3162e-cc78-11e3-81ab-4c9367dc0958"))
+	assert.NoError(t, err)
+	r.Header.Set(HookIDHeader, "12345")
+	delivery, err := ParseRequest(r, testSecret)
+	assert.NoError(t, err)
+	assert.Equal(t, "72d3162e-cc78-11e3-81ab-4c9367dc0958", delivery.ID)
+	assert.Equal(t, "12345", delivery.HookID)
+	assert.IsType(t, &PullRequestEvent{}, delivery.Event)
+	assert.NotEmpty(t, delivery.Payload)
+
+	r, err = NewSampleRequest("/webhook", "push", testSecret)
+	assert.NoError(t, err)
+	_, err = ParseRequest(r, "wrong secret")
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	r, err = NewSampleRequest("/webhook", "push", testSecret)
+	assert.NoError(t, err)
+	r.Header.Del(EventHeader)
+	_, err = ParseRequest(r, testSecret)
+	assert.EqualError(t, err, "missing webhook event name")
+}
+
+func TestReplayGuard(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := NewReplayGuard(5 * time.Minute)
+	g.now = func() time.Time { return now }
+
+	assert.NoError(t, g.Check("a"))
+	assert.NoError(t, g.Check("b"))
+	assert.ErrorIs(t, g.Check("a"), ErrReplayed)
+
+	now = now.Add(6 * time.Minute)
+	assert.NoError(t, g.Check("a"))
+	assert.Len(t, g.seen, 1)
+
+	assert.EqualError(t, g.Check(""), "missing webhook delivery ID")
+}
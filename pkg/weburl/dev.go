@@ -0,0 +1,87 @@
+package weburl
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/repository"
+)
+
+// ErrUnsupportedHost is returned when a link is requested for a host that does not
+// offer the linked service, such as github.dev for a GitHub Enterprise Server host.
+var ErrUnsupportedHost = errors.New("host does not support github.dev or Codespaces")
+
+// Browser is the interface of [browser.Browser] used to open links.
+type Browser interface {
+	Browse(string) error
+}
+
+// Open opens url with b.
+func Open(b Browser, url string) error {
+	return b.Browse(url)
+}
+
+// Editor returns the URL opening a repository in the github.dev web editor. If ref is
+// not empty the editor opens at that ref, and if filePath is not empty it opens that file.
+// Only repositories on github.com can be opened in github.dev.
+func Editor(r repository.Repository, ref, filePath string) (string, error) {
+	if !isGitHub(r.Host) {
+		return "", ErrUnsupportedHost
+	}
+	u := "https://github.dev/" + escapePath(r.Owner) + "/" + escapePath(r.Name)
+	if ref == "" && filePath == "" {
+		return u, nil
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+	u += "/blob/" + escapePath(ref)
+	if filePath != "" {
+		u += "/" + escapePath(strings.TrimPrefix(filePath, "/"))
+	}
+	return u, nil
+}
+
+// CodespaceOptions are the optional settings of a new codespace.
+type CodespaceOptions struct {
+	// Ref is the branch or tag to create the codespace from. It defaults to the
+	// default branch of the repository.
+	Ref string
+	// Machine is the name of the machine type, e.g. "basicLinux32gb".
+	Machine string
+	// DevcontainerPath is the path of the devcontainer.json file in the repository.
+	DevcontainerPath string
+	// Location is the region to create the codespace in, e.g. "WestUs2".
+	Location string
+}
+
+// NewCodespace returns the URL of the page creating a codespace for a repository with
+// the given options preselected. Only repositories on github.com support Codespaces.
+func NewCodespace(r repository.Repository, opts CodespaceOptions) (string, error) {
+	if !isGitHub(r.Host) {
+		return "", ErrUnsupportedHost
+	}
+	u := Host(r.Host) + "codespaces/new/" + escapePath(r.Owner) + "/" + escapePath(r.Name)
+	q := url.Values{}
+	if opts.Ref != "" {
+		q.Set("ref", opts.Ref)
+	}
+	if opts.Machine != "" {
+		q.Set("machine", opts.Machine)
+	}
+	if opts.DevcontainerPath != "" {
+		q.Set("devcontainer_path", opts.DevcontainerPath)
+	}
+	if opts.Location != "" {
+		q.Set("location", opts.Location)
+	}
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	return u, nil
+}
+
+func isGitHub(host string) bool {
+	return Host(host) == "https://"+github+"/"
+}
@@ -0,0 +1,105 @@
+package weburl
+
+import (
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/browser"
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditor(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     repository.Repository
+		ref      string
+		filePath string
+		want     string
+		wantErr  error
+	}{
+		{
+			name: "repository",
+			repo: repository.Repository{Host: "github.com", Owner: "cli", Name: "go-gh"},
+			want: "https://github.dev/cli/go-gh",
+		},
+		{
+			name: "ref",
+			repo: repository.Repository{Host: "github.com", Owner: "cli", Name: "go-gh"},
+			ref:  "feature/dev",
+			want: "https://github.dev/cli/go-gh/blob/feature/dev",
+		},
+		{
+			name:     "file without ref",
+			repo:     repository.Repository{Host: "api.github.com", Owner: "cli", Name: "go-gh"},
+			filePath: "/pkg/api/client.go",
+			want:     "https://github.dev/cli/go-gh/blob/HEAD/pkg/api/client.go",
+		},
+		{
+			name:    "enterprise host",
+			repo:    repository.Repository{Host: "ghe.example.com", Owner: "acme", Name: "widgets"},
+			wantErr: ErrUnsupportedHost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Editor(tt.repo, tt.ref, tt.filePath)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewCodespace(t *testing.T) {
+	tests := []struct {
+		name    string
+		repo    repository.Repository
+		opts    CodespaceOptions
+		want    string
+		wantErr error
+	}{
+		{
+			name: "no options",
+			repo: repository.Repository{Host: "github.com", Owner: "cli", Name: "go-gh"},
+			want: "https://github.com/codespaces/new/cli/go-gh",
+		},
+		{
+			name: "all options",
+			repo: repository.Repository{Host: "github.com", Owner: "cli", Name: "go-gh"},
+			opts: CodespaceOptions{
+				Ref:              "trunk",
+				Machine:          "basicLinux32gb",
+				DevcontainerPath: ".devcontainer/go/devcontainer.json",
+				Location:         "WestUs2",
+			},
+			want: "https://github.com/codespaces/new/cli/go-gh?devcontainer_path=.devcontainer%2Fgo%2Fdevcontainer.json&location=WestUs2&machine=basicLinux32gb&ref=trunk",
+		},
+		{
+			name:    "enterprise host",
+			repo:    repository.Repository{Host: "ghe.example.com", Owner: "acme", Name: "widgets"},
+			wantErr: ErrUnsupportedHost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCodespace(tt.repo, tt.opts)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	b := browser.NewStub(nil)
+	u, err := Editor(repository.Repository{Host: "github.com", Owner: "cli", Name: "go-gh"}, "", "")
+	assert.NoError(t, err)
+	assert.NoError(t, Open(b, u))
+	b.Verify(t, "https://github.dev/cli/go-gh")
+}
@@ -0,0 +1,91 @@
+// Package weburl builds the URLs of GitHub resources in the web interface,
+// for example to open them with the browser package.
+package weburl
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/repository"
+)
+
+const (
+	github    = "github.com"
+	localhost = "github.localhost"
+)
+
+// Host returns the base URL of the web interface of host, e.g. "https://github.com/".
+// Subdomains of github.com such as "api.github.com" refer to github.com itself.
+func Host(host string) string {
+	host = strings.ToLower(host)
+	if host == "garage.github.com" {
+		return "https://" + host + "/"
+	}
+	if strings.HasSuffix(host, "."+github) {
+		host = github
+	}
+	if host == localhost || strings.HasSuffix(host, "."+localhost) {
+		return "http://" + localhost + "/"
+	}
+	return "https://" + host + "/"
+}
+
+// Repo returns the URL of the home page of a repository.
+func Repo(r repository.Repository) string {
+	return Host(r.Host) + escapePath(r.Owner) + "/" + escapePath(r.Name)
+}
+
+// Branch returns the URL of the file tree of a branch, or of any other ref.
+func Branch(r repository.Repository, branch string) string {
+	return Repo(r) + "/tree/" + escapePath(branch)
+}
+
+// File returns the URL of a file at the given ref. If startLine is greater than zero the
+// URL highlights that line, or the range of lines up to endLine if it is greater than
+// startLine. Markdown files are shown as source so that lines can be highlighted.
+func File(r repository.Repository, ref, filePath string, startLine, endLine int) string {
+	u := Repo(r) + "/blob/" + escapePath(ref) + "/" + escapePath(strings.TrimPrefix(filePath, "/"))
+	if startLine <= 0 {
+		return u
+	}
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".md", ".markdown", ".mdown", ".mkdn":
+		u += "?plain=1"
+	}
+	if endLine > startLine {
+		return fmt.Sprintf("%s#L%d-L%d", u, startLine, endLine)
+	}
+	return fmt.Sprintf("%s#L%d", u, startLine)
+}
+
+// Issue returns the URL of an issue.
+func Issue(r repository.Repository, number int) string {
+	return fmt.Sprintf("%s/issues/%d", Repo(r), number)
+}
+
+// PullRequest returns the URL of a pull request.
+func PullRequest(r repository.Repository, number int) string {
+	return fmt.Sprintf("%s/pull/%d", Repo(r), number)
+}
+
+// Commit returns the URL of a commit.
+func Commit(r repository.Repository, sha string) string {
+	return Repo(r) + "/commit/" + url.PathEscape(sha)
+}
+
+// Compare returns the URL comparing the head ref to the base ref. Refs of a fork
+// can be given in the "owner:branch" form.
+func Compare(r repository.Repository, base, head string) string {
+	return Repo(r) + "/compare/" + escapePath(base) + "..." + escapePath(head)
+}
+
+// escapePath escapes each segment of a slash-separated path.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
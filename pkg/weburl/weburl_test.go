@@ -0,0 +1,101 @@
+package weburl
+
+import (
+	"testing"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "github.com", want: "https://github.com/"},
+		{host: "API.GitHub.com", want: "https://github.com/"},
+		{host: "www.github.com", want: "https://github.com/"},
+		{host: "garage.github.com", want: "https://garage.github.com/"},
+		{host: "github.localhost", want: "http://github.localhost/"},
+		{host: "api.github.localhost", want: "http://github.localhost/"},
+		{host: "ghe.example.com", want: "https://ghe.example.com/"},
+		{host: "acme.ghe.com", want: "https://acme.ghe.com/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			assert.Equal(t, tt.want, Host(tt.host))
+		})
+	}
+}
+
+func TestURLs(t *testing.T) {
+	r := repository.Repository{Host: "github.com", Owner: "cli", Name: "go-gh"}
+	ghes := repository.Repository{Host: "ghe.example.com", Owner: "acme", Name: "widgets"}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{
+			name: "repo",
+			got:  Repo(r),
+			want: "https://github.com/cli/go-gh",
+		},
+		{
+			name: "enterprise repo",
+			got:  Repo(ghes),
+			want: "https://ghe.example.com/acme/widgets",
+		},
+		{
+			name: "branch with slash and special characters",
+			got:  Branch(r, "feature/100% done"),
+			want: "https://github.com/cli/go-gh/tree/feature/100%25%20done",
+		},
+		{
+			name: "file",
+			got:  File(r, "main", "/pkg/api/client.go", 0, 0),
+			want: "https://github.com/cli/go-gh/blob/main/pkg/api/client.go",
+		},
+		{
+			name: "file line",
+			got:  File(r, "main", "pkg/api/client.go", 10, 0),
+			want: "https://github.com/cli/go-gh/blob/main/pkg/api/client.go#L10",
+		},
+		{
+			name: "file line range",
+			got:  File(ghes, "v1.0.0", "main.go", 10, 20),
+			want: "https://ghe.example.com/acme/widgets/blob/v1.0.0/main.go#L10-L20",
+		},
+		{
+			name: "markdown file line range",
+			got:  File(r, "trunk", "README.md", 3, 5),
+			want: "https://github.com/cli/go-gh/blob/trunk/README.md?plain=1#L3-L5",
+		},
+		{
+			name: "issue",
+			got:  Issue(r, 123),
+			want: "https://github.com/cli/go-gh/issues/123",
+		},
+		{
+			name: "pull request",
+			got:  PullRequest(ghes, 45),
+			want: "https://ghe.example.com/acme/widgets/pull/45",
+		},
+		{
+			name: "commit",
+			got:  Commit(r, "8b0f0a4"),
+			want: "https://github.com/cli/go-gh/commit/8b0f0a4",
+		},
+		{
+			name: "compare",
+			got:  Compare(r, "trunk", "monalisa:fix/typo"),
+			want: "https://github.com/cli/go-gh/compare/trunk...monalisa:fix/typo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Package yamlpretty implements a converter from JSON to YAML for structured output.
+package yamlpretty
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format reads a stream of JSON values from r and writes each of them to w as a YAML document.
+// Object keys keep the order in which they appear in the input so that output is stable.
+// When the input contains more than one value, documents are separated by "---".
+func Format(w io.Writer, r io.Reader) error {
+	dec := json.NewDecoder(r)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	docs := 0
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		// JSON is a subset of YAML, so decoding into a node retains key order and scalar types.
+		var doc yaml.Node
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return err
+		}
+		resetStyle(&doc)
+		if err := enc.Encode(&doc); err != nil {
+			return err
+		}
+		docs++
+	}
+
+	if docs == 0 {
+		return nil
+	}
+	return enc.Close()
+}
+
+// resetStyle clears the flow and quoting styles inherited from the JSON input so that
+// the encoder emits block-style YAML and only quotes strings where necessary.
+func resetStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		resetStyle(c)
+	}
+}
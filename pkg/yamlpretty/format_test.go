@@ -0,0 +1,77 @@
+package yamlpretty
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  "",
+		},
+		{
+			name:  "object keeps key order",
+			input: `{"name":"goctl","id":42,"private":false,"topics":["cli","go"],"owner":{"login":"octocat"},"license":null}`,
+			want: heredoc.Doc(`
+				name: goctl
+				id: 42
+				private: false
+				topics:
+				  - cli
+				  - go
+				owner:
+				  login: octocat
+				license: null
+			`),
+		},
+		{
+			name:  "strings that need quoting",
+			input: `{"version":"1.0","enabled":"true","message":"hello: world","empty":""}`,
+			want: heredoc.Doc(`
+				version: "1.0"
+				enabled: "true"
+				message: 'hello: world'
+				empty: ""
+			`),
+		},
+		{
+			name:  "multiple documents",
+			input: "{\"a\":1}\n{\"b\":2}\n",
+			want:  "a: 1\n---\nb: 2\n",
+		},
+		{
+			name:  "scalar",
+			input: `"hello"`,
+			want:  "hello\n",
+		},
+		{
+			name:    "invalid JSON",
+			input:   `{"a":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := Format(w, strings.NewReader(tt.input))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, w.String())
+		})
+	}
+}
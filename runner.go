@@ -0,0 +1,129 @@
+package goctl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultConcurrency is the number of goctl processes a Runner runs at the same time
+// unless configured otherwise.
+const defaultConcurrency = 4
+
+// RunnerOptions configures a Runner.
+type RunnerOptions struct {
+	// Concurrency is the maximum number of goctl processes that run at the same time.
+	// Further commands are queued until a process exits.
+	// Default is 4.
+	Concurrency int
+	// Timeout is the time after which each command is killed. The time a command spends
+	// in the queue does not count.
+	// Default is no timeout.
+	Timeout time.Duration
+	// Dir and Env are the working directory and the additional environment variables of
+	// the commands, as in ExecOptions.
+	Dir string
+	Env []string
+}
+
+// Result is the outcome of a command run by a Runner.
+type Result struct {
+	Args   []string
+	Stdout bytes.Buffer
+	Stderr bytes.Buffer
+	// Err is the error of the command, such as an *ExecError, or the error of the context
+	// if it was done before the command started.
+	Err error
+}
+
+// Runner runs many goctl commands concurrently while limiting the number of processes,
+// for tools that fan out calls such as "goctl api" over many repositories. It is safe for
+// concurrent use.
+type Runner struct {
+	opts RunnerOptions
+	sem  chan struct{}
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewRunner returns a Runner configured by opts.
+func NewRunner(opts RunnerOptions) *Runner {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultConcurrency
+	}
+	return &Runner{opts: opts, sem: make(chan struct{}, opts.Concurrency)}
+}
+
+// Go queues a goctl command and returns immediately. Once the command exits, fn is called
+// with its result and the error it returns is collected by Wait. If fn is nil the error of
+// the command is collected instead. A command that is still queued when ctx is done does
+// not run, and its result carries the error of the context.
+func (r *Runner) Go(ctx context.Context, args []string, fn func(*Result) error) {
+	r.mu.Lock()
+	i := len(r.errs)
+	r.errs = append(r.errs, nil)
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		res := r.run(ctx, args)
+		err := res.Err
+		if fn != nil {
+			err = fn(res)
+		}
+		r.mu.Lock()
+		r.errs[i] = err
+		r.mu.Unlock()
+	}()
+}
+
+// GoCommand is like Go for a command built with New.
+func (r *Runner) GoCommand(ctx context.Context, c *Command, fn func(*Result) error) {
+	args, err := c.Args()
+	if err != nil {
+		r.mu.Lock()
+		r.errs = append(r.errs, err)
+		r.mu.Unlock()
+		return
+	}
+	r.Go(ctx, args, fn)
+}
+
+// Wait waits for all queued commands to finish and returns the collected errors joined
+// with errors.Join, in the order the commands were queued, or nil if there were none.
+// The Runner can be reused afterwards.
+func (r *Runner) Wait() error {
+	r.wg.Wait()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err := errors.Join(r.errs...)
+	r.errs = nil
+	return err
+}
+
+func (r *Runner) run(ctx context.Context, args []string) *Result {
+	res := &Result{Args: args}
+	select {
+	case r.sem <- struct{}{}:
+		defer func() { <-r.sem }()
+	case <-ctx.Done():
+		res.Err = ctx.Err()
+		return res
+	}
+	goctlExe, err := Path()
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	if r.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.opts.Timeout)
+		defer cancel()
+	}
+	res.Err = run(ctx, goctlExe, r.opts.Dir, execEnv(r.opts.Env), nil, &res.Stdout, &res.Stderr, args)
+	return res
+}
@@ -0,0 +1,85 @@
+package goctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunner(t *testing.T) {
+	t.Setenv("GOCTL_PATH", os.Args[0])
+	t.Cleanup(middleware.Reset)
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	Use(Middleware{
+		Command: func(next middleware.RunFunc) middleware.RunFunc {
+			return func(ctx context.Context, cmd middleware.Cmd) error {
+				mu.Lock()
+				running++
+				if running > maxRunning {
+					maxRunning = running
+				}
+				mu.Unlock()
+				defer func() {
+					mu.Lock()
+					running--
+					mu.Unlock()
+				}()
+				return next(ctx, cmd)
+			}
+		},
+	})
+
+	r := NewRunner(RunnerOptions{Concurrency: 2, Env: []string{"GOCTL_WANT_HELPER_PROCESS=1"}})
+	ctx := context.Background()
+	outputs := make([]string, 6)
+	for i := range outputs {
+		i := i
+		r.Go(ctx, []string{"-test.run=TestHelperProcess", "--", "api", fmt.Sprintf("repos/%d", i)}, func(res *Result) error {
+			outputs[i] = res.Stdout.String()
+			return res.Err
+		})
+	}
+	r.GoCommand(ctx, New("api").Flag("--method=POST"), nil)
+	r.Go(ctx, []string{"-test.run=TestHelperProcess", "--", "api", "error"}, nil)
+
+	err := r.Wait()
+	var exitErr *ExecError
+	assert.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, "invalid flag name \"--method=POST\"\ngoctl execution failed: exit status 1", err.Error())
+	assert.Equal(t, []string{"[api repos/0]", "[api repos/1]", "[api repos/2]", "[api repos/3]", "[api repos/4]", "[api repos/5]"}, outputs)
+	assert.LessOrEqual(t, maxRunning, 2)
+	assert.NoError(t, r.Wait())
+}
+
+func TestRunnerTimeout(t *testing.T) {
+	t.Setenv("GOCTL_PATH", os.Args[0])
+	r := NewRunner(RunnerOptions{Timeout: 100 * time.Millisecond, Env: []string{"GOCTL_WANT_HELPER_PROCESS=1"}})
+	start := time.Now()
+	r.Go(context.Background(), []string{"-test.run=TestHelperProcessLongRunning", "--", "run", "watch"}, nil)
+	assert.Error(t, r.Wait())
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestRunnerCanceledWhileQueued(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := NewRunner(RunnerOptions{Concurrency: 1})
+	// Occupy the only slot so that the command stays queued.
+	r.sem <- struct{}{}
+	var res *Result
+	r.Go(ctx, []string{"api", "user"}, func(got *Result) error {
+		res = got
+		return got.Err
+	})
+	err := r.Wait()
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, []string{"api", "user"}, res.Args)
+}
@@ -0,0 +1,94 @@
+package goctl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/auth"
+)
+
+// StreamOptions configures a goctl command run by ExecStream.
+type StreamOptions struct {
+	// Args are the arguments of the goctl command, such as "run", "watch".
+	Args []string
+	// Stdin is the standard input of the command. It is empty if nil.
+	Stdin io.Reader
+	// Stdout and Stderr receive the output of the command as it is written. Output is
+	// discarded if they are nil and no line callback is set.
+	Stdout io.Writer
+	Stderr io.Writer
+	// OnStdoutLine and OnStderrLine are called with each line of output, without its
+	// line ending, as soon as the line is complete. A final line without a line ending
+	// is passed when the command exits. Each callback is called from a single goroutine.
+	OnStdoutLine func(line string)
+	OnStderrLine func(line string)
+	// TokenSource authenticates the command with a token, as in ExecWithTokenSource.
+	// The credentials of the logged in user are used if it is nil.
+	TokenSource auth.TokenSource
+}
+
+// ExecStream invokes a goctl command in a subprocess and passes its output to the writers
+// and line callbacks of opts while it runs, which suits long-running commands such as
+// "goctl run watch". The command is killed when ctx is done.
+func ExecStream(ctx context.Context, opts StreamOptions) error {
+	goctlExe, err := Path()
+	if err != nil {
+		return err
+	}
+	env, err := tokenEnv(ctx, opts.TokenSource)
+	if err != nil {
+		return err
+	}
+	stdout := newStreamWriter(opts.Stdout, opts.OnStdoutLine)
+	stderr := newStreamWriter(opts.Stderr, opts.OnStderrLine)
+	err = run(ctx, goctlExe, "", env, opts.Stdin, stdout, stderr, opts.Args)
+	stdout.flush()
+	stderr.flush()
+	return err
+}
+
+// streamWriter forwards output to a writer and splits it into lines for a callback.
+type streamWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	onLine func(string)
+	buf    []byte
+}
+
+func newStreamWriter(w io.Writer, onLine func(string)) *streamWriter {
+	if w == nil {
+		w = io.Discard
+	}
+	return &streamWriter{w: w, onLine: onLine}
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, err := s.w.Write(p)
+	if s.onLine == nil {
+		return n, err
+	}
+	s.buf = append(s.buf, p...)
+	for {
+		i := bytes.IndexByte(s.buf, '\n')
+		if i < 0 {
+			break
+		}
+		s.onLine(string(bytes.TrimSuffix(s.buf[:i], []byte("\r"))))
+		s.buf = s.buf[i+1:]
+	}
+	return n, err
+}
+
+// flush passes the last line to the callback if the output did not end with a newline.
+func (s *streamWriter) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.onLine != nil && len(s.buf) > 0 {
+		s.onLine(string(s.buf))
+		s.buf = nil
+	}
+}
@@ -0,0 +1,121 @@
+package goctl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/khulnasoft-lab/go-goctl/v2/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelperProcessStream(t *testing.T) {
+	if os.Getenv("GOCTL_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args[3:]
+	switch args[len(args)-1] {
+	case "watch":
+		fmt.Fprint(os.Stdout, "run 1 queued\r\nrun 1 in_progress\n")
+		fmt.Fprint(os.Stderr, "refreshing...\n")
+		fmt.Fprint(os.Stdout, "run 1 completed")
+	case "token":
+		fmt.Fprint(os.Stdout, os.Getenv("GOCTL_TOKEN"))
+	case "hang":
+		fmt.Fprintln(os.Stdout, "ready")
+		time.Sleep(10 * time.Second)
+	}
+	os.Exit(0)
+}
+
+func stubStreamHelper(t *testing.T) {
+	t.Setenv("GOCTL_PATH", os.Args[0])
+	t.Setenv("GOCTL_WANT_HELPER_PROCESS", "1")
+}
+
+func TestExecStream(t *testing.T) {
+	stubStreamHelper(t)
+	var stdout bytes.Buffer
+	var lines, errLines []string
+	err := ExecStream(context.Background(), StreamOptions{
+		Args:         []string{"-test.run=TestHelperProcessStream", "--", "run", "watch"},
+		Stdout:       &stdout,
+		OnStdoutLine: func(line string) { lines = append(lines, line) },
+		OnStderrLine: func(line string) { errLines = append(errLines, line) },
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "run 1 queued\r\nrun 1 in_progress\nrun 1 completed", stdout.String())
+	assert.Equal(t, []string{"run 1 queued", "run 1 in_progress", "run 1 completed"}, lines)
+	assert.Equal(t, []string{"refreshing..."}, errLines)
+}
+
+func TestExecStreamTokenSource(t *testing.T) {
+	stubStreamHelper(t)
+	t.Setenv("GOCTL_TOKEN", "user_token")
+	var stdout bytes.Buffer
+	err := ExecStream(context.Background(), StreamOptions{
+		Args:        []string{"-test.run=TestHelperProcessStream", "--", "auth", "token"},
+		Stdout:      &stdout,
+		TokenSource: auth.StaticTokenSource("ghs_installation"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ghs_installation", stdout.String())
+
+	_, _, err = ExecWithTokenSource(context.Background(), auth.TokenSourceFunc(func(context.Context) (string, error) {
+		return "", errors.New("boom")
+	}), "auth", "token")
+	assert.EqualError(t, err, "failed to get token: boom")
+}
+
+func TestTokenEnv(t *testing.T) {
+	t.Setenv("GOCTL_TOKEN", "user_token")
+	t.Setenv("GOCTL_TOKEN_github_com", "user_token")
+	t.Setenv("GOCTL_TOKEN_GHE_EXAMPLE_COM", "user_token")
+	env, err := tokenEnv(context.Background(), auth.StaticTokenSource("ghs_installation"))
+	assert.NoError(t, err)
+	assert.NotContains(t, env, "GOCTL_TOKEN_github_com=user_token")
+	assert.NotContains(t, env, "GOCTL_TOKEN_GHE_EXAMPLE_COM=user_token")
+	assert.Equal(t, []string{"GOCTL_TOKEN=ghs_installation", "GOCTL_ENTERPRISE_TOKEN=ghs_installation"}, env[len(env)-2:])
+
+	env, err = tokenEnv(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Nil(t, env)
+}
+
+func TestExecStreamCancel(t *testing.T) {
+	stubStreamHelper(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var lines []string
+	start := time.Now()
+	err := ExecStream(ctx, StreamOptions{
+		Args: []string{"-test.run=TestHelperProcessStream", "--", "run", "hang"},
+		OnStdoutLine: func(line string) {
+			lines = append(lines, line)
+			cancel()
+		},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"ready"}, lines)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestStreamWriter(t *testing.T) {
+	var lines []string
+	w := newStreamWriter(nil, func(line string) { lines = append(lines, line) })
+	for _, chunk := range []string{"fir", "st\nsec", "ond\n\nla", "st"} {
+		n, err := w.Write([]byte(chunk))
+		assert.NoError(t, err)
+		assert.Equal(t, len(chunk), n)
+	}
+	assert.Equal(t, []string{"first", "second", ""}, lines)
+	w.flush()
+	assert.Equal(t, "first|second||last", strings.Join(lines, "|"))
+	w.flush()
+	assert.Len(t, lines, 4)
+}